@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/delly/amartha/common/logger"
 	"github.com/delly/amartha/config"
 	handler "github.com/delly/amartha/handler/http"
+	"github.com/delly/amartha/handler/http/middleware"
 	filestorage "github.com/delly/amartha/repository/file_storage"
 	"github.com/delly/amartha/repository/file_storage/gcs"
 	localfilestorage "github.com/delly/amartha/repository/file_storage/local_file_storage"
@@ -48,7 +50,7 @@ func main() {
 		logger.Info("Database connection closed")
 	}()
 
-	querier := dbgen.New(pool)
+	querier := dbgen.NewStore(pool)
 	var fileStorage filestorage.FileStorageRepository
 	if cfg.LocalStorage.UseLocal {
 		currentDir, err := os.Getwd()
@@ -63,14 +65,33 @@ func main() {
 	}
 	reconFinderSvc := reconciliatonjob.NewFinderService(querier)
 	reconCreatorSvc := reconciliatonjob.NewCreatorService(querier, fileStorage)
-	reconJobHandler := handler.NewReconciliationJobHandler(reconFinderSvc, reconCreatorSvc)
+	reconCancelerSvc := reconciliatonjob.NewCancelerService(querier)
+	reconExporterSvc := reconciliatonjob.NewExporterService(querier)
+	reconReporterSvc := reconciliatonjob.NewReporterService(querier)
+	reconAdjusterSvc := reconciliatonjob.NewAdjusterService(querier)
+	reconApproverSvc := reconciliatonjob.NewApproverService(querier)
+	reconCaseManagerSvc := reconciliatonjob.NewCaseManagerService(querier, cfg.CaseManager)
+	reconJobHandler := handler.NewReconciliationJobHandler(reconFinderSvc, reconCreatorSvc, reconCancelerSvc, reconExporterSvc, reconReporterSvc, reconAdjusterSvc, reconApproverSvc)
+	reconCaseHandler := handler.NewReconciliationCaseHandler(reconCaseManagerSvc)
 
 	r := httprouter.New()
 	reconJobHandler.Register(r)
+	reconCaseHandler.Register(r)
 
+	// the acting user is only trusted from any caller when it is explicitly
+	// opted out, otherwise it must be set by the gateway along with its token
+	var h http.Handler = r
+	if cfg.Server.TrustUserHeader {
+		logger.Warn("SERVER_TRUST_USER_HEADER is enabled, the acting user is trusted from any caller")
+	} else {
+		if cfg.Server.GatewayToken == "" {
+			checkError(errors.New("SERVER_GATEWAY_TOKEN is required unless SERVER_TRUST_USER_HEADER is enabled"))
+		}
+		h = middleware.WithGatewayAuth(r, cfg.Server.GatewayToken)
+	}
 	srv := http.Server{
 		Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
-		Handler: r,
+		Handler: h,
 	}
 
 	signalChan := make(chan os.Signal, 1)
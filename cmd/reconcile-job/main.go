@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"cloud.google.com/go/storage"
 	"github.com/delly/amartha/common/logger"
@@ -19,7 +21,8 @@ import (
 )
 
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 	cfg, err := config.NewConfig(".env")
 	checkError(err)
 
@@ -41,7 +44,7 @@ func main() {
 		logger.Info("Database connection closed")
 	}()
 
-	querier := dbgen.New(pool)
+	store := dbgen.NewStore(pool)
 	var fileStorage filestorage.FileStorageRepository
 	if cfg.LocalStorage.UseLocal {
 		currentDir, err := os.Getwd()
@@ -54,7 +57,7 @@ func main() {
 		bucket := client.Bucket(cfg.GCS.Bucket)
 		fileStorage = gcs.NewBucket(bucket)
 	}
-	reconProcesserService := reconciliatonjob.NewProcesserService(querier, fileStorage)
+	reconProcesserService := reconciliatonjob.NewProcesserService(store, fileStorage, cfg.Processer)
 
 	logger.Info("Processing reconciliation job...")
 	err = reconProcesserService.Process(ctx)
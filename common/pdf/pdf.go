@@ -0,0 +1,200 @@
+// Package pdf write simple text documents as PDF without any external
+// dependency, it only supports what is needed for printable reports, text in
+// the standard Helvetica fonts, table rows and blank fields to be filled by hand
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size and margin in points
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+	margin     = 50.0
+	// ContentWidth is the width available for the content of a page
+	ContentWidth = pageWidth - 2*margin
+)
+
+const (
+	fontRegular = "F1"
+	fontBold    = "F2"
+	textSize    = 10.0
+	headingSize = 12.0
+	titleSize   = 16.0
+	lineSpacing = 1.4
+	// charWidth is the approximate width of a Helvetica character relative
+	// to the font size, used to truncate the text that does not fit a cell
+	charWidth = 0.56
+)
+
+// Document is a PDF document written from top to bottom, a new page is added
+// when the content does not fit the current page
+type Document struct {
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// New create new document with a single empty page
+func New() *Document {
+	d := &Document{}
+	d.addPage()
+
+	return d
+}
+
+// Title write a large bold line
+func (d *Document) Title(text string) {
+	d.line(titleSize)
+	d.text(margin, fontBold, titleSize, text)
+}
+
+// Heading write a bold line separated from the previous content
+func (d *Document) Heading(text string) {
+	d.Space(headingSize)
+	d.line(headingSize)
+	d.text(margin, fontBold, headingSize, text)
+}
+
+// Text write a line of text, the text that does not fit the page is truncated
+func (d *Document) Text(text string) {
+	d.line(textSize)
+	d.text(margin, fontRegular, textSize, fit(text, ContentWidth, textSize))
+}
+
+// Row write a row of cells with the given widths, the cells that do not fit
+// their width are truncated
+func (d *Document) Row(widths []float64, bold bool, cells ...string) {
+	font := fontRegular
+	if bold {
+		font = fontBold
+	}
+	d.line(textSize)
+	x := margin
+	for i, cell := range cells {
+		if i >= len(widths) {
+			break
+		}
+		d.text(x, font, textSize, fit(cell, widths[i]-textSize/2, textSize))
+		x += widths[i]
+	}
+}
+
+// BlankField write a label followed by a line of the given width to be filled by hand
+func (d *Document) BlankField(label string, width float64) {
+	d.Space(textSize)
+	d.line(textSize)
+	d.text(margin, fontRegular, textSize, label)
+	x := margin + textWidth(label, textSize) + textSize/2
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x, d.y-2, x+width, d.y-2)
+}
+
+// PageBreak start a new page, the next content is written at its top
+func (d *Document) PageBreak() {
+	d.addPage()
+}
+
+// Space add vertical space, a new page is added when it does not fit
+func (d *Document) Space(height float64) {
+	if d.y-height < margin {
+		d.addPage()
+	}
+	d.y -= height
+}
+
+// WriteTo write the whole document
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	offsets := []int{}
+	object := func(content string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), content)
+	}
+
+	// the catalog, the page tree and the fonts come first, then every page
+	// is followed by its content stream
+	const firstPageObject = 5
+	kids := make([]string, 0, len(d.pages))
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPageObject+2*i))
+	}
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /%s 3 0 R /%s 4 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, fontRegular, fontBold, firstPageObject+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", page.Len(), page.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+func (d *Document) addPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// line move to the baseline of the next line of the font size
+func (d *Document) line(size float64) {
+	d.Space(size * lineSpacing)
+}
+
+func (d *Document) text(x float64, font string, size float64, text string) {
+	fmt.Fprintf(d.page(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, d.y, escape(text))
+}
+
+func fit(text string, width, size float64) string {
+	if textWidth(text, size) <= width {
+		return text
+	}
+	runes := []rune(text)
+	n := int(width/(size*charWidth)) - 3
+	if n <= 0 {
+		return ""
+	}
+
+	return string(runes[:min(n, len(runes))]) + "..."
+}
+
+func textWidth(text string, size float64) float64 {
+	return float64(len([]rune(text))) * size * charWidth
+}
+
+// escape encode the text as WinAnsi string literal, the characters outside
+// of Latin-1 are replaced with question mark
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r >= 32 && r < 127:
+			b.WriteByte(byte(r))
+		case r >= 160 && r <= 255:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			b.WriteByte('?')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package pdf_test
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/delly/amartha/common/pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocument(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should write a document with valid cross reference", func(t *testing.T) {
+		t.Parallel()
+
+		doc := pdf.New()
+		doc.Title("Reconciliation Statement")
+		doc.Heading("Summary")
+		doc.Text("Total (matched) \\ unmatched: 10")
+		doc.Row([]float64{100, 100}, true, "Bank", "Amount")
+		doc.BlankField("Approved By", 200)
+		var buf bytes.Buffer
+
+		_, err := doc.WriteTo(&buf)
+
+		require.NoError(t, err)
+		content := buf.String()
+		assert.True(t, strings.HasPrefix(content, "%PDF-1.4\n"))
+		assert.True(t, strings.HasSuffix(content, "%%EOF\n"))
+		assert.Contains(t, content, `(Total \(matched\) \\ unmatched: 10) Tj`)
+		assert.Contains(t, content, "/Count 1")
+		assertCrossReference(t, content)
+	})
+
+	t.Run("should add pages when the content does not fit", func(t *testing.T) {
+		t.Parallel()
+
+		doc := pdf.New()
+		for i := 0; i < 200; i++ {
+			doc.Row([]float64{100, 100}, false, strconv.Itoa(i), "a cell longer than its width is truncated")
+		}
+		var buf bytes.Buffer
+
+		_, err := doc.WriteTo(&buf)
+
+		require.NoError(t, err)
+		content := buf.String()
+		assert.Contains(t, content, "/Count 4")
+		assert.Contains(t, content, "(a cell longer...) Tj")
+		assertCrossReference(t, content)
+	})
+
+	t.Run("should start a new page on page break", func(t *testing.T) {
+		t.Parallel()
+
+		doc := pdf.New()
+		doc.Title("BCA")
+		doc.PageBreak()
+		doc.Title("BRI")
+		var buf bytes.Buffer
+
+		_, err := doc.WriteTo(&buf)
+
+		require.NoError(t, err)
+		content := buf.String()
+		assert.Contains(t, content, "/Count 2")
+		assert.Contains(t, content, fmt.Sprintf("50.00 %.2f Td (BRI) Tj", 841.89-50-16*1.4))
+		assertCrossReference(t, content)
+	})
+
+	t.Run("should replace characters outside of latin-1", func(t *testing.T) {
+		t.Parallel()
+
+		doc := pdf.New()
+		doc.Text("Café ✓")
+		var buf bytes.Buffer
+
+		_, err := doc.WriteTo(&buf)
+
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `(Caf\351 ?) Tj`)
+	})
+}
+
+// assertCrossReference check every object is at the offset of the cross reference table
+func assertCrossReference(t *testing.T, content string) {
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(content)
+	require.Len(t, startxref, 2)
+	xref, _ := strconv.Atoi(startxref[1])
+	require.True(t, strings.HasPrefix(content[xref:], "xref\n"))
+
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllStringSubmatch(content[xref:], -1)
+	require.NotEmpty(t, offsets)
+	for i, offset := range offsets {
+		n, _ := strconv.Atoi(offset[1])
+		assert.True(t, strings.HasPrefix(content[n:], fmt.Sprintf("%d 0 obj\n", i+1)), "object %d", i+1)
+	}
+}
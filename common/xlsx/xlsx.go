@@ -0,0 +1,210 @@
+// Package xlsx write Office Open XML spreadsheets without any external
+// dependency, it only supports what is needed to export tabular data, inline
+// string and number cells in one or more sheets
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxSheetNameLength = 31
+
+var (
+	// ErrInvalidSheetName is an error when sheet name is empty, too long or has forbidden characters
+	ErrInvalidSheetName = errors.New("sheet name must be 1 to 31 characters without []:*?/\\")
+	// ErrNoSheet is an error when a row is written before any sheet is added
+	ErrNoSheet = errors.New("add a sheet before writing a row")
+)
+
+const (
+	contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+		`%s</Types>`
+	sheetContentTypeXML = `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`
+	rootRelsXML         = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`
+	workbookXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets>%s</sheets></workbook>`
+	workbookSheetXML = `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`
+	workbookRelsXML  = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">%s` +
+		`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+		`</Relationships>`
+	workbookSheetRelXML = `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`
+	stylesXML           = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<fonts count="1"><font><sz val="11"/><name val="Calibri"/></font></fonts>` +
+		`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+		`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+		`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+		`<cellXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/></cellXfs>` +
+		`</styleSheet>`
+	sheetHeaderXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`
+	sheetFooterXML = `</sheetData></worksheet>`
+)
+
+// Writer write a workbook sheet by sheet, the rows are streamed to the
+// underlying writer so the workbook is never held in memory, the sheets are
+// written in the order they are added
+type Writer struct {
+	zw     *zip.Writer
+	sheet  io.Writer
+	sheets []string
+	row    int
+}
+
+// NewWriter create new workbook writer
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{zw: zip.NewWriter(w)}
+}
+
+// AddSheet end the current sheet and start a new sheet, the next rows are
+// written to the new sheet
+func (w *Writer) AddSheet(name string) error {
+	if name == "" || len([]rune(name)) > maxSheetNameLength || strings.ContainsAny(name, `[]:*?/\`) {
+		return ErrInvalidSheetName
+	}
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	sheet, err := w.zw.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)+1))
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(sheet, sheetHeaderXML); err != nil {
+		return err
+	}
+	w.sheet = sheet
+	w.sheets = append(w.sheets, name)
+	w.row = 0
+
+	return nil
+}
+
+// WriteRow write a row to the current sheet, numbers are written as number
+// cells, time as RFC3339 text and everything else as text, text starting with
+// = + - @ tab or carriage return is prefixed with a quote so it is never
+// evaluated as a formula
+func (w *Writer) WriteRow(values ...any) error {
+	if w.sheet == nil {
+		return ErrNoSheet
+	}
+	w.row++
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<row r="%d">`, w.row)
+	for _, value := range values {
+		writeCell(&b, value)
+	}
+	b.WriteString(`</row>`)
+	_, err := io.WriteString(w.sheet, b.String())
+
+	return err
+}
+
+// Close end the current sheet and write the workbook parts, it does not
+// close the underlying writer
+func (w *Writer) Close() error {
+	if err := w.endSheet(); err != nil {
+		return err
+	}
+
+	var sheetTypes, sheets, sheetRels strings.Builder
+	for i, name := range w.sheets {
+		fmt.Fprintf(&sheetTypes, sheetContentTypeXML, i+1)
+		fmt.Fprintf(&sheets, workbookSheetXML, escape(name), i+1, i+1)
+		fmt.Fprintf(&sheetRels, workbookSheetRelXML, i+1, i+1)
+	}
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", fmt.Sprintf(contentTypesXML, sheetTypes.String())},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", fmt.Sprintf(workbookXML, sheets.String())},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(workbookRelsXML, sheetRels.String(), len(w.sheets)+1)},
+		{"xl/styles.xml", stylesXML},
+	}
+	for _, part := range parts {
+		f, err := w.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return err
+		}
+	}
+
+	return w.zw.Close()
+}
+
+func (w *Writer) endSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	_, err := io.WriteString(w.sheet, sheetFooterXML)
+	w.sheet = nil
+
+	return err
+}
+
+func writeCell(b *strings.Builder, value any) {
+	var number string
+	switch v := value.(type) {
+	case int:
+		number = strconv.Itoa(v)
+	case int32:
+		number = strconv.FormatInt(int64(v), 10)
+	case int64:
+		number = strconv.FormatInt(v, 10)
+	case float32:
+		number = strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		number = strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		writeTextCell(b, v.Format(time.RFC3339))
+		return
+	case nil:
+		b.WriteString(`<c/>`)
+		return
+	default:
+		writeTextCell(b, escapeFormula(fmt.Sprint(v)))
+		return
+	}
+	fmt.Fprintf(b, `<c><v>%s</v></c>`, number)
+}
+
+func writeTextCell(b *strings.Builder, text string) {
+	fmt.Fprintf(b, `<c t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, escape(text))
+}
+
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+
+	return text
+}
+
+func escape(text string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(text))
+
+	return b.String()
+}
@@ -0,0 +1,98 @@
+package xlsx_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/delly/amartha/common/xlsx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("should write every sheet as a valid workbook part", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		w := xlsx.NewWriter(&buf)
+		require.NoError(t, w.AddSheet("Summary"))
+		require.NoError(t, w.WriteRow("Total", 10))
+		require.NoError(t, w.AddSheet("Missing & Matched"))
+		require.NoError(t, w.WriteRow("ID", "Amount", "Time"))
+		require.NoError(t, w.WriteRow("<TRX-1>", 1500.5, time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)))
+		require.NoError(t, w.Close())
+
+		files := readZip(t, buf.Bytes())
+		assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Summary" sheetId="1" r:id="rId1"/>`)
+		assert.Contains(t, files["xl/workbook.xml"], `<sheet name="Missing &amp; Matched" sheetId="2" r:id="rId2"/>`)
+		assert.Contains(t, files["[Content_Types].xml"], `/xl/worksheets/sheet2.xml`)
+		assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<row r="1"><c t="inlineStr"><is><t xml:space="preserve">Total</t></is></c><c><v>10</v></c></row>`)
+		assert.Contains(t, files["xl/worksheets/sheet2.xml"], `<row r="2"><c t="inlineStr"><is><t xml:space="preserve">&lt;TRX-1&gt;</t></is></c>`+
+			`<c><v>1500.5</v></c><c t="inlineStr"><is><t xml:space="preserve">2024-11-01T00:00:00Z</t></is></c></row>`)
+		for name, content := range files {
+			assert.NoError(t, xml.Unmarshal([]byte(content), new(any)), name)
+		}
+	})
+
+	t.Run("should prefix a quote to text read as formula", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		w := xlsx.NewWriter(&buf)
+		require.NoError(t, w.AddSheet("Summary"))
+		require.NoError(t, w.WriteRow("=1+1", "+1", "-1", "@SUM(A1)", "\tTRX-1", "\rTRX-1", "TRX-1", -1))
+		require.NoError(t, w.Close())
+
+		files := readZip(t, buf.Bytes())
+		assert.Contains(t, files["xl/worksheets/sheet1.xml"], `<row r="1">`+
+			`<c t="inlineStr"><is><t xml:space="preserve">&#39;=1+1</t></is></c>`+
+			`<c t="inlineStr"><is><t xml:space="preserve">&#39;+1</t></is></c>`+
+			`<c t="inlineStr"><is><t xml:space="preserve">&#39;-1</t></is></c>`+
+			`<c t="inlineStr"><is><t xml:space="preserve">&#39;@SUM(A1)</t></is></c>`+
+			`<c t="inlineStr"><is><t xml:space="preserve">&#39;&#x9;TRX-1</t></is></c>`+
+			`<c t="inlineStr"><is><t xml:space="preserve">&#39;&#xD;TRX-1</t></is></c>`+
+			`<c t="inlineStr"><is><t xml:space="preserve">TRX-1</t></is></c>`+
+			`<c><v>-1</v></c></row>`)
+	})
+
+	t.Run("should reject invalid sheet name", func(t *testing.T) {
+		t.Parallel()
+
+		w := xlsx.NewWriter(io.Discard)
+
+		assert.ErrorIs(t, w.AddSheet(""), xlsx.ErrInvalidSheetName)
+		assert.ErrorIs(t, w.AddSheet("Missing/Matched"), xlsx.ErrInvalidSheetName)
+		assert.ErrorIs(t, w.AddSheet("a sheet name longer than thirty one"), xlsx.ErrInvalidSheetName)
+	})
+
+	t.Run("should reject row before any sheet", func(t *testing.T) {
+		t.Parallel()
+
+		w := xlsx.NewWriter(io.Discard)
+
+		assert.ErrorIs(t, w.WriteRow("Total", 10), xlsx.ErrNoSheet)
+	})
+}
+
+func readZip(t *testing.T, b []byte) map[string]string {
+	r, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	require.NoError(t, err)
+
+	files := map[string]string{}
+	for _, f := range r.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	return files
+}
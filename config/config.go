@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"github.com/joeshaw/envdecode"
 	"github.com/joho/godotenv"
 	"github.com/pkg/errors"
@@ -8,11 +10,13 @@ import (
 
 // Config holds the configuration for the application.
 type Config struct {
-	Env          string `env:"ENV,default=development"`
+	Env          string `env:"ENV"`
 	Database     DatabaseConfig
 	Server       ServerConfig
 	LocalStorage LocalStorageConfig
 	GCS          GCSConfig
+	Processer    ProcesserConfig
+	CaseManager  CaseManagerConfig
 }
 
 // DatabaseConfig holds the configuration for the database.
@@ -27,6 +31,11 @@ type DatabaseConfig struct {
 // ServerConfig holds the configuration for the server.
 type ServerConfig struct {
 	Port int `env:"SERVER_PORT,default=8080"`
+	// GatewayToken is the token shared with the gateway that sets the acting user
+	GatewayToken string `env:"SERVER_GATEWAY_TOKEN"`
+	// TrustUserHeader trust the acting user of every caller without the gateway
+	// token, it must only be enabled when the service cannot be reached directly
+	TrustUserHeader bool `env:"SERVER_TRUST_USER_HEADER,default=false"`
 }
 
 type LocalStorageConfig struct {
@@ -40,6 +49,26 @@ type GCSConfig struct {
 	KeyJSON   string `env:"GCS_KEY_JSON"`
 }
 
+// ProcesserConfig holds the configuration for the reconciliation job processer.
+type ProcesserConfig struct {
+	WorkerSize          int           `env:"PROCESSER_WORKER_SIZE,default=4"`
+	JobTimeout          time.Duration `env:"PROCESSER_JOB_TIMEOUT,default=30m"`
+	CancelCheckInterval time.Duration `env:"PROCESSER_CANCEL_CHECK_INTERVAL,default=5s"`
+	ProgressInterval    time.Duration `env:"PROCESSER_PROGRESS_INTERVAL,default=2s"`
+	OutOfCore           bool          `env:"PROCESSER_OUT_OF_CORE,default=false"`
+	TempDir             string        `env:"PROCESSER_TEMP_DIR"`
+	PartitionCount      int           `env:"PROCESSER_PARTITION_COUNT,default=64"`
+	MatchWorkerSize     int           `env:"PROCESSER_MATCH_WORKER_SIZE"`
+	LeaseDuration       time.Duration `env:"PROCESSER_LEASE_DURATION,default=2m"`
+	SaveTimeout         time.Duration `env:"PROCESSER_SAVE_TIMEOUT,default=1m"`
+}
+
+// CaseManagerConfig holds the configuration for the reconciliation case manager.
+type CaseManagerConfig struct {
+	// Reviewers is the users allowed to change the status of any case besides its assignee
+	Reviewers []string `env:"CASE_REVIEWERS"`
+}
+
 // NewConfig creates an instance of Config.
 func NewConfig(env string) (*Config, error) {
 	_ = godotenv.Load(env)
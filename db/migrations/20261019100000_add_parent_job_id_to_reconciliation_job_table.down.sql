@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs DROP COLUMN parent_job_id;
+
+END;
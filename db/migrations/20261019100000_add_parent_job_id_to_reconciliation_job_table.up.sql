@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs ADD COLUMN parent_job_id BIGINT REFERENCES reconciliation_jobs(id);
+
+END;
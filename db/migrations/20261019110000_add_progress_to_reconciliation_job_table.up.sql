@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs ADD COLUMN progress JSONB;
+
+END;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs ADD COLUMN matching_options JSONB NOT NULL DEFAULT '{}';
+
+END;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reconciliation_matches;
+DROP TABLE IF EXISTS reconciliation_items;
+
+END;
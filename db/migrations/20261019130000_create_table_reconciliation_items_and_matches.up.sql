@@ -0,0 +1,35 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS reconciliation_items (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES reconciliation_jobs(id) ON DELETE CASCADE,
+    source VARCHAR(10) NOT NULL,
+    bank_name VARCHAR,
+    row_number INT NOT NULL,
+    transaction_id VARCHAR NOT NULL,
+    amount FLOAT NOT NULL,
+    type VARCHAR(10) NOT NULL,
+    transaction_time TIMESTAMPTZ NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reconciliation_items_job_id_status ON reconciliation_items(job_id, status);
+CREATE INDEX idx_reconciliation_items_bank_name_status_transaction_time ON reconciliation_items(bank_name, status, transaction_time);
+
+CREATE TABLE IF NOT EXISTS reconciliation_matches (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES reconciliation_jobs(id) ON DELETE CASCADE,
+    system_row INT NOT NULL,
+    bank_name VARCHAR NOT NULL,
+    bank_row INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    confidence FLOAT NOT NULL,
+    expected_fee FLOAT NOT NULL,
+    implied_fee FLOAT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reconciliation_matches_job_id ON reconciliation_matches(job_id);
+
+END;
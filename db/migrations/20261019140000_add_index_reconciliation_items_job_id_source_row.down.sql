@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_reconciliation_items_job_id_source_bank_name_row_number;
+
+END;
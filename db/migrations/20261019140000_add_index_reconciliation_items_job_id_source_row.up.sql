@@ -0,0 +1,5 @@
+BEGIN;
+
+CREATE INDEX idx_reconciliation_items_job_id_source_bank_name_row_number ON reconciliation_items(job_id, source, bank_name, row_number);
+
+END;
@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_reconciliation_jobs_end_date_status;
+DROP INDEX IF EXISTS idx_reconciliation_items_carried_from_id;
+
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS resolved_job_id;
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS carried_from_id;
+
+END;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE reconciliation_items ADD COLUMN carried_from_id BIGINT REFERENCES reconciliation_items(id) ON DELETE SET NULL;
+ALTER TABLE reconciliation_items ADD COLUMN resolved_job_id BIGINT REFERENCES reconciliation_jobs(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_reconciliation_items_carried_from_id ON reconciliation_items(carried_from_id);
+CREATE INDEX idx_reconciliation_jobs_end_date_status ON reconciliation_jobs(end_date, status);
+
+END;
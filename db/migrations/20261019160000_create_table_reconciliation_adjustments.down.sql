@@ -0,0 +1,8 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs DROP COLUMN IF EXISTS adjusted_totals;
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS adjustment_id;
+
+DROP TABLE IF EXISTS reconciliation_adjustments;
+
+END;
@@ -0,0 +1,18 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS reconciliation_adjustments (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES reconciliation_jobs(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    reason_code VARCHAR(30),
+    note TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reconciliation_adjustments_job_id ON reconciliation_adjustments(job_id);
+
+ALTER TABLE reconciliation_items ADD COLUMN adjustment_id BIGINT REFERENCES reconciliation_adjustments(id) ON DELETE SET NULL;
+ALTER TABLE reconciliation_jobs ADD COLUMN adjusted_totals JSONB;
+
+END;
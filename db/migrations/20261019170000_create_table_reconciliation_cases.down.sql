@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reconciliation_case_comments;
+DROP TABLE IF EXISTS reconciliation_cases;
+
+END;
@@ -0,0 +1,27 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS reconciliation_cases (
+    id BIGSERIAL PRIMARY KEY,
+    item_id BIGINT NOT NULL UNIQUE REFERENCES reconciliation_items(id) ON DELETE CASCADE,
+    assignee VARCHAR NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    due_date DATE,
+    created_by VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reconciliation_cases_assignee_status ON reconciliation_cases(assignee, status);
+
+CREATE TABLE IF NOT EXISTS reconciliation_case_comments (
+    id BIGSERIAL PRIMARY KEY,
+    case_id BIGINT NOT NULL REFERENCES reconciliation_cases(id) ON DELETE CASCADE,
+    status VARCHAR(20),
+    comment TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reconciliation_case_comments_case_id ON reconciliation_case_comments(case_id);
+
+END;
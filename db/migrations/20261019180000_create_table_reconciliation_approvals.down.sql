@@ -0,0 +1,8 @@
+BEGIN;
+
+DROP TABLE IF EXISTS reconciliation_period_locks;
+DROP TABLE IF EXISTS reconciliation_approvals;
+
+ALTER TABLE reconciliation_jobs DROP COLUMN IF EXISTS approval_status;
+
+END;
@@ -0,0 +1,31 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs ADD COLUMN approval_status VARCHAR(20);
+
+CREATE TABLE IF NOT EXISTS reconciliation_approvals (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES reconciliation_jobs(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_by VARCHAR NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_reconciliation_approvals_job_id ON reconciliation_approvals(job_id);
+
+CREATE TABLE IF NOT EXISTS reconciliation_period_locks (
+    id BIGSERIAL PRIMARY KEY,
+    job_id BIGINT NOT NULL REFERENCES reconciliation_jobs(id) ON DELETE CASCADE,
+    bank_name VARCHAR NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL,
+    locked_by VARCHAR NOT NULL,
+    locked_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    unlocked_by VARCHAR,
+    unlocked_at TIMESTAMPTZ,
+    unlock_reason TEXT
+);
+
+CREATE INDEX idx_reconciliation_period_locks_active_bank_name ON reconciliation_period_locks(bank_name) WHERE unlocked_at IS NULL;
+
+END;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP INDEX IF EXISTS idx_reconciliation_jobs_processing_lease_expires_at;
+ALTER TABLE reconciliation_jobs DROP COLUMN IF EXISTS lease_expires_at;
+
+END;
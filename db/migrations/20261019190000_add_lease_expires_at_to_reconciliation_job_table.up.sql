@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs ADD COLUMN lease_expires_at TIMESTAMPTZ;
+
+CREATE INDEX idx_reconciliation_jobs_processing_lease_expires_at ON reconciliation_jobs(lease_expires_at) WHERE status = 'PROCESSING';
+
+END;
@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS paired_row;
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS paired_bank_name;
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS overlap_row;
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS overlap_bank_name;
+ALTER TABLE reconciliation_items DROP COLUMN IF EXISTS duplicate_of_row;
+
+END;
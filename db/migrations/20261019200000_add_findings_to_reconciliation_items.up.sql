@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE reconciliation_items ADD COLUMN duplicate_of_row INT;
+ALTER TABLE reconciliation_items ADD COLUMN overlap_bank_name VARCHAR;
+ALTER TABLE reconciliation_items ADD COLUMN overlap_row INT;
+ALTER TABLE reconciliation_items ADD COLUMN paired_bank_name VARCHAR;
+ALTER TABLE reconciliation_items ADD COLUMN paired_row INT;
+
+END;
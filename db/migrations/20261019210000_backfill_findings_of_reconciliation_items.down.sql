@@ -0,0 +1,6 @@
+BEGIN;
+
+-- the backfilled findings are kept, they are the same as the result of the
+-- job and the columns are dropped by the down migration that added them
+
+END;
@@ -0,0 +1,93 @@
+BEGIN;
+
+-- jobs saved with items before the findings were stored on the items keep
+-- their findings in the result only, copy them to the items so the items
+-- endpoint can list them once the job returns its summary only
+
+CREATE TEMPORARY TABLE backfill_jobs ON COMMIT DROP AS
+SELECT j.id, j.result
+FROM reconciliation_jobs j
+WHERE j.result IS NOT NULL
+  AND EXISTS (SELECT 1 FROM reconciliation_items i WHERE i.job_id = j.id);
+
+-- duplicates excluded by deduplicate were not saved as items
+INSERT INTO reconciliation_items (job_id, source, bank_name, row_number, transaction_id, amount, type, transaction_time, status, duplicate_of_row)
+SELECT j.id,
+    CASE WHEN d.key = 'SYSTEM' THEN 'SYSTEM' ELSE 'BANK' END,
+    NULLIF(d.key, 'SYSTEM'),
+    (t.value->>'row')::INT,
+    t.value->>'id',
+    (t.value->>'amount')::FLOAT,
+    t.value->>'type',
+    (t.value->>'time')::TIMESTAMPTZ,
+    'DUPLICATE',
+    (t.value->>'duplicate_of_row')::INT
+FROM backfill_jobs j
+CROSS JOIN LATERAL jsonb_each(CASE WHEN jsonb_typeof(j.result->'duplicates') = 'object' THEN j.result->'duplicates' ELSE '{}' END) d
+CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(d.value) = 'array' THEN d.value ELSE '[]' END) t
+WHERE NOT EXISTS (
+    SELECT 1 FROM reconciliation_items i
+    WHERE i.job_id = j.id
+      AND i.source = CASE WHEN d.key = 'SYSTEM' THEN 'SYSTEM' ELSE 'BANK' END
+      AND i.bank_name IS NOT DISTINCT FROM NULLIF(d.key, 'SYSTEM')
+      AND i.row_number = (t.value->>'row')::INT
+);
+
+UPDATE reconciliation_items i
+SET duplicate_of_row = (t.value->>'duplicate_of_row')::INT
+FROM backfill_jobs j
+CROSS JOIN LATERAL jsonb_each(CASE WHEN jsonb_typeof(j.result->'duplicates') = 'object' THEN j.result->'duplicates' ELSE '{}' END) d
+CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(d.value) = 'array' THEN d.value ELSE '[]' END) t
+WHERE i.job_id = j.id
+  AND i.source = CASE WHEN d.key = 'SYSTEM' THEN 'SYSTEM' ELSE 'BANK' END
+  AND i.bank_name IS NOT DISTINCT FROM NULLIF(d.key, 'SYSTEM')
+  AND i.row_number = (t.value->>'row')::INT;
+
+UPDATE reconciliation_items i
+SET overlap_bank_name = t.value->>'overlap_bank_name', overlap_row = (t.value->>'overlap_row')::INT
+FROM backfill_jobs j
+CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(j.result->'warnings'->'bank_file_overlaps') = 'array' THEN j.result->'warnings'->'bank_file_overlaps' ELSE '[]' END) t
+WHERE i.job_id = j.id
+  AND i.source = 'BANK'
+  AND i.bank_name = t.value->>'bank_name'
+  AND i.row_number = (t.value->>'row')::INT;
+
+-- reversals pair two rows of the same file, each leg points to the other
+UPDATE reconciliation_items i
+SET paired_bank_name = NULLIF(p.key, 'SYSTEM'), paired_row = p.paired_row
+FROM (
+    SELECT j.id AS job_id, r.key, (t.value->'original'->>'row')::INT AS row_number, (t.value->'reversal'->>'row')::INT AS paired_row
+    FROM backfill_jobs j
+    CROSS JOIN LATERAL jsonb_each(CASE WHEN jsonb_typeof(j.result->'reversals') = 'object' THEN j.result->'reversals' ELSE '{}' END) r
+    CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(r.value) = 'array' THEN r.value ELSE '[]' END) t
+    UNION ALL
+    SELECT j.id, r.key, (t.value->'reversal'->>'row')::INT, (t.value->'original'->>'row')::INT
+    FROM backfill_jobs j
+    CROSS JOIN LATERAL jsonb_each(CASE WHEN jsonb_typeof(j.result->'reversals') = 'object' THEN j.result->'reversals' ELSE '{}' END) r
+    CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(r.value) = 'array' THEN r.value ELSE '[]' END) t
+) p
+WHERE i.job_id = p.job_id
+  AND i.source = CASE WHEN p.key = 'SYSTEM' THEN 'SYSTEM' ELSE 'BANK' END
+  AND i.bank_name IS NOT DISTINCT FROM NULLIF(p.key, 'SYSTEM')
+  AND i.row_number = p.row_number;
+
+-- internal transfers pair rows of two bank files, each leg points to the other
+UPDATE reconciliation_items i
+SET paired_bank_name = p.paired_bank_name, paired_row = p.paired_row
+FROM (
+    SELECT j.id AS job_id, t.value->'from'->>'bank_name' AS bank_name, (t.value->'from'->>'row')::INT AS row_number,
+        t.value->'to'->>'bank_name' AS paired_bank_name, (t.value->'to'->>'row')::INT AS paired_row
+    FROM backfill_jobs j
+    CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(j.result->'internal_transfers') = 'array' THEN j.result->'internal_transfers' ELSE '[]' END) t
+    UNION ALL
+    SELECT j.id, t.value->'to'->>'bank_name', (t.value->'to'->>'row')::INT,
+        t.value->'from'->>'bank_name', (t.value->'from'->>'row')::INT
+    FROM backfill_jobs j
+    CROSS JOIN LATERAL jsonb_array_elements(CASE WHEN jsonb_typeof(j.result->'internal_transfers') = 'array' THEN j.result->'internal_transfers' ELSE '[]' END) t
+) p
+WHERE i.job_id = p.job_id
+  AND i.source = 'BANK'
+  AND i.bank_name = p.bank_name
+  AND i.row_number = p.row_number;
+
+END;
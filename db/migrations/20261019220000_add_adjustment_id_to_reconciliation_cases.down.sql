@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_cases DROP COLUMN IF EXISTS adjustment_id;
+
+END;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_cases ADD COLUMN adjustment_id BIGINT REFERENCES reconciliation_adjustments(id) ON DELETE SET NULL;
+
+END;
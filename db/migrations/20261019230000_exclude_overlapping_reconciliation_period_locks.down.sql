@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_period_locks DROP CONSTRAINT IF EXISTS reconciliation_period_locks_active_period_excl;
+
+END;
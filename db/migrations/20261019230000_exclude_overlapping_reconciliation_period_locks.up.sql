@@ -0,0 +1,8 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE reconciliation_period_locks ADD CONSTRAINT reconciliation_period_locks_active_period_excl
+EXCLUDE USING gist (bank_name WITH =, daterange(start_date, end_date, '[]') WITH &&) WHERE (unlocked_at IS NULL);
+
+END;
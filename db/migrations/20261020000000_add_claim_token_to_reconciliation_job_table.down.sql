@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs DROP COLUMN IF EXISTS claim_token;
+
+END;
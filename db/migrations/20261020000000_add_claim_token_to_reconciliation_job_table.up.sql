@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE reconciliation_jobs ADD COLUMN claim_token BIGINT NOT NULL DEFAULT 0;
+
+END;
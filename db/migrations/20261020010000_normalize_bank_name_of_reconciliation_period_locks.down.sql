@@ -0,0 +1,9 @@
+BEGIN;
+
+ALTER TABLE reconciliation_period_locks DROP CONSTRAINT IF EXISTS reconciliation_period_locks_active_period_excl;
+ALTER TABLE reconciliation_period_locks DROP CONSTRAINT IF EXISTS reconciliation_period_locks_bank_name_key_check;
+
+ALTER TABLE reconciliation_period_locks ADD CONSTRAINT reconciliation_period_locks_active_period_excl
+EXCLUDE USING gist (bank_name WITH =, daterange(start_date, end_date, '[]') WITH &&) WHERE (unlocked_at IS NULL);
+
+END;
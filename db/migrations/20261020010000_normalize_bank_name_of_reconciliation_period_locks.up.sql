@@ -0,0 +1,16 @@
+BEGIN;
+
+-- the bank name of a lock is kept as its key so a lock of BCA also covers
+-- bca and " BCA", the exclusion constraint compares the key as well
+ALTER TABLE reconciliation_period_locks DROP CONSTRAINT IF EXISTS reconciliation_period_locks_active_period_excl;
+
+UPDATE reconciliation_period_locks SET bank_name = UPPER(TRIM(bank_name))
+WHERE bank_name <> UPPER(TRIM(bank_name));
+
+ALTER TABLE reconciliation_period_locks ADD CONSTRAINT reconciliation_period_locks_bank_name_key_check
+CHECK (bank_name = UPPER(TRIM(bank_name)));
+
+ALTER TABLE reconciliation_period_locks ADD CONSTRAINT reconciliation_period_locks_active_period_excl
+EXCLUDE USING gist (UPPER(TRIM(bank_name)) WITH =, daterange(start_date, end_date, '[]') WITH &&) WHERE (unlocked_at IS NULL);
+
+END;
@@ -0,0 +1,15 @@
+-- name: CreateReconciliationAdjustment :one
+INSERT INTO reconciliation_adjustments (job_id, type, reason_code, note, created_by) VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: DeleteReconciliationAdjustment :exec
+DELETE FROM reconciliation_adjustments WHERE id = $1;
+
+-- name: ListReconciliationAdjustments :many
+SELECT a.id, a.job_id, a.type, a.reason_code, a.note, a.created_by, a.created_at,
+ARRAY_AGG(i.id ORDER BY i.id)::BIGINT[] AS item_ids
+FROM reconciliation_adjustments a
+JOIN reconciliation_items i ON i.adjustment_id = a.id
+WHERE a.job_id = $1
+GROUP BY a.id
+ORDER BY a.id;
@@ -0,0 +1,8 @@
+-- name: CreateReconciliationApproval :one
+INSERT INTO reconciliation_approvals (job_id, status, comment, created_by) VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListReconciliationApprovals :many
+SELECT * FROM reconciliation_approvals
+WHERE job_id = $1
+ORDER BY id;
@@ -0,0 +1,40 @@
+-- name: CountReconciliationCasesByAssignee :one
+SELECT COUNT(1) FROM reconciliation_cases
+WHERE assignee = sqlc.arg(assignee)
+AND (sqlc.narg(status)::VARCHAR IS NULL OR status = sqlc.narg(status));
+
+-- name: CreateReconciliationCase :one
+INSERT INTO reconciliation_cases (item_id, assignee, due_date, created_by) VALUES ($1, $2, $3, $4)
+ON CONFLICT (item_id) DO NOTHING
+RETURNING *;
+
+-- name: CreateReconciliationCaseComment :one
+INSERT INTO reconciliation_case_comments (case_id, status, comment, created_by) VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetReconciliationCaseById :one
+SELECT c.id, c.item_id, c.assignee, c.status, c.due_date, c.created_by, c.created_at, c.updated_at, c.adjustment_id,
+i.job_id, i.source, i.bank_name, i.transaction_id, i.amount, i.type, i.transaction_time
+FROM reconciliation_cases c
+JOIN reconciliation_items i ON i.id = c.item_id
+WHERE c.id = $1;
+
+-- name: ListReconciliationCaseComments :many
+SELECT * FROM reconciliation_case_comments
+WHERE case_id = $1
+ORDER BY id;
+
+-- name: ListReconciliationCasesByAssignee :many
+SELECT c.id, c.item_id, c.assignee, c.status, c.due_date, c.created_by, c.created_at, c.updated_at, c.adjustment_id,
+i.job_id, i.source, i.bank_name, i.transaction_id, i.amount, i.type, i.transaction_time
+FROM reconciliation_cases c
+JOIN reconciliation_items i ON i.id = c.item_id
+WHERE c.assignee = sqlc.arg(assignee)
+AND (sqlc.narg(status)::VARCHAR IS NULL OR c.status = sqlc.narg(status))
+ORDER BY c.due_date NULLS LAST, c.id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdateReconciliationCaseStatus :one
+UPDATE reconciliation_cases SET status = sqlc.arg(status), adjustment_id = sqlc.narg(adjustment_id), updated_at = now()
+WHERE id = sqlc.arg(id) AND status = sqlc.arg(from_status)
+RETURNING *;
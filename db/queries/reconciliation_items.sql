@@ -0,0 +1,176 @@
+-- name: CopyReconciliationItems :copyfrom
+INSERT INTO reconciliation_items (job_id, source, bank_name, row_number, transaction_id, amount, type, transaction_time, status, carried_from_id, duplicate_of_row, overlap_bank_name, overlap_row, paired_bank_name, paired_row) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15);
+
+-- name: CopyReconciliationMatches :copyfrom
+INSERT INTO reconciliation_matches (job_id, system_row, bank_name, bank_row, status, confidence, expected_fee, implied_fee) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListReconciliationItems :many
+SELECT * FROM reconciliation_items
+WHERE job_id = sqlc.arg(job_id)
+AND (sqlc.narg(source)::VARCHAR IS NULL OR source = sqlc.narg(source))
+AND (sqlc.narg(bank_name)::VARCHAR IS NULL OR bank_name = sqlc.narg(bank_name))
+AND (sqlc.narg(type)::VARCHAR IS NULL OR type = sqlc.narg(type))
+AND (sqlc.narg(status)::VARCHAR IS NULL OR status = sqlc.narg(status))
+AND (sqlc.narg(min_amount)::FLOAT IS NULL OR amount >= sqlc.narg(min_amount))
+AND (sqlc.narg(max_amount)::FLOAT IS NULL OR amount <= sqlc.narg(max_amount))
+AND (sqlc.narg(start_time)::TIMESTAMPTZ IS NULL OR transaction_time >= sqlc.narg(start_time))
+AND (sqlc.narg(end_time)::TIMESTAMPTZ IS NULL OR transaction_time <= sqlc.narg(end_time))
+AND (sqlc.narg(finding)::VARCHAR IS NULL
+    OR (sqlc.narg(finding) = 'duplicate' AND duplicate_of_row IS NOT NULL)
+    OR (sqlc.narg(finding) = 'overlap' AND overlap_row IS NOT NULL))
+ORDER BY
+    CASE WHEN sqlc.arg(sort_by)::VARCHAR = 'amount' AND NOT sqlc.arg(sort_desc)::BOOLEAN THEN amount END ASC,
+    CASE WHEN sqlc.arg(sort_by)::VARCHAR = 'amount' AND sqlc.arg(sort_desc)::BOOLEAN THEN amount END DESC,
+    CASE WHEN sqlc.arg(sort_by)::VARCHAR = 'transaction_time' AND NOT sqlc.arg(sort_desc)::BOOLEAN THEN transaction_time END ASC,
+    CASE WHEN sqlc.arg(sort_by)::VARCHAR = 'transaction_time' AND sqlc.arg(sort_desc)::BOOLEAN THEN transaction_time END DESC,
+    id ASC
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountReconciliationDiffItems :one
+WITH base AS (
+    SELECT source, COALESCE(bank_name, '') AS bank_name, transaction_id,
+    ROW_NUMBER() OVER (PARTITION BY source, COALESCE(bank_name, ''), transaction_id ORDER BY row_number) AS occurrence,
+    row_number, amount, status
+    FROM reconciliation_items
+    WHERE job_id = sqlc.arg(job_id)
+), other AS (
+    SELECT source, COALESCE(bank_name, '') AS bank_name, transaction_id,
+    ROW_NUMBER() OVER (PARTITION BY source, COALESCE(bank_name, ''), transaction_id ORDER BY row_number) AS occurrence,
+    row_number, amount, status
+    FROM reconciliation_items
+    WHERE job_id = sqlc.arg(other_job_id)
+)
+SELECT COUNT(1)
+FROM base b
+FULL OUTER JOIN other o ON o.source = b.source AND o.bank_name = b.bank_name AND o.transaction_id = b.transaction_id
+AND o.occurrence = b.occurrence
+WHERE b.status IS DISTINCT FROM o.status OR b.amount IS DISTINCT FROM o.amount;
+
+-- name: CountReconciliationItems :one
+SELECT COUNT(1) FROM reconciliation_items
+WHERE job_id = sqlc.arg(job_id)
+AND (sqlc.narg(source)::VARCHAR IS NULL OR source = sqlc.narg(source))
+AND (sqlc.narg(bank_name)::VARCHAR IS NULL OR bank_name = sqlc.narg(bank_name))
+AND (sqlc.narg(type)::VARCHAR IS NULL OR type = sqlc.narg(type))
+AND (sqlc.narg(status)::VARCHAR IS NULL OR status = sqlc.narg(status))
+AND (sqlc.narg(min_amount)::FLOAT IS NULL OR amount >= sqlc.narg(min_amount))
+AND (sqlc.narg(max_amount)::FLOAT IS NULL OR amount <= sqlc.narg(max_amount))
+AND (sqlc.narg(start_time)::TIMESTAMPTZ IS NULL OR transaction_time >= sqlc.narg(start_time))
+AND (sqlc.narg(end_time)::TIMESTAMPTZ IS NULL OR transaction_time <= sqlc.narg(end_time))
+AND (sqlc.narg(finding)::VARCHAR IS NULL
+    OR (sqlc.narg(finding) = 'duplicate' AND duplicate_of_row IS NOT NULL)
+    OR (sqlc.narg(finding) = 'overlap' AND overlap_row IS NOT NULL));
+
+-- name: ExistsReconciliationItems :one
+SELECT EXISTS (SELECT 1 FROM reconciliation_items WHERE job_id = $1);
+
+-- name: ListMissingReconciliationItems :many
+SELECT * FROM reconciliation_items
+WHERE job_id = sqlc.arg(job_id) AND source = sqlc.arg(source) AND status = 'MISSING' AND id > sqlc.arg(id)
+AND (sqlc.narg(bank_name)::VARCHAR IS NULL OR bank_name = sqlc.narg(bank_name))
+ORDER BY id
+LIMIT sqlc.arg('limit');
+
+-- name: ListReconciliationMatchedPairs :many
+SELECT m.id, m.bank_name, m.status, m.confidence, m.expected_fee, m.implied_fee,
+s.row_number AS system_row, s.transaction_id AS system_transaction_id, s.amount AS system_amount,
+s.type AS system_type, s.transaction_time AS system_transaction_time,
+b.row_number AS bank_row, b.transaction_id AS bank_transaction_id, b.amount AS bank_amount,
+b.type AS bank_type, b.transaction_time AS bank_transaction_time
+FROM reconciliation_matches m
+JOIN reconciliation_items s ON s.job_id = m.job_id AND s.source = 'SYSTEM' AND s.row_number = m.system_row
+JOIN reconciliation_items b ON b.job_id = m.job_id AND b.source = 'BANK' AND b.bank_name = m.bank_name AND b.row_number = m.bank_row
+WHERE m.job_id = sqlc.arg(job_id) AND m.id > sqlc.arg(id)
+AND (sqlc.narg(status)::VARCHAR IS NULL OR m.status = sqlc.narg(status))
+AND (sqlc.narg(bank_name)::VARCHAR IS NULL OR m.bank_name = sqlc.narg(bank_name))
+ORDER BY m.id
+LIMIT sqlc.arg('limit');
+
+-- name: SummarizeReconciliationItems :many
+SELECT source, bank_name, status, COUNT(1) AS total_transaction, COALESCE(SUM(amount), 0)::FLOAT AS total_amount
+FROM reconciliation_items
+WHERE job_id = $1
+GROUP BY source, bank_name, status
+ORDER BY source DESC, bank_name, status;
+
+-- name: DiffReconciliationItems :many
+WITH base AS (
+    SELECT source, COALESCE(bank_name, '') AS bank_name, transaction_id,
+    ROW_NUMBER() OVER (PARTITION BY source, COALESCE(bank_name, ''), transaction_id ORDER BY row_number) AS occurrence,
+    row_number, amount, status
+    FROM reconciliation_items
+    WHERE job_id = sqlc.arg(job_id)
+), other AS (
+    SELECT source, COALESCE(bank_name, '') AS bank_name, transaction_id,
+    ROW_NUMBER() OVER (PARTITION BY source, COALESCE(bank_name, ''), transaction_id ORDER BY row_number) AS occurrence,
+    row_number, amount, status
+    FROM reconciliation_items
+    WHERE job_id = sqlc.arg(other_job_id)
+)
+SELECT COALESCE(b.source, o.source)::VARCHAR AS source,
+COALESCE(b.bank_name, o.bank_name)::VARCHAR AS bank_name,
+COALESCE(b.transaction_id, o.transaction_id)::VARCHAR AS transaction_id,
+b.row_number AS base_row, b.amount AS base_amount, b.status AS base_status,
+o.row_number AS other_row, o.amount AS other_amount, o.status AS other_status
+FROM base b
+FULL OUTER JOIN other o ON o.source = b.source AND o.bank_name = b.bank_name AND o.transaction_id = b.transaction_id
+AND o.occurrence = b.occurrence
+WHERE b.status IS DISTINCT FROM o.status OR b.amount IS DISTINCT FROM o.amount
+ORDER BY 1 DESC, 2, 3, COALESCE(b.occurrence, o.occurrence)
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListOpenReconciliationItems :many
+SELECT * FROM reconciliation_items
+WHERE job_id = $1 AND status = 'MISSING' AND resolved_job_id IS NULL AND adjustment_id IS NULL
+ORDER BY id;
+
+-- name: ResolveCarriedReconciliationItems :exec
+WITH RECURSIVE carried AS (
+    SELECT id, carried_from_id FROM reconciliation_items WHERE id = ANY(sqlc.arg(ids)::BIGINT[])
+    UNION
+    SELECT i.id, i.carried_from_id FROM reconciliation_items i
+    JOIN carried c ON i.id = c.carried_from_id
+)
+UPDATE reconciliation_items SET resolved_job_id = sqlc.arg(resolved_job_id)
+WHERE id IN (SELECT id FROM carried) AND status = 'MISSING' AND resolved_job_id IS NULL;
+
+-- name: SummarizeUnresolvedReconciliationItems :many
+WITH jobs AS (
+    SELECT id, parent_job_id, start_date, end_date, created_at, cutoff,
+    ARRAY(SELECT UPPER(TRIM(b->>'bank_name')) FROM jsonb_array_elements(bank_transaction_csv_paths) b)::VARCHAR[] AS bank_names
+    FROM reconciliation_jobs,
+    LATERAL (SELECT (sqlc.arg(as_of)::DATE + 1)::TIMESTAMP AT TIME ZONE 'UTC' AS cutoff) c
+    WHERE status = 'SUCCESS' AND created_at < cutoff
+)
+SELECT u.source, u.bank_name, u.type,
+(sqlc.arg(as_of)::DATE - u.transaction_date)::INT AS age_days,
+COUNT(1) AS total_transaction, COALESCE(SUM(u.amount), 0)::FLOAT AS total_amount
+FROM (
+    SELECT i.source, i.bank_name, i.type, i.amount, j.id AS job_id, j.created_at AS job_created_at, j.bank_names,
+    (i.transaction_time AT TIME ZONE 'UTC')::DATE AS transaction_date
+    FROM reconciliation_items i
+    JOIN jobs j ON j.id = i.job_id
+    WHERE i.status = 'MISSING'
+    AND NOT EXISTS (SELECT 1 FROM jobs r WHERE r.id = i.resolved_job_id)
+    AND NOT EXISTS (SELECT 1 FROM reconciliation_adjustments a WHERE a.id = i.adjustment_id AND a.created_at < j.cutoff)
+    AND NOT EXISTS (SELECT 1 FROM reconciliation_items c JOIN jobs cj ON cj.id = c.job_id WHERE c.carried_from_id = i.id)
+) u
+WHERE NOT EXISTS (
+    SELECT 1 FROM jobs l
+    WHERE (l.created_at, l.id) > (u.job_created_at, u.job_id)
+    AND u.transaction_date BETWEEN l.start_date AND l.end_date
+    AND CASE WHEN u.bank_name IS NULL THEN l.bank_names && u.bank_names ELSE UPPER(TRIM(u.bank_name)) = ANY(l.bank_names) END
+)
+GROUP BY u.source, u.bank_name, u.type, age_days
+ORDER BY u.source DESC, u.bank_name, u.type, age_days;
+
+-- name: ListReconciliationItemsByIds :many
+SELECT * FROM reconciliation_items
+WHERE job_id = sqlc.arg(job_id) AND id = ANY(sqlc.arg(ids)::BIGINT[])
+ORDER BY id;
+
+-- name: AdjustReconciliationItems :execrows
+UPDATE reconciliation_items i SET adjustment_id = sqlc.arg(adjustment_id)
+WHERE i.job_id = sqlc.arg(job_id) AND i.id = ANY(sqlc.arg(ids)::BIGINT[])
+AND i.status = 'MISSING' AND i.adjustment_id IS NULL AND i.resolved_job_id IS NULL
+AND NOT EXISTS (SELECT 1 FROM reconciliation_items c WHERE c.carried_from_id = i.id);
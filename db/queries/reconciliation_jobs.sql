@@ -1,6 +1,6 @@
 -- name: ListReconciliationJobs :many
 SELECT id, status, start_date, end_date, discrepancy_threshold,
-system_transaction_csv_path, bank_transaction_csv_paths FROM reconciliation_jobs
+system_transaction_csv_path, bank_transaction_csv_paths, approval_status FROM reconciliation_jobs
 ORDER BY id DESC
 LIMIT $1 OFFSET $2;
 
@@ -10,17 +10,68 @@ SELECT COUNT(1) FROM reconciliation_jobs;
 -- name: ListPendingReconciliationJobs :many
 SELECT * FROM reconciliation_jobs
 WHERE status = 'PENDING'
+OR (status = 'PROCESSING' AND COALESCE(lease_expires_at, '-infinity') < now())
 ORDER BY created_at ASC;
 
 -- name: GetReconciliationJobById :one
 SELECT * FROM reconciliation_jobs WHERE id = $1;
 
 -- name: CreateReconciliationJob :one
-INSERT INTO reconciliation_jobs (status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date) VALUES ('PENDING', $1, $2, $3, $4, $5)
+INSERT INTO reconciliation_jobs (status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, parent_job_id, matching_options) VALUES ('PENDING', $1, $2, $3, $4, $5, $6, $7)
 RETURNING *;
 
 -- name: SaveFailedReconciliationJob :one
-UPDATE reconciliation_jobs SET status = 'FAILED', error_information = $2 WHERE id = $1 RETURNING *;
+UPDATE reconciliation_jobs SET status = 'FAILED', error_information = $2, error_code = $3 WHERE id = $1 AND status = 'PROCESSING' AND claim_token = $4 RETURNING *;
 
 -- name: SaveSuccessReconciliationJob :one
-UPDATE reconciliation_jobs SET status = 'SUCCESS', result = $2 WHERE id = $1 RETURNING *;
+UPDATE reconciliation_jobs SET status = 'SUCCESS', result = $2 WHERE id = $1 AND status = 'PROCESSING' AND claim_token = $3 RETURNING *;
+
+-- name: StartReconciliationJob :one
+UPDATE reconciliation_jobs SET status = 'PROCESSING', lease_expires_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::FLOAT8), claim_token = claim_token + 1
+WHERE id = sqlc.arg(id) AND (status = 'PENDING' OR (status = 'PROCESSING' AND COALESCE(lease_expires_at, '-infinity') < now()))
+RETURNING *;
+
+-- name: RenewReconciliationJobLease :execrows
+UPDATE reconciliation_jobs SET lease_expires_at = now() + make_interval(secs => sqlc.arg(lease_seconds)::FLOAT8)
+WHERE id = sqlc.arg(id) AND status = 'PROCESSING' AND claim_token = sqlc.arg(claim_token);
+
+-- name: CancelReconciliationJob :one
+UPDATE reconciliation_jobs SET status = 'CANCELLED' WHERE id = $1 AND status IN ('PENDING', 'PROCESSING') RETURNING *;
+
+-- name: GetReconciliationJobStatus :one
+SELECT status FROM reconciliation_jobs WHERE id = $1;
+
+-- name: UpdateReconciliationJobProgress :execrows
+UPDATE reconciliation_jobs SET progress = $2 WHERE id = $1 AND status = 'PROCESSING' AND claim_token = $3;
+
+-- name: ListSuccessReconciliationJobsByEndDate :many
+SELECT * FROM reconciliation_jobs
+WHERE status = 'SUCCESS' AND end_date = $1
+ORDER BY id DESC;
+
+-- name: GetReconciliationJobStatusForUpdate :one
+SELECT status FROM reconciliation_jobs WHERE id = $1 FOR UPDATE;
+
+-- name: UpdateReconciliationJobAdjustedTotals :exec
+UPDATE reconciliation_jobs j SET adjusted_totals = jsonb_build_object(
+    'total_transaction_matched', (j.result->>'total_transaction_matched')::INT + a.total_manually_matched,
+    'total_transaction_manually_matched', a.total_manually_matched,
+    'total_transaction_resolved', a.total_resolved,
+    'total_transaction_unmatched', (j.result->>'total_transaction_unmatched')::INT - a.total_system_adjusted,
+    'total_discrepancy_amount', (j.result->>'total_discrepancy_amount')::FLOAT - a.total_adjusted_amount
+)
+FROM (
+    SELECT COUNT(DISTINCT adj.id) FILTER (WHERE adj.type = 'MANUAL_MATCH') AS total_manually_matched,
+    COUNT(1) FILTER (WHERE adj.type = 'RESOLVED') AS total_resolved,
+    COUNT(1) FILTER (WHERE i.source = 'SYSTEM') AS total_system_adjusted,
+    COALESCE(SUM(i.amount), 0) AS total_adjusted_amount
+    FROM reconciliation_items i
+    JOIN reconciliation_adjustments adj ON adj.id = i.adjustment_id
+    WHERE i.job_id = $1
+) a
+WHERE j.id = $1;
+
+-- name: UpdateReconciliationJobApprovalStatus :execrows
+UPDATE reconciliation_jobs SET approval_status = sqlc.arg(approval_status)
+WHERE id = sqlc.arg(id) AND status = 'SUCCESS'
+AND COALESCE(approval_status, '') = ANY(sqlc.arg(from_statuses)::VARCHAR[]);
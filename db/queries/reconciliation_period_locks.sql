@@ -0,0 +1,32 @@
+-- name: CreateReconciliationPeriodLocks :exec
+INSERT INTO reconciliation_period_locks (job_id, bank_name, start_date, end_date, locked_by)
+SELECT sqlc.arg(job_id), UPPER(TRIM(UNNEST(sqlc.arg(bank_names)::VARCHAR[]))), sqlc.arg(start_date), sqlc.arg(end_date), sqlc.arg(locked_by);
+
+-- name: GetReconciliationPeriodLockById :one
+SELECT * FROM reconciliation_period_locks WHERE id = $1;
+
+-- name: LockReconciliationPeriodBanks :exec
+SELECT pg_advisory_xact_lock(hashtext('reconciliation_period'), hashtext(b.bank_name))
+FROM (SELECT DISTINCT UPPER(TRIM(bank_name)) AS bank_name FROM UNNEST(sqlc.arg(bank_names)::VARCHAR[]) bank_name ORDER BY 1) b;
+
+-- name: ListOverlappingReconciliationPeriodLocks :many
+SELECT * FROM reconciliation_period_locks
+WHERE unlocked_at IS NULL
+AND bank_name IN (SELECT UPPER(TRIM(bank_name)) FROM UNNEST(sqlc.arg(bank_names)::VARCHAR[]) bank_name)
+AND start_date <= sqlc.arg(end_date) AND end_date >= sqlc.arg(start_date)
+ORDER BY id;
+
+-- name: ListReconciliationPeriodLocks :many
+SELECT * FROM reconciliation_period_locks
+WHERE (sqlc.narg(bank_name)::VARCHAR IS NULL OR bank_name = UPPER(TRIM(sqlc.narg(bank_name))))
+AND (NOT sqlc.arg(active_only)::BOOLEAN OR unlocked_at IS NULL)
+ORDER BY id DESC;
+
+-- name: UnlockReconciliationPeriodLock :one
+UPDATE reconciliation_period_locks SET unlocked_by = $2, unlocked_at = now(), unlock_reason = $3
+WHERE id = $1 AND unlocked_at IS NULL
+RETURNING *;
+
+-- name: UnlockReconciliationPeriodLocksByJob :exec
+UPDATE reconciliation_period_locks SET unlocked_by = $2, unlocked_at = now(), unlock_reason = $3
+WHERE job_id = $1 AND unlocked_at IS NULL;
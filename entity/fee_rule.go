@@ -0,0 +1,29 @@
+package entity
+
+// FeeType is a custom type for how bank fee is charged
+type FeeType string
+
+const (
+	// FeeTypeFixed charge the same fee for every transaction
+	FeeTypeFixed FeeType = "FIXED"
+	// FeeTypePercentage charge a rate of the transaction amount
+	FeeTypePercentage FeeType = "PERCENTAGE"
+	// FeeTypeTiered charge the fee of the tier the transaction amount falls into
+	FeeTypeTiered FeeType = "TIERED"
+)
+
+// FeeTier hold the fee charged for transaction amount starting from MinAmount
+type FeeTier struct {
+	MinAmount float64 `json:"min_amount"`
+	Fixed     float64 `json:"fixed"`
+	Rate      float64 `json:"rate"`
+}
+
+// FeeRule hold how a bank deducts fee and tax from the credited amount, rate
+// is a fraction of the system amount, e.g. 0.007 for 0.7%
+type FeeRule struct {
+	Type  FeeType   `json:"type"`
+	Fixed float64   `json:"fixed,omitempty"`
+	Rate  float64   `json:"rate,omitempty"`
+	Tiers []FeeTier `json:"tiers,omitempty"`
+}
@@ -1,6 +1,6 @@
 package entity
 
 const (
-	LimitCSVSize     = int64(10 << 20)  // 10 MB
-	LimitContentSize = int64(100 << 20) // 100 MB
+	LimitCSVSize     = int64(1 << 30)  // 1 GB
+	LimitContentSize = int64(10 << 30) // 10 GB
 )
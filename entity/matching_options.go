@@ -0,0 +1,36 @@
+package entity
+
+// DuplicateRule is a custom type for rule to detect duplicate transactions within a file
+type DuplicateRule string
+
+const (
+	// DuplicateRuleID detect transactions with the same ID as duplicate
+	DuplicateRuleID DuplicateRule = "ID"
+	// DuplicateRuleFingerprint detect transactions with the same amount, date and type as duplicate
+	DuplicateRuleFingerprint DuplicateRule = "FINGERPRINT"
+)
+
+// MatchingOptions hold options of how transactions of a reconciliation job are matched
+type MatchingOptions struct {
+	DuplicateRule DuplicateRule `json:"duplicate_rule"`
+	Deduplicate   bool          `json:"deduplicate"`
+	// PairTransfers pair unmatched debit and credit bank transactions of
+	// different bank files as internal transfers
+	PairTransfers bool `json:"pair_transfers"`
+	// PairReversals pair unmatched transactions with the transaction that
+	// reverses them in the same file
+	PairReversals bool `json:"pair_reversals"`
+	// ReversalWindowDays is the maximum days between a transaction and its reversal
+	ReversalWindowDays int `json:"reversal_window_days"`
+	// FeeRules is the fee rule of the bank credits keyed by bank name, the
+	// expected bank amount is the system amount net of the fee
+	FeeRules map[string]FeeRule `json:"fee_rules,omitempty"`
+	// MinConfidence is the minimum confidence score of a match to be counted
+	// as matched, matches below it need review, zero disables the review
+	MinConfidence float64 `json:"min_confidence"`
+	// CarryForward include the still open unmatched items of the previous
+	// job of the same banks whose period ends the day before the job starts,
+	// the carried items of the last day of the previous period are matched
+	// with the missing transactions of the first day of the job
+	CarryForward bool `json:"carry_forward"`
+}
@@ -0,0 +1,69 @@
+package entity
+
+import "time"
+
+// ReconciliationAdjustmentType is a custom type for how the unmatched items of a job are adjusted
+type ReconciliationAdjustmentType string
+
+const (
+	// ReconciliationAdjustmentTypeManualMatch is a missing system transaction
+	// paired by hand with a missing bank transaction
+	ReconciliationAdjustmentTypeManualMatch ReconciliationAdjustmentType = "MANUAL_MATCH"
+	// ReconciliationAdjustmentTypeResolved is a missing transaction marked as resolved
+	ReconciliationAdjustmentTypeResolved ReconciliationAdjustmentType = "RESOLVED"
+)
+
+// ResolutionReasonCode is a custom type for the reason a missing transaction is resolved
+type ResolutionReasonCode string
+
+const (
+	// ResolutionReasonTimingDifference is a transaction booked in another period on the other side
+	ResolutionReasonTimingDifference ResolutionReasonCode = "TIMING_DIFFERENCE"
+	// ResolutionReasonBankError is a transaction wrongly booked by the bank
+	ResolutionReasonBankError ResolutionReasonCode = "BANK_ERROR"
+	// ResolutionReasonSystemError is a transaction wrongly booked by the system
+	ResolutionReasonSystemError ResolutionReasonCode = "SYSTEM_ERROR"
+	// ResolutionReasonDuplicate is a transaction booked more than once
+	ResolutionReasonDuplicate ResolutionReasonCode = "DUPLICATE"
+	// ResolutionReasonWriteOff is a transaction written off
+	ResolutionReasonWriteOff ResolutionReasonCode = "WRITE_OFF"
+	// ResolutionReasonOther is any other reason explained in the note
+	ResolutionReasonOther ResolutionReasonCode = "OTHER"
+)
+
+// ResolutionReasonCodes is every supported resolution reason code
+var ResolutionReasonCodes = []ResolutionReasonCode{
+	ResolutionReasonTimingDifference,
+	ResolutionReasonBankError,
+	ResolutionReasonSystemError,
+	ResolutionReasonDuplicate,
+	ResolutionReasonWriteOff,
+	ResolutionReasonOther,
+}
+
+// ReconciliationAdjustment hold a manual adjustment of the missing items of a
+// job, the items keep the status given by the job and refer to the adjustment
+// instead, reason code of manual match is only given when the amounts of the
+// pair differ beyond the discrepancy threshold of the job
+type ReconciliationAdjustment struct {
+	ID         int64                        `json:"id"`
+	JobID      int64                        `json:"job_id"`
+	Type       ReconciliationAdjustmentType `json:"type"`
+	ReasonCode ResolutionReasonCode         `json:"reason_code"`
+	Note       string                       `json:"note"`
+	ItemIDs    []int64                      `json:"item_ids"`
+	CreatedBy  string                       `json:"created_by"`
+	CreatedAt  time.Time                    `json:"created_at"`
+}
+
+// ReconciliationAdjustedTotals hold the totals of the job result after the
+// manual adjustments, the matched transactions include the manual matches,
+// the unmatched transactions count the system transactions only like the
+// result does while the resolved transactions count both sides
+type ReconciliationAdjustedTotals struct {
+	TotalTransactionMatched         int     `json:"total_transaction_matched"`
+	TotalTransactionManuallyMatched int     `json:"total_transaction_manually_matched"`
+	TotalTransactionResolved        int     `json:"total_transaction_resolved"`
+	TotalTransactionUnmatched       int     `json:"total_transaction_unmatched"`
+	TotalDiscrepancyAmount          float64 `json:"total_discrepancy_amount"`
+}
@@ -0,0 +1,43 @@
+package entity
+
+import "time"
+
+// ReconciliationApprovalStatus is a custom type for the review status of a successful job
+type ReconciliationApprovalStatus string
+
+const (
+	// ReconciliationApprovalStatusSubmitted is a job submitted by its preparer for approval
+	ReconciliationApprovalStatusSubmitted ReconciliationApprovalStatus = "SUBMITTED"
+	// ReconciliationApprovalStatusApproved is a job approved by a reviewer, its period is locked
+	ReconciliationApprovalStatusApproved ReconciliationApprovalStatus = "APPROVED"
+	// ReconciliationApprovalStatusRejected is a job rejected by a reviewer, it can be submitted again
+	ReconciliationApprovalStatusRejected ReconciliationApprovalStatus = "REJECTED"
+	// ReconciliationApprovalStatusUnlocked is an approved job whose period is unlocked, it can be submitted again
+	ReconciliationApprovalStatusUnlocked ReconciliationApprovalStatus = "UNLOCKED"
+)
+
+// ReconciliationApproval hold a step of the approval of a job, status is the
+// approval status the job is changed to by the user
+type ReconciliationApproval struct {
+	ID        int64                        `json:"id"`
+	JobID     int64                        `json:"job_id"`
+	Status    ReconciliationApprovalStatus `json:"status"`
+	Comment   string                       `json:"comment"`
+	CreatedBy string                       `json:"created_by"`
+	CreatedAt time.Time                    `json:"created_at"`
+}
+
+// ReconciliationPeriodLock hold the period of a bank locked by an approved
+// job, the lock is active until it is unlocked with a reason
+type ReconciliationPeriodLock struct {
+	ID           int64      `json:"id"`
+	JobID        int64      `json:"job_id"`
+	BankName     string     `json:"bank_name"`
+	StartDate    time.Time  `json:"start_date"`
+	EndDate      time.Time  `json:"end_date"`
+	LockedBy     string     `json:"locked_by"`
+	LockedAt     time.Time  `json:"locked_at"`
+	UnlockedBy   string     `json:"unlocked_by"`
+	UnlockedAt   *time.Time `json:"unlocked_at"`
+	UnlockReason string     `json:"unlock_reason"`
+}
@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// ReconciliationCaseStatus is a custom type for the follow up status of an exception case
+type ReconciliationCaseStatus string
+
+const (
+	// ReconciliationCaseStatusOpen is a case not yet picked up by its assignee
+	ReconciliationCaseStatusOpen ReconciliationCaseStatus = "OPEN"
+	// ReconciliationCaseStatusInvestigating is a case being investigated by its assignee
+	ReconciliationCaseStatusInvestigating ReconciliationCaseStatus = "INVESTIGATING"
+	// ReconciliationCaseStatusResolved is a case whose transaction is explained
+	ReconciliationCaseStatusResolved ReconciliationCaseStatus = "RESOLVED"
+	// ReconciliationCaseStatusWrittenOff is a case whose transaction is written off
+	ReconciliationCaseStatusWrittenOff ReconciliationCaseStatus = "WRITTEN_OFF"
+)
+
+// ReconciliationCaseStatuses is every status of an exception case
+var ReconciliationCaseStatuses = []ReconciliationCaseStatus{
+	ReconciliationCaseStatusOpen,
+	ReconciliationCaseStatusInvestigating,
+	ReconciliationCaseStatusResolved,
+	ReconciliationCaseStatusWrittenOff,
+}
+
+// ReconciliationCase hold the follow up of an unmatched item assigned to a
+// user, an item has at most a case, bank name is empty for system transaction
+// and due date is nil when the case has no deadline
+type ReconciliationCase struct {
+	ID          int64                    `json:"id"`
+	ItemID      int64                    `json:"item_id"`
+	JobID       int64                    `json:"job_id"`
+	Source      ReconciliationItemSource `json:"source"`
+	BankName    string                   `json:"bank_name"`
+	Transaction Transaction              `json:"transaction"`
+	Assignee    string                   `json:"assignee"`
+	Status      ReconciliationCaseStatus `json:"status"`
+	DueDate     *time.Time               `json:"due_date"`
+	// AdjustmentID is the adjustment resolving the item when the case is
+	// resolved or written off, it is removed when the case is reopened
+	AdjustmentID *int64 `json:"adjustment_id"`
+	// Comments is the history of the case from the oldest, it is only
+	// loaded when a single case is requested
+	Comments  []ReconciliationCaseComment `json:"comments,omitempty"`
+	CreatedBy string                      `json:"created_by"`
+	CreatedAt time.Time                   `json:"created_at"`
+	UpdatedAt time.Time                   `json:"updated_at"`
+}
+
+// ReconciliationCaseComment hold a comment on a case, status is the status the
+// case is changed to along with the comment and is empty for a plain comment
+type ReconciliationCaseComment struct {
+	ID        int64                    `json:"id"`
+	CaseID    int64                    `json:"case_id"`
+	Status    ReconciliationCaseStatus `json:"status"`
+	Comment   string                   `json:"comment"`
+	CreatedBy string                   `json:"created_by"`
+	CreatedAt time.Time                `json:"created_at"`
+}
@@ -0,0 +1,110 @@
+package entity
+
+// ReconciliationItemSource is a custom type for the file a reconciliation item comes from
+type ReconciliationItemSource string
+
+const (
+	// ReconciliationItemSourceSystem is the system transaction file
+	ReconciliationItemSourceSystem ReconciliationItemSource = "SYSTEM"
+	// ReconciliationItemSourceBank is a bank transaction file
+	ReconciliationItemSourceBank ReconciliationItemSource = "BANK"
+)
+
+// ReconciliationItemStatus is a custom type for the reconciliation status of an item or a match
+type ReconciliationItemStatus string
+
+const (
+	// ReconciliationItemStatusMatched is a transaction matched with the other side
+	ReconciliationItemStatusMatched ReconciliationItemStatus = "MATCHED"
+	// ReconciliationItemStatusMissing is a transaction missing on the other side
+	ReconciliationItemStatusMissing ReconciliationItemStatus = "MISSING"
+	// ReconciliationItemStatusNeedsReview is a transaction matched below the minimum confidence
+	ReconciliationItemStatusNeedsReview ReconciliationItemStatus = "NEEDS_REVIEW"
+	// ReconciliationItemStatusReversed is a transaction paired with its reversal
+	ReconciliationItemStatusReversed ReconciliationItemStatus = "REVERSED"
+	// ReconciliationItemStatusInternalTransfer is a bank transaction paired as internal transfer
+	ReconciliationItemStatusInternalTransfer ReconciliationItemStatus = "INTERNAL_TRANSFER"
+	// ReconciliationItemStatusDuplicate is a duplicate transaction excluded from matching
+	ReconciliationItemStatusDuplicate ReconciliationItemStatus = "DUPLICATE"
+)
+
+// ReconciliationItem hold a transaction of a reconciliation job along with its
+// position in the file and its reconciliation status, bank name is empty for
+// system transaction
+type ReconciliationItem struct {
+	ID          int64                    `json:"id"`
+	JobID       int64                    `json:"job_id"`
+	Transaction Transaction              `json:"transaction"`
+	Source      ReconciliationItemSource `json:"source"`
+	BankName    string                   `json:"bank_name"`
+	Row         int                      `json:"row"`
+	Status      ReconciliationItemStatus `json:"status"`
+	// CarriedFromID is the item of the previous job the item is carried
+	// from, the row of a carried item follows the last row of its file
+	CarriedFromID *int64 `json:"carried_from_id"`
+	// ResolvedJobID is the later job that matched the item once it is
+	// carried forward
+	ResolvedJobID *int64 `json:"resolved_job_id"`
+	// AdjustmentID is the manual adjustment of the item, the status of an
+	// adjusted item is kept as given by the job
+	AdjustmentID *int64 `json:"adjustment_id"`
+	// DuplicateOfRow is the earlier row of the same file with the same key
+	DuplicateOfRow *int `json:"duplicate_of_row"`
+	// OverlapBankName and OverlapRow is the transaction of an earlier bank
+	// file with the same key
+	OverlapBankName *string `json:"overlap_bank_name"`
+	OverlapRow      *int    `json:"overlap_row"`
+	// PairedBankName and PairedRow is the other transaction of a reversal or
+	// an internal transfer, the bank name is nil for system transaction
+	PairedBankName *string `json:"paired_bank_name"`
+	PairedRow      *int    `json:"paired_row"`
+}
+
+// ReconciliationMatch hold a system transaction and the bank transaction it is
+// matched with, both are identified by their row in the file
+type ReconciliationMatch struct {
+	ID          int64                    `json:"id"`
+	JobID       int64                    `json:"job_id"`
+	SystemRow   int                      `json:"system_row"`
+	BankName    string                   `json:"bank_name"`
+	BankRow     int                      `json:"bank_row"`
+	Status      ReconciliationItemStatus `json:"status"`
+	Confidence  float64                  `json:"confidence"`
+	ExpectedFee float64                  `json:"expected_fee"`
+	ImpliedFee  float64                  `json:"implied_fee"`
+}
+
+// ReconciliationDiff hold the items whose reconciliation changed from a job to
+// the other job, an item is identified by its source, bank name, transaction ID
+// and its occurrence among the items of the file with the same transaction ID,
+// an item is listed in every list it belongs to
+type ReconciliationDiff struct {
+	JobID      int64 `json:"job_id"`
+	OtherJobID int64 `json:"other_job_id"`
+	// NewlyMatched is the items matched in the other job but not in the job
+	NewlyMatched []ReconciliationDiffItem `json:"newly_matched"`
+	// NewlyUnmatched is the items missing in the other job but not in the job
+	NewlyUnmatched []ReconciliationDiffItem `json:"newly_unmatched"`
+	// StatusChanges is the items in both jobs with a different status
+	StatusChanges []ReconciliationDiffItem `json:"status_changes"`
+	// Added is the items in the other job but not in the job
+	Added []ReconciliationDiffItem `json:"added"`
+	// Removed is the items in the job but not in the other job
+	Removed []ReconciliationDiffItem `json:"removed"`
+	// AmountChanges is the items in both jobs with a different amount
+	AmountChanges []ReconciliationDiffItem `json:"amount_changes"`
+}
+
+// ReconciliationDiffItem hold the row, the status and the amount of an item in
+// both jobs, they are nil when the item is not in the job
+type ReconciliationDiffItem struct {
+	Source        ReconciliationItemSource  `json:"source"`
+	BankName      string                    `json:"bank_name"`
+	TransactionID string                    `json:"transaction_id"`
+	Row           *int                      `json:"row"`
+	Status        *ReconciliationItemStatus `json:"status"`
+	Amount        *float64                  `json:"amount"`
+	OtherRow      *int                      `json:"other_row"`
+	OtherStatus   *ReconciliationItemStatus `json:"other_status"`
+	OtherAmount   *float64                  `json:"other_amount"`
+}
@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"strings"
 	"time"
 )
 
@@ -16,46 +17,181 @@ const (
 	ReconciliationJobStatusSuccess ReconciliationJobStatus = "SUCCESS"
 	// ReconciliationJobStatusFailed is a failed status of reconciliation job
 	ReconciliationJobStatusFailed ReconciliationJobStatus = "FAILED"
+	// ReconciliationJobStatusCancelled is a cancelled status of reconciliation job
+	ReconciliationJobStatusCancelled ReconciliationJobStatus = "CANCELLED"
 )
 
+// ReconciliationJobErrorCode is a custom type for the reason a reconciliation job failed
+type ReconciliationJobErrorCode string
+
+const (
+	// ReconciliationJobErrorCodeProcessFailed is an error code when reconciliation job failed to be processed
+	ReconciliationJobErrorCodeProcessFailed ReconciliationJobErrorCode = "PROCESS_FAILED"
+	// ReconciliationJobErrorCodeTimeout is an error code when reconciliation job exceeded the processing timeout
+	ReconciliationJobErrorCodeTimeout ReconciliationJobErrorCode = "TIMEOUT"
+	// ReconciliationJobErrorCodePeriodLocked is an error code when the period of reconciliation job is locked before its result is saved
+	ReconciliationJobErrorCodePeriodLocked ReconciliationJobErrorCode = "PERIOD_LOCKED"
+)
+
+// ReconciliationJobPhase is a custom type for the current phase of processing reconciliation job
+type ReconciliationJobPhase string
+
+const (
+	// ReconciliationJobPhaseDownloading is a phase when the csv files are being downloaded
+	ReconciliationJobPhaseDownloading ReconciliationJobPhase = "DOWNLOADING"
+	// ReconciliationJobPhaseParsing is a phase when the csv files are being parsed
+	ReconciliationJobPhaseParsing ReconciliationJobPhase = "PARSING"
+	// ReconciliationJobPhaseMatching is a phase when the transactions are being matched
+	ReconciliationJobPhaseMatching ReconciliationJobPhase = "MATCHING"
+	// ReconciliationJobPhaseSaving is a phase when the result is being saved
+	ReconciliationJobPhaseSaving ReconciliationJobPhase = "SAVING"
+)
+
+// ReconciliationJobProgress hold progress data of processing reconciliation job,
+// the rows parsed of the bank files are keyed by bank name
+type ReconciliationJobProgress struct {
+	Phase                 ReconciliationJobPhase `json:"phase"`
+	SystemRowsParsed      int                    `json:"system_rows_parsed"`
+	RowsParsed            map[string]int         `json:"rows_parsed"`
+	TotalTransactions     int                    `json:"total_transactions"`
+	TransactionsProcessed int                    `json:"transactions_processed"`
+	TransactionsMatched   int                    `json:"transactions_matched"`
+}
+
 // BankTransactionCsv hold bank transaction csv data
 type BankTransactionCsv struct {
 	BankName string `json:"bank_name"`
 	FilePath string `json:"file_path"`
 }
 
+// BankNameKey return the key to compare bank names, bank names of a job are
+// unique regardless of their case and surrounding spaces
+func BankNameKey(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
 // ReconciliationResult hold reconciliation result data
 type ReconciliationResult struct {
-	TotalTransactionProcessed int                      `json:"total_transaction_processed"`
-	TotalTransactionMatched   int                      `json:"total_transaction_matched"`
-	TotalTransactionUnmatched int                      `json:"total_transaction_unmatched"`
-	TotalDiscrepancyAmount    float64                  `json:"total_discrepancy_amount"`
-	MissingTransactions       []Transaction            `json:"missing_transactions"`
-	MissingBankTransactions   map[string][]Transaction `json:"missing_bank_transactions"`
+	TotalTransactionProcessed int `json:"total_transaction_processed"`
+	TotalTransactionMatched   int `json:"total_transaction_matched"`
+	TotalTransactionUnmatched int `json:"total_transaction_unmatched"`
+	// TotalTransactionReversed is the number of system transactions paired
+	// as reversal, they are not counted as unmatched
+	TotalTransactionReversed int `json:"total_transaction_reversed"`
+	// TotalTransactionNeedsReview is the number of system transactions
+	// matched below the minimum confidence, they are not counted as matched
+	TotalTransactionNeedsReview int `json:"total_transaction_needs_review"`
+	// TotalTransactionDuplicate is the number of transactions of every file
+	// with the same key as an earlier transaction of the same file
+	TotalTransactionDuplicate int `json:"total_transaction_duplicate"`
+	// TotalTransactionInternalTransfer is the number of bank transactions
+	// paired as internal transfer, they are not counted as discrepancy
+	TotalTransactionInternalTransfer int     `json:"total_transaction_internal_transfer"`
+	TotalDiscrepancyAmount           float64 `json:"total_discrepancy_amount"`
+	// the lists below are stored as items and matches, they are omitted from
+	// the stored summary of the result so it only holds the totals of the job
+	MissingTransactions     []Transaction            `json:"missing_transactions,omitempty"`
+	MissingBankTransactions map[string][]Transaction `json:"missing_bank_transactions,omitempty"`
+	// NeedsReview is the matches below the minimum confidence ordered by the
+	// system transaction, they are not counted as discrepancy
+	NeedsReview []ReviewMatch `json:"needs_review,omitempty"`
+	// Duplicates is keyed by SYSTEM for system transaction file and by bank
+	// name for bank transaction files
+	Duplicates map[string][]DuplicateTransaction `json:"duplicates,omitempty"`
+	// Reversals is the unmatched transactions paired with their reversal,
+	// keyed the same as Duplicates, they are not counted as discrepancy
+	Reversals map[string][]ReversalPair `json:"reversals,omitempty"`
+	// InternalTransfers is the unmatched bank transactions paired as a
+	// transfer between two bank files, they are not counted as discrepancy
+	InternalTransfers []InternalTransfer `json:"internal_transfers,omitempty"`
+	// BankFees is the fees of the matched transactions of the banks with fee
+	// rule, keyed by bank name
+	BankFees map[string]BankFeeSummary `json:"bank_fees"`
+	Warnings ReconciliationWarnings    `json:"warnings"`
+	// CarryForward is the summary of the items carried from the previous
+	// job, it is only set when the carry forward option is enabled
+	CarryForward *CarryForwardSummary `json:"carry_forward,omitempty"`
+	// ContentHash is the SHA-256 of the result content, identical inputs
+	// always produce the same hash
+	ContentHash string `json:"content_hash"`
+}
+
+// BankFeeSummary hold the fees of the matched transactions of a bank, the
+// matches are stored as matches and omitted from the stored summary
+type BankFeeSummary struct {
+	TotalFee         float64    `json:"total_fee"`
+	TotalExpectedFee float64    `json:"total_expected_fee"`
+	Matches          []MatchFee `json:"matches,omitempty"`
+}
+
+// MatchFee hold the fee of a matched pair, the implied fee is the difference
+// between the system amount and the bank amount while the expected fee is
+// computed from the fee rule of the bank
+type MatchFee struct {
+	SystemTransaction Transaction `json:"system_transaction"`
+	BankTransaction   Transaction `json:"bank_transaction"`
+	ExpectedFee       float64     `json:"expected_fee"`
+	ImpliedFee        float64     `json:"implied_fee"`
+}
+
+// CarryForwardSummary hold the number of the open items carried from the
+// previous job and how many of them are resolved by the job, FromJobID is nil
+// when there is no previous job
+type CarryForwardSummary struct {
+	FromJobID     *int64 `json:"from_job_id"`
+	TotalCarried  int    `json:"total_carried"`
+	TotalResolved int    `json:"total_resolved"`
+	// the carried system transactions are processed by the previous job, they
+	// are counted here and not in the totals of the job
+	TotalSystemCarried     int `json:"total_system_carried"`
+	TotalSystemMatched     int `json:"total_system_matched"`
+	TotalSystemNeedsReview int `json:"total_system_needs_review"`
+	// TotalDiscrepancyAmount is the amount of the carried transactions that
+	// are still missing, it is not counted in the discrepancy of the job
+	TotalDiscrepancyAmount float64 `json:"total_discrepancy_amount"`
+}
+
+// ReconciliationWarnings hold findings of the inputs that do not fail the job
+// but may make the result incorrect
+type ReconciliationWarnings struct {
+	// BankFileOverlaps is the transactions found in more than one bank file,
+	// it usually means the same statement is uploaded under two bank names
+	BankFileOverlaps     []OverlapTransaction `json:"bank_file_overlaps,omitempty"`
+	TotalBankFileOverlap int                  `json:"total_bank_file_overlap"`
 }
 
-// ReconciliationJob hold reconciliation job data
+// ReconciliationJob hold reconciliation job data, adjusted totals is the
+// totals of the result after the manual adjustments, it is nil until the
+// result is adjusted, approval status is empty until the job is submitted for
+// approval
 type ReconciliationJob struct {
-	ID                       int64                   `json:"id"`
-	Status                   ReconciliationJobStatus `json:"status"`
-	SystemTransactionCsvPath string                  `json:"system_transaction_csv_path"`
-	BankTransactionCsvPaths  []BankTransactionCsv    `json:"bank_transaction_csv_paths"`
-	DiscrepancyThreshold     float32                 `json:"discrepancy_threshold"`
-	ErrorInformation         string                  `json:"error_information"`
-	Result                   *ReconciliationResult   `json:"result"`
-	StartDate                time.Time               `json:"start_date"`
-	EndDate                  time.Time               `json:"end_date"`
-	CreatedAt                time.Time               `json:"created_at"`
-	UpdatedAt                time.Time               `json:"updated_at"`
+	ID                       int64                         `json:"id"`
+	ParentJobID              *int64                        `json:"parent_job_id"`
+	Status                   ReconciliationJobStatus       `json:"status"`
+	SystemTransactionCsvPath string                        `json:"system_transaction_csv_path"`
+	BankTransactionCsvPaths  []BankTransactionCsv          `json:"bank_transaction_csv_paths"`
+	DiscrepancyThreshold     float32                       `json:"discrepancy_threshold"`
+	MatchingOptions          MatchingOptions               `json:"matching_options"`
+	ErrorInformation         string                        `json:"error_information"`
+	ErrorCode                ReconciliationJobErrorCode    `json:"error_code"`
+	Result                   *ReconciliationResult         `json:"result"`
+	AdjustedTotals           *ReconciliationAdjustedTotals `json:"adjusted_totals"`
+	ApprovalStatus           ReconciliationApprovalStatus  `json:"approval_status"`
+	Progress                 *ReconciliationJobProgress    `json:"progress"`
+	StartDate                time.Time                     `json:"start_date"`
+	EndDate                  time.Time                     `json:"end_date"`
+	CreatedAt                time.Time                     `json:"created_at"`
+	UpdatedAt                time.Time                     `json:"updated_at"`
 }
 
 // SimpleReconciliationJob hold simple reconciliation job data
 type SimpleReconciliationJob struct {
-	ID                       int64                   `json:"id"`
-	Status                   ReconciliationJobStatus `json:"status"`
-	DiscrepancyThreshold     float32                 `json:"discrepancy_threshold"`
-	SystemTransactionCsvPath string                  `json:"system_transaction_csv_path"`
-	BankTransactionCsvPaths  []BankTransactionCsv    `json:"bank_transaction_csv_paths"`
-	StartDate                time.Time               `json:"start_date"`
-	EndDate                  time.Time               `json:"end_date"`
+	ID                       int64                        `json:"id"`
+	Status                   ReconciliationJobStatus      `json:"status"`
+	DiscrepancyThreshold     float32                      `json:"discrepancy_threshold"`
+	SystemTransactionCsvPath string                       `json:"system_transaction_csv_path"`
+	BankTransactionCsvPaths  []BankTransactionCsv         `json:"bank_transaction_csv_paths"`
+	ApprovalStatus           ReconciliationApprovalStatus `json:"approval_status"`
+	StartDate                time.Time                    `json:"start_date"`
+	EndDate                  time.Time                    `json:"end_date"`
 }
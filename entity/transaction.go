@@ -21,3 +21,67 @@ type Transaction struct {
 	Type   TransactionType `json:"type"`
 	Time   time.Time       `json:"time"`
 }
+
+// DuplicateTransaction hold transaction that duplicates an earlier transaction
+// in the same file, rows are the position of the transactions in the file
+type DuplicateTransaction struct {
+	Transaction
+	Row            int `json:"row"`
+	DuplicateOfRow int `json:"duplicate_of_row"`
+}
+
+// OverlapTransaction hold bank transaction that is also found in another bank
+// file of the same job, overlap is the first transaction found
+type OverlapTransaction struct {
+	Transaction
+	BankName        string `json:"bank_name"`
+	Row             int    `json:"row"`
+	OverlapBankName string `json:"overlap_bank_name"`
+	OverlapRow      int    `json:"overlap_row"`
+}
+
+// TransferLeg hold a bank transaction of an internal transfer along with the
+// bank file and row it is found in
+type TransferLeg struct {
+	Transaction
+	BankName string `json:"bank_name"`
+	Row      int    `json:"row"`
+}
+
+// InternalTransfer hold a pair of bank transactions that move money between
+// two bank accounts of our own, From is the debit and To is the credit
+type InternalTransfer struct {
+	From TransferLeg `json:"from"`
+	To   TransferLeg `json:"to"`
+}
+
+// ReversalLeg hold a transaction of a reversal along with its row in the file
+type ReversalLeg struct {
+	Transaction
+	Row int `json:"row"`
+}
+
+// ReversalPair hold a transaction and the transaction of the opposite type
+// and the same amount that reverses it in the same file, both net to zero
+type ReversalPair struct {
+	Original ReversalLeg `json:"original"`
+	Reversal ReversalLeg `json:"reversal"`
+}
+
+// MatchConfidence hold the confidence score of a match along with the score
+// of its components, every score is between 0 and 1
+type MatchConfidence struct {
+	Score      float64 `json:"score"`
+	Amount     float64 `json:"amount"`
+	Date       float64 `json:"date"`
+	Uniqueness float64 `json:"uniqueness"`
+}
+
+// ReviewMatch hold a match with confidence score below the minimum confidence,
+// it needs to be reviewed before it is counted as matched
+type ReviewMatch struct {
+	SystemTransaction Transaction     `json:"system_transaction"`
+	BankName          string          `json:"bank_name"`
+	BankTransaction   Transaction     `json:"bank_transaction"`
+	Confidence        MatchConfidence `json:"confidence"`
+}
@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// AgeingBucket is a custom type for the range of days a transaction has been unmatched
+type AgeingBucket string
+
+const (
+	// AgeingBucket0To7 is unmatched for up to 7 days
+	AgeingBucket0To7 AgeingBucket = "0-7"
+	// AgeingBucket8To30 is unmatched for 8 to 30 days
+	AgeingBucket8To30 AgeingBucket = "8-30"
+	// AgeingBucket31To90 is unmatched for 31 to 90 days
+	AgeingBucket31To90 AgeingBucket = "31-90"
+	// AgeingBucketOver90 is unmatched for more than 90 days
+	AgeingBucketOver90 AgeingBucket = "90+"
+)
+
+// AgeingBuckets is every ageing bucket from the youngest to the oldest
+var AgeingBuckets = []AgeingBucket{AgeingBucket0To7, AgeingBucket8To30, AgeingBucket31To90, AgeingBucketOver90}
+
+// UnmatchedAgeing hold the unresolved unmatched transactions of every job
+// grouped by how long they have been unmatched as of a date
+type UnmatchedAgeing struct {
+	AsOf time.Time `json:"as_of"`
+	// Groups is the ageing of every source, bank and transaction type
+	Groups []UnmatchedAgeingGroup `json:"groups"`
+	// Buckets is the total of every bucket across the groups
+	Buckets          []AgeingBucketTotal `json:"buckets"`
+	TotalTransaction int64               `json:"total_transaction"`
+	TotalAmount      float64             `json:"total_amount"`
+}
+
+// UnmatchedAgeingGroup hold the ageing of the unmatched transactions of a file
+// in a single direction, bank name is empty for system transaction
+type UnmatchedAgeingGroup struct {
+	Source           ReconciliationItemSource `json:"source"`
+	BankName         string                   `json:"bank_name"`
+	Type             TransactionType          `json:"type"`
+	Buckets          []AgeingBucketTotal      `json:"buckets"`
+	TotalTransaction int64                    `json:"total_transaction"`
+	TotalAmount      float64                  `json:"total_amount"`
+}
+
+// AgeingBucketTotal hold the number and the amount of the unmatched
+// transactions in an ageing bucket
+type AgeingBucketTotal struct {
+	Bucket           AgeingBucket `json:"bucket"`
+	TotalTransaction int64        `json:"total_transaction"`
+	TotalAmount      float64      `json:"total_amount"`
+}
@@ -22,8 +22,57 @@ var (
 	ErrFileSizeExceedLimit = func(fname, limit string) error {
 		return fmt.Errorf("file size %s more than %s", fname, limit)
 	}
+	errFileSizeExceeded = errors.New("file size exceeded")
+	// ErrContentSizeExceedLimit is an error when request content size exceed limit
+	ErrContentSizeExceedLimit = func(limit string) error {
+		return fmt.Errorf("content size more than %s", limit)
+	}
+	// ErrInvalidDate is an error when date is not in YYYY-MM-DD format
+	ErrInvalidDate = func(field string) error {
+		return fmt.Errorf("%s must be in YYYY-MM-DD format", field)
+	}
+	// ErrInvalidDateRange is an error when start date is after end date
+	ErrInvalidDateRange = errors.New("start date must be before end date")
+	// ErrInvalidNumber is an error when a number param is not a valid number
+	ErrInvalidNumber = func(field string) error {
+		return fmt.Errorf("%s must be a number", field)
+	}
+	// ErrInvalidAmountRange is an error when min amount is greater than max amount
+	ErrInvalidAmountRange = errors.New("min amount must not be greater than max amount")
+	// ErrInvalidOption is an error when a param is not one of the allowed options
+	ErrInvalidOption = func(field string, options any) error {
+		return fmt.Errorf("%s must be one of %v", field, options)
+	}
+	// ErrInvalidMatchingOptionsFormat is an error when matching options is not a valid JSON object
+	ErrInvalidMatchingOptionsFormat = errors.New("matching options must be a valid JSON object")
+	// ErrBankNameNotUnique is an error when a bank name is given more than once
+	ErrBankNameNotUnique = func(name string) error {
+		return fmt.Errorf("bank name %s must be unique", name)
+	}
+	// ErrBankNameReserved is an error when a bank is named after the system transaction file
+	ErrBankNameReserved = func(name string) error {
+		return fmt.Errorf("bank name %s is reserved for the system transaction file", name)
+	}
+	// ErrSystemTrxFileEmpty is an error when system transaction file is not uploaded
+	ErrSystemTrxFileEmpty = errors.New("system transaction file is required")
+	// ErrSystemTrxFileNotUnique is an error when system transaction file is uploaded more than once
+	ErrSystemTrxFileNotUnique = errors.New("system transaction file must be uploaded once")
+	// ErrFormValueSizeExceedLimit is an error when a non file field of the form is too large
+	ErrFormValueSizeExceedLimit = func(field string) error {
+		return fmt.Errorf("field %s is too large", field)
+	}
+	// ErrStoreUploadedFile is an error when an uploaded file cannot be stored
+	ErrStoreUploadedFile = errors.New("failed to store uploaded file")
 	// ErrBankTrxFileEmpty is an error when bank transaction files is empty
 	ErrBankTrxFileEmpty = errors.New("bank transaction files is required, at least provide one")
 	// ErrBankFileAndNameLengthNotMatch is an error when bank names and bank transaction files length not match
 	ErrBankFileAndNameLengthNotMatch = errors.New("bank names and bank transaction files length must be same")
+	// ErrInvalidRequestBody is an error when request body is not a valid JSON object
+	ErrInvalidRequestBody = errors.New("request body must be a valid JSON object")
+	// ErrFieldRequired is an error when a required field is empty
+	ErrFieldRequired = func(field string) error {
+		return fmt.Errorf("%s is required", field)
+	}
+	// ErrUserRequired is an error when the acting user is not given
+	ErrUserRequired = fmt.Errorf("%s header is required", userIDHeader)
 )
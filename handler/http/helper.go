@@ -2,16 +2,39 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/delly/amartha/entity"
+	"github.com/delly/amartha/handler/http/middleware"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
 )
 
 const (
 	minLimit   = 10
 	maxLimit   = 100
 	dateFormat = "2006-01-02"
+	// userIDHeader is the header of the acting user set by the gateway
+	userIDHeader = middleware.UserIDHeader
+)
+
+var (
+	itemSources      = []entity.ReconciliationItemSource{entity.ReconciliationItemSourceSystem, entity.ReconciliationItemSourceBank}
+	transactionTypes = []entity.TransactionType{entity.TxTypeCredit, entity.TxTypeDebit}
+	itemStatuses     = []entity.ReconciliationItemStatus{
+		entity.ReconciliationItemStatusMatched,
+		entity.ReconciliationItemStatusMissing,
+		entity.ReconciliationItemStatusNeedsReview,
+		entity.ReconciliationItemStatusReversed,
+		entity.ReconciliationItemStatusInternalTransfer,
+		entity.ReconciliationItemStatusDuplicate,
+	}
+	itemSorts    = []string{reconciliatonjob.ItemSortByAmount, reconciliatonjob.ItemSortByTransactionTime}
+	itemFindings = []string{reconciliatonjob.ItemFindingDuplicate, reconciliatonjob.ItemFindingOverlap}
 )
 
 type pagination struct {
@@ -56,6 +79,52 @@ func writeNotFound(w http.ResponseWriter, message string) {
 	json.NewEncoder(w).Encode(map[string]string{"message": message})
 }
 
+func writeUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+func writeForbidden(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+func writeConflict(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	json.NewEncoder(w).Encode(map[string]string{"message": message})
+}
+
+// attachmentWriter set the headers of a downloaded file on the first write,
+// so an error response can still be written when nothing is written yet
+type attachmentWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	written     bool
+}
+
+func newAttachmentWriter(w http.ResponseWriter, contentType, filename string) *attachmentWriter {
+	return &attachmentWriter{
+		w:           w,
+		contentType: contentType,
+		filename:    filename,
+	}
+}
+
+func (a *attachmentWriter) Write(p []byte) (int, error) {
+	if !a.written {
+		a.written = true
+		a.w.Header().Set("Content-Type", a.contentType)
+		a.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, a.filename))
+		a.w.WriteHeader(http.StatusOK)
+	}
+
+	return a.w.Write(p)
+}
+
 func getPagination(r *http.Request) pagination {
 	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 	if limit <= 0 {
@@ -89,6 +158,123 @@ func parseFloat32(str string) float32 {
 	return float32(f)
 }
 
+// parseMatchingOptions parse matching options from JSON string, unknown
+// options are rejected so a typo would not be silently ignored
+func parseMatchingOptions(str string) (*entity.MatchingOptions, error) {
+	var opts entity.MatchingOptions
+	decoder := json.NewDecoder(strings.NewReader(str))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&opts); err != nil {
+		return nil, ErrInvalidMatchingOptionsFormat
+	}
+
+	return &opts, nil
+}
+
+// decodeJSONBody decode the JSON request body, unknown fields are rejected so
+// a typo would not be silently ignored
+func decodeJSONBody(r *http.Request, v any) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return ErrInvalidRequestBody
+	}
+
+	return nil
+}
+
+// getUserID get the acting user from the request header
+func getUserID(r *http.Request) (string, error) {
+	userID := strings.TrimSpace(r.Header.Get(userIDHeader))
+	if userID == "" {
+		return "", ErrUserRequired
+	}
+
+	return userID, nil
+}
+
+// parseItemFilter parse the filter of the reconciliation items from the query
+// params, the enumerated params are case insensitive
+func parseItemFilter(r *http.Request) (reconciliatonjob.ItemFilter, error) {
+	query := r.URL.Query()
+	filter := reconciliatonjob.ItemFilter{
+		Source:   entity.ReconciliationItemSource(strings.ToUpper(query.Get("source"))),
+		BankName: strings.TrimSpace(query.Get("bank_name")),
+		Type:     entity.TransactionType(strings.ToUpper(query.Get("type"))),
+		Status:   entity.ReconciliationItemStatus(strings.ToUpper(query.Get("status"))),
+		Finding:  strings.ToLower(query.Get("finding")),
+		SortBy:   strings.ToLower(query.Get("sort_by")),
+	}
+	if filter.Source != "" && !slices.Contains(itemSources, filter.Source) {
+		return filter, ErrInvalidOption("source", itemSources)
+	}
+	if filter.Type != "" && !slices.Contains(transactionTypes, filter.Type) {
+		return filter, ErrInvalidOption("type", transactionTypes)
+	}
+	if filter.Status != "" && !slices.Contains(itemStatuses, filter.Status) {
+		return filter, ErrInvalidOption("status", itemStatuses)
+	}
+	if filter.Finding != "" && !slices.Contains(itemFindings, filter.Finding) {
+		return filter, ErrInvalidOption("finding", itemFindings)
+	}
+	if filter.SortBy != "" && !slices.Contains(itemSorts, filter.SortBy) {
+		return filter, ErrInvalidOption("sort by", itemSorts)
+	}
+	switch order := strings.ToLower(query.Get("order")); order {
+	case "", "asc":
+	case "desc":
+		filter.SortDesc = true
+	default:
+		return filter, ErrInvalidOption("order", []string{"asc", "desc"})
+	}
+
+	var err error
+	if filter.MinAmount, err = parseOptionalFloat(query.Get("min_amount"), "min amount"); err != nil {
+		return filter, err
+	}
+	if filter.MaxAmount, err = parseOptionalFloat(query.Get("max_amount"), "max amount"); err != nil {
+		return filter, err
+	}
+	if filter.MinAmount != nil && filter.MaxAmount != nil && *filter.MinAmount > *filter.MaxAmount {
+		return filter, ErrInvalidAmountRange
+	}
+	if filter.StartDate, err = parseOptionalDate(query.Get("start_date"), "start date"); err != nil {
+		return filter, err
+	}
+	if filter.EndDate, err = parseOptionalDate(query.Get("end_date"), "end date"); err != nil {
+		return filter, err
+	}
+	if filter.StartDate != nil && filter.EndDate != nil && filter.StartDate.After(*filter.EndDate) {
+		return filter, ErrInvalidDateRange
+	}
+
+	return filter, nil
+}
+
+func parseOptionalFloat(str, field string) (*float64, error) {
+	if str == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return nil, ErrInvalidNumber(field)
+	}
+
+	return &f, nil
+}
+
+func parseOptionalDate(str, field string) (*time.Time, error) {
+	if str == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(dateFormat, str)
+	if err != nil {
+		return nil, ErrInvalidDate(field)
+	}
+
+	return &date, nil
+}
+
 func isCSVExtension(filename string) bool {
-	return strings.ToLower(filename[len(filename)-4:]) == ".csv"
+	return strings.HasSuffix(strings.ToLower(filename), ".csv")
 }
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// UserIDHeader is the header of the acting user set by the gateway
+	UserIDHeader = "X-User-ID"
+	// GatewayTokenHeader is the header of the token shared with the gateway
+	GatewayTokenHeader = "X-Gateway-Token"
+)
+
+// WithGatewayAuth is a middleware to trust the acting user of a request only
+// when it is set by the gateway, the gateway authenticates the user and sends
+// the shared token along with the user, a request acting as a user without
+// the token is rejected so the user cannot be forged by calling the service
+// directly, every request acting as a user is rejected when the token is empty
+func WithGatewayAuth(handler http.Handler, token string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(UserIDHeader) != "" &&
+			(token == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get(GatewayTokenHeader)), []byte(token)) != 1) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{
+				"message": fmt.Sprintf("%s header is not set by the gateway", UserIDHeader),
+			})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,83 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/delly/amartha/handler/http/middleware"
+	"github.com/stretchr/testify/suite"
+)
+
+type GatewayAuthTestSuite struct {
+	suite.Suite
+	handler http.Handler
+}
+
+func (s *GatewayAuthTestSuite) SetupTest() {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	s.handler = middleware.WithGatewayAuth(next, "secret")
+}
+
+func TestGatewayAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(GatewayAuthTestSuite))
+}
+
+func (s *GatewayAuthTestSuite) TestWithGatewayAuth() {
+	s.Run("success user set by gateway", func() {
+		req := httptest.NewRequest(http.MethodPost, "/reconciliations/1/approve", nil)
+		req.Header.Set(middleware.UserIDHeader, "bob")
+		req.Header.Set(middleware.GatewayTokenHeader, "secret")
+		rec := httptest.NewRecorder()
+
+		s.handler.ServeHTTP(rec, req)
+
+		s.Equal(http.StatusOK, rec.Code)
+	})
+
+	s.Run("success without user", func() {
+		req := httptest.NewRequest(http.MethodGet, "/reconciliations", nil)
+		rec := httptest.NewRecorder()
+
+		s.handler.ServeHTTP(rec, req)
+
+		s.Equal(http.StatusOK, rec.Code)
+	})
+
+	s.Run("error user without token", func() {
+		req := httptest.NewRequest(http.MethodPost, "/reconciliations/1/approve", nil)
+		req.Header.Set(middleware.UserIDHeader, "bob")
+		rec := httptest.NewRecorder()
+
+		s.handler.ServeHTTP(rec, req)
+
+		s.Equal(http.StatusUnauthorized, rec.Code)
+		s.JSONEq(`{"message":"X-User-ID header is not set by the gateway"}`, rec.Body.String())
+	})
+
+	s.Run("error user with wrong token", func() {
+		req := httptest.NewRequest(http.MethodPost, "/reconciliations/1/approve", nil)
+		req.Header.Set(middleware.UserIDHeader, "bob")
+		req.Header.Set(middleware.GatewayTokenHeader, "guess")
+		rec := httptest.NewRecorder()
+
+		s.handler.ServeHTTP(rec, req)
+
+		s.Equal(http.StatusUnauthorized, rec.Code)
+	})
+
+	s.Run("error user without token configured", func() {
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/reconciliations/1/approve", nil)
+		req.Header.Set(middleware.UserIDHeader, "bob")
+		rec := httptest.NewRecorder()
+
+		middleware.WithGatewayAuth(next, "").ServeHTTP(rec, req)
+
+		s.Equal(http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -0,0 +1,181 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+type manualMatchRequest struct {
+	SystemItemID int64  `json:"system_item_id"`
+	BankItemID   int64  `json:"bank_item_id"`
+	ReasonCode   string `json:"reason_code"`
+	Note         string `json:"note"`
+}
+
+type resolutionRequest struct {
+	ItemID     int64  `json:"item_id"`
+	ReasonCode string `json:"reason_code"`
+	Note       string `json:"note"`
+}
+
+// ManualMatchReconciliationItems pair a missing system item with a missing
+// bank item of a successful reconciliation job
+func (h *ReconciliationJobHandler) ManualMatchReconciliationItems(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "ManualMatchReconciliationItems")
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	var req manualMatchRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		log.Error("failed to decode manual match request", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	if req.SystemItemID == 0 {
+		writeBadRequest(w, ErrFieldRequired("system_item_id").Error())
+		return
+	}
+	if req.BankItemID == 0 {
+		writeBadRequest(w, ErrFieldRequired("bank_item_id").Error())
+		return
+	}
+	reasonCode := entity.ResolutionReasonCode(strings.ToUpper(strings.TrimSpace(req.ReasonCode)))
+	if reasonCode != "" && !slices.Contains(entity.ResolutionReasonCodes, reasonCode) {
+		writeBadRequest(w, ErrInvalidOption("reason_code", entity.ResolutionReasonCodes).Error())
+		return
+	}
+	rj, ok := h.findReconciliationJob(w, r, p, log)
+	if !ok {
+		return
+	}
+
+	adjustment, err := h.adjusterService.ManualMatch(r.Context(), rj, &reconciliatonjob.ManualMatchParams{
+		SystemItemID: req.SystemItemID,
+		BankItemID:   req.BankItemID,
+		ReasonCode:   reasonCode,
+		Note:         strings.TrimSpace(req.Note),
+		CreatedBy:    userID,
+	})
+	if err != nil {
+		h.writeAdjustmentError(w, log, err, rj.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, adjustment, nil)
+}
+
+// ResolveReconciliationItem mark a missing item of a successful
+// reconciliation job as resolved with a reason code
+func (h *ReconciliationJobHandler) ResolveReconciliationItem(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "ResolveReconciliationItem")
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	var req resolutionRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		log.Error("failed to decode resolution request", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	if req.ItemID == 0 {
+		writeBadRequest(w, ErrFieldRequired("item_id").Error())
+		return
+	}
+	reasonCode := entity.ResolutionReasonCode(strings.ToUpper(strings.TrimSpace(req.ReasonCode)))
+	if !slices.Contains(entity.ResolutionReasonCodes, reasonCode) {
+		writeBadRequest(w, ErrInvalidOption("reason_code", entity.ResolutionReasonCodes).Error())
+		return
+	}
+	rj, ok := h.findReconciliationJob(w, r, p, log)
+	if !ok {
+		return
+	}
+
+	adjustment, err := h.adjusterService.Resolve(r.Context(), rj, &reconciliatonjob.ResolveParams{
+		ItemID:     req.ItemID,
+		ReasonCode: reasonCode,
+		Note:       strings.TrimSpace(req.Note),
+		CreatedBy:  userID,
+	})
+	if err != nil {
+		h.writeAdjustmentError(w, log, err, rj.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, adjustment, nil)
+}
+
+// GetReconciliationAdjustments get the manual adjustments of a reconciliation job
+func (h *ReconciliationJobHandler) GetReconciliationAdjustments(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "GetReconciliationAdjustments")
+	rj, ok := h.findReconciliationJob(w, r, p, log)
+	if !ok {
+		return
+	}
+
+	adjustments, err := h.adjusterService.FindAdjustments(r.Context(), rj.ID)
+	if err != nil {
+		log.Error("failed to get reconciliation adjustments", zap.Error(err), zap.Int64("id", rj.ID))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, adjustments, nil)
+}
+
+// findReconciliationJob find the reconciliation job of the id param, the
+// error response is written when it returns false
+func (h *ReconciliationJobHandler) findReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params, log *zap.Logger) (*entity.ReconciliationJob, bool) {
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return nil, false
+	}
+
+	rj, err := h.finderService.FindByID(r.Context(), id)
+	if err != nil {
+		log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return nil, false
+	}
+	if rj == nil {
+		log.Error("reconciliation job not found", zap.Int64("id", id))
+		writeNotFound(w, "reconciliation job not found")
+		return nil, false
+	}
+
+	return rj, true
+}
+
+func (h *ReconciliationJobHandler) writeAdjustmentError(w http.ResponseWriter, log *zap.Logger, err error, jobID int64) {
+	switch {
+	case errors.Is(err, reconciliatonjob.ErrReconciliationItemNotFound):
+		writeNotFound(w, err.Error())
+	case errors.Is(err, reconciliatonjob.ErrInvalidManualMatch),
+		errors.Is(err, reconciliatonjob.ErrManualMatchTypeMismatch),
+		errors.Is(err, reconciliatonjob.ErrManualMatchReasonRequired):
+		writeBadRequest(w, err.Error())
+	case errors.Is(err, reconciliatonjob.ErrReconciliationJobNotSuccess),
+		errors.Is(err, reconciliatonjob.ErrReconciliationItemNotAdjustable),
+		errors.Is(err, reconciliatonjob.ErrReconciliationJobAwaitingApproval),
+		errors.Is(err, reconciliatonjob.ErrPeriodLocked):
+		writeConflict(w, err.Error())
+	default:
+		log.Error("failed to adjust reconciliation items", zap.Error(err), zap.Int64("id", jobID))
+		writeInternalServerError(w)
+	}
+}
@@ -0,0 +1,176 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+type approvalRequest struct {
+	Comment string `json:"comment"`
+}
+
+type unlockPeriodRequest struct {
+	Reason string `json:"reason"`
+}
+
+// SubmitReconciliationJob submit a successful reconciliation job for approval
+func (h *ReconciliationJobHandler) SubmitReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	h.reviewReconciliationJob(w, r, p, "SubmitReconciliationJob", http.StatusCreated, h.approverService.Submit)
+}
+
+// ApproveReconciliationJob approve a submitted reconciliation job and lock its
+// period for every bank of the job
+func (h *ReconciliationJobHandler) ApproveReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	h.reviewReconciliationJob(w, r, p, "ApproveReconciliationJob", http.StatusOK, h.approverService.Approve)
+}
+
+// RejectReconciliationJob reject a submitted reconciliation job with a comment
+func (h *ReconciliationJobHandler) RejectReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	h.reviewReconciliationJob(w, r, p, "RejectReconciliationJob", http.StatusOK, h.approverService.Reject)
+}
+
+// GetReconciliationApprovals get the approval history of a reconciliation job
+func (h *ReconciliationJobHandler) GetReconciliationApprovals(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "GetReconciliationApprovals")
+	rj, ok := h.findReconciliationJob(w, r, p, log)
+	if !ok {
+		return
+	}
+
+	approvals, err := h.approverService.FindApprovals(r.Context(), rj.ID)
+	if err != nil {
+		log.Error("failed to get reconciliation approvals", zap.Error(err), zap.Int64("id", rj.ID))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, approvals, nil)
+}
+
+// GetReconciliationPeriodLocks get the period locks filtered by bank name,
+// only the active locks are returned when active is true
+func (h *ReconciliationJobHandler) GetReconciliationPeriodLocks(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	log := logger.WithMethod(h.log, "GetReconciliationPeriodLocks")
+	filter := reconciliatonjob.PeriodLockFilter{
+		BankName: strings.TrimSpace(r.URL.Query().Get("bank_name")),
+	}
+	if active := r.URL.Query().Get("active"); active != "" {
+		activeOnly, err := strconv.ParseBool(active)
+		if err != nil {
+			writeBadRequest(w, ErrInvalidOption("active", []string{"true", "false"}).Error())
+			return
+		}
+		filter.ActiveOnly = activeOnly
+	}
+
+	locks, err := h.approverService.FindPeriodLocks(r.Context(), filter)
+	if err != nil {
+		log.Error("failed to get reconciliation period locks", zap.Error(err))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, locks, nil)
+}
+
+// UnlockReconciliationPeriod unlock a locked period with a reason so new jobs
+// and manual matches of the period are allowed again
+func (h *ReconciliationJobHandler) UnlockReconciliationPeriod(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "UnlockReconciliationPeriod")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	var req unlockPeriodRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		log.Error("failed to decode unlock period request", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		writeBadRequest(w, ErrFieldRequired("reason").Error())
+		return
+	}
+
+	lock, err := h.approverService.Unlock(r.Context(), &reconciliatonjob.UnlockParams{
+		LockID:     id,
+		Reason:     reason,
+		UnlockedBy: userID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, reconciliatonjob.ErrPeriodLockNotFound):
+			writeNotFound(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrPeriodLockNotActive):
+			writeConflict(w, err.Error())
+		default:
+			log.Error("failed to unlock reconciliation period", zap.Error(err), zap.Int64("id", id))
+			writeInternalServerError(w)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, lock, nil)
+}
+
+// reviewReconciliationJob run one of the approval actions on the reconciliation
+// job of the id param on behalf of the user
+func (h *ReconciliationJobHandler) reviewReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params, method string, statusCode int,
+	review func(ctx context.Context, job *entity.ReconciliationJob, params *reconciliatonjob.ApprovalParams) (*entity.ReconciliationApproval, error)) {
+	log := logger.WithMethod(h.log, method)
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	var req approvalRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		log.Error("failed to decode approval request", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	rj, ok := h.findReconciliationJob(w, r, p, log)
+	if !ok {
+		return
+	}
+
+	approval, err := review(r.Context(), rj, &reconciliatonjob.ApprovalParams{
+		Comment:   strings.TrimSpace(req.Comment),
+		CreatedBy: userID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, reconciliatonjob.ErrApprovalCommentRequired):
+			writeBadRequest(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrSelfApproval):
+			writeForbidden(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrReconciliationJobNotSuccess),
+			errors.Is(err, reconciliatonjob.ErrInvalidApprovalTransition),
+			errors.Is(err, reconciliatonjob.ErrPeriodLocked):
+			writeConflict(w, err.Error())
+		default:
+			log.Error("failed to review reconciliation job", zap.Error(err), zap.Int64("id", rj.ID))
+			writeInternalServerError(w)
+		}
+		return
+	}
+
+	writeJSON(w, statusCode, approval, nil)
+}
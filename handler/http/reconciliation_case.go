@@ -0,0 +1,287 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	"github.com/delly/amartha/handler/http/middleware"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+type openCaseRequest struct {
+	ItemID   int64  `json:"item_id"`
+	Assignee string `json:"assignee"`
+	DueDate  string `json:"due_date"`
+	Comment  string `json:"comment"`
+}
+
+type changeCaseStatusRequest struct {
+	Status     string `json:"status"`
+	ReasonCode string `json:"reason_code"`
+	Comment    string `json:"comment"`
+}
+
+type caseCommentRequest struct {
+	Comment string `json:"comment"`
+}
+
+// ReconciliationCaseHandler is a handler for the exception cases of the unmatched items
+type ReconciliationCaseHandler struct {
+	caseService reconciliatonjob.CaseManager
+	log         *zap.Logger
+}
+
+// NewReconciliationCaseHandler create new reconciliation case handler, it used to open a case on an unmatched item, get a case, list the cases of the user, change the status of a case and comment on it
+func NewReconciliationCaseHandler(caseService reconciliatonjob.CaseManager) *ReconciliationCaseHandler {
+	return &ReconciliationCaseHandler{
+		caseService: caseService,
+		log:         zap.L().With(zap.String("handler", "reconciliation_case")),
+	}
+}
+
+// Register register reconciliation case handler to router
+func (h *ReconciliationCaseHandler) Register(router *httprouter.Router) {
+	router.GET("/me/reconciliation-cases", middleware.PrependMiddleware(h.GetMyReconciliationCases, middleware.WithLogger))
+	router.GET("/reconciliation-cases/:id", middleware.PrependMiddleware(h.GetReconciliationCaseByID, middleware.WithLogger))
+	router.POST("/reconciliations/:id/cases", middleware.PrependMiddleware(h.OpenReconciliationCase, middleware.WithLogger))
+	router.POST("/reconciliation-cases/:id/status", middleware.PrependMiddleware(h.ChangeReconciliationCaseStatus, middleware.WithLogger))
+	router.POST("/reconciliation-cases/:id/comments", middleware.PrependMiddleware(h.CommentReconciliationCase, middleware.WithLogger))
+}
+
+// OpenReconciliationCase open a case on a missing item of a reconciliation
+// job, the case is assigned to the user opening it when assignee is empty
+func (h *ReconciliationCaseHandler) OpenReconciliationCase(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "OpenReconciliationCase")
+	jobID, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	var req openCaseRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		log.Error("failed to decode open case request", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	if req.ItemID == 0 {
+		writeBadRequest(w, ErrFieldRequired("item_id").Error())
+		return
+	}
+	dueDate, err := parseOptionalDate(strings.TrimSpace(req.DueDate), "due_date")
+	if err != nil {
+		writeBadRequest(w, err.Error())
+		return
+	}
+	assignee := strings.TrimSpace(req.Assignee)
+	if assignee == "" {
+		assignee = userID
+	}
+
+	rc, err := h.caseService.Open(r.Context(), &reconciliatonjob.OpenCaseParams{
+		JobID:     jobID,
+		ItemID:    req.ItemID,
+		Assignee:  assignee,
+		DueDate:   dueDate,
+		Comment:   strings.TrimSpace(req.Comment),
+		CreatedBy: userID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, reconciliatonjob.ErrReconciliationItemNotFound):
+			writeNotFound(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrReconciliationItemNotOpen),
+			errors.Is(err, reconciliatonjob.ErrReconciliationCaseExists):
+			writeConflict(w, err.Error())
+		default:
+			log.Error("failed to open reconciliation case", zap.Error(err), zap.Int64("job_id", jobID), zap.Int64("item_id", req.ItemID))
+			writeInternalServerError(w)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, rc, nil)
+}
+
+// GetReconciliationCaseByID get a reconciliation case along with its comments
+func (h *ReconciliationCaseHandler) GetReconciliationCaseByID(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "GetReconciliationCaseByID")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+
+	rc, err := h.caseService.FindByID(r.Context(), id)
+	if err != nil {
+		if errors.Is(err, reconciliatonjob.ErrReconciliationCaseNotFound) {
+			writeNotFound(w, err.Error())
+			return
+		}
+		log.Error("failed to get reconciliation case by id", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rc, nil)
+}
+
+// GetMyReconciliationCases get the reconciliation cases assigned to the user
+// filtered by status and paginated
+func (h *ReconciliationCaseHandler) GetMyReconciliationCases(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	log := logger.WithMethod(h.log, "GetMyReconciliationCases")
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	filter := reconciliatonjob.CaseFilter{
+		Assignee: userID,
+		Status:   entity.ReconciliationCaseStatus(strings.ToUpper(r.URL.Query().Get("status"))),
+	}
+	if filter.Status != "" && !slices.Contains(entity.ReconciliationCaseStatuses, filter.Status) {
+		writeBadRequest(w, ErrInvalidOption("status", entity.ReconciliationCaseStatuses).Error())
+		return
+	}
+
+	pagination := getPagination(r)
+	total, err := h.caseService.CountByAssignee(r.Context(), filter)
+	if err != nil {
+		log.Error("failed to count reconciliation cases", zap.Error(err))
+		writeInternalServerError(w)
+		return
+	}
+	pagination.Total = int32(total)
+	if total == 0 {
+		writeJSON(w, http.StatusOK, []*entity.ReconciliationCase{}, pagination)
+		return
+	}
+
+	cases, err := h.caseService.FindByAssignee(r.Context(), filter, pagination.Limit, pagination.Offset)
+	if err != nil {
+		log.Error("failed to list reconciliation cases", zap.Error(err))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cases, pagination)
+}
+
+// ChangeReconciliationCaseStatus change the status of a reconciliation case
+func (h *ReconciliationCaseHandler) ChangeReconciliationCaseStatus(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "ChangeReconciliationCaseStatus")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	var req changeCaseStatusRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		log.Error("failed to decode change case status request", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	status := entity.ReconciliationCaseStatus(strings.ToUpper(strings.TrimSpace(req.Status)))
+	if !slices.Contains(entity.ReconciliationCaseStatuses, status) {
+		writeBadRequest(w, ErrInvalidOption("status", entity.ReconciliationCaseStatuses).Error())
+		return
+	}
+	reasonCode := entity.ResolutionReasonCode(strings.ToUpper(strings.TrimSpace(req.ReasonCode)))
+	if reasonCode != "" && !slices.Contains(entity.ResolutionReasonCodes, reasonCode) {
+		writeBadRequest(w, ErrInvalidOption("reason_code", entity.ResolutionReasonCodes).Error())
+		return
+	}
+
+	rc, err := h.caseService.ChangeStatus(r.Context(), &reconciliatonjob.ChangeCaseStatusParams{
+		CaseID:     id,
+		Status:     status,
+		ReasonCode: reasonCode,
+		Comment:    strings.TrimSpace(req.Comment),
+		ChangedBy:  userID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, reconciliatonjob.ErrReconciliationCaseNotFound):
+			writeNotFound(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrCaseCommentRequired):
+			writeBadRequest(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrCaseStatusForbidden):
+			writeForbidden(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrInvalidCaseStatusTransition),
+			errors.Is(err, reconciliatonjob.ErrReconciliationJobNotSuccess),
+			errors.Is(err, reconciliatonjob.ErrReconciliationJobAwaitingApproval),
+			errors.Is(err, reconciliatonjob.ErrPeriodLocked),
+			errors.Is(err, reconciliatonjob.ErrReconciliationItemNotAdjustable):
+			writeConflict(w, err.Error())
+		default:
+			log.Error("failed to change reconciliation case status", zap.Error(err), zap.Int64("id", id))
+			writeInternalServerError(w)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rc, nil)
+}
+
+// CommentReconciliationCase add a comment to a reconciliation case
+func (h *ReconciliationCaseHandler) CommentReconciliationCase(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "CommentReconciliationCase")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+	userID, err := getUserID(r)
+	if err != nil {
+		writeUnauthorized(w, err.Error())
+		return
+	}
+	var req caseCommentRequest
+	if err := decodeJSONBody(r, &req); err != nil {
+		log.Error("failed to decode case comment request", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	comment := strings.TrimSpace(req.Comment)
+	if comment == "" {
+		writeBadRequest(w, ErrFieldRequired("comment").Error())
+		return
+	}
+
+	res, err := h.caseService.AddComment(r.Context(), &reconciliatonjob.AddCaseCommentParams{
+		CaseID:    id,
+		Comment:   comment,
+		CreatedBy: userID,
+	})
+	if err != nil {
+		if errors.Is(err, reconciliatonjob.ErrReconciliationCaseNotFound) {
+			writeNotFound(w, err.Error())
+			return
+		}
+		log.Error("failed to comment reconciliation case", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, res, nil)
+}
@@ -0,0 +1,355 @@
+package http_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/delly/amartha/entity"
+	handler "github.com/delly/amartha/handler/http"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/julienschmidt/httprouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+var entityReconCase = &entity.ReconciliationCase{
+	ID:       1,
+	ItemID:   10,
+	JobID:    id,
+	Source:   entity.ReconciliationItemSourceBank,
+	BankName: "BCA",
+	Transaction: entity.Transaction{
+		ID:     "BCA-1",
+		Amount: 1000,
+		Type:   entity.TxTypeCredit,
+		Time:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	},
+	Assignee:  "alice",
+	Status:    entity.ReconciliationCaseStatusOpen,
+	CreatedBy: "bob",
+	CreatedAt: now,
+	UpdatedAt: now,
+}
+
+type ReconciliationCaseHandlerTestSuite struct {
+	suite.Suite
+	router          *httprouter.Router
+	mockCaseService *mock_reconciliatonjob.MockCaseManager
+	handler         *handler.ReconciliationCaseHandler
+}
+
+func (s *ReconciliationCaseHandlerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.mockCaseService = mock_reconciliatonjob.NewMockCaseManager(ctrl)
+	s.handler = handler.NewReconciliationCaseHandler(s.mockCaseService)
+
+	s.router = httprouter.New()
+	s.handler.Register(s.router)
+}
+
+func TestReconciliationCaseHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReconciliationCaseHandlerTestSuite))
+}
+
+func (s *ReconciliationCaseHandlerTestSuite) TestOpenReconciliationCase() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/cases", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "bob")
+		return req
+	}
+	dueDate := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	body := `{"item_id": 10, "assignee": "alice", "due_date": "2024-01-15", "comment": "please check"}`
+	params := &reconciliatonjob.OpenCaseParams{JobID: id, ItemID: 10, Assignee: "alice", DueDate: &dueDate, Comment: "please check", CreatedBy: "bob"}
+
+	s.Run("success", func() {
+		s.mockCaseService.EXPECT().Open(ctx, params).Return(entityReconCase, nil)
+
+		resp := s.executeReq(newReq(body))
+
+		jsonCase, _ := json.Marshal(entityReconCase)
+		s.Equal(http.StatusCreated, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonCase))
+	})
+
+	s.Run("assigned to the user by default", func() {
+		s.mockCaseService.EXPECT().Open(ctx, &reconciliatonjob.OpenCaseParams{JobID: id, ItemID: 10, Assignee: "bob", CreatedBy: "bob"}).Return(entityReconCase, nil)
+
+		resp := s.executeReq(newReq(`{"item_id": 10}`))
+
+		s.Equal(http.StatusCreated, resp.Code)
+	})
+
+	s.Run("user is required", func() {
+		req := newReq(body)
+		req.Header.Del("X-User-ID")
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusUnauthorized, resp.Code)
+	})
+
+	s.Run("invalid due date", func() {
+		resp := s.executeReq(newReq(`{"item_id": 10, "due_date": "15-01-2024"}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "due_date must be in YYYY-MM-DD format")
+	})
+
+	s.Run("item is required", func() {
+		resp := s.executeReq(newReq(`{"assignee": "alice"}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "item_id is required")
+	})
+
+	s.Run("item not found", func() {
+		s.mockCaseService.EXPECT().Open(ctx, params).Return(nil, reconciliatonjob.ErrReconciliationItemNotFound)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+
+	s.Run("case exists", func() {
+		s.mockCaseService.EXPECT().Open(ctx, params).Return(nil, reconciliatonjob.ErrReconciliationCaseExists)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("internal server error", func() {
+		s.mockCaseService.EXPECT().Open(ctx, params).Return(nil, assert.AnError)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationCaseHandlerTestSuite) TestGetReconciliationCaseByID() {
+	ctx := context.Background()
+
+	req, _ := http.NewRequest(http.MethodGet, "/reconciliation-cases/1", nil)
+	s.Run("success", func() {
+		s.mockCaseService.EXPECT().FindByID(ctx, int64(1)).Return(entityReconCase, nil)
+
+		resp := s.executeReq(req)
+
+		jsonCase, _ := json.Marshal(entityReconCase)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonCase))
+	})
+
+	s.Run("not found", func() {
+		s.mockCaseService.EXPECT().FindByID(ctx, int64(1)).Return(nil, reconciliatonjob.ErrReconciliationCaseNotFound)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+}
+
+func (s *ReconciliationCaseHandlerTestSuite) TestGetMyReconciliationCases() {
+	ctx := context.Background()
+	newReq := func(query string) *http.Request {
+		req, _ := http.NewRequest(http.MethodGet, "/me/reconciliation-cases"+query, nil)
+		req.Header.Set("X-User-ID", "alice")
+		return req
+	}
+	filter := reconciliatonjob.CaseFilter{Assignee: "alice", Status: entity.ReconciliationCaseStatusOpen}
+
+	s.Run("success", func() {
+		s.mockCaseService.EXPECT().CountByAssignee(ctx, filter).Return(int64(1), nil)
+		s.mockCaseService.EXPECT().FindByAssignee(ctx, filter, int32(10), int32(0)).Return([]*entity.ReconciliationCase{entityReconCase}, nil)
+
+		resp := s.executeReq(newReq("?status=open"))
+
+		jsonCases, _ := json.Marshal([]*entity.ReconciliationCase{entityReconCase})
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonCases))
+		s.Contains(resp.Body.String(), `"total":1`)
+	})
+
+	s.Run("no data", func() {
+		s.mockCaseService.EXPECT().CountByAssignee(ctx, reconciliatonjob.CaseFilter{Assignee: "alice"}).Return(int64(0), nil)
+
+		resp := s.executeReq(newReq(""))
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), "[]")
+	})
+
+	s.Run("user is required", func() {
+		req := newReq("")
+		req.Header.Del("X-User-ID")
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusUnauthorized, resp.Code)
+	})
+
+	s.Run("invalid status", func() {
+		resp := s.executeReq(newReq("?status=closed"))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("internal server error", func() {
+		s.mockCaseService.EXPECT().CountByAssignee(ctx, filter).Return(int64(0), assert.AnError)
+
+		resp := s.executeReq(newReq("?status=OPEN"))
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationCaseHandlerTestSuite) TestChangeReconciliationCaseStatus() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliation-cases/1/status", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "alice")
+		return req
+	}
+	body := `{"status": "written_off", "comment": "immaterial"}`
+	params := &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusWrittenOff, Comment: "immaterial", ChangedBy: "alice"}
+
+	s.Run("success", func() {
+		rc := *entityReconCase
+		rc.Status = entity.ReconciliationCaseStatusWrittenOff
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, params).Return(&rc, nil)
+
+		resp := s.executeReq(newReq(body))
+
+		jsonCase, _ := json.Marshal(&rc)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonCase))
+	})
+
+	s.Run("invalid status", func() {
+		resp := s.executeReq(newReq(`{"status": "CLOSED"}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "status must be one of")
+	})
+
+	s.Run("success resolve with reason code", func() {
+		resolve := &reconciliatonjob.ChangeCaseStatusParams{
+			CaseID:     1,
+			Status:     entity.ReconciliationCaseStatusResolved,
+			ReasonCode: entity.ResolutionReasonBankError,
+			Comment:    "bank corrected it",
+			ChangedBy:  "alice",
+		}
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, resolve).Return(entityReconCase, nil)
+
+		resp := s.executeReq(newReq(`{"status": "resolved", "reason_code": "bank_error", "comment": "bank corrected it"}`))
+
+		s.Equal(http.StatusOK, resp.Code)
+	})
+
+	s.Run("invalid reason code", func() {
+		resp := s.executeReq(newReq(`{"status": "resolved", "reason_code": "LOST", "comment": "found"}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "reason_code must be one of")
+	})
+
+	s.Run("neither assignee nor reviewer", func() {
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, params).Return(nil, reconciliatonjob.ErrCaseStatusForbidden)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusForbidden, resp.Code)
+	})
+
+	s.Run("period locked", func() {
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, params).Return(nil, reconciliatonjob.ErrPeriodLocked)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("comment required", func() {
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, params).Return(nil, reconciliatonjob.ErrCaseCommentRequired)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("not found", func() {
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, params).Return(nil, reconciliatonjob.ErrReconciliationCaseNotFound)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+
+	s.Run("invalid transition", func() {
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, params).Return(nil, reconciliatonjob.ErrInvalidCaseStatusTransition)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("internal server error", func() {
+		s.mockCaseService.EXPECT().ChangeStatus(ctx, params).Return(nil, assert.AnError)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationCaseHandlerTestSuite) TestCommentReconciliationCase() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliation-cases/1/comments", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "alice")
+		return req
+	}
+	params := &reconciliatonjob.AddCaseCommentParams{CaseID: 1, Comment: "asked the bank", CreatedBy: "alice"}
+
+	s.Run("success", func() {
+		comment := &entity.ReconciliationCaseComment{ID: 2, CaseID: 1, Comment: "asked the bank", CreatedBy: "alice", CreatedAt: now}
+		s.mockCaseService.EXPECT().AddComment(ctx, params).Return(comment, nil)
+
+		resp := s.executeReq(newReq(`{"comment": "asked the bank"}`))
+
+		jsonComment, _ := json.Marshal(comment)
+		s.Equal(http.StatusCreated, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonComment))
+	})
+
+	s.Run("comment is required", func() {
+		resp := s.executeReq(newReq(`{"comment": " "}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "comment is required")
+	})
+
+	s.Run("not found", func() {
+		s.mockCaseService.EXPECT().AddComment(ctx, params).Return(nil, reconciliatonjob.ErrReconciliationCaseNotFound)
+
+		resp := s.executeReq(newReq(`{"comment": "asked the bank"}`))
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+}
+
+func (s *ReconciliationCaseHandlerTestSuite) executeReq(req *http.Request) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	s.router.ServeHTTP(rr, req)
+	return rr
+}
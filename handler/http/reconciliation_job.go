@@ -1,12 +1,17 @@
 package http
 
 import (
-	"bytes"
+	"bufio"
+	"context"
 	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/delly/amartha/common/logger"
 	"github.com/delly/amartha/entity"
@@ -18,24 +23,51 @@ import (
 )
 
 const (
-	allowedMimeType       = "text/csv"
-	humanizeLimitFileSize = "10MB"
+	allowedMimeType          = "text/csv"
+	humanizeLimitFileSize    = "1GB"
+	humanizeLimitContentSize = "10GB"
+	// formValueLimit is the maximum size of a non file field of the uploaded form
+	formValueLimit = 1 << 20
+	// fileHeaderSize is the size of file header needed to detect the file type
+	fileHeaderSize    = 8192
+	reportContentType = "application/pdf"
 )
 
+var exportContentTypes = map[reconciliatonjob.ExportFormat]string{
+	reconciliatonjob.ExportFormatCSV:  "text/csv",
+	reconciliatonjob.ExportFormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
 // ReconciliationJobHandler is a handler for reconciliation job
 type ReconciliationJobHandler struct {
-	finderService  reconciliatonjob.Finder
-	creatorService reconciliatonjob.Creator
-	log            *zap.Logger
+	finderService   reconciliatonjob.Finder
+	creatorService  reconciliatonjob.Creator
+	cancelerService reconciliatonjob.Canceler
+	exporterService reconciliatonjob.Exporter
+	reporterService reconciliatonjob.Reporter
+	adjusterService reconciliatonjob.Adjuster
+	approverService reconciliatonjob.Approver
+	log             *zap.Logger
 }
 
-// NewReconciliationJobHandler create new reconciliation job handler, it used to create new reconciliation job, get reconciliation job by id, and get all reconciliation job
+// NewReconciliationJobHandler create new reconciliation job handler to create,
+// find, cancel, export, report, adjust and approve reconciliation job
 func NewReconciliationJobHandler(finderService reconciliatonjob.Finder,
-	creatorService reconciliatonjob.Creator) *ReconciliationJobHandler {
+	creatorService reconciliatonjob.Creator,
+	cancelerService reconciliatonjob.Canceler,
+	exporterService reconciliatonjob.Exporter,
+	reporterService reconciliatonjob.Reporter,
+	adjusterService reconciliatonjob.Adjuster,
+	approverService reconciliatonjob.Approver) *ReconciliationJobHandler {
 	return &ReconciliationJobHandler{
-		finderService:  finderService,
-		creatorService: creatorService,
-		log:            zap.L().With(zap.String("handler", "reconciliation_job")),
+		finderService:   finderService,
+		creatorService:  creatorService,
+		cancelerService: cancelerService,
+		exporterService: exporterService,
+		reporterService: reporterService,
+		adjusterService: adjusterService,
+		approverService: approverService,
+		log:             zap.L().With(zap.String("handler", "reconciliation_job")),
 	}
 }
 
@@ -43,7 +75,23 @@ func NewReconciliationJobHandler(finderService reconciliatonjob.Finder,
 func (h *ReconciliationJobHandler) Register(router *httprouter.Router) {
 	router.GET("/reconciliations", middleware.PrependMiddleware(h.GetAllReconciliationJob, middleware.WithLogger))
 	router.GET("/reconciliations/:id", middleware.PrependMiddleware(h.GetReconciliationJobByID, middleware.WithLogger))
+	router.GET("/reconciliations/:id/items", middleware.PrependMiddleware(h.GetReconciliationItems, middleware.WithLogger))
+	router.GET("/reconciliations/:id/export", middleware.PrependMiddleware(h.ExportReconciliationJob, middleware.WithLogger))
+	router.GET("/reconciliations/:id/report", middleware.PrependMiddleware(h.ReportReconciliationJob, middleware.WithLogger))
+	router.GET("/reconciliations/:id/diff/:other_id", middleware.PrependMiddleware(h.DiffReconciliationJob, middleware.WithLogger))
+	router.GET("/reconciliations/:id/adjustments", middleware.PrependMiddleware(h.GetReconciliationAdjustments, middleware.WithLogger))
+	router.GET("/reconciliations/:id/approvals", middleware.PrependMiddleware(h.GetReconciliationApprovals, middleware.WithLogger))
+	router.GET("/period-locks", middleware.PrependMiddleware(h.GetReconciliationPeriodLocks, middleware.WithLogger))
+	router.GET("/unmatched-transactions/ageing", middleware.PrependMiddleware(h.GetUnmatchedAgeing, middleware.WithLogger))
 	router.POST("/reconciliations", middleware.PrependMiddleware(h.CreateReconciliationJob, middleware.WithLogger))
+	router.POST("/reconciliations/:id/cancel", middleware.PrependMiddleware(h.CancelReconciliationJob, middleware.WithLogger))
+	router.POST("/reconciliations/:id/rerun", middleware.PrependMiddleware(h.RerunReconciliationJob, middleware.WithLogger))
+	router.POST("/reconciliations/:id/manual-matches", middleware.PrependMiddleware(h.ManualMatchReconciliationItems, middleware.WithLogger))
+	router.POST("/reconciliations/:id/resolutions", middleware.PrependMiddleware(h.ResolveReconciliationItem, middleware.WithLogger))
+	router.POST("/reconciliations/:id/submit", middleware.PrependMiddleware(h.SubmitReconciliationJob, middleware.WithLogger))
+	router.POST("/reconciliations/:id/approve", middleware.PrependMiddleware(h.ApproveReconciliationJob, middleware.WithLogger))
+	router.POST("/reconciliations/:id/reject", middleware.PrependMiddleware(h.RejectReconciliationJob, middleware.WithLogger))
+	router.POST("/period-locks/:id/unlock", middleware.PrependMiddleware(h.UnlockReconciliationPeriod, middleware.WithLogger))
 }
 
 // GetReconciliationJobByID get reconciliation job by id
@@ -71,6 +119,237 @@ func (h *ReconciliationJobHandler) GetReconciliationJobByID(w http.ResponseWrite
 	writeJSON(w, http.StatusOK, rj, nil)
 }
 
+// GetReconciliationItems get the items of a reconciliation job filtered, sorted and paginated
+func (h *ReconciliationJobHandler) GetReconciliationItems(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "GetReconciliationItems")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+
+	filter, err := parseItemFilter(r)
+	if err != nil {
+		log.Error("failed to parse item filter", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	filter.JobID = id
+
+	rj, err := h.finderService.FindByID(r.Context(), id)
+	if err != nil {
+		log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return
+	}
+	if rj == nil {
+		log.Error("reconciliation job not found", zap.Int64("id", id))
+		writeNotFound(w, "reconciliation job not found")
+		return
+	}
+
+	pagination := getPagination(r)
+	total, err := h.finderService.CountItems(r.Context(), filter)
+	if err != nil {
+		log.Error("failed to count reconciliation items", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return
+	}
+	pagination.Total = int32(total)
+	if total == 0 {
+		writeJSON(w, http.StatusOK, []*entity.ReconciliationItem{}, pagination)
+		return
+	}
+
+	items, err := h.finderService.FindItems(r.Context(), filter, pagination.Limit, pagination.Offset)
+	if err != nil {
+		log.Error("failed to list reconciliation items", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, items, pagination)
+}
+
+// ExportReconciliationJob download the result of a successful reconciliation job as CSV or XLSX
+func (h *ReconciliationJobHandler) ExportReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "ExportReconciliationJob")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+
+	format := reconciliatonjob.ExportFormat(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		format = reconciliatonjob.ExportFormatCSV
+	}
+	contentType, ok := exportContentTypes[format]
+	if !ok {
+		writeBadRequest(w, ErrInvalidOption("format", []reconciliatonjob.ExportFormat{reconciliatonjob.ExportFormatCSV, reconciliatonjob.ExportFormatXLSX}).Error())
+		return
+	}
+
+	rj, err := h.finderService.FindByID(r.Context(), id)
+	if err != nil {
+		log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return
+	}
+	if rj == nil {
+		log.Error("reconciliation job not found", zap.Int64("id", id))
+		writeNotFound(w, "reconciliation job not found")
+		return
+	}
+
+	aw := newAttachmentWriter(w, contentType, fmt.Sprintf("reconciliation_%d.%s", id, format))
+	if err := h.exporterService.Export(r.Context(), rj, format, aw); err != nil {
+		// the response can not be changed once the file is partially sent
+		if aw.written {
+			log.Error("failed to stream reconciliation job export", zap.Error(err), zap.Int64("id", id))
+			return
+		}
+		if errors.Is(err, reconciliatonjob.ErrReconciliationJobNotSuccess) {
+			writeConflict(w, err.Error())
+			return
+		}
+		log.Error("failed to export reconciliation job", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+	}
+}
+
+// ReportReconciliationJob download the printable PDF report of a successful reconciliation job
+func (h *ReconciliationJobHandler) ReportReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "ReportReconciliationJob")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+
+	rj, err := h.finderService.FindByID(r.Context(), id)
+	if err != nil {
+		log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+		return
+	}
+	if rj == nil {
+		log.Error("reconciliation job not found", zap.Int64("id", id))
+		writeNotFound(w, "reconciliation job not found")
+		return
+	}
+
+	aw := newAttachmentWriter(w, reportContentType, fmt.Sprintf("reconciliation_%d.pdf", id))
+	if err := h.reporterService.Report(r.Context(), rj, aw); err != nil {
+		if aw.written {
+			log.Error("failed to send reconciliation job report", zap.Error(err), zap.Int64("id", id))
+			return
+		}
+		if errors.Is(err, reconciliatonjob.ErrReconciliationJobNotSuccess) {
+			writeConflict(w, err.Error())
+			return
+		}
+		log.Error("failed to report reconciliation job", zap.Error(err), zap.Int64("id", id))
+		writeInternalServerError(w)
+	}
+}
+
+// DiffReconciliationJob compare the result of a reconciliation job with the other job
+func (h *ReconciliationJobHandler) DiffReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "DiffReconciliationJob")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+	otherID, err := strconv.ParseInt(p.ByName("other_id"), 10, 64)
+	if err != nil {
+		log.Error("invalid other id", zap.String("other_id", p.ByName("other_id")), zap.Error(err))
+		writeBadRequest(w, "invalid other id")
+		return
+	}
+
+	jobs := make([]*entity.ReconciliationJob, 0, 2)
+	for _, jobID := range []int64{id, otherID} {
+		rj, err := h.finderService.FindByID(r.Context(), jobID)
+		if err != nil {
+			log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("id", jobID))
+			writeInternalServerError(w)
+			return
+		}
+		if rj == nil {
+			log.Error("reconciliation job not found", zap.Int64("id", jobID))
+			writeNotFound(w, fmt.Sprintf("reconciliation job %d not found", jobID))
+			return
+		}
+		jobs = append(jobs, rj)
+	}
+
+	pagination := getPagination(r)
+	total, err := h.finderService.CountDiff(r.Context(), jobs[0], jobs[1])
+	if err != nil {
+		if errors.Is(err, reconciliatonjob.ErrReconciliationJobNotSuccess) {
+			writeConflict(w, err.Error())
+			return
+		}
+		log.Error("failed to count reconciliation job diff", zap.Error(err), zap.Int64("id", id), zap.Int64("other_id", otherID))
+		writeInternalServerError(w)
+		return
+	}
+	pagination.Total = int32(total)
+	if total == 0 {
+		writeJSON(w, http.StatusOK, &entity.ReconciliationDiff{
+			JobID:          id,
+			OtherJobID:     otherID,
+			NewlyMatched:   []entity.ReconciliationDiffItem{},
+			NewlyUnmatched: []entity.ReconciliationDiffItem{},
+			StatusChanges:  []entity.ReconciliationDiffItem{},
+			Added:          []entity.ReconciliationDiffItem{},
+			Removed:        []entity.ReconciliationDiffItem{},
+			AmountChanges:  []entity.ReconciliationDiffItem{},
+		}, pagination)
+		return
+	}
+
+	diff, err := h.finderService.Diff(r.Context(), jobs[0], jobs[1], pagination.Limit, pagination.Offset)
+	if err != nil {
+		log.Error("failed to diff reconciliation job", zap.Error(err), zap.Int64("id", id), zap.Int64("other_id", otherID))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, diff, pagination)
+}
+
+// GetUnmatchedAgeing get the unresolved unmatched transactions of every job
+// grouped into ageing buckets as of a date, today by default
+func (h *ReconciliationJobHandler) GetUnmatchedAgeing(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	log := logger.WithMethod(h.log, "GetUnmatchedAgeing")
+	asOf, err := parseOptionalDate(r.URL.Query().Get("as_of"), "as of")
+	if err != nil {
+		log.Error("failed to parse as of date", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	if asOf == nil {
+		today, _ := time.Parse(dateFormat, time.Now().Format(dateFormat))
+		asOf = &today
+	}
+
+	ageing, err := h.finderService.Ageing(r.Context(), *asOf)
+	if err != nil {
+		log.Error("failed to get unmatched ageing", zap.Error(err))
+		writeInternalServerError(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ageing, nil)
+}
+
 // GetAllReconciliationJob get all reconciliation job
 func (h *ReconciliationJobHandler) GetAllReconciliationJob(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	log := logger.WithMethod(h.log, "GetAllReconciliationJob")
@@ -100,8 +379,16 @@ func (h *ReconciliationJobHandler) GetAllReconciliationJob(w http.ResponseWriter
 // CreateReconciliationJob create new reconciliation job
 func (h *ReconciliationJobHandler) CreateReconciliationJob(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	log := logger.WithMethod(h.log, "CreateReconciliationJob")
-	params, err := h.parseCreateReconciliationJobParams(r)
-	if err != nil {
+	// the uploaded files are stored as they are read, so they are discarded
+	// when the job is not created
+	params := &reconciliatonjob.CreateParams{}
+	if err := h.parseCreateReconciliationJobParams(w, r, params); err != nil {
+		h.creatorService.DiscardFiles(context.WithoutCancel(r.Context()), params)
+		if errors.Is(err, ErrStoreUploadedFile) {
+			log.Error("failed to store uploaded file", zap.Error(err))
+			writeInternalServerError(w)
+			return
+		}
 		log.Error("failed to parse create reconciliation job params", zap.Error(err))
 		writeBadRequest(w, err.Error())
 		return
@@ -109,136 +396,321 @@ func (h *ReconciliationJobHandler) CreateReconciliationJob(w http.ResponseWriter
 
 	rj, err := h.creatorService.Create(r.Context(), params)
 	if err != nil {
-		log.Error("failed to create reconciliation job", zap.Error(err))
-		writeInternalServerError(w)
+		h.creatorService.DiscardFiles(context.WithoutCancel(r.Context()), params)
+		switch {
+		case errors.Is(err, reconciliatonjob.ErrInvalidMatchingOptions):
+			writeBadRequest(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrPeriodLocked):
+			writeConflict(w, err.Error())
+		default:
+			log.Error("failed to create reconciliation job", zap.Error(err))
+			writeInternalServerError(w)
+		}
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, rj, nil)
 }
 
-func (h *ReconciliationJobHandler) parseCreateReconciliationJobParams(r *http.Request) (*reconciliatonjob.CreateParams, error) {
-	params, err := h.buildFileParams(r)
+// CancelReconciliationJob cancel pending or processing reconciliation job
+func (h *ReconciliationJobHandler) CancelReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "CancelReconciliationJob")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
 	if err != nil {
-		return nil, err
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
 	}
 
-	startDate := parseDate(r.FormValue("start_date"))
-	endDate := parseDate(r.FormValue("end_date"))
+	rj, err := h.cancelerService.Cancel(r.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, reconciliatonjob.ErrReconciliationJobNotFound):
+			writeNotFound(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrReconciliationJobNotCancellable):
+			writeConflict(w, err.Error())
+		default:
+			log.Error("failed to cancel reconciliation job", zap.Error(err), zap.Int64("id", id))
+			writeInternalServerError(w)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rj, nil)
+}
+
+// RerunReconciliationJob create new reconciliation job using the uploaded files of an existing job
+func (h *ReconciliationJobHandler) RerunReconciliationJob(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log := logger.WithMethod(h.log, "RerunReconciliationJob")
+	id, err := strconv.ParseInt(p.ByName("id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", zap.String("id", p.ByName("id")), zap.Error(err))
+		writeBadRequest(w, "invalid id")
+		return
+	}
+
+	params, err := h.parseRerunReconciliationJobParams(r)
+	if err != nil {
+		log.Error("failed to parse rerun reconciliation job params", zap.Error(err))
+		writeBadRequest(w, err.Error())
+		return
+	}
+	params.JobID = id
+
+	rj, err := h.creatorService.Rerun(r.Context(), params)
+	if err != nil {
+		switch {
+		case errors.Is(err, reconciliatonjob.ErrReconciliationJobNotFound):
+			writeNotFound(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrInvalidDateRange),
+			errors.Is(err, reconciliatonjob.ErrInvalidMatchingOptions):
+			writeBadRequest(w, err.Error())
+		case errors.Is(err, reconciliatonjob.ErrPeriodLocked):
+			writeConflict(w, err.Error())
+		default:
+			log.Error("failed to rerun reconciliation job", zap.Error(err), zap.Int64("id", id))
+			writeInternalServerError(w)
+		}
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, rj, nil)
+}
+
+func (h *ReconciliationJobHandler) parseRerunReconciliationJobParams(r *http.Request) (*reconciliatonjob.RerunParams, error) {
+	params := &reconciliatonjob.RerunParams{}
+	if v := r.FormValue("start_date"); v != "" {
+		startDate, err := time.Parse(dateFormat, v)
+		if err != nil {
+			return nil, ErrInvalidDate("start date")
+		}
+		params.StartDate = &startDate
+	}
+	if v := r.FormValue("end_date"); v != "" {
+		endDate, err := time.Parse(dateFormat, v)
+		if err != nil {
+			return nil, ErrInvalidDate("end date")
+		}
+		params.EndDate = &endDate
+	}
+	if v := r.FormValue("discrepancy_threshold"); v != "" {
+		discrepancyThreshold := parseFloat32(v)
+		if discrepancyThreshold < 0 {
+			discrepancyThreshold = 0
+		}
+		params.DiscrepancyThreshold = &discrepancyThreshold
+	}
+	if v := r.FormValue("matching_options"); v != "" {
+		matchingOptions, err := parseMatchingOptions(v)
+		if err != nil {
+			return nil, err
+		}
+		params.MatchingOptions = matchingOptions
+	}
+
+	return params, nil
+}
+
+func (h *ReconciliationJobHandler) parseCreateReconciliationJobParams(w http.ResponseWriter, r *http.Request, params *reconciliatonjob.CreateParams) error {
+	form, err := h.readUploadedForm(w, r, params)
+	if err != nil {
+		return err
+	}
+
+	startDate := parseDate(form.Get("start_date"))
+	endDate := parseDate(form.Get("end_date"))
 	if startDate.After(endDate) {
-		return nil, errors.New("start date must be before end date")
+		return ErrInvalidDateRange
 	}
 	params.StartDate = startDate
 	params.EndDate = endDate
 
-	discrepancyThreshold := parseFloat32(r.FormValue("discrepancy_threshold"))
+	discrepancyThreshold := parseFloat32(form.Get("discrepancy_threshold"))
 	if discrepancyThreshold < 0 {
 		discrepancyThreshold = 0
 	}
 	params.DiscrepancyThreshold = discrepancyThreshold
 
-	return params, nil
+	if v := form.Get("matching_options"); v != "" {
+		matchingOptions, err := parseMatchingOptions(v)
+		if err != nil {
+			return err
+		}
+		params.MatchingOptions = *matchingOptions
+	}
+
+	return nil
 }
 
-func (h *ReconciliationJobHandler) buildFileParams(r *http.Request) (*reconciliatonjob.CreateParams, error) {
-	if err := r.ParseMultipartForm(entity.LimitContentSize); err != nil {
-		return nil, err
-	}
+// readUploadedForm read the uploaded form part by part, every file is streamed
+// straight to the storage as it is read so it is neither buffered in memory
+// nor in temporary files, the other fields are returned as form values
+func (h *ReconciliationJobHandler) readUploadedForm(w http.ResponseWriter, r *http.Request, params *reconciliatonjob.CreateParams) (url.Values, error) {
 	if r.ContentLength > entity.LimitContentSize {
-		return nil, errors.New("content size more than 100mb")
+		return nil, ErrContentSizeExceedLimit(humanizeLimitContentSize)
 	}
-
-	file, err := h.buildSystemTrxFile(r.MultipartForm)
+	r.Body = http.MaxBytesReader(w, r.Body, entity.LimitContentSize)
+	mr, err := r.MultipartReader()
 	if err != nil {
 		return nil, err
 	}
 
-	bankFiles, err := h.buildBankTrxFiles(r.MultipartForm)
-	if err != nil {
-		return nil, err
+	form := url.Values{}
+	bankTrxFiles := []*reconciliatonjob.File{}
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, uploadReadError(err)
+		}
+
+		switch {
+		case part.FormName() == "system_transaction_file":
+			if params.SystemTransactionCsv != nil {
+				return nil, ErrSystemTrxFileNotUnique
+			}
+			if params.SystemTransactionCsv, err = h.storeUploadedFile(r.Context(), params, part); err != nil {
+				return nil, err
+			}
+		case part.FormName() == "bank_transaction_files":
+			file, err := h.storeUploadedFile(r.Context(), params, part)
+			if err != nil {
+				return nil, err
+			}
+			bankTrxFiles = append(bankTrxFiles, file)
+		case part.FileName() == "":
+			value, err := io.ReadAll(io.LimitReader(part, formValueLimit+1))
+			if err != nil {
+				return nil, uploadReadError(err)
+			}
+			if len(value) > formValueLimit {
+				return nil, ErrFormValueSizeExceedLimit(part.FormName())
+			}
+			form.Add(part.FormName(), string(value))
+		}
 	}
 
-	params := &reconciliatonjob.CreateParams{
-		SystemTransactionCsv: file,
-		BankTransactionCsvs:  bankFiles,
+	if params.SystemTransactionCsv == nil {
+		return nil, ErrSystemTrxFileEmpty
+	}
+	if params.BankTransactionCsvs, err = buildBankTrxFiles(form["bank_names"], bankTrxFiles); err != nil {
+		return nil, err
 	}
 
-	return params, nil
+	return form, nil
 }
 
-func (h *ReconciliationJobHandler) buildSystemTrxFile(form *multipart.Form) (*reconciliatonjob.File, error) {
-	systemTrxFile := form.File["system_transaction_file"]
-	if len(systemTrxFile) == 0 {
-		return nil, errors.New("system transaction file is required")
+// storeUploadedFile validate the header of the uploaded csv file and stream it
+// to the storage, the file is rejected once it is read past its size limit
+func (h *ReconciliationJobHandler) storeUploadedFile(ctx context.Context, params *reconciliatonjob.CreateParams, part *multipart.Part) (*reconciliatonjob.File, error) {
+	filename := part.FileName()
+	if !isCSVExtension(filename) {
+		return nil, ErrExtensionFileInvalid(filename)
 	}
 
-	buf, err := h.validateCSVFile(systemTrxFile[0])
-	if err != nil {
+	br := bufio.NewReaderSize(part, fileHeaderSize)
+	header, err := br.Peek(fileHeaderSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		if errors.As(err, new(*http.MaxBytesError)) {
+			return nil, ErrContentSizeExceedLimit(humanizeLimitContentSize)
+		}
+		return nil, ErrFileCannotBeAccessed(filename)
+	}
+	if err = validateCSVFileHeader(filename, header); err != nil {
 		return nil, err
 	}
 
-	return &reconciliatonjob.File{
-		Name: systemTrxFile[0].Filename,
-		Buf:  buf,
-	}, nil
+	limiter := &fileSizeLimiter{r: br, remaining: entity.LimitCSVSize}
+	file := &reconciliatonjob.File{
+		Name:   filename,
+		Reader: limiter,
+	}
+	if err = h.creatorService.StoreFile(ctx, params, file); err != nil {
+		switch {
+		case limiter.exceeded:
+			return nil, ErrFileSizeExceedLimit(filename, humanizeLimitFileSize)
+		case errors.As(err, new(*http.MaxBytesError)):
+			return nil, ErrContentSizeExceedLimit(humanizeLimitContentSize)
+		default:
+			return nil, fmt.Errorf("%w %s: %w", ErrStoreUploadedFile, filename, err)
+		}
+	}
+
+	return file, nil
 }
 
-func (h *ReconciliationJobHandler) buildBankTrxFiles(form *multipart.Form) ([]*reconciliatonjob.BankTransactionFile, error) {
-	bankNames := form.Value["bank_names"]
-	bankTrxFiles := form.File["bank_transaction_files"]
+func buildBankTrxFiles(bankNames []string, bankTrxFiles []*reconciliatonjob.File) ([]*reconciliatonjob.BankTransactionFile, error) {
 	if len(bankTrxFiles) == 0 {
 		return nil, ErrBankTrxFileEmpty
 	}
 	if len(bankNames) != len(bankTrxFiles) {
 		return nil, ErrBankFileAndNameLengthNotMatch
 	}
+	seen := map[string]bool{}
+	for _, name := range bankNames {
+		key := entity.BankNameKey(name)
+		if key == string(entity.ReconciliationItemSourceSystem) {
+			return nil, ErrBankNameReserved(name)
+		}
+		if seen[key] {
+			return nil, ErrBankNameNotUnique(name)
+		}
+		seen[key] = true
+	}
 
-	result := []*reconciliatonjob.BankTransactionFile{}
+	result := make([]*reconciliatonjob.BankTransactionFile, len(bankTrxFiles))
 	for idx, file := range bankTrxFiles {
-		buf, err := h.validateCSVFile(file)
-		if err != nil {
-			return nil, err
-		}
-		bankFile := &reconciliatonjob.BankTransactionFile{
+		result[idx] = &reconciliatonjob.BankTransactionFile{
 			BankName: bankNames[idx],
-			File: &reconciliatonjob.File{
-				Name: file.Filename,
-				Buf:  buf,
-			},
+			File:     file,
 		}
-		result = append(result, bankFile)
 	}
 
 	return result, nil
 }
 
-func (h *ReconciliationJobHandler) validateCSVFile(file *multipart.FileHeader) (*bytes.Buffer, error) {
-	if !isCSVExtension(file.Filename) {
-		return nil, ErrExtensionFileInvalid(file.Filename)
-	}
-	if file.Size > entity.LimitCSVSize {
-		return nil, ErrFileSizeExceedLimit(file.Filename, humanizeLimitFileSize)
-	}
-
-	byteFileBuf := bytes.NewBuffer(nil)
-	f, err := file.Open()
+func validateCSVFileHeader(filename string, header []byte) error {
+	fileType, err := filetype.Match(header)
 	if err != nil {
-		return nil, ErrFileCannotBeAccessed(file.Filename)
+		return ErrExtensionFileUnknown(filename)
 	}
-	defer f.Close()
 
-	if _, err = io.Copy(byteFileBuf, f); err != nil {
-		return bytes.NewBuffer(nil), ErrFileCannotBeAccessed(file.Filename)
+	if fileType.MIME.Value == allowedMimeType {
+		return ErrExtensionFileInvalid(filename)
 	}
 
-	fileType, err := filetype.Match(byteFileBuf.Bytes())
-	if err != nil {
-		return bytes.NewBuffer(nil), ErrExtensionFileUnknown(file.Filename)
+	return nil
+}
+
+// uploadReadError convert the error of reading the uploaded form, the request
+// body is cut off once it exceeds the content size limit
+func uploadReadError(err error) error {
+	if errors.As(err, new(*http.MaxBytesError)) {
+		return ErrContentSizeExceedLimit(humanizeLimitContentSize)
 	}
 
-	if fileType.MIME.Value == allowedMimeType {
-		return bytes.NewBuffer(nil), ErrExtensionFileInvalid(file.Filename)
+	return err
+}
+
+// fileSizeLimiter fail the read once more than the remaining bytes are read
+type fileSizeLimiter struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *fileSizeLimiter) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		l.exceeded = true
+		return n, errFileSizeExceeded
 	}
 
-	return byteFileBuf, nil
+	return n, err
 }
@@ -8,12 +8,15 @@ import (
 	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/delly/amartha/entity"
 	handler "github.com/delly/amartha/handler/http"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
 	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
 	"github.com/julienschmidt/httprouter"
 	"github.com/stretchr/testify/assert"
@@ -85,6 +88,11 @@ type ReconciliationJobHandlerTestSuite struct {
 	router             *httprouter.Router
 	mockFinderService  *mock_reconciliatonjob.MockFinder
 	mockCreatorService *mock_reconciliatonjob.MockCreator
+	mockCancelService  *mock_reconciliatonjob.MockCanceler
+	mockExportService  *mock_reconciliatonjob.MockExporter
+	mockReportService  *mock_reconciliatonjob.MockReporter
+	mockAdjustService  *mock_reconciliatonjob.MockAdjuster
+	mockApproveService *mock_reconciliatonjob.MockApprover
 	handler            *handler.ReconciliationJobHandler
 }
 
@@ -92,7 +100,12 @@ func (s *ReconciliationJobHandlerTestSuite) SetupTest() {
 	ctrl := gomock.NewController(s.T())
 	s.mockFinderService = mock_reconciliatonjob.NewMockFinder(ctrl)
 	s.mockCreatorService = mock_reconciliatonjob.NewMockCreator(ctrl)
-	s.handler = handler.NewReconciliationJobHandler(s.mockFinderService, s.mockCreatorService)
+	s.mockCancelService = mock_reconciliatonjob.NewMockCanceler(ctrl)
+	s.mockExportService = mock_reconciliatonjob.NewMockExporter(ctrl)
+	s.mockReportService = mock_reconciliatonjob.NewMockReporter(ctrl)
+	s.mockAdjustService = mock_reconciliatonjob.NewMockAdjuster(ctrl)
+	s.mockApproveService = mock_reconciliatonjob.NewMockApprover(ctrl)
+	s.handler = handler.NewReconciliationJobHandler(s.mockFinderService, s.mockCreatorService, s.mockCancelService, s.mockExportService, s.mockReportService, s.mockAdjustService, s.mockApproveService)
 
 	s.router = httprouter.New()
 	s.handler.Register(s.router)
@@ -207,8 +220,852 @@ func (s *ReconciliationJobHandlerTestSuite) TestGetAllReconciliationJob() {
 	})
 }
 
+func (s *ReconciliationJobHandlerTestSuite) TestGetReconciliationItems() {
+	ctx := context.Background()
+	item := &entity.ReconciliationItem{
+		ID:    1,
+		JobID: id,
+		Transaction: entity.Transaction{
+			ID:     "BCA-1",
+			Amount: 1000,
+			Type:   entity.TxTypeCredit,
+			Time:   time.Date(2024, 11, 2, 0, 0, 0, 0, time.UTC),
+		},
+		Source:   entity.ReconciliationItemSourceBank,
+		BankName: "BCA",
+		Row:      1,
+		Status:   entity.ReconciliationItemStatusMissing,
+	}
+
+	s.Run("success with filters", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/items?source=bank&bank_name=BCA&type=credit&status=missing"+
+			"&min_amount=100&max_amount=5000&start_date=2024-11-01&end_date=2024-11-30&finding=duplicate&sort_by=amount&order=desc&limit=20&offset=40", nil)
+		minAmount, maxAmount := 100.0, 5000.0
+		startDate := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+		filter := reconciliatonjob.ItemFilter{
+			JobID:     id,
+			Source:    entity.ReconciliationItemSourceBank,
+			BankName:  "BCA",
+			Type:      entity.TxTypeCredit,
+			Status:    entity.ReconciliationItemStatusMissing,
+			MinAmount: &minAmount,
+			MaxAmount: &maxAmount,
+			StartDate: &startDate,
+			EndDate:   &endDate,
+			Finding:   reconciliatonjob.ItemFindingDuplicate,
+			SortBy:    reconciliatonjob.ItemSortByAmount,
+			SortDesc:  true,
+		}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().CountItems(ctx, filter).Return(int64(41), nil)
+		s.mockFinderService.EXPECT().FindItems(ctx, filter, int32(20), int32(40)).Return([]*entity.ReconciliationItem{item}, nil)
+
+		resp := s.executeReq(req)
+
+		jsonItems, _ := json.Marshal([]*entity.ReconciliationItem{item})
+		bodyJson := resp.Body.String()
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(bodyJson, string(jsonItems))
+		s.Contains(bodyJson, `"total":41`)
+	})
+
+	s.Run("no data", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/items", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().CountItems(ctx, reconciliatonjob.ItemFilter{JobID: id}).Return(int64(0), nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), `"data":[]`)
+	})
+
+	s.Run("invalid filters", func() {
+		for query, message := range map[string]string{
+			"source=file":                   "source must be one of [SYSTEM BANK]",
+			"type=transfer":                 "type must be one of [CREDIT DEBIT]",
+			"status=open":                   "status must be one of",
+			"finding=fee":                   "finding must be one of [duplicate overlap]",
+			"sort_by=id":                    "sort by must be one of [amount transaction_time]",
+			"order=up":                      "order must be one of [asc desc]",
+			"min_amount=abc":                "min amount must be a number",
+			"min_amount=500&max_amount=100": "min amount must not be greater than max amount",
+			"start_date=2024/11/01":         "start date must be in YYYY-MM-DD format",
+			"start_date=2024-11-30&end_date=2024-11-01": "start date must be before end date",
+		} {
+			req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/items?"+query, nil)
+
+			resp := s.executeReq(req)
+
+			s.Equal(http.StatusBadRequest, resp.Code, query)
+			s.Contains(resp.Body.String(), message, query)
+		}
+	})
+
+	s.Run("not found", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/items", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(nil, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusNotFound, resp.Code)
+		s.Contains(resp.Body.String(), "reconciliation job not found")
+	})
+
+	s.Run("error on count", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/items", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().CountItems(ctx, gomock.Any()).Return(int64(0), assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+
+	s.Run("error on find items", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/items", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().CountItems(ctx, gomock.Any()).Return(int64(1), nil)
+		s.mockFinderService.EXPECT().FindItems(ctx, gomock.Any(), int32(10), int32(0)).Return(nil, assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+
+	s.Run("invalid id", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/invalid/items", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "invalid id")
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestExportReconciliationJob() {
+	ctx := context.Background()
+
+	s.Run("success csv by default", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/export", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockExportService.EXPECT().Export(ctx, entityReconJob, reconciliatonjob.ExportFormatCSV, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.ReconciliationJob, _ reconciliatonjob.ExportFormat, w io.Writer) error {
+				_, err := io.WriteString(w, "Summary\n")
+				return err
+			})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Equal("text/csv", resp.Header().Get("Content-Type"))
+		s.Equal(`attachment; filename="reconciliation_1.csv"`, resp.Header().Get("Content-Disposition"))
+		s.Equal("Summary\n", resp.Body.String())
+	})
+
+	s.Run("success xlsx", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/export?format=XLSX", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockExportService.EXPECT().Export(ctx, entityReconJob, reconciliatonjob.ExportFormatXLSX, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.ReconciliationJob, _ reconciliatonjob.ExportFormat, w io.Writer) error {
+				_, err := io.WriteString(w, "PK")
+				return err
+			})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Equal("application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", resp.Header().Get("Content-Type"))
+		s.Equal(`attachment; filename="reconciliation_1.xlsx"`, resp.Header().Get("Content-Disposition"))
+	})
+
+	s.Run("invalid format", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/export?format=pdf", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "format must be one of [csv xlsx]")
+	})
+
+	s.Run("not found", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/export", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(nil, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusNotFound, resp.Code)
+		s.Contains(resp.Body.String(), "reconciliation job not found")
+	})
+
+	s.Run("not success", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/export", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockExportService.EXPECT().Export(ctx, entityReconJob, reconciliatonjob.ExportFormatCSV, gomock.Any()).Return(reconciliatonjob.ErrReconciliationJobNotSuccess)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusConflict, resp.Code)
+		s.Empty(resp.Header().Get("Content-Disposition"))
+		s.Contains(resp.Body.String(), reconciliatonjob.ErrReconciliationJobNotSuccess.Error())
+	})
+
+	s.Run("internal server error", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/export", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockExportService.EXPECT().Export(ctx, entityReconJob, reconciliatonjob.ExportFormatCSV, gomock.Any()).Return(assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+
+	s.Run("error after streaming started", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/export", nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockExportService.EXPECT().Export(ctx, entityReconJob, reconciliatonjob.ExportFormatCSV, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.ReconciliationJob, _ reconciliatonjob.ExportFormat, w io.Writer) error {
+				io.WriteString(w, "Summary\n")
+				return assert.AnError
+			})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Equal("Summary\n", resp.Body.String())
+	})
+
+	s.Run("invalid id", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/invalid/export", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "invalid id")
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestReportReconciliationJob() {
+	ctx := context.Background()
+	req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/report", nil)
+
+	s.Run("success", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockReportService.EXPECT().Report(ctx, entityReconJob, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ *entity.ReconciliationJob, w io.Writer) error {
+				_, err := io.WriteString(w, "%PDF-1.4\n")
+				return err
+			})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Equal("application/pdf", resp.Header().Get("Content-Type"))
+		s.Equal(`attachment; filename="reconciliation_1.pdf"`, resp.Header().Get("Content-Disposition"))
+		s.Equal("%PDF-1.4\n", resp.Body.String())
+	})
+
+	s.Run("not found", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(nil, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusNotFound, resp.Code)
+		s.Contains(resp.Body.String(), "reconciliation job not found")
+	})
+
+	s.Run("not success", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockReportService.EXPECT().Report(ctx, entityReconJob, gomock.Any()).Return(reconciliatonjob.ErrReconciliationJobNotSuccess)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusConflict, resp.Code)
+		s.Empty(resp.Header().Get("Content-Disposition"))
+		s.Contains(resp.Body.String(), reconciliatonjob.ErrReconciliationJobNotSuccess.Error())
+	})
+
+	s.Run("internal server error", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockReportService.EXPECT().Report(ctx, entityReconJob, gomock.Any()).Return(assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+
+	s.Run("invalid id", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/invalid/report", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "invalid id")
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestDiffReconciliationJob() {
+	ctx := context.Background()
+	req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/diff/2", nil)
+	otherReconJob := &entity.ReconciliationJob{ID: 2, Status: entity.ReconciliationJobStatusSuccess}
+
+	s.Run("success", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/diff/2?limit=20&offset=40", nil)
+		diff := &entity.ReconciliationDiff{
+			JobID:          id,
+			OtherJobID:     2,
+			NewlyMatched:   []entity.ReconciliationDiffItem{{Source: entity.ReconciliationItemSourceSystem, TransactionID: "TRX-1"}},
+			NewlyUnmatched: []entity.ReconciliationDiffItem{},
+			StatusChanges:  []entity.ReconciliationDiffItem{{Source: entity.ReconciliationItemSourceSystem, TransactionID: "TRX-1"}},
+			Added:          []entity.ReconciliationDiffItem{},
+			Removed:        []entity.ReconciliationDiffItem{},
+			AmountChanges:  []entity.ReconciliationDiffItem{},
+		}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, int64(2)).Return(otherReconJob, nil)
+		s.mockFinderService.EXPECT().CountDiff(ctx, entityReconJob, otherReconJob).Return(int64(41), nil)
+		s.mockFinderService.EXPECT().Diff(ctx, entityReconJob, otherReconJob, int32(20), int32(40)).Return(diff, nil)
+
+		resp := s.executeReq(req)
+
+		jsonDiff, _ := json.Marshal(diff)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonDiff))
+		s.Contains(resp.Body.String(), `"total":41`)
+	})
+
+	s.Run("no change", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, int64(2)).Return(otherReconJob, nil)
+		s.mockFinderService.EXPECT().CountDiff(ctx, entityReconJob, otherReconJob).Return(int64(0), nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), `"newly_matched":[],"newly_unmatched":[],"status_changes":[],"added":[],"removed":[],"amount_changes":[]`)
+	})
+
+	s.Run("other job not found", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, int64(2)).Return(nil, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusNotFound, resp.Code)
+		s.Contains(resp.Body.String(), "reconciliation job 2 not found")
+	})
+
+	s.Run("not success", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockFinderService.EXPECT().FindByID(ctx, int64(2)).Return(otherReconJob, nil)
+		s.mockFinderService.EXPECT().CountDiff(ctx, entityReconJob, otherReconJob).Return(int64(0), reconciliatonjob.ErrReconciliationJobNotSuccess)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("internal server error", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(nil, assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+
+	s.Run("invalid other id", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/diff/invalid", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "invalid other id")
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestGetUnmatchedAgeing() {
+	ctx := context.Background()
+	asOf := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	s.Run("success", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/unmatched-transactions/ageing?as_of=2024-12-31", nil)
+		ageing := &entity.UnmatchedAgeing{
+			AsOf: asOf,
+			Groups: []entity.UnmatchedAgeingGroup{
+				{
+					Source:           entity.ReconciliationItemSourceBank,
+					BankName:         "BCA",
+					Type:             entity.TxTypeCredit,
+					Buckets:          []entity.AgeingBucketTotal{{Bucket: entity.AgeingBucket0To7, TotalTransaction: 1, TotalAmount: 1000}},
+					TotalTransaction: 1,
+					TotalAmount:      1000,
+				},
+			},
+			Buckets:          []entity.AgeingBucketTotal{{Bucket: entity.AgeingBucket0To7, TotalTransaction: 1, TotalAmount: 1000}},
+			TotalTransaction: 1,
+			TotalAmount:      1000,
+		}
+		s.mockFinderService.EXPECT().Ageing(ctx, asOf).Return(ageing, nil)
+
+		resp := s.executeReq(req)
+
+		jsonAgeing, _ := json.Marshal(ageing)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonAgeing))
+	})
+
+	s.Run("success as of today by default", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/unmatched-transactions/ageing", nil)
+		today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+		s.mockFinderService.EXPECT().Ageing(ctx, today).Return(&entity.UnmatchedAgeing{AsOf: today}, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusOK, resp.Code)
+	})
+
+	s.Run("invalid as of date", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/unmatched-transactions/ageing?as_of=31-12-2024", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("internal server error", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/unmatched-transactions/ageing?as_of=2024-12-31", nil)
+		s.mockFinderService.EXPECT().Ageing(ctx, asOf).Return(nil, assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestManualMatchReconciliationItems() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/manual-matches", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "alice")
+		return req
+	}
+	body := `{"system_item_id": 10, "bank_item_id": 20, "note": " same transfer "}`
+	params := &reconciliatonjob.ManualMatchParams{SystemItemID: 10, BankItemID: 20, Note: "same transfer", CreatedBy: "alice"}
+
+	s.Run("success", func() {
+		adjustment := &entity.ReconciliationAdjustment{
+			ID:        1,
+			JobID:     id,
+			Type:      entity.ReconciliationAdjustmentTypeManualMatch,
+			Note:      "same transfer",
+			ItemIDs:   []int64{10, 20},
+			CreatedBy: "alice",
+			CreatedAt: now,
+		}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().ManualMatch(ctx, entityReconJob, params).Return(adjustment, nil)
+
+		resp := s.executeReq(newReq(body))
+
+		jsonAdjustment, _ := json.Marshal(adjustment)
+		s.Equal(http.StatusCreated, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonAdjustment))
+	})
+
+	s.Run("user is required", func() {
+		req := newReq(body)
+		req.Header.Del("X-User-ID")
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusUnauthorized, resp.Code)
+	})
+
+	s.Run("invalid body", func() {
+		resp := s.executeReq(newReq(`{"system_item_id": 10, "bank_item": 20}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("bank item is required", func() {
+		resp := s.executeReq(newReq(`{"system_item_id": 10}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "bank_item_id is required")
+	})
+
+	s.Run("success with reason code", func() {
+		withReason := *params
+		withReason.ReasonCode = entity.ResolutionReasonBankError
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().ManualMatch(ctx, entityReconJob, &withReason).Return(&entity.ReconciliationAdjustment{ID: 1}, nil)
+
+		resp := s.executeReq(newReq(`{"system_item_id": 10, "bank_item_id": 20, "reason_code": "bank_error", "note": "same transfer"}`))
+
+		s.Equal(http.StatusCreated, resp.Code)
+	})
+
+	s.Run("invalid reason code", func() {
+		resp := s.executeReq(newReq(`{"system_item_id": 10, "bank_item_id": 20, "reason_code": "LOST"}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "reason_code")
+	})
+
+	s.Run("job not found", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(nil, nil)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+
+	s.Run("item not found", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().ManualMatch(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrReconciliationItemNotFound)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+
+	s.Run("invalid manual match", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().ManualMatch(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrInvalidManualMatch)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("reason required", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().ManualMatch(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrManualMatchReasonRequired)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("item not adjustable", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().ManualMatch(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrReconciliationItemNotAdjustable)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("internal server error", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().ManualMatch(ctx, entityReconJob, params).Return(nil, assert.AnError)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestResolveReconciliationItem() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/resolutions", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "alice")
+		return req
+	}
+	body := `{"item_id": 10, "reason_code": "bank_error", "note": "bank reversed it"}`
+	params := &reconciliatonjob.ResolveParams{ItemID: 10, ReasonCode: entity.ResolutionReasonBankError, Note: "bank reversed it", CreatedBy: "alice"}
+
+	s.Run("success", func() {
+		adjustment := &entity.ReconciliationAdjustment{
+			ID:         1,
+			JobID:      id,
+			Type:       entity.ReconciliationAdjustmentTypeResolved,
+			ReasonCode: entity.ResolutionReasonBankError,
+			Note:       "bank reversed it",
+			ItemIDs:    []int64{10},
+			CreatedBy:  "alice",
+			CreatedAt:  now,
+		}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().Resolve(ctx, entityReconJob, params).Return(adjustment, nil)
+
+		resp := s.executeReq(newReq(body))
+
+		jsonAdjustment, _ := json.Marshal(adjustment)
+		s.Equal(http.StatusCreated, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonAdjustment))
+	})
+
+	s.Run("invalid reason code", func() {
+		resp := s.executeReq(newReq(`{"item_id": 10, "reason_code": "LOST"}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "reason_code must be one of")
+	})
+
+	s.Run("job not success", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().Resolve(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrReconciliationJobNotSuccess)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestGetReconciliationAdjustments() {
+	ctx := context.Background()
+
+	req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/adjustments", nil)
+	s.Run("success", func() {
+		adjustments := []*entity.ReconciliationAdjustment{
+			{ID: 1, JobID: id, Type: entity.ReconciliationAdjustmentTypeManualMatch, ItemIDs: []int64{10, 20}, CreatedBy: "alice", CreatedAt: now},
+		}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().FindAdjustments(ctx, id).Return(adjustments, nil)
+
+		resp := s.executeReq(req)
+
+		jsonAdjustments, _ := json.Marshal(adjustments)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonAdjustments))
+	})
+
+	s.Run("internal server error", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockAdjustService.EXPECT().FindAdjustments(ctx, id).Return(nil, assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestSubmitReconciliationJob() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/submit", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "alice")
+		return req
+	}
+	params := &reconciliatonjob.ApprovalParams{Comment: "ready", CreatedBy: "alice"}
+
+	s.Run("success", func() {
+		approval := &entity.ReconciliationApproval{ID: 1, JobID: id, Status: entity.ReconciliationApprovalStatusSubmitted, Comment: "ready", CreatedBy: "alice", CreatedAt: now}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().Submit(ctx, entityReconJob, params).Return(approval, nil)
+
+		resp := s.executeReq(newReq(`{"comment": "ready"}`))
+
+		jsonApproval, _ := json.Marshal(approval)
+		s.Equal(http.StatusCreated, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonApproval))
+	})
+
+	s.Run("user required", func() {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/submit", strings.NewReader(`{}`))
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusUnauthorized, resp.Code)
+	})
+
+	s.Run("already submitted", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().Submit(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrInvalidApprovalTransition)
+
+		resp := s.executeReq(newReq(`{"comment": "ready"}`))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestApproveReconciliationJob() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/approve", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "bob")
+		return req
+	}
+	params := &reconciliatonjob.ApprovalParams{CreatedBy: "bob"}
+
+	s.Run("success", func() {
+		approval := &entity.ReconciliationApproval{ID: 2, JobID: id, Status: entity.ReconciliationApprovalStatusApproved, CreatedBy: "bob", CreatedAt: now}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().Approve(ctx, entityReconJob, params).Return(approval, nil)
+
+		resp := s.executeReq(newReq(`{}`))
+
+		s.Equal(http.StatusOK, resp.Code)
+	})
+
+	s.Run("approved by preparer", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().Approve(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrSelfApproval)
+
+		resp := s.executeReq(newReq(`{}`))
+
+		s.Equal(http.StatusForbidden, resp.Code)
+	})
+
+	s.Run("period locked", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().Approve(ctx, entityReconJob, params).Return(nil, reconciliatonjob.ErrPeriodLocked)
+
+		resp := s.executeReq(newReq(`{}`))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("invalid request body", func() {
+		resp := s.executeReq(newReq(`{"note": "typo"}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestRejectReconciliationJob() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/reject", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "bob")
+		return req
+	}
+
+	s.Run("success", func() {
+		approval := &entity.ReconciliationApproval{ID: 2, JobID: id, Status: entity.ReconciliationApprovalStatusRejected, Comment: "wrong period", CreatedBy: "bob", CreatedAt: now}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().Reject(ctx, entityReconJob, &reconciliatonjob.ApprovalParams{Comment: "wrong period", CreatedBy: "bob"}).Return(approval, nil)
+
+		resp := s.executeReq(newReq(`{"comment": " wrong period "}`))
+
+		s.Equal(http.StatusOK, resp.Code)
+	})
+
+	s.Run("comment required", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().Reject(ctx, entityReconJob, &reconciliatonjob.ApprovalParams{CreatedBy: "bob"}).Return(nil, reconciliatonjob.ErrApprovalCommentRequired)
+
+		resp := s.executeReq(newReq(`{}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestGetReconciliationApprovals() {
+	ctx := context.Background()
+
+	req, _ := http.NewRequest(http.MethodGet, "/reconciliations/1/approvals", nil)
+	s.Run("success", func() {
+		approvals := []*entity.ReconciliationApproval{
+			{ID: 1, JobID: id, Status: entity.ReconciliationApprovalStatusSubmitted, CreatedBy: "alice", CreatedAt: now},
+		}
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().FindApprovals(ctx, id).Return(approvals, nil)
+
+		resp := s.executeReq(req)
+
+		jsonApprovals, _ := json.Marshal(approvals)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonApprovals))
+	})
+
+	s.Run("internal server error", func() {
+		s.mockFinderService.EXPECT().FindByID(ctx, id).Return(entityReconJob, nil)
+		s.mockApproveService.EXPECT().FindApprovals(ctx, id).Return(nil, assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestGetReconciliationPeriodLocks() {
+	ctx := context.Background()
+
+	s.Run("success", func() {
+		locks := []*entity.ReconciliationPeriodLock{
+			{ID: 7, JobID: id, BankName: "BCA", StartDate: now, EndDate: now, LockedBy: "bob", LockedAt: now},
+		}
+		req, _ := http.NewRequest(http.MethodGet, "/period-locks?bank_name=BCA&active=true", nil)
+		s.mockApproveService.EXPECT().FindPeriodLocks(ctx, reconciliatonjob.PeriodLockFilter{BankName: "BCA", ActiveOnly: true}).Return(locks, nil)
+
+		resp := s.executeReq(req)
+
+		jsonLocks, _ := json.Marshal(locks)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonLocks))
+	})
+
+	s.Run("invalid active", func() {
+		req, _ := http.NewRequest(http.MethodGet, "/period-locks?active=yes", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestUnlockReconciliationPeriod() {
+	ctx := context.Background()
+	newReq := func(body string) *http.Request {
+		req, _ := http.NewRequest(http.MethodPost, "/period-locks/7/unlock", strings.NewReader(body))
+		req.Header.Set("X-User-ID", "dave")
+		return req
+	}
+	body := `{"reason": "late bank statement"}`
+	params := &reconciliatonjob.UnlockParams{LockID: 7, Reason: "late bank statement", UnlockedBy: "dave"}
+
+	s.Run("success", func() {
+		lock := &entity.ReconciliationPeriodLock{ID: 7, JobID: id, BankName: "BCA", UnlockedBy: "dave", UnlockedAt: &now, UnlockReason: "late bank statement"}
+		s.mockApproveService.EXPECT().Unlock(ctx, params).Return(lock, nil)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), `"unlock_reason":"late bank statement"`)
+	})
+
+	s.Run("reason required", func() {
+		resp := s.executeReq(newReq(`{"reason": " "}`))
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "reason is required")
+	})
+
+	s.Run("not found", func() {
+		s.mockApproveService.EXPECT().Unlock(ctx, params).Return(nil, reconciliatonjob.ErrPeriodLockNotFound)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+
+	s.Run("already unlocked", func() {
+		s.mockApproveService.EXPECT().Unlock(ctx, params).Return(nil, reconciliatonjob.ErrPeriodLockNotActive)
+
+		resp := s.executeReq(newReq(body))
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+}
+
 func (s *ReconciliationJobHandlerTestSuite) TestCreateReconciliationJob() {
 	ctx := context.Background()
+	var stored []string
+	s.mockCreatorService.EXPECT().StoreFile(ctx, gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, _ *reconciliatonjob.CreateParams, file *reconciliatonjob.File) error {
+		if _, err := io.Copy(io.Discard, file.Reader); err != nil {
+			return err
+		}
+		stored = append(stored, file.Name)
+		return nil
+	}).AnyTimes()
+	discarded := 0
+	s.mockCreatorService.EXPECT().DiscardFiles(gomock.Any(), gomock.Any()).Do(func(context.Context, *reconciliatonjob.CreateParams) {
+		discarded++
+	}).AnyTimes()
 
 	s.Run("success", func() {
 		req := s.buildCreatorReq(func(mw *multipart.Writer) {
@@ -220,10 +1077,12 @@ func (s *ReconciliationJobHandlerTestSuite) TestCreateReconciliationJob() {
 			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
 		})
 		s.mockCreatorService.EXPECT().Create(ctx, gomock.Any()).Return(entityReconJob, nil)
+		discarded = 0
 
 		resp := s.executeReq(req)
 
 		s.Equal(http.StatusCreated, resp.Code)
+		s.Zero(discarded)
 	})
 
 	s.Run("error on create", func() {
@@ -236,10 +1095,135 @@ func (s *ReconciliationJobHandlerTestSuite) TestCreateReconciliationJob() {
 			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
 		})
 		s.mockCreatorService.EXPECT().Create(ctx, gomock.Any()).Return(nil, assert.AnError)
+		discarded = 0
 
 		resp := s.executeReq(req)
 
 		s.Equal(http.StatusInternalServerError, resp.Code)
+		s.Equal(1, discarded)
+	})
+
+	s.Run("error bank named after system transaction file", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "System")
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+		})
+		discarded = 0
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "bank name System is reserved")
+		s.Equal(1, discarded)
+	})
+
+	s.Run("success stream every uploaded file in the order it is uploaded", func() {
+		stored = nil
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "BCA")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			mw.WriteField("bank_names", "BRI")
+			s.createFormFile(mw, "bank_transaction_files", "bri_trx.csv")
+		})
+		s.mockCreatorService.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, params *reconciliatonjob.CreateParams) (*entity.ReconciliationJob, error) {
+			s.Equal("system_trx.csv", params.SystemTransactionCsv.Name)
+			s.Equal("BCA", params.BankTransactionCsvs[0].BankName)
+			s.Equal("bca_trx.csv", params.BankTransactionCsvs[0].File.Name)
+			s.Equal("BRI", params.BankTransactionCsvs[1].BankName)
+			s.Equal("bri_trx.csv", params.BankTransactionCsvs[1].File.Name)
+			return entityReconJob, nil
+		})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusCreated, resp.Code)
+		s.Equal([]string{"bca_trx.csv", "system_trx.csv", "bri_trx.csv"}, stored)
+	})
+
+	s.Run("error system transaction file uploaded twice", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "BCA")
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+		})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("error form value too large", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("matching_options", strings.Repeat(" ", 1<<20+1))
+		})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("success with matching options", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "BCA")
+			mw.WriteField("matching_options", `{"duplicate_rule":"FINGERPRINT","deduplicate":true}`)
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+		})
+		s.mockCreatorService.EXPECT().Create(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, params *reconciliatonjob.CreateParams) (*entity.ReconciliationJob, error) {
+			s.Equal(entity.MatchingOptions{
+				DuplicateRule: entity.DuplicateRuleFingerprint,
+				Deduplicate:   true,
+			}, params.MatchingOptions)
+			return entityReconJob, nil
+		})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusCreated, resp.Code)
+	})
+
+	s.Run("invalid matching options format", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "BCA")
+			mw.WriteField("matching_options", `{"duplicate":"ID"}`)
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+		})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("invalid matching options", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "BCA")
+			mw.WriteField("matching_options", `{"duplicate_rule":"UNKNOWN"}`)
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+		})
+		s.mockCreatorService.EXPECT().Create(ctx, gomock.Any()).Return(nil, reconciliatonjob.ErrInvalidMatchingOptions)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
 	})
 
 	s.Run("empty payload", func() {
@@ -297,6 +1281,23 @@ func (s *ReconciliationJobHandlerTestSuite) TestCreateReconciliationJob() {
 		s.Equal(http.StatusBadRequest, resp.Code)
 	})
 
+	s.Run("duplicate bank names", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("discrepancy_threshold", "0.1")
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "BCA")
+			mw.WriteField("bank_names", " bca")
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+		})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
 	s.Run("empty bank transaction files", func() {
 		req := s.buildCreatorReq(func(mw *multipart.Writer) {
 			mw.WriteField("discrepancy_threshold", "0.1")
@@ -342,6 +1343,191 @@ func (s *ReconciliationJobHandlerTestSuite) TestCreateReconciliationJob() {
 	})
 }
 
+func (s *ReconciliationJobHandlerTestSuite) TestCreateReconciliationJob_StoreFile() {
+	ctx := context.Background()
+
+	s.Run("error store uploaded file", func() {
+		req := s.buildCreatorReq(func(mw *multipart.Writer) {
+			mw.WriteField("start_date", now.Format("2006-01-02"))
+			mw.WriteField("end_date", now.Format("2006-01-02"))
+			mw.WriteField("bank_names", "BCA")
+			s.createFormFile(mw, "system_transaction_file", "system_trx.csv")
+			s.createFormFile(mw, "bank_transaction_files", "bca_trx.csv")
+		})
+		s.mockCreatorService.EXPECT().StoreFile(ctx, gomock.Any(), gomock.Any()).Return(assert.AnError)
+		s.mockCreatorService.EXPECT().DiscardFiles(gomock.Any(), gomock.Any())
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestCancelReconciliationJob() {
+	ctx := context.Background()
+
+	req, _ := http.NewRequest(http.MethodPost, "/reconciliations/1/cancel", nil)
+	s.Run("success", func() {
+		s.mockCancelService.EXPECT().Cancel(ctx, id).Return(entityReconJob, nil)
+
+		resp := s.executeReq(req)
+
+		jsonRecon, _ := json.Marshal(entityReconJob)
+		s.Equal(http.StatusOK, resp.Code)
+		s.Contains(resp.Body.String(), string(jsonRecon))
+	})
+
+	s.Run("not found", func() {
+		s.mockCancelService.EXPECT().Cancel(ctx, id).Return(nil, reconciliatonjob.ErrReconciliationJobNotFound)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusNotFound, resp.Code)
+		s.Contains(resp.Body.String(), "reconciliation job not found")
+	})
+
+	s.Run("not cancellable", func() {
+		s.mockCancelService.EXPECT().Cancel(ctx, id).Return(nil, reconciliatonjob.ErrReconciliationJobNotCancellable)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("internal server error", func() {
+		s.mockCancelService.EXPECT().Cancel(ctx, id).Return(nil, assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+
+	s.Run("invalid id", func() {
+		req, _ := http.NewRequest(http.MethodPost, "/reconciliations/invalid/cancel", nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+}
+
+func (s *ReconciliationJobHandlerTestSuite) TestRerunReconciliationJob() {
+	ctx := context.Background()
+
+	s.Run("success", func() {
+		form := url.Values{}
+		form.Set("start_date", "2024-11-01")
+		form.Set("end_date", "2024-11-30")
+		form.Set("discrepancy_threshold", "0.2")
+		req := s.buildFormReq("/reconciliations/1/rerun", form)
+		startDate := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+		endDate := time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+		threshold := float32(0.2)
+		s.mockCreatorService.EXPECT().Rerun(ctx, &reconciliatonjob.RerunParams{
+			JobID:                id,
+			StartDate:            &startDate,
+			EndDate:              &endDate,
+			DiscrepancyThreshold: &threshold,
+		}).Return(entityReconJob, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusCreated, resp.Code)
+	})
+
+	s.Run("success without overridden params", func() {
+		req := s.buildFormReq("/reconciliations/1/rerun", url.Values{})
+		s.mockCreatorService.EXPECT().Rerun(ctx, &reconciliatonjob.RerunParams{JobID: id}).Return(entityReconJob, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusCreated, resp.Code)
+	})
+
+	s.Run("success with overridden matching options", func() {
+		form := url.Values{}
+		form.Set("matching_options", `{"duplicate_rule":"ID","deduplicate":true}`)
+		req := s.buildFormReq("/reconciliations/1/rerun", form)
+		s.mockCreatorService.EXPECT().Rerun(ctx, &reconciliatonjob.RerunParams{
+			JobID: id,
+			MatchingOptions: &entity.MatchingOptions{
+				DuplicateRule: entity.DuplicateRuleID,
+				Deduplicate:   true,
+			},
+		}).Return(entityReconJob, nil)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusCreated, resp.Code)
+	})
+
+	s.Run("invalid matching options format", func() {
+		form := url.Values{}
+		form.Set("matching_options", "duplicate_rule=ID")
+		req := s.buildFormReq("/reconciliations/1/rerun", form)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "matching options must be a valid JSON object")
+	})
+
+	s.Run("not found", func() {
+		req := s.buildFormReq("/reconciliations/1/rerun", url.Values{})
+		s.mockCreatorService.EXPECT().Rerun(ctx, gomock.Any()).Return(nil, reconciliatonjob.ErrReconciliationJobNotFound)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusNotFound, resp.Code)
+	})
+
+	s.Run("period locked", func() {
+		req := s.buildFormReq("/reconciliations/1/rerun", url.Values{})
+		s.mockCreatorService.EXPECT().Rerun(ctx, gomock.Any()).Return(nil, reconciliatonjob.ErrPeriodLocked)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusConflict, resp.Code)
+	})
+
+	s.Run("invalid date range", func() {
+		req := s.buildFormReq("/reconciliations/1/rerun", url.Values{})
+		s.mockCreatorService.EXPECT().Rerun(ctx, gomock.Any()).Return(nil, reconciliatonjob.ErrInvalidDateRange)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+
+	s.Run("invalid start date", func() {
+		form := url.Values{}
+		form.Set("start_date", "01-11-2024")
+		req := s.buildFormReq("/reconciliations/1/rerun", form)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+		s.Contains(resp.Body.String(), "start date must be in YYYY-MM-DD format")
+	})
+
+	s.Run("internal server error", func() {
+		req := s.buildFormReq("/reconciliations/1/rerun", url.Values{})
+		s.mockCreatorService.EXPECT().Rerun(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusInternalServerError, resp.Code)
+	})
+
+	s.Run("invalid id", func() {
+		req := s.buildFormReq("/reconciliations/invalid/rerun", url.Values{})
+
+		resp := s.executeReq(req)
+
+		s.Equal(http.StatusBadRequest, resp.Code)
+	})
+}
+
 func (s *ReconciliationJobHandlerTestSuite) executeReq(req *http.Request) *httptest.ResponseRecorder {
 	rr := httptest.NewRecorder()
 	s.router.ServeHTTP(rr, req)
@@ -360,6 +1546,13 @@ func (s *ReconciliationJobHandlerTestSuite) buildCreatorReq(fn func(mw *multipar
 	return req
 }
 
+func (s *ReconciliationJobHandlerTestSuite) buildFormReq(path string, form url.Values) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return req
+}
+
 func (s *ReconciliationJobHandlerTestSuite) createFormFile(mw *multipart.Writer, fieldName, fileName string) {
 	f, _ := mw.CreateFormFile(fieldName, fileName)
 	file, _ := os.Open("../../test/data/" + fileName)
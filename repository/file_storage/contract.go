@@ -1,9 +1,14 @@
 package filestorage
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
-// FileStorageRepository is contract to store and get file
+// FileStorageRepository is contract to store, get and delete file, files are
+// streamed on both directions so the content is never loaded whole into memory
 type FileStorageRepository interface {
-	Get(ctx context.Context, filePath string) (*File, error)
+	Delete(ctx context.Context, filePath string) error
+	Get(ctx context.Context, filePath string) (io.ReadCloser, error)
 	Store(ctx context.Context, file *File) (string, error)
 }
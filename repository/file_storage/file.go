@@ -1,10 +1,10 @@
 package filestorage
 
-import "bytes"
+import "io"
 
-// File is a struct to hold metadata of csv file
+// File is a struct to hold metadata and content stream of csv file
 type File struct {
-	Name string
-	Dir  string
-	Buf  *bytes.Buffer
+	Name   string
+	Dir    string
+	Reader io.Reader
 }
@@ -1,7 +1,6 @@
 package gcs
 
 import (
-	"bytes"
 	"context"
 	"io"
 	"path/filepath"
@@ -26,8 +25,8 @@ func NewBucket(bucket *storage.BucketHandle) *Bucket {
 	}
 }
 
-// Get get file from GCS Bucket
-func (b *Bucket) Get(ctx context.Context, filePath string) (*filestorage.File, error) {
+// Get get file stream from GCS Bucket, caller must close the returned reader
+func (b *Bucket) Get(ctx context.Context, filePath string) (io.ReadCloser, error) {
 	log := logger.WithMethod(b.log, "Get")
 	obj := b.bucket.Object(filePath)
 	r, err := obj.NewReader(ctx)
@@ -36,17 +35,7 @@ func (b *Bucket) Get(ctx context.Context, filePath string) (*filestorage.File, e
 		return nil, err
 	}
 
-	buf, err := io.ReadAll(r)
-	if err != nil {
-		log.Error("failed to read file", zap.Error(err), zap.String("file", filePath))
-		return nil, err
-	}
-
-	return &filestorage.File{
-		Name: filepath.Base(filePath),
-		Dir:  filepath.Dir(filePath),
-		Buf:  bytes.NewBuffer(buf),
-	}, nil
+	return r, nil
 }
 
 // Store store file to GCS Bucket
@@ -54,10 +43,16 @@ func (b *Bucket) Store(ctx context.Context, file *filestorage.File) (string, err
 	log := logger.WithMethod(b.log, "Store")
 	target := filepath.Join(file.Dir, file.Name)
 	obj := b.bucket.Object(target)
+	// the upload is cancelled instead of closed when the content cannot be
+	// read, so a partial object is never committed
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	w := obj.NewWriter(ctx)
 
-	if _, err := io.Copy(w, file.Buf); err != nil {
+	if _, err := io.Copy(w, file.Reader); err != nil {
 		log.Error("failed to write file", zap.Error(err), zap.String("file", target))
+		cancel()
+		w.Close()
 		return "", err
 	}
 
@@ -68,3 +63,14 @@ func (b *Bucket) Store(ctx context.Context, file *filestorage.File) (string, err
 
 	return target, nil
 }
+
+// Delete delete file from GCS Bucket
+func (b *Bucket) Delete(ctx context.Context, filePath string) error {
+	log := logger.WithMethod(b.log, "Delete")
+	if err := b.bucket.Object(filePath).Delete(ctx); err != nil {
+		log.Error("failed to delete file", zap.Error(err), zap.String("file", filePath))
+		return err
+	}
+
+	return nil
+}
@@ -1,9 +1,9 @@
 package localfilestorage
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -36,35 +36,36 @@ func (lfs *Storage) Store(_ context.Context, file *filestorage.File) (string, er
 	if err != nil {
 		return "", fmt.Errorf("failed to create file: %w", err)
 	}
-	defer outFile.Close()
 
-	if _, err := outFile.Write(file.Buf.Bytes()); err != nil {
+	// the partially written file is removed so a rejected upload is not kept
+	if _, err := io.Copy(outFile, file.Reader); err != nil {
+		outFile.Close()
+		os.Remove(targetPath)
 		return "", fmt.Errorf("failed to write file content: %w", err)
 	}
+	if err := outFile.Close(); err != nil {
+		os.Remove(targetPath)
+		return "", fmt.Errorf("failed to close file: %w", err)
+	}
 
 	return targetPath, nil
 }
 
-// Get is a function to get file from local storage
-func (lfs *Storage) Get(_ context.Context, filePath string) (*filestorage.File, error) {
+// Get is a function to get file stream from local storage, caller must close the returned reader
+func (lfs *Storage) Get(_ context.Context, filePath string) (io.ReadCloser, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
-	defer file.Close()
 
-	stat, err := file.Stat()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get file stat: %w", err)
-	}
+	return file, nil
+}
 
-	buf := make([]byte, stat.Size())
-	if _, err := file.Read(buf); err != nil {
-		return nil, fmt.Errorf("failed to read file content: %w", err)
+// Delete is a function to delete file from local storage
+func (lfs *Storage) Delete(_ context.Context, filePath string) error {
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
 	}
 
-	return &filestorage.File{
-		Name: stat.Name(),
-		Buf:  bytes.NewBuffer(buf),
-	}, nil
+	return nil
 }
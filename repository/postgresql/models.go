@@ -11,6 +11,68 @@ import (
 	"github.com/jackc/pgtype"
 )
 
+type ReconciliationAdjustment struct {
+	ID         int64          `db:"id"`
+	JobID      int64          `db:"job_id"`
+	Type       string         `db:"type"`
+	ReasonCode sql.NullString `db:"reason_code"`
+	Note       string         `db:"note"`
+	CreatedBy  string         `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+type ReconciliationApproval struct {
+	ID        int64     `db:"id"`
+	JobID     int64     `db:"job_id"`
+	Status    string    `db:"status"`
+	Comment   string    `db:"comment"`
+	CreatedBy string    `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type ReconciliationCase struct {
+	ID           int64         `db:"id"`
+	ItemID       int64         `db:"item_id"`
+	Assignee     string        `db:"assignee"`
+	Status       string        `db:"status"`
+	DueDate      sql.NullTime  `db:"due_date"`
+	CreatedBy    string        `db:"created_by"`
+	CreatedAt    time.Time     `db:"created_at"`
+	UpdatedAt    time.Time     `db:"updated_at"`
+	AdjustmentID sql.NullInt64 `db:"adjustment_id"`
+}
+
+type ReconciliationCaseComment struct {
+	ID        int64          `db:"id"`
+	CaseID    int64          `db:"case_id"`
+	Status    sql.NullString `db:"status"`
+	Comment   string         `db:"comment"`
+	CreatedBy string         `db:"created_by"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+type ReconciliationItem struct {
+	ID              int64          `db:"id"`
+	JobID           int64          `db:"job_id"`
+	Source          string         `db:"source"`
+	BankName        sql.NullString `db:"bank_name"`
+	RowNumber       int32          `db:"row_number"`
+	TransactionID   string         `db:"transaction_id"`
+	Amount          float64        `db:"amount"`
+	Type            string         `db:"type"`
+	TransactionTime time.Time      `db:"transaction_time"`
+	Status          string         `db:"status"`
+	CreatedAt       time.Time      `db:"created_at"`
+	CarriedFromID   sql.NullInt64  `db:"carried_from_id"`
+	ResolvedJobID   sql.NullInt64  `db:"resolved_job_id"`
+	AdjustmentID    sql.NullInt64  `db:"adjustment_id"`
+	DuplicateOfRow  sql.NullInt32  `db:"duplicate_of_row"`
+	OverlapBankName sql.NullString `db:"overlap_bank_name"`
+	OverlapRow      sql.NullInt32  `db:"overlap_row"`
+	PairedBankName  sql.NullString `db:"paired_bank_name"`
+	PairedRow       sql.NullInt32  `db:"paired_row"`
+}
+
 type ReconciliationJob struct {
 	ID                       int64          `db:"id"`
 	Status                   string         `db:"status"`
@@ -23,4 +85,25 @@ type ReconciliationJob struct {
 	CreatedAt                time.Time      `db:"created_at"`
 	UpdatedAt                time.Time      `db:"updated_at"`
 	ErrorInformation         sql.NullString `db:"error_information"`
+	ErrorCode                sql.NullString `db:"error_code"`
+	ParentJobID              sql.NullInt64  `db:"parent_job_id"`
+	Progress                 pgtype.JSONB   `db:"progress"`
+	MatchingOptions          pgtype.JSONB   `db:"matching_options"`
+	AdjustedTotals           pgtype.JSONB   `db:"adjusted_totals"`
+	ApprovalStatus           sql.NullString `db:"approval_status"`
+	LeaseExpiresAt           sql.NullTime   `db:"lease_expires_at"`
+	ClaimToken               int64          `db:"claim_token"`
+}
+
+type ReconciliationPeriodLock struct {
+	ID           int64          `db:"id"`
+	JobID        int64          `db:"job_id"`
+	BankName     string         `db:"bank_name"`
+	StartDate    time.Time      `db:"start_date"`
+	EndDate      time.Time      `db:"end_date"`
+	LockedBy     string         `db:"locked_by"`
+	LockedAt     time.Time      `db:"locked_at"`
+	UnlockedBy   sql.NullString `db:"unlocked_by"`
+	UnlockedAt   sql.NullTime   `db:"unlocked_at"`
+	UnlockReason sql.NullString `db:"unlock_reason"`
 }
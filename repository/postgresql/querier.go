@@ -6,16 +6,60 @@ package dbgen
 
 import (
 	"context"
+	"time"
 )
 
 type Querier interface {
+	AdjustReconciliationItems(ctx context.Context, arg AdjustReconciliationItemsParams) (int64, error)
+	CancelReconciliationJob(ctx context.Context, id int64) (ReconciliationJob, error)
+	CopyReconciliationItems(ctx context.Context, arg []CopyReconciliationItemsParams) (int64, error)
+	CopyReconciliationMatches(ctx context.Context, arg []CopyReconciliationMatchesParams) (int64, error)
+	CountReconciliationCasesByAssignee(ctx context.Context, arg CountReconciliationCasesByAssigneeParams) (int64, error)
+	CountReconciliationDiffItems(ctx context.Context, arg CountReconciliationDiffItemsParams) (int64, error)
+	CountReconciliationItems(ctx context.Context, arg CountReconciliationItemsParams) (int64, error)
 	CountReconciliationJobs(ctx context.Context) (int64, error)
+	CreateReconciliationAdjustment(ctx context.Context, arg CreateReconciliationAdjustmentParams) (ReconciliationAdjustment, error)
+	CreateReconciliationApproval(ctx context.Context, arg CreateReconciliationApprovalParams) (ReconciliationApproval, error)
+	CreateReconciliationCase(ctx context.Context, arg CreateReconciliationCaseParams) (ReconciliationCase, error)
+	CreateReconciliationCaseComment(ctx context.Context, arg CreateReconciliationCaseCommentParams) (ReconciliationCaseComment, error)
 	CreateReconciliationJob(ctx context.Context, arg CreateReconciliationJobParams) (ReconciliationJob, error)
+	CreateReconciliationPeriodLocks(ctx context.Context, arg CreateReconciliationPeriodLocksParams) error
+	DeleteReconciliationAdjustment(ctx context.Context, id int64) error
+	DiffReconciliationItems(ctx context.Context, arg DiffReconciliationItemsParams) ([]DiffReconciliationItemsRow, error)
+	ExistsReconciliationItems(ctx context.Context, jobID int64) (bool, error)
+	GetReconciliationCaseById(ctx context.Context, id int64) (GetReconciliationCaseByIdRow, error)
 	GetReconciliationJobById(ctx context.Context, id int64) (ReconciliationJob, error)
+	GetReconciliationJobStatus(ctx context.Context, id int64) (string, error)
+	GetReconciliationJobStatusForUpdate(ctx context.Context, id int64) (string, error)
+	GetReconciliationPeriodLockById(ctx context.Context, id int64) (ReconciliationPeriodLock, error)
+	ListMissingReconciliationItems(ctx context.Context, arg ListMissingReconciliationItemsParams) ([]ReconciliationItem, error)
+	ListOpenReconciliationItems(ctx context.Context, jobID int64) ([]ReconciliationItem, error)
+	ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg ListOverlappingReconciliationPeriodLocksParams) ([]ReconciliationPeriodLock, error)
 	ListPendingReconciliationJobs(ctx context.Context) ([]ReconciliationJob, error)
+	ListReconciliationAdjustments(ctx context.Context, jobID int64) ([]ListReconciliationAdjustmentsRow, error)
+	ListReconciliationApprovals(ctx context.Context, jobID int64) ([]ReconciliationApproval, error)
+	ListReconciliationCaseComments(ctx context.Context, caseID int64) ([]ReconciliationCaseComment, error)
+	ListReconciliationCasesByAssignee(ctx context.Context, arg ListReconciliationCasesByAssigneeParams) ([]ListReconciliationCasesByAssigneeRow, error)
+	ListReconciliationItems(ctx context.Context, arg ListReconciliationItemsParams) ([]ReconciliationItem, error)
+	ListReconciliationItemsByIds(ctx context.Context, arg ListReconciliationItemsByIdsParams) ([]ReconciliationItem, error)
 	ListReconciliationJobs(ctx context.Context, arg ListReconciliationJobsParams) ([]ListReconciliationJobsRow, error)
+	ListReconciliationMatchedPairs(ctx context.Context, arg ListReconciliationMatchedPairsParams) ([]ListReconciliationMatchedPairsRow, error)
+	ListReconciliationPeriodLocks(ctx context.Context, arg ListReconciliationPeriodLocksParams) ([]ReconciliationPeriodLock, error)
+	ListSuccessReconciliationJobsByEndDate(ctx context.Context, endDate time.Time) ([]ReconciliationJob, error)
+	LockReconciliationPeriodBanks(ctx context.Context, bankNames []string) error
+	RenewReconciliationJobLease(ctx context.Context, arg RenewReconciliationJobLeaseParams) (int64, error)
+	ResolveCarriedReconciliationItems(ctx context.Context, arg ResolveCarriedReconciliationItemsParams) error
 	SaveFailedReconciliationJob(ctx context.Context, arg SaveFailedReconciliationJobParams) (ReconciliationJob, error)
 	SaveSuccessReconciliationJob(ctx context.Context, arg SaveSuccessReconciliationJobParams) (ReconciliationJob, error)
+	StartReconciliationJob(ctx context.Context, arg StartReconciliationJobParams) (ReconciliationJob, error)
+	SummarizeReconciliationItems(ctx context.Context, jobID int64) ([]SummarizeReconciliationItemsRow, error)
+	SummarizeUnresolvedReconciliationItems(ctx context.Context, asOf time.Time) ([]SummarizeUnresolvedReconciliationItemsRow, error)
+	UnlockReconciliationPeriodLock(ctx context.Context, arg UnlockReconciliationPeriodLockParams) (ReconciliationPeriodLock, error)
+	UnlockReconciliationPeriodLocksByJob(ctx context.Context, arg UnlockReconciliationPeriodLocksByJobParams) error
+	UpdateReconciliationCaseStatus(ctx context.Context, arg UpdateReconciliationCaseStatusParams) (ReconciliationCase, error)
+	UpdateReconciliationJobAdjustedTotals(ctx context.Context, jobID int64) error
+	UpdateReconciliationJobApprovalStatus(ctx context.Context, arg UpdateReconciliationJobApprovalStatusParams) (int64, error)
+	UpdateReconciliationJobProgress(ctx context.Context, arg UpdateReconciliationJobProgressParams) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,161 @@
+package dbgen_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/suite"
+)
+
+// ReconciliationItemsTestSuite run the queries of the reconciliation items
+// against the database of TEST_DATABASE_URL, every test runs on a new schema
+// migrated from db/migrations and the suite is skipped when it is not set
+type ReconciliationItemsTestSuite struct {
+	suite.Suite
+	conn *pgx.Conn
+	q    *dbgen.Queries
+}
+
+func TestReconciliationItemsTestSuite(t *testing.T) {
+	suite.Run(t, new(ReconciliationItemsTestSuite))
+}
+
+func (s *ReconciliationItemsTestSuite) SetupTest() {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		s.T().Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, url)
+	s.Require().NoError(err)
+	s.conn = conn
+	s.q = dbgen.New(conn)
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	// the extensions are shared by every schema so they are kept in public
+	_, err = conn.Exec(ctx, "CREATE EXTENSION IF NOT EXISTS btree_gist SCHEMA public")
+	s.Require().NoError(err)
+	_, err = conn.Exec(ctx, fmt.Sprintf("CREATE SCHEMA %s; SET search_path TO %s, public", schema, schema))
+	s.Require().NoError(err)
+	s.T().Cleanup(func() {
+		conn.Exec(context.Background(), fmt.Sprintf("DROP SCHEMA %s CASCADE", schema))
+		conn.Close(context.Background())
+	})
+
+	migrations, err := filepath.Glob("../../db/migrations/*.up.sql")
+	s.Require().NoError(err)
+	slices.Sort(migrations)
+	for _, migration := range migrations {
+		b, err := os.ReadFile(migration)
+		s.Require().NoError(err)
+		_, err = conn.Exec(ctx, string(b))
+		s.Require().NoError(err, migration)
+	}
+}
+
+// createJob create a successful job of the banks, parentJobID is zero when
+// the job is not a re-run
+func (s *ReconciliationItemsTestSuite) createJob(startDate, endDate string, createdAt time.Time, parentJobID int64, bankNames ...string) int64 {
+	banks := "["
+	for i, bankName := range bankNames {
+		if i > 0 {
+			banks += ","
+		}
+		banks += fmt.Sprintf(`{"bank_name":%q,"file_path":"%s.csv"}`, bankName, bankName)
+	}
+	banks += "]"
+	var id int64
+	err := s.conn.QueryRow(context.Background(), `INSERT INTO reconciliation_jobs
+(status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, created_at, parent_job_id)
+VALUES ('SUCCESS', 'system.csv', $1, 0, $2, $3, $4, NULLIF($5, 0)) RETURNING id`,
+		banks, startDate, endDate, createdAt, parentJobID).Scan(&id)
+	s.Require().NoError(err)
+
+	return id
+}
+
+// createItem create an item of the job, bankName is empty for system item
+func (s *ReconciliationItemsTestSuite) createItem(jobID int64, bankName string, row int32, transactionID string, amount float64, transactionTime, status string) {
+	source := "BANK"
+	if bankName == "" {
+		source = "SYSTEM"
+	}
+	_, err := s.conn.Exec(context.Background(), `INSERT INTO reconciliation_items
+(job_id, source, bank_name, row_number, transaction_id, amount, type, transaction_time, status)
+VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6, 'CREDIT', $7, $8)`,
+		jobID, source, bankName, row, transactionID, amount, transactionTime, status)
+	s.Require().NoError(err)
+}
+
+func (s *ReconciliationItemsTestSuite) TestSummarizeUnresolvedReconciliationItems() {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	asOf := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	s.Run("success count the items of a job outside the range of its narrower re-run", func() {
+		jobID := s.createJob("2024-11-01", "2024-11-30", createdAt, 0, "BCA")
+		s.createItem(jobID, "", 1, "TRX-1", 100, "2024-11-05T10:00:00Z", "MISSING")
+		s.createItem(jobID, "", 2, "TRX-2", 200, "2024-11-20T10:00:00Z", "MISSING")
+		rerunID := s.createJob("2024-11-15", "2024-11-30", createdAt.Add(time.Hour), jobID, "BCA")
+		s.createItem(rerunID, "", 1, "TRX-2", 250, "2024-11-20T10:00:00Z", "MISSING")
+
+		rows, err := s.q.SummarizeUnresolvedReconciliationItems(ctx, asOf)
+
+		s.Require().NoError(err)
+		// the item within the range of the re-run is only counted from the re-run
+		s.Equal([]dbgen.SummarizeUnresolvedReconciliationItemsRow{
+			{Source: "SYSTEM", BankName: sql.NullString{}, Type: "CREDIT", AgeDays: 41, TotalTransaction: 1, TotalAmount: 250},
+			{Source: "SYSTEM", BankName: sql.NullString{}, Type: "CREDIT", AgeDays: 56, TotalTransaction: 1, TotalAmount: 100},
+		}, rows)
+	})
+}
+
+func (s *ReconciliationItemsTestSuite) TestDiffReconciliationItems() {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	row := func(v int32) sql.NullInt32 { return sql.NullInt32{Int32: v, Valid: true} }
+	amount := func(v float64) sql.NullFloat64 { return sql.NullFloat64{Float64: v, Valid: true} }
+	status := func(v string) sql.NullString { return sql.NullString{String: v, Valid: true} }
+
+	s.Run("success report every status and amount change along with the added and removed items", func() {
+		jobID := s.createJob("2024-11-01", "2024-11-30", createdAt, 0, "BCA")
+		s.createItem(jobID, "", 1, "TRX-1", 100, "2024-11-01T10:00:00Z", "MISSING")
+		s.createItem(jobID, "", 2, "TRX-2", 500, "2024-11-02T10:00:00Z", "MATCHED")
+		s.createItem(jobID, "", 3, "TRX-3", 300, "2024-11-03T10:00:00Z", "NEEDS_REVIEW")
+		s.createItem(jobID, "", 4, "TRX-4", 400, "2024-11-04T10:00:00Z", "MATCHED")
+		s.createItem(jobID, "", 5, "TRX-5", 50, "2024-11-05T10:00:00Z", "DUPLICATE")
+		s.createItem(jobID, "", 6, "TRX-6", 70, "2024-11-06T10:00:00Z", "MISSING")
+		s.createItem(jobID, "", 7, "TRX-6", 70, "2024-11-06T10:00:00Z", "MISSING")
+		otherID := s.createJob("2024-11-01", "2024-11-30", createdAt.Add(time.Hour), jobID, "BCA")
+		s.createItem(otherID, "", 1, "TRX-1", 100, "2024-11-01T10:00:00Z", "MATCHED")
+		s.createItem(otherID, "", 2, "TRX-2", 550, "2024-11-02T10:00:00Z", "MATCHED")
+		s.createItem(otherID, "", 3, "TRX-3", 300, "2024-11-03T10:00:00Z", "REVERSED")
+		s.createItem(otherID, "", 4, "TRX-4", 400, "2024-11-04T10:00:00Z", "MATCHED")
+		s.createItem(otherID, "", 6, "TRX-6", 70, "2024-11-06T10:00:00Z", "MISSING")
+		s.createItem(otherID, "", 7, "TRX-6", 70, "2024-11-06T10:00:00Z", "INTERNAL_TRANSFER")
+		s.createItem(otherID, "BCA", 1, "BCA-1", 200, "2024-11-01T00:00:00Z", "MISSING")
+
+		rows, err := s.q.DiffReconciliationItems(ctx, dbgen.DiffReconciliationItemsParams{JobID: jobID, OtherJobID: otherID, Limit: 10})
+		s.Require().NoError(err)
+		total, err := s.q.CountReconciliationDiffItems(ctx, dbgen.CountReconciliationDiffItemsParams{JobID: jobID, OtherJobID: otherID})
+		s.Require().NoError(err)
+
+		s.Equal([]dbgen.DiffReconciliationItemsRow{
+			{Source: "SYSTEM", TransactionID: "TRX-1", BaseRow: row(1), BaseAmount: amount(100), BaseStatus: status("MISSING"), OtherRow: row(1), OtherAmount: amount(100), OtherStatus: status("MATCHED")},
+			{Source: "SYSTEM", TransactionID: "TRX-2", BaseRow: row(2), BaseAmount: amount(500), BaseStatus: status("MATCHED"), OtherRow: row(2), OtherAmount: amount(550), OtherStatus: status("MATCHED")},
+			{Source: "SYSTEM", TransactionID: "TRX-3", BaseRow: row(3), BaseAmount: amount(300), BaseStatus: status("NEEDS_REVIEW"), OtherRow: row(3), OtherAmount: amount(300), OtherStatus: status("REVERSED")},
+			{Source: "SYSTEM", TransactionID: "TRX-5", BaseRow: row(5), BaseAmount: amount(50), BaseStatus: status("DUPLICATE")},
+			{Source: "SYSTEM", TransactionID: "TRX-6", BaseRow: row(7), BaseAmount: amount(70), BaseStatus: status("MISSING"), OtherRow: row(7), OtherAmount: amount(70), OtherStatus: status("INTERNAL_TRANSFER")},
+			{Source: "BANK", BankName: "BCA", TransactionID: "BCA-1", OtherRow: row(1), OtherAmount: amount(200), OtherStatus: status("MISSING")},
+		}, rows)
+		s.Equal(int64(len(rows)), total)
+	})
+}
@@ -13,6 +13,37 @@ import (
 	"github.com/jackc/pgtype"
 )
 
+const cancelReconciliationJob = `-- name: CancelReconciliationJob :one
+UPDATE reconciliation_jobs SET status = 'CANCELLED' WHERE id = $1 AND status IN ('PENDING', 'PROCESSING') RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at, claim_token
+`
+
+func (q *Queries) CancelReconciliationJob(ctx context.Context, id int64) (ReconciliationJob, error) {
+	row := q.db.QueryRow(ctx, cancelReconciliationJob, id)
+	var i ReconciliationJob
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.SystemTransactionCsvPath,
+		&i.BankTransactionCsvPaths,
+		&i.DiscrepancyThreshold,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Result,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ErrorInformation,
+		&i.ErrorCode,
+		&i.ParentJobID,
+		&i.Progress,
+		&i.MatchingOptions,
+		&i.AdjustedTotals,
+		&i.ApprovalStatus,
+		&i.LeaseExpiresAt,
+		&i.ClaimToken,
+	)
+	return i, err
+}
+
 const countReconciliationJobs = `-- name: CountReconciliationJobs :one
 SELECT COUNT(1) FROM reconciliation_jobs
 `
@@ -25,16 +56,18 @@ func (q *Queries) CountReconciliationJobs(ctx context.Context) (int64, error) {
 }
 
 const createReconciliationJob = `-- name: CreateReconciliationJob :one
-INSERT INTO reconciliation_jobs (status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date) VALUES ('PENDING', $1, $2, $3, $4, $5)
-RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information
+INSERT INTO reconciliation_jobs (status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, parent_job_id, matching_options) VALUES ('PENDING', $1, $2, $3, $4, $5, $6, $7)
+RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at, claim_token
 `
 
 type CreateReconciliationJobParams struct {
-	SystemTransactionCsvPath string       `db:"system_transaction_csv_path"`
-	BankTransactionCsvPaths  pgtype.JSONB `db:"bank_transaction_csv_paths"`
-	DiscrepancyThreshold     float64      `db:"discrepancy_threshold"`
-	StartDate                time.Time    `db:"start_date"`
-	EndDate                  time.Time    `db:"end_date"`
+	SystemTransactionCsvPath string        `db:"system_transaction_csv_path"`
+	BankTransactionCsvPaths  pgtype.JSONB  `db:"bank_transaction_csv_paths"`
+	DiscrepancyThreshold     float64       `db:"discrepancy_threshold"`
+	StartDate                time.Time     `db:"start_date"`
+	EndDate                  time.Time     `db:"end_date"`
+	ParentJobID              sql.NullInt64 `db:"parent_job_id"`
+	MatchingOptions          pgtype.JSONB  `db:"matching_options"`
 }
 
 func (q *Queries) CreateReconciliationJob(ctx context.Context, arg CreateReconciliationJobParams) (ReconciliationJob, error) {
@@ -44,6 +77,8 @@ func (q *Queries) CreateReconciliationJob(ctx context.Context, arg CreateReconci
 		arg.DiscrepancyThreshold,
 		arg.StartDate,
 		arg.EndDate,
+		arg.ParentJobID,
+		arg.MatchingOptions,
 	)
 	var i ReconciliationJob
 	err := row.Scan(
@@ -58,12 +93,20 @@ func (q *Queries) CreateReconciliationJob(ctx context.Context, arg CreateReconci
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ErrorInformation,
+		&i.ErrorCode,
+		&i.ParentJobID,
+		&i.Progress,
+		&i.MatchingOptions,
+		&i.AdjustedTotals,
+		&i.ApprovalStatus,
+		&i.LeaseExpiresAt,
+		&i.ClaimToken,
 	)
 	return i, err
 }
 
 const getReconciliationJobById = `-- name: GetReconciliationJobById :one
-SELECT id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information FROM reconciliation_jobs WHERE id = $1
+SELECT id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at FROM reconciliation_jobs WHERE id = $1
 `
 
 func (q *Queries) GetReconciliationJobById(ctx context.Context, id int64) (ReconciliationJob, error) {
@@ -81,13 +124,44 @@ func (q *Queries) GetReconciliationJobById(ctx context.Context, id int64) (Recon
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ErrorInformation,
+		&i.ErrorCode,
+		&i.ParentJobID,
+		&i.Progress,
+		&i.MatchingOptions,
+		&i.AdjustedTotals,
+		&i.ApprovalStatus,
+		&i.LeaseExpiresAt,
+		&i.ClaimToken,
 	)
 	return i, err
 }
 
+const getReconciliationJobStatus = `-- name: GetReconciliationJobStatus :one
+SELECT status FROM reconciliation_jobs WHERE id = $1
+`
+
+func (q *Queries) GetReconciliationJobStatus(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRow(ctx, getReconciliationJobStatus, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const getReconciliationJobStatusForUpdate = `-- name: GetReconciliationJobStatusForUpdate :one
+SELECT status FROM reconciliation_jobs WHERE id = $1 FOR UPDATE
+`
+
+func (q *Queries) GetReconciliationJobStatusForUpdate(ctx context.Context, id int64) (string, error) {
+	row := q.db.QueryRow(ctx, getReconciliationJobStatusForUpdate, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
+}
+
 const listPendingReconciliationJobs = `-- name: ListPendingReconciliationJobs :many
-SELECT id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information FROM reconciliation_jobs
+SELECT id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at FROM reconciliation_jobs
 WHERE status = 'PENDING'
+OR (status = 'PROCESSING' AND COALESCE(lease_expires_at, '-infinity') < now())
 ORDER BY created_at ASC
 `
 
@@ -112,6 +186,14 @@ func (q *Queries) ListPendingReconciliationJobs(ctx context.Context) ([]Reconcil
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.ErrorInformation,
+			&i.ErrorCode,
+			&i.ParentJobID,
+			&i.Progress,
+			&i.MatchingOptions,
+			&i.AdjustedTotals,
+			&i.ApprovalStatus,
+			&i.LeaseExpiresAt,
+			&i.ClaimToken,
 		); err != nil {
 			return nil, err
 		}
@@ -125,7 +207,7 @@ func (q *Queries) ListPendingReconciliationJobs(ctx context.Context) ([]Reconcil
 
 const listReconciliationJobs = `-- name: ListReconciliationJobs :many
 SELECT id, status, start_date, end_date, discrepancy_threshold,
-system_transaction_csv_path, bank_transaction_csv_paths FROM reconciliation_jobs
+system_transaction_csv_path, bank_transaction_csv_paths, approval_status FROM reconciliation_jobs
 ORDER BY id DESC
 LIMIT $1 OFFSET $2
 `
@@ -136,13 +218,14 @@ type ListReconciliationJobsParams struct {
 }
 
 type ListReconciliationJobsRow struct {
-	ID                       int64        `db:"id"`
-	Status                   string       `db:"status"`
-	StartDate                time.Time    `db:"start_date"`
-	EndDate                  time.Time    `db:"end_date"`
-	DiscrepancyThreshold     float64      `db:"discrepancy_threshold"`
-	SystemTransactionCsvPath string       `db:"system_transaction_csv_path"`
-	BankTransactionCsvPaths  pgtype.JSONB `db:"bank_transaction_csv_paths"`
+	ID                       int64          `db:"id"`
+	Status                   string         `db:"status"`
+	StartDate                time.Time      `db:"start_date"`
+	EndDate                  time.Time      `db:"end_date"`
+	DiscrepancyThreshold     float64        `db:"discrepancy_threshold"`
+	SystemTransactionCsvPath string         `db:"system_transaction_csv_path"`
+	BankTransactionCsvPaths  pgtype.JSONB   `db:"bank_transaction_csv_paths"`
+	ApprovalStatus           sql.NullString `db:"approval_status"`
 }
 
 func (q *Queries) ListReconciliationJobs(ctx context.Context, arg ListReconciliationJobsParams) ([]ListReconciliationJobsRow, error) {
@@ -162,6 +245,7 @@ func (q *Queries) ListReconciliationJobs(ctx context.Context, arg ListReconcilia
 			&i.DiscrepancyThreshold,
 			&i.SystemTransactionCsvPath,
 			&i.BankTransactionCsvPaths,
+			&i.ApprovalStatus,
 		); err != nil {
 			return nil, err
 		}
@@ -173,17 +257,89 @@ func (q *Queries) ListReconciliationJobs(ctx context.Context, arg ListReconcilia
 	return items, nil
 }
 
+const listSuccessReconciliationJobsByEndDate = `-- name: ListSuccessReconciliationJobsByEndDate :many
+SELECT id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at FROM reconciliation_jobs
+WHERE status = 'SUCCESS' AND end_date = $1
+ORDER BY id DESC
+`
+
+func (q *Queries) ListSuccessReconciliationJobsByEndDate(ctx context.Context, endDate time.Time) ([]ReconciliationJob, error) {
+	rows, err := q.db.Query(ctx, listSuccessReconciliationJobsByEndDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReconciliationJob
+	for rows.Next() {
+		var i ReconciliationJob
+		if err := rows.Scan(
+			&i.ID,
+			&i.Status,
+			&i.SystemTransactionCsvPath,
+			&i.BankTransactionCsvPaths,
+			&i.DiscrepancyThreshold,
+			&i.StartDate,
+			&i.EndDate,
+			&i.Result,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ErrorInformation,
+			&i.ErrorCode,
+			&i.ParentJobID,
+			&i.Progress,
+			&i.MatchingOptions,
+			&i.AdjustedTotals,
+			&i.ApprovalStatus,
+			&i.LeaseExpiresAt,
+			&i.ClaimToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const renewReconciliationJobLease = `-- name: RenewReconciliationJobLease :execrows
+UPDATE reconciliation_jobs SET lease_expires_at = now() + make_interval(secs => $1::FLOAT8)
+WHERE id = $2 AND status = 'PROCESSING' AND claim_token = $3
+`
+
+type RenewReconciliationJobLeaseParams struct {
+	LeaseSeconds float64 `db:"lease_seconds"`
+	ID           int64   `db:"id"`
+	ClaimToken   int64   `db:"claim_token"`
+}
+
+func (q *Queries) RenewReconciliationJobLease(ctx context.Context, arg RenewReconciliationJobLeaseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, renewReconciliationJobLease, arg.LeaseSeconds, arg.ID, arg.ClaimToken)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const saveFailedReconciliationJob = `-- name: SaveFailedReconciliationJob :one
-UPDATE reconciliation_jobs SET status = 'FAILED', error_information = $2 WHERE id = $1 RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information
+UPDATE reconciliation_jobs SET status = 'FAILED', error_information = $2, error_code = $3 WHERE id = $1 AND status = 'PROCESSING' AND claim_token = $4 RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at, claim_token
 `
 
 type SaveFailedReconciliationJobParams struct {
 	ID               int64          `db:"id"`
 	ErrorInformation sql.NullString `db:"error_information"`
+	ErrorCode        sql.NullString `db:"error_code"`
+	ClaimToken       int64          `db:"claim_token"`
 }
 
 func (q *Queries) SaveFailedReconciliationJob(ctx context.Context, arg SaveFailedReconciliationJobParams) (ReconciliationJob, error) {
-	row := q.db.QueryRow(ctx, saveFailedReconciliationJob, arg.ID, arg.ErrorInformation)
+	row := q.db.QueryRow(ctx, saveFailedReconciliationJob,
+		arg.ID,
+		arg.ErrorInformation,
+		arg.ErrorCode,
+		arg.ClaimToken,
+	)
 	var i ReconciliationJob
 	err := row.Scan(
 		&i.ID,
@@ -197,21 +353,30 @@ func (q *Queries) SaveFailedReconciliationJob(ctx context.Context, arg SaveFaile
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ErrorInformation,
+		&i.ErrorCode,
+		&i.ParentJobID,
+		&i.Progress,
+		&i.MatchingOptions,
+		&i.AdjustedTotals,
+		&i.ApprovalStatus,
+		&i.LeaseExpiresAt,
+		&i.ClaimToken,
 	)
 	return i, err
 }
 
 const saveSuccessReconciliationJob = `-- name: SaveSuccessReconciliationJob :one
-UPDATE reconciliation_jobs SET status = 'SUCCESS', result = $2 WHERE id = $1 RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information
+UPDATE reconciliation_jobs SET status = 'SUCCESS', result = $2 WHERE id = $1 AND status = 'PROCESSING' AND claim_token = $3 RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at, claim_token
 `
 
 type SaveSuccessReconciliationJobParams struct {
-	ID     int64        `db:"id"`
-	Result pgtype.JSONB `db:"result"`
+	ID         int64        `db:"id"`
+	Result     pgtype.JSONB `db:"result"`
+	ClaimToken int64        `db:"claim_token"`
 }
 
 func (q *Queries) SaveSuccessReconciliationJob(ctx context.Context, arg SaveSuccessReconciliationJobParams) (ReconciliationJob, error) {
-	row := q.db.QueryRow(ctx, saveSuccessReconciliationJob, arg.ID, arg.Result)
+	row := q.db.QueryRow(ctx, saveSuccessReconciliationJob, arg.ID, arg.Result, arg.ClaimToken)
 	var i ReconciliationJob
 	err := row.Scan(
 		&i.ID,
@@ -225,6 +390,115 @@ func (q *Queries) SaveSuccessReconciliationJob(ctx context.Context, arg SaveSucc
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.ErrorInformation,
+		&i.ErrorCode,
+		&i.ParentJobID,
+		&i.Progress,
+		&i.MatchingOptions,
+		&i.AdjustedTotals,
+		&i.ApprovalStatus,
+		&i.LeaseExpiresAt,
+		&i.ClaimToken,
 	)
 	return i, err
 }
+
+const startReconciliationJob = `-- name: StartReconciliationJob :one
+UPDATE reconciliation_jobs SET status = 'PROCESSING', lease_expires_at = now() + make_interval(secs => $1::FLOAT8), claim_token = claim_token + 1
+WHERE id = $2 AND (status = 'PENDING' OR (status = 'PROCESSING' AND COALESCE(lease_expires_at, '-infinity') < now()))
+RETURNING id, status, system_transaction_csv_path, bank_transaction_csv_paths, discrepancy_threshold, start_date, end_date, result, created_at, updated_at, error_information, error_code, parent_job_id, progress, matching_options, adjusted_totals, approval_status, lease_expires_at, claim_token
+`
+
+type StartReconciliationJobParams struct {
+	LeaseSeconds float64 `db:"lease_seconds"`
+	ID           int64   `db:"id"`
+}
+
+func (q *Queries) StartReconciliationJob(ctx context.Context, arg StartReconciliationJobParams) (ReconciliationJob, error) {
+	row := q.db.QueryRow(ctx, startReconciliationJob, arg.LeaseSeconds, arg.ID)
+	var i ReconciliationJob
+	err := row.Scan(
+		&i.ID,
+		&i.Status,
+		&i.SystemTransactionCsvPath,
+		&i.BankTransactionCsvPaths,
+		&i.DiscrepancyThreshold,
+		&i.StartDate,
+		&i.EndDate,
+		&i.Result,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ErrorInformation,
+		&i.ErrorCode,
+		&i.ParentJobID,
+		&i.Progress,
+		&i.MatchingOptions,
+		&i.AdjustedTotals,
+		&i.ApprovalStatus,
+		&i.LeaseExpiresAt,
+		&i.ClaimToken,
+	)
+	return i, err
+}
+
+const updateReconciliationJobAdjustedTotals = `-- name: UpdateReconciliationJobAdjustedTotals :exec
+UPDATE reconciliation_jobs j SET adjusted_totals = jsonb_build_object(
+    'total_transaction_matched', (j.result->>'total_transaction_matched')::INT + a.total_manually_matched,
+    'total_transaction_manually_matched', a.total_manually_matched,
+    'total_transaction_resolved', a.total_resolved,
+    'total_transaction_unmatched', (j.result->>'total_transaction_unmatched')::INT - a.total_system_adjusted,
+    'total_discrepancy_amount', (j.result->>'total_discrepancy_amount')::FLOAT - a.total_adjusted_amount
+)
+FROM (
+    SELECT COUNT(DISTINCT adj.id) FILTER (WHERE adj.type = 'MANUAL_MATCH') AS total_manually_matched,
+    COUNT(1) FILTER (WHERE adj.type = 'RESOLVED') AS total_resolved,
+    COUNT(1) FILTER (WHERE i.source = 'SYSTEM') AS total_system_adjusted,
+    COALESCE(SUM(i.amount), 0) AS total_adjusted_amount
+    FROM reconciliation_items i
+    JOIN reconciliation_adjustments adj ON adj.id = i.adjustment_id
+    WHERE i.job_id = $1
+) a
+WHERE j.id = $1
+`
+
+func (q *Queries) UpdateReconciliationJobAdjustedTotals(ctx context.Context, jobID int64) error {
+	_, err := q.db.Exec(ctx, updateReconciliationJobAdjustedTotals, jobID)
+	return err
+}
+
+const updateReconciliationJobApprovalStatus = `-- name: UpdateReconciliationJobApprovalStatus :execrows
+UPDATE reconciliation_jobs SET approval_status = $1
+WHERE id = $2 AND status = 'SUCCESS'
+AND COALESCE(approval_status, '') = ANY($3::VARCHAR[])
+`
+
+type UpdateReconciliationJobApprovalStatusParams struct {
+	ApprovalStatus sql.NullString `db:"approval_status"`
+	ID             int64          `db:"id"`
+	FromStatuses   []string       `db:"from_statuses"`
+}
+
+func (q *Queries) UpdateReconciliationJobApprovalStatus(ctx context.Context, arg UpdateReconciliationJobApprovalStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateReconciliationJobApprovalStatus, arg.ApprovalStatus, arg.ID, arg.FromStatuses)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateReconciliationJobProgress = `-- name: UpdateReconciliationJobProgress :execrows
+UPDATE reconciliation_jobs SET progress = $2 WHERE id = $1 AND status = 'PROCESSING' AND claim_token = $3
+`
+
+type UpdateReconciliationJobProgressParams struct {
+	ID         int64        `db:"id"`
+	Progress   pgtype.JSONB `db:"progress"`
+	ClaimToken int64        `db:"claim_token"`
+}
+
+func (q *Queries) UpdateReconciliationJobProgress(ctx context.Context, arg UpdateReconciliationJobProgressParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateReconciliationJobProgress, arg.ID, arg.Progress, arg.ClaimToken)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,485 @@
+package dbgen
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"iter"
+	"time"
+
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrNotAdjustable is an error when the job of an adjustment is not
+// successful or any of its items is no longer open
+var ErrNotAdjustable = errors.New("reconciliation items are not adjustable")
+
+// ErrApprovalStatusChanged is an error when the job is no longer successful or
+// in the approval status it is changed from
+var ErrApprovalStatusChanged = errors.New("reconciliation job approval status has changed")
+
+// PeriodLockedError is an error when a bank is locked in a day of the period,
+// Lock is the overlapping lock and is zero when it is only known from the
+// exclusion constraint of the active locks
+type PeriodLockedError struct {
+	Lock ReconciliationPeriodLock
+}
+
+func (e *PeriodLockedError) Error() string {
+	return "reconciliation period is locked"
+}
+
+// exclusionViolation is the SQLSTATE of a row conflicting with an exclusion constraint
+const exclusionViolation = "23P01"
+
+// TxDBTX is a DBTX that can begin a database transaction
+type TxDBTX interface {
+	DBTX
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Store provide the queries along with the operations that span multiple
+// queries in a single database transaction
+type Store struct {
+	*Queries
+	db TxDBTX
+}
+
+// NewStore create new store
+func NewStore(db TxDBTX) *Store {
+	return &Store{
+		Queries: New(db),
+		db:      db,
+	}
+}
+
+// SaveSuccessReconciliationJobWithItemsParams is a parameter to save the
+// summary result of a job along with its items and matches, the items and the
+// matches are sequences so they are generated while they are copied instead
+// of being held in memory, the copy fails at the first error of a sequence,
+// ResolvedItemIDs is the items of the previous jobs
+// carried forward and resolved by the job, Period is the banks and the period
+// of the job that must not be locked and is nil when it is not checked
+type SaveSuccessReconciliationJobWithItemsParams struct {
+	SaveSuccessReconciliationJobParams
+	Items           iter.Seq2[CopyReconciliationItemsParams, error]
+	Matches         iter.Seq2[CopyReconciliationMatchesParams, error]
+	ResolvedItemIDs []int64
+	Period          *ListOverlappingReconciliationPeriodLocksParams
+}
+
+// SaveSuccessReconciliationJobWithItems save the job as success, copy its
+// items and matches and mark the carried items it resolved in a single
+// transaction, nothing is saved when the job is no longer processing, or when
+// the period is locked for any of the banks in which case it returns
+// PeriodLockedError
+func (s *Store) SaveSuccessReconciliationJobWithItems(ctx context.Context, arg SaveSuccessReconciliationJobWithItemsParams) (ReconciliationJob, error) {
+	var job ReconciliationJob
+	err := s.execTx(ctx, func(q *Queries) error {
+		if arg.Period != nil {
+			if err := q.holdUnlockedPeriod(ctx, arg.Period.BankNames, arg.Period.StartDate, arg.Period.EndDate); err != nil {
+				return err
+			}
+		}
+		var err error
+		if job, err = q.SaveSuccessReconciliationJob(ctx, arg.SaveSuccessReconciliationJobParams); err != nil {
+			return err
+		}
+		if _, err = q.copyReconciliationItemsSeq(ctx, arg.Items); err != nil {
+			return err
+		}
+		if _, err = q.copyReconciliationMatchesSeq(ctx, arg.Matches); err != nil {
+			return err
+		}
+		if len(arg.ResolvedItemIDs) == 0 {
+			return nil
+		}
+		return q.ResolveCarriedReconciliationItems(ctx, ResolveCarriedReconciliationItemsParams{
+			Ids:           arg.ResolvedItemIDs,
+			ResolvedJobID: sql.NullInt64{Int64: arg.SaveSuccessReconciliationJobParams.ID, Valid: true},
+		})
+	})
+
+	return job, err
+}
+
+// CreateReconciliationJobInUnlockedPeriodParams is a parameter to create a
+// job whose period must not be locked for any of its banks
+type CreateReconciliationJobInUnlockedPeriodParams struct {
+	CreateReconciliationJobParams
+	BankNames []string
+}
+
+// CreateReconciliationJobInUnlockedPeriod create the job once none of its
+// banks is locked in its period, the banks are held until the job is created
+// so a period cannot be locked in between, it returns PeriodLockedError when
+// a bank is locked
+func (s *Store) CreateReconciliationJobInUnlockedPeriod(ctx context.Context, arg CreateReconciliationJobInUnlockedPeriodParams) (ReconciliationJob, error) {
+	var job ReconciliationJob
+	err := s.execTx(ctx, func(q *Queries) error {
+		if err := q.holdUnlockedPeriod(ctx, arg.BankNames, arg.StartDate, arg.EndDate); err != nil {
+			return err
+		}
+		var err error
+		job, err = q.CreateReconciliationJob(ctx, arg.CreateReconciliationJobParams)
+		return err
+	})
+
+	return job, err
+}
+
+// SaveReconciliationAdjustmentParams is a parameter to save a manual
+// adjustment along with the items it adjusts
+type SaveReconciliationAdjustmentParams struct {
+	CreateReconciliationAdjustmentParams
+	ItemIDs []int64
+}
+
+// SaveReconciliationAdjustment save the adjustment, link its items to it and
+// recompute the adjusted totals of the job in a single transaction, the job
+// is locked so the adjustments of a job are saved one at a time, nothing is
+// saved when the job is not successful or any of the items is no longer open
+func (s *Store) SaveReconciliationAdjustment(ctx context.Context, arg SaveReconciliationAdjustmentParams) (ReconciliationAdjustment, error) {
+	var adjustment ReconciliationAdjustment
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		adjustment, err = q.saveReconciliationAdjustment(ctx, arg)
+		return err
+	})
+
+	return adjustment, err
+}
+
+// RevertReconciliationAdjustmentParams is a parameter to remove an adjustment
+// of a job
+type RevertReconciliationAdjustmentParams struct {
+	JobID int64
+	ID    int64
+}
+
+// OpenReconciliationCaseParams is a parameter to open a case on an item along
+// with the comment it is opened with
+type OpenReconciliationCaseParams struct {
+	CreateReconciliationCaseParams
+	Status  string
+	Comment string
+}
+
+// OpenReconciliationCase create the case and record its status along with the
+// comment in a single transaction, it returns pgx.ErrNoRows when the item
+// already has a case
+func (s *Store) OpenReconciliationCase(ctx context.Context, arg OpenReconciliationCaseParams) (ReconciliationCase, error) {
+	var rc ReconciliationCase
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		if rc, err = q.CreateReconciliationCase(ctx, arg.CreateReconciliationCaseParams); err != nil {
+			return err
+		}
+		_, err = q.CreateReconciliationCaseComment(ctx, CreateReconciliationCaseCommentParams{
+			CaseID:    rc.ID,
+			Status:    sql.NullString{String: arg.Status, Valid: true},
+			Comment:   arg.Comment,
+			CreatedBy: arg.CreatedBy,
+		})
+		return err
+	})
+
+	return rc, err
+}
+
+// ChangeReconciliationCaseStatusParams is a parameter to change the status of
+// a case along with the comment explaining it, Resolution is the adjustment
+// closing the item of the case and Reverted is the adjustment of the case
+// removed when it is reopened, both are nil when there is none
+type ChangeReconciliationCaseStatusParams struct {
+	UpdateReconciliationCaseStatusParams
+	Comment    string
+	CreatedBy  string
+	Resolution *SaveReconciliationAdjustmentParams
+	Reverted   *RevertReconciliationAdjustmentParams
+}
+
+// ChangeReconciliationCaseStatus save the resolution of the item or remove
+// the reverted adjustment, change the status of the case and record it along
+// with the comment in a single transaction, it returns pgx.ErrNoRows when the
+// case is no longer in the status it is changed from and ErrNotAdjustable
+// when the job is not successful or the item is no longer open
+func (s *Store) ChangeReconciliationCaseStatus(ctx context.Context, arg ChangeReconciliationCaseStatusParams) (ReconciliationCase, error) {
+	var rc ReconciliationCase
+	err := s.execTx(ctx, func(q *Queries) error {
+		update := arg.UpdateReconciliationCaseStatusParams
+		if arg.Resolution != nil {
+			adjustment, err := q.saveReconciliationAdjustment(ctx, *arg.Resolution)
+			if err != nil {
+				return err
+			}
+			update.AdjustmentID = sql.NullInt64{Int64: adjustment.ID, Valid: true}
+		}
+		if arg.Reverted != nil {
+			if err := q.revertReconciliationAdjustment(ctx, *arg.Reverted); err != nil {
+				return err
+			}
+		}
+		var err error
+		if rc, err = q.UpdateReconciliationCaseStatus(ctx, update); err != nil {
+			return err
+		}
+		_, err = q.CreateReconciliationCaseComment(ctx, CreateReconciliationCaseCommentParams{
+			CaseID:    rc.ID,
+			Status:    sql.NullString{String: arg.Status, Valid: true},
+			Comment:   arg.Comment,
+			CreatedBy: arg.CreatedBy,
+		})
+		return err
+	})
+
+	return rc, err
+}
+
+// SaveReconciliationApprovalParams is a parameter to change the approval
+// status of a job, an empty from status is a job not submitted yet, Lock is
+// the periods locked along with the change and is nil when nothing is locked
+type SaveReconciliationApprovalParams struct {
+	CreateReconciliationApprovalParams
+	FromStatuses []string
+	Lock         *CreateReconciliationPeriodLocksParams
+}
+
+// SaveReconciliationApproval change the approval status of the job, record
+// it and lock the periods in a single transaction, nothing is saved when the
+// job is no longer in any of the from statuses, or when the periods are
+// already locked for any of the banks in which case it returns
+// PeriodLockedError
+func (s *Store) SaveReconciliationApproval(ctx context.Context, arg SaveReconciliationApprovalParams) (ReconciliationApproval, error) {
+	var approval ReconciliationApproval
+	err := s.execTx(ctx, func(q *Queries) error {
+		if arg.Lock != nil {
+			if err := q.holdUnlockedPeriod(ctx, arg.Lock.BankNames, arg.Lock.StartDate, arg.Lock.EndDate); err != nil {
+				return err
+			}
+		}
+		updated, err := q.UpdateReconciliationJobApprovalStatus(ctx, UpdateReconciliationJobApprovalStatusParams{
+			ApprovalStatus: sql.NullString{String: arg.Status, Valid: true},
+			ID:             arg.JobID,
+			FromStatuses:   arg.FromStatuses,
+		})
+		if err != nil {
+			return err
+		}
+		if updated == 0 {
+			return ErrApprovalStatusChanged
+		}
+		if approval, err = q.CreateReconciliationApproval(ctx, arg.CreateReconciliationApprovalParams); err != nil {
+			return err
+		}
+		if arg.Lock == nil {
+			return nil
+		}
+		err = q.CreateReconciliationPeriodLocks(ctx, *arg.Lock)
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == exclusionViolation {
+			return &PeriodLockedError{}
+		}
+		return err
+	})
+
+	return approval, err
+}
+
+// UnlockReconciliationPeriodParams is a parameter to unlock a period lock,
+// the job of the lock is changed to Status when it is in any of FromStatuses
+type UnlockReconciliationPeriodParams struct {
+	UnlockReconciliationPeriodLockParams
+	Status       string
+	FromStatuses []string
+}
+
+// UnlockReconciliationPeriod unlock the period lock along with the other
+// active locks of its job, change the approval status of the job and record
+// it in a single transaction since the locks are held by the approval of the
+// job, it returns pgx.ErrNoRows when the lock is not found or already unlocked
+func (s *Store) UnlockReconciliationPeriod(ctx context.Context, arg UnlockReconciliationPeriodParams) (ReconciliationPeriodLock, error) {
+	var lock ReconciliationPeriodLock
+	err := s.execTx(ctx, func(q *Queries) error {
+		var err error
+		if lock, err = q.UnlockReconciliationPeriodLock(ctx, arg.UnlockReconciliationPeriodLockParams); err != nil {
+			return err
+		}
+		err = q.UnlockReconciliationPeriodLocksByJob(ctx, UnlockReconciliationPeriodLocksByJobParams{
+			JobID:        lock.JobID,
+			UnlockedBy:   arg.UnlockedBy,
+			UnlockReason: arg.UnlockReason,
+		})
+		if err != nil {
+			return err
+		}
+		updated, err := q.UpdateReconciliationJobApprovalStatus(ctx, UpdateReconciliationJobApprovalStatusParams{
+			ApprovalStatus: sql.NullString{String: arg.Status, Valid: true},
+			ID:             lock.JobID,
+			FromStatuses:   arg.FromStatuses,
+		})
+		if err != nil || updated == 0 {
+			return err
+		}
+		_, err = q.CreateReconciliationApproval(ctx, CreateReconciliationApprovalParams{
+			JobID:     lock.JobID,
+			Status:    arg.Status,
+			Comment:   arg.UnlockReason.String,
+			CreatedBy: arg.UnlockedBy.String,
+		})
+		return err
+	})
+
+	return lock, err
+}
+
+// saveReconciliationAdjustment save the adjustment, link its items to it and
+// recompute the adjusted totals of the job, the job is locked first so the
+// adjustments of a job are saved one at a time
+func (q *Queries) saveReconciliationAdjustment(ctx context.Context, arg SaveReconciliationAdjustmentParams) (ReconciliationAdjustment, error) {
+	if err := q.lockAdjustableReconciliationJob(ctx, arg.JobID); err != nil {
+		return ReconciliationAdjustment{}, err
+	}
+	adjustment, err := q.CreateReconciliationAdjustment(ctx, arg.CreateReconciliationAdjustmentParams)
+	if err != nil {
+		return ReconciliationAdjustment{}, err
+	}
+	adjusted, err := q.AdjustReconciliationItems(ctx, AdjustReconciliationItemsParams{
+		AdjustmentID: sql.NullInt64{Int64: adjustment.ID, Valid: true},
+		JobID:        arg.JobID,
+		Ids:          arg.ItemIDs,
+	})
+	if err != nil {
+		return ReconciliationAdjustment{}, err
+	}
+	if adjusted != int64(len(arg.ItemIDs)) {
+		return ReconciliationAdjustment{}, ErrNotAdjustable
+	}
+
+	return adjustment, q.UpdateReconciliationJobAdjustedTotals(ctx, arg.JobID)
+}
+
+// revertReconciliationAdjustment remove the adjustment, its items are no
+// longer linked to it, and recompute the adjusted totals of the job
+func (q *Queries) revertReconciliationAdjustment(ctx context.Context, arg RevertReconciliationAdjustmentParams) error {
+	if err := q.lockAdjustableReconciliationJob(ctx, arg.JobID); err != nil {
+		return err
+	}
+	if err := q.DeleteReconciliationAdjustment(ctx, arg.ID); err != nil {
+		return err
+	}
+
+	return q.UpdateReconciliationJobAdjustedTotals(ctx, arg.JobID)
+}
+
+// holdUnlockedPeriod hold the banks until the end of the transaction so
+// their periods are checked and locked one transaction at a time, and check
+// none of them is locked in any day of the period
+func (q *Queries) holdUnlockedPeriod(ctx context.Context, bankNames []string, startDate, endDate time.Time) error {
+	if err := q.LockReconciliationPeriodBanks(ctx, bankNames); err != nil {
+		return err
+	}
+	locks, err := q.ListOverlappingReconciliationPeriodLocks(ctx, ListOverlappingReconciliationPeriodLocksParams{
+		BankNames: bankNames,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return err
+	}
+	if len(locks) > 0 {
+		return &PeriodLockedError{Lock: locks[0]}
+	}
+
+	return nil
+}
+
+func (q *Queries) lockAdjustableReconciliationJob(ctx context.Context, jobID int64) error {
+	status, err := q.GetReconciliationJobStatusForUpdate(ctx, jobID)
+	if err != nil {
+		return err
+	}
+	if status != "SUCCESS" {
+		return ErrNotAdjustable
+	}
+
+	return nil
+}
+
+// seqCopyFromSource implements pgx.CopyFromSource over a sequence, a row is
+// only generated once it is copied and the copy stops at the first error of
+// the sequence
+type seqCopyFromSource[T any] struct {
+	next   func() (T, error, bool)
+	row    T
+	err    error
+	values func(T) ([]interface{}, error)
+}
+
+func newSeqCopyFromSource[T any](next func() (T, error, bool), values func(T) ([]interface{}, error)) *seqCopyFromSource[T] {
+	return &seqCopyFromSource[T]{
+		next:   next,
+		values: values,
+	}
+}
+
+func (r *seqCopyFromSource[T]) Next() bool {
+	row, err, ok := r.next()
+	if !ok {
+		return false
+	}
+	if err != nil {
+		r.err = err
+		return false
+	}
+	r.row = row
+	return true
+}
+
+func (r *seqCopyFromSource[T]) Values() ([]interface{}, error) {
+	return r.values(r.row)
+}
+
+func (r *seqCopyFromSource[T]) Err() error {
+	return r.err
+}
+
+// copyReconciliationItemsSeq is CopyReconciliationItems over a sequence
+func (q *Queries) copyReconciliationItemsSeq(ctx context.Context, seq iter.Seq2[CopyReconciliationItemsParams, error]) (int64, error) {
+	next, stop := iter.Pull2(seq)
+	defer stop()
+
+	return q.db.CopyFrom(ctx, []string{"reconciliation_items"},
+		[]string{"job_id", "source", "bank_name", "row_number", "transaction_id", "amount", "type", "transaction_time", "status", "carried_from_id", "duplicate_of_row", "overlap_bank_name", "overlap_row", "paired_bank_name", "paired_row"},
+		newSeqCopyFromSource(next, func(row CopyReconciliationItemsParams) ([]interface{}, error) {
+			return iteratorForCopyReconciliationItems{rows: []CopyReconciliationItemsParams{row}}.Values()
+		}))
+}
+
+// copyReconciliationMatchesSeq is CopyReconciliationMatches over a sequence
+func (q *Queries) copyReconciliationMatchesSeq(ctx context.Context, seq iter.Seq2[CopyReconciliationMatchesParams, error]) (int64, error) {
+	next, stop := iter.Pull2(seq)
+	defer stop()
+
+	return q.db.CopyFrom(ctx, []string{"reconciliation_matches"},
+		[]string{"job_id", "system_row", "bank_name", "bank_row", "status", "confidence", "expected_fee", "implied_fee"},
+		newSeqCopyFromSource(next, func(row CopyReconciliationMatchesParams) ([]interface{}, error) {
+			return iteratorForCopyReconciliationMatches{rows: []CopyReconciliationMatchesParams{row}}.Values()
+		}))
+}
+
+func (s *Store) execTx(ctx context.Context, fn func(q *Queries) error) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// rollback is a no-op once the transaction is committed
+	defer tx.Rollback(ctx)
+
+	if err = fn(s.WithTx(tx)); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
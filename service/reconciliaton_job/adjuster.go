@@ -0,0 +1,226 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+// Adjuster is a contract to manually adjust the missing items of reconciliation job
+type Adjuster interface {
+	ManualMatch(ctx context.Context, job *entity.ReconciliationJob, params *ManualMatchParams) (*entity.ReconciliationAdjustment, error)
+	Resolve(ctx context.Context, job *entity.ReconciliationJob, params *ResolveParams) (*entity.ReconciliationAdjustment, error)
+	FindAdjustments(ctx context.Context, jobID int64) ([]*entity.ReconciliationAdjustment, error)
+}
+
+// AdjusterRepository is a contract to manually adjust the missing items of reconciliation job
+type AdjusterRepository interface {
+	ListReconciliationItemsByIds(ctx context.Context, arg dbgen.ListReconciliationItemsByIdsParams) ([]dbgen.ReconciliationItem, error)
+	ListReconciliationAdjustments(ctx context.Context, jobID int64) ([]dbgen.ListReconciliationAdjustmentsRow, error)
+	SaveReconciliationAdjustment(ctx context.Context, arg dbgen.SaveReconciliationAdjustmentParams) (dbgen.ReconciliationAdjustment, error)
+	ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error)
+}
+
+// ManualMatchParams is a parameter to pair a missing system item with a missing
+// bank item, reason code is required when the amounts differ beyond the
+// discrepancy threshold of the job
+type ManualMatchParams struct {
+	SystemItemID int64
+	BankItemID   int64
+	ReasonCode   entity.ResolutionReasonCode
+	Note         string
+	CreatedBy    string
+}
+
+// ResolveParams is a parameter to mark a missing item as resolved
+type ResolveParams struct {
+	ItemID     int64
+	ReasonCode entity.ResolutionReasonCode
+	Note       string
+	CreatedBy  string
+}
+
+// AdjusterService is a service to manually adjust the missing items of reconciliation job
+type AdjusterService struct {
+	repo AdjusterRepository
+	log  *zap.Logger
+}
+
+var _ = Adjuster(&AdjusterService{})
+
+// NewAdjusterService create new adjuster service
+func NewAdjusterService(repo AdjusterRepository) *AdjusterService {
+	return &AdjusterService{
+		repo: repo,
+		log:  zap.L().With(zap.String("service", "reconciliation_job.adjuster")),
+	}
+}
+
+// ManualMatch pair a missing system item with a missing bank item of the same
+// type of a successful job, both items keep their status and refer to the
+// adjustment while the adjusted totals of the job are recomputed, a pair whose
+// amounts differ beyond the discrepancy threshold of the job needs a reason
+func (s *AdjusterService) ManualMatch(ctx context.Context, job *entity.ReconciliationJob, params *ManualMatchParams) (*entity.ReconciliationAdjustment, error) {
+	items, err := s.openItems(ctx, job, params.SystemItemID, params.BankItemID)
+	if err != nil {
+		return nil, err
+	}
+	system, bank := items[params.SystemItemID], items[params.BankItemID]
+	if system.Source != string(entity.ReconciliationItemSourceSystem) ||
+		bank.Source != string(entity.ReconciliationItemSourceBank) {
+		return nil, ErrInvalidManualMatch
+	}
+	if system.Type != bank.Type {
+		return nil, ErrManualMatchTypeMismatch
+	}
+	if params.ReasonCode == "" && exceedsDiscrepancyThreshold(job, system, bank) {
+		return nil, ErrManualMatchReasonRequired
+	}
+
+	return s.save(ctx, dbgen.SaveReconciliationAdjustmentParams{
+		CreateReconciliationAdjustmentParams: dbgen.CreateReconciliationAdjustmentParams{
+			JobID:      job.ID,
+			Type:       string(entity.ReconciliationAdjustmentTypeManualMatch),
+			ReasonCode: sql.NullString{String: string(params.ReasonCode), Valid: params.ReasonCode != ""},
+			Note:       params.Note,
+			CreatedBy:  params.CreatedBy,
+		},
+		ItemIDs: []int64{params.SystemItemID, params.BankItemID},
+	})
+}
+
+// Resolve mark a missing item of a successful job as resolved with a reason,
+// the item keeps its status and refers to the adjustment while the adjusted
+// totals of the job are recomputed
+func (s *AdjusterService) Resolve(ctx context.Context, job *entity.ReconciliationJob, params *ResolveParams) (*entity.ReconciliationAdjustment, error) {
+	if _, err := s.openItems(ctx, job, params.ItemID); err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, dbgen.SaveReconciliationAdjustmentParams{
+		CreateReconciliationAdjustmentParams: dbgen.CreateReconciliationAdjustmentParams{
+			JobID:      job.ID,
+			Type:       string(entity.ReconciliationAdjustmentTypeResolved),
+			ReasonCode: sql.NullString{String: string(params.ReasonCode), Valid: true},
+			Note:       params.Note,
+			CreatedBy:  params.CreatedBy,
+		},
+		ItemIDs: []int64{params.ItemID},
+	})
+}
+
+// FindAdjustments find the adjustments of a job in the order they are made
+func (s *AdjusterService) FindAdjustments(ctx context.Context, jobID int64) ([]*entity.ReconciliationAdjustment, error) {
+	log := logger.WithMethod(s.log, "FindAdjustments")
+	rows, err := s.repo.ListReconciliationAdjustments(ctx, jobID)
+	if err != nil {
+		log.Error("failed to list reconciliation adjustments", zap.Error(err), zap.Int64("job_id", jobID))
+		return nil, err
+	}
+
+	res := make([]*entity.ReconciliationAdjustment, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, convertRowListDbToEntityReconciliationAdjustment(row))
+	}
+
+	return res, nil
+}
+
+// exceedsDiscrepancyThreshold check whether the bank amount differs from the
+// expected bank amount of the system item beyond the discrepancy threshold of
+// the job, the same way the automated matching compares them
+func exceedsDiscrepancyThreshold(job *entity.ReconciliationJob, system, bank dbgen.ReconciliationItem) bool {
+	expectedAmount := system.Amount
+	if rule, ok := findFeeRule(&job.MatchingOptions, bank.BankName.String); ok && system.Type == string(entity.TxTypeCredit) {
+		expectedAmount -= bankFee(rule, system.Amount)
+	}
+
+	return math.Abs(bank.Amount-expectedAmount) > float64(job.DiscrepancyThreshold)*expectedAmount
+}
+
+// checkJobAdjustable check the items of the job can be adjusted, a submitted
+// job is frozen while it is reviewed and an approved period is frozen until it
+// is unlocked
+func checkJobAdjustable(ctx context.Context,
+	listLocks func(context.Context, dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error),
+	job *entity.ReconciliationJob) error {
+	if job.Status != entity.ReconciliationJobStatusSuccess {
+		return ErrReconciliationJobNotSuccess
+	}
+	if job.ApprovalStatus == entity.ReconciliationApprovalStatusSubmitted {
+		return ErrReconciliationJobAwaitingApproval
+	}
+
+	return checkPeriodUnlocked(ctx, listLocks, jobBankNames(job), job.StartDate, job.EndDate)
+}
+
+// isUnexpectedAdjustableError check whether the error of checkJobAdjustable
+// is a failure to check rather than a job that cannot be adjusted
+func isUnexpectedAdjustableError(err error) bool {
+	return !errors.Is(err, ErrReconciliationJobNotSuccess) &&
+		!errors.Is(err, ErrReconciliationJobAwaitingApproval) &&
+		!errors.Is(err, ErrPeriodLocked)
+}
+
+// openItems get the items of the job keyed by id, every item must be missing
+// and not adjusted yet, whether an item is carried forward is only checked
+// once the adjustment is saved
+func (s *AdjusterService) openItems(ctx context.Context, job *entity.ReconciliationJob, ids ...int64) (map[int64]dbgen.ReconciliationItem, error) {
+	log := logger.WithMethod(s.log, "openItems")
+	if err := checkJobAdjustable(ctx, s.repo.ListOverlappingReconciliationPeriodLocks, job); err != nil {
+		if isUnexpectedAdjustableError(err) {
+			log.Error("failed to list overlapping reconciliation period locks", zap.Error(err), zap.Int64("job_id", job.ID))
+		}
+		return nil, err
+	}
+	rows, err := s.repo.ListReconciliationItemsByIds(ctx, dbgen.ListReconciliationItemsByIdsParams{JobID: job.ID, Ids: ids})
+	if err != nil {
+		log.Error("failed to list reconciliation items by ids", zap.Error(err), zap.Int64("job_id", job.ID), zap.Int64s("ids", ids))
+		return nil, err
+	}
+
+	items := make(map[int64]dbgen.ReconciliationItem, len(rows))
+	for _, item := range rows {
+		items[item.ID] = item
+	}
+	for _, id := range ids {
+		item, ok := items[id]
+		if !ok {
+			return nil, ErrReconciliationItemNotFound
+		}
+		if item.Status != string(entity.ReconciliationItemStatusMissing) || item.AdjustmentID.Valid || item.ResolvedJobID.Valid {
+			return nil, ErrReconciliationItemNotAdjustable
+		}
+	}
+
+	return items, nil
+}
+
+func (s *AdjusterService) save(ctx context.Context, params dbgen.SaveReconciliationAdjustmentParams) (*entity.ReconciliationAdjustment, error) {
+	log := logger.WithMethod(s.log, "save")
+	adjustment, err := s.repo.SaveReconciliationAdjustment(ctx, params)
+	if err != nil {
+		if errors.Is(err, dbgen.ErrNotAdjustable) {
+			return nil, ErrReconciliationItemNotAdjustable
+		}
+		log.Error("failed to save reconciliation adjustment", zap.Error(err), zap.Int64("job_id", params.JobID))
+		return nil, err
+	}
+
+	return &entity.ReconciliationAdjustment{
+		ID:         adjustment.ID,
+		JobID:      adjustment.JobID,
+		Type:       entity.ReconciliationAdjustmentType(adjustment.Type),
+		ReasonCode: entity.ResolutionReasonCode(adjustment.ReasonCode.String),
+		Note:       adjustment.Note,
+		ItemIDs:    params.ItemIDs,
+		CreatedBy:  adjustment.CreatedBy,
+		CreatedAt:  adjustment.CreatedAt,
+	}, nil
+}
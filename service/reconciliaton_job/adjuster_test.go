@@ -0,0 +1,368 @@
+package reconciliatonjob_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type AdjusterTestSuite struct {
+	suite.Suite
+	repo *mock_reconciliatonjob.MockAdjusterRepository
+	svc  *reconciliatonjob.AdjusterService
+}
+
+func (s *AdjusterTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_reconciliatonjob.NewMockAdjusterRepository(ctrl)
+	s.svc = reconciliatonjob.NewAdjusterService(s.repo)
+}
+
+func TestAdjusterTestSuite(t *testing.T) {
+	suite.Run(t, new(AdjusterTestSuite))
+}
+
+func missingItem(itemID int64, source entity.ReconciliationItemSource) dbgen.ReconciliationItem {
+	item := dbgen.ReconciliationItem{
+		ID:              itemID,
+		JobID:           id,
+		Source:          string(source),
+		TransactionID:   "trx",
+		Amount:          1000,
+		Type:            string(entity.TxTypeDebit),
+		TransactionTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Status:          string(entity.ReconciliationItemStatusMissing),
+	}
+	if source == entity.ReconciliationItemSourceBank {
+		item.BankName = sql.NullString{String: "BCA", Valid: true}
+	}
+
+	return item
+}
+
+func (s *AdjusterTestSuite) TestManualMatch() {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	params := &reconciliatonjob.ManualMatchParams{SystemItemID: 10, BankItemID: 20, Note: "same transfer", CreatedBy: "alice"}
+	listParams := dbgen.ListReconciliationItemsByIdsParams{JobID: id, Ids: []int64{10, 20}}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(20, entity.ReconciliationItemSourceBank),
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+		}, nil)
+		s.repo.EXPECT().SaveReconciliationAdjustment(ctx, dbgen.SaveReconciliationAdjustmentParams{
+			CreateReconciliationAdjustmentParams: dbgen.CreateReconciliationAdjustmentParams{
+				JobID:     id,
+				Type:      "MANUAL_MATCH",
+				Note:      "same transfer",
+				CreatedBy: "alice",
+			},
+			ItemIDs: []int64{10, 20},
+		}).Return(dbgen.ReconciliationAdjustment{
+			ID:        1,
+			JobID:     id,
+			Type:      "MANUAL_MATCH",
+			Note:      "same transfer",
+			CreatedBy: "alice",
+			CreatedAt: createdAt,
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.NoError(err)
+		s.Equal(&entity.ReconciliationAdjustment{
+			ID:        1,
+			JobID:     id,
+			Type:      entity.ReconciliationAdjustmentTypeManualMatch,
+			Note:      "same transfer",
+			ItemIDs:   []int64{10, 20},
+			CreatedBy: "alice",
+			CreatedAt: createdAt,
+		}, res)
+	})
+
+	s.Run("job not success", func() {
+		rj := *entityReconJob
+		rj.Status = entity.ReconciliationJobStatusFailed
+
+		res, err := s.svc.ManualMatch(ctx, &rj, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotSuccess)
+		s.Nil(res)
+	})
+
+	s.Run("job awaiting approval", func() {
+		rj := *entityReconJob
+		rj.ApprovalStatus = entity.ReconciliationApprovalStatusSubmitted
+
+		res, err := s.svc.ManualMatch(ctx, &rj, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobAwaitingApproval)
+	})
+
+	s.Run("period locked", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return([]dbgen.ReconciliationPeriodLock{dbPeriodLock}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+	})
+
+	s.Run("item not found", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotFound)
+		s.Nil(res)
+	})
+
+	s.Run("items from the same source", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+			missingItem(20, entity.ReconciliationItemSourceSystem),
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidManualMatch)
+		s.Nil(res)
+	})
+
+	s.Run("items of different type", func() {
+		credit := missingItem(20, entity.ReconciliationItemSourceBank)
+		credit.Type = string(entity.TxTypeCredit)
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+			credit,
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrManualMatchTypeMismatch)
+		s.Nil(res)
+	})
+
+	s.Run("amounts differ beyond the discrepancy threshold without reason", func() {
+		bank := missingItem(20, entity.ReconciliationItemSourceBank)
+		bank.Amount = 1101
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+			bank,
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrManualMatchReasonRequired)
+		s.Nil(res)
+	})
+
+	s.Run("success amounts differ beyond the discrepancy threshold with reason", func() {
+		bank := missingItem(20, entity.ReconciliationItemSourceBank)
+		bank.Amount = 1101
+		withReason := *params
+		withReason.ReasonCode = entity.ResolutionReasonBankError
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+			bank,
+		}, nil)
+		s.repo.EXPECT().SaveReconciliationAdjustment(ctx, dbgen.SaveReconciliationAdjustmentParams{
+			CreateReconciliationAdjustmentParams: dbgen.CreateReconciliationAdjustmentParams{
+				JobID:      id,
+				Type:       "MANUAL_MATCH",
+				ReasonCode: sql.NullString{String: "BANK_ERROR", Valid: true},
+				Note:       "same transfer",
+				CreatedBy:  "alice",
+			},
+			ItemIDs: []int64{10, 20},
+		}).Return(dbgen.ReconciliationAdjustment{
+			ID:         1,
+			JobID:      id,
+			Type:       "MANUAL_MATCH",
+			ReasonCode: sql.NullString{String: "BANK_ERROR", Valid: true},
+			Note:       "same transfer",
+			CreatedBy:  "alice",
+			CreatedAt:  createdAt,
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, &withReason)
+
+		s.NoError(err)
+		s.Equal(entity.ResolutionReasonBankError, res.ReasonCode)
+		s.Equal([]int64{10, 20}, res.ItemIDs)
+	})
+
+	s.Run("item already adjusted", func() {
+		adjusted := missingItem(20, entity.ReconciliationItemSourceBank)
+		adjusted.AdjustmentID = sql.NullInt64{Int64: 2, Valid: true}
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+			adjusted,
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotAdjustable)
+		s.Nil(res)
+	})
+
+	s.Run("item not missing", func() {
+		matched := missingItem(10, entity.ReconciliationItemSourceSystem)
+		matched.Status = string(entity.ReconciliationItemStatusMatched)
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			matched,
+			missingItem(20, entity.ReconciliationItemSourceBank),
+		}, nil)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotAdjustable)
+		s.Nil(res)
+	})
+
+	s.Run("item adjusted concurrently", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceSystem),
+			missingItem(20, entity.ReconciliationItemSourceBank),
+		}, nil)
+		s.repo.EXPECT().SaveReconciliationAdjustment(ctx, gomock.Any()).Return(dbgen.ReconciliationAdjustment{}, dbgen.ErrNotAdjustable)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotAdjustable)
+		s.Nil(res)
+	})
+
+	s.Run("error list items", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return(nil, assert.AnError)
+
+		res, err := s.svc.ManualMatch(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
+
+func (s *AdjusterTestSuite) TestResolve() {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	params := &reconciliatonjob.ResolveParams{ItemID: 10, ReasonCode: entity.ResolutionReasonBankError, Note: "bank reversed it", CreatedBy: "alice"}
+	listParams := dbgen.ListReconciliationItemsByIdsParams{JobID: id, Ids: []int64{10}}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceBank),
+		}, nil)
+		s.repo.EXPECT().SaveReconciliationAdjustment(ctx, dbgen.SaveReconciliationAdjustmentParams{
+			CreateReconciliationAdjustmentParams: dbgen.CreateReconciliationAdjustmentParams{
+				JobID:      id,
+				Type:       "RESOLVED",
+				ReasonCode: sql.NullString{String: "BANK_ERROR", Valid: true},
+				Note:       "bank reversed it",
+				CreatedBy:  "alice",
+			},
+			ItemIDs: []int64{10},
+		}).Return(dbgen.ReconciliationAdjustment{
+			ID:         1,
+			JobID:      id,
+			Type:       "RESOLVED",
+			ReasonCode: sql.NullString{String: "BANK_ERROR", Valid: true},
+			Note:       "bank reversed it",
+			CreatedBy:  "alice",
+			CreatedAt:  createdAt,
+		}, nil)
+
+		res, err := s.svc.Resolve(ctx, entityReconJob, params)
+
+		s.NoError(err)
+		s.Equal(&entity.ReconciliationAdjustment{
+			ID:         1,
+			JobID:      id,
+			Type:       entity.ReconciliationAdjustmentTypeResolved,
+			ReasonCode: entity.ResolutionReasonBankError,
+			Note:       "bank reversed it",
+			ItemIDs:    []int64{10},
+			CreatedBy:  "alice",
+			CreatedAt:  createdAt,
+		}, res)
+	})
+
+	s.Run("item carried forward", func() {
+		carried := missingItem(10, entity.ReconciliationItemSourceBank)
+		carried.ResolvedJobID = sql.NullInt64{Int64: 2, Valid: true}
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{carried}, nil)
+
+		res, err := s.svc.Resolve(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotAdjustable)
+		s.Nil(res)
+	})
+
+	s.Run("error save adjustment", func() {
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceBank),
+		}, nil)
+		s.repo.EXPECT().SaveReconciliationAdjustment(ctx, gomock.Any()).Return(dbgen.ReconciliationAdjustment{}, assert.AnError)
+
+		res, err := s.svc.Resolve(ctx, entityReconJob, params)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
+
+func (s *AdjusterTestSuite) TestFindAdjustments() {
+	ctx := context.Background()
+	createdAt := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListReconciliationAdjustments(ctx, id).Return([]dbgen.ListReconciliationAdjustmentsRow{
+			{ID: 1, JobID: id, Type: "MANUAL_MATCH", CreatedBy: "alice", CreatedAt: createdAt, ItemIds: []int64{10, 20}},
+			{ID: 2, JobID: id, Type: "RESOLVED", ReasonCode: sql.NullString{String: "WRITE_OFF", Valid: true}, Note: "immaterial", CreatedBy: "bob", CreatedAt: createdAt, ItemIds: []int64{30}},
+		}, nil)
+
+		res, err := s.svc.FindAdjustments(ctx, id)
+
+		s.NoError(err)
+		s.Equal([]*entity.ReconciliationAdjustment{
+			{ID: 1, JobID: id, Type: entity.ReconciliationAdjustmentTypeManualMatch, ItemIDs: []int64{10, 20}, CreatedBy: "alice", CreatedAt: createdAt},
+			{ID: 2, JobID: id, Type: entity.ReconciliationAdjustmentTypeResolved, ReasonCode: entity.ResolutionReasonWriteOff, Note: "immaterial", ItemIDs: []int64{30}, CreatedBy: "bob", CreatedAt: createdAt},
+		}, res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().ListReconciliationAdjustments(ctx, id).Return(nil, assert.AnError)
+
+		res, err := s.svc.FindAdjustments(ctx, id)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
@@ -0,0 +1,313 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	"github.com/jackc/pgx/v4"
+	"go.uber.org/zap"
+)
+
+// Approver is a contract to review successful reconciliation job and lock its period once approved
+type Approver interface {
+	Submit(ctx context.Context, job *entity.ReconciliationJob, params *ApprovalParams) (*entity.ReconciliationApproval, error)
+	Approve(ctx context.Context, job *entity.ReconciliationJob, params *ApprovalParams) (*entity.ReconciliationApproval, error)
+	Reject(ctx context.Context, job *entity.ReconciliationJob, params *ApprovalParams) (*entity.ReconciliationApproval, error)
+	FindApprovals(ctx context.Context, jobID int64) ([]*entity.ReconciliationApproval, error)
+	FindPeriodLocks(ctx context.Context, filter PeriodLockFilter) ([]*entity.ReconciliationPeriodLock, error)
+	Unlock(ctx context.Context, params *UnlockParams) (*entity.ReconciliationPeriodLock, error)
+}
+
+// ApproverRepository is a contract to review successful reconciliation job and lock its period once approved
+type ApproverRepository interface {
+	ListReconciliationApprovals(ctx context.Context, jobID int64) ([]dbgen.ReconciliationApproval, error)
+	SaveReconciliationApproval(ctx context.Context, arg dbgen.SaveReconciliationApprovalParams) (dbgen.ReconciliationApproval, error)
+	ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error)
+	ListReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error)
+	GetReconciliationPeriodLockById(ctx context.Context, id int64) (dbgen.ReconciliationPeriodLock, error)
+	UnlockReconciliationPeriod(ctx context.Context, arg dbgen.UnlockReconciliationPeriodParams) (dbgen.ReconciliationPeriodLock, error)
+}
+
+// ApprovalParams is a parameter to submit, approve or reject a job
+type ApprovalParams struct {
+	Comment   string
+	CreatedBy string
+}
+
+// UnlockParams is a parameter to unlock a locked period
+type UnlockParams struct {
+	LockID     int64
+	Reason     string
+	UnlockedBy string
+}
+
+// PeriodLockFilter is a filter to find the period locks, every bank is found
+// when bank name is empty
+type PeriodLockFilter struct {
+	BankName   string
+	ActiveOnly bool
+}
+
+// ApproverService is a service to review successful reconciliation job and lock its period once approved
+type ApproverService struct {
+	repo ApproverRepository
+	log  *zap.Logger
+}
+
+var _ = Approver(&ApproverService{})
+
+// NewApproverService create new approver service
+func NewApproverService(repo ApproverRepository) *ApproverService {
+	return &ApproverService{
+		repo: repo,
+		log:  zap.L().With(zap.String("service", "reconciliation_job.approver")),
+	}
+}
+
+// Submit submit a successful job for approval by its preparer, a rejected job
+// or a job whose period is unlocked can be submitted again
+func (s *ApproverService) Submit(ctx context.Context, job *entity.ReconciliationJob, params *ApprovalParams) (*entity.ReconciliationApproval, error) {
+	if job.Status != entity.ReconciliationJobStatusSuccess {
+		return nil, ErrReconciliationJobNotSuccess
+	}
+	switch job.ApprovalStatus {
+	case "", entity.ReconciliationApprovalStatusRejected, entity.ReconciliationApprovalStatusUnlocked:
+	default:
+		return nil, errApprovalTransition(job.ApprovalStatus, "submitted")
+	}
+
+	return s.save(ctx, job, dbgen.SaveReconciliationApprovalParams{
+		CreateReconciliationApprovalParams: dbgen.CreateReconciliationApprovalParams{
+			JobID:     job.ID,
+			Status:    string(entity.ReconciliationApprovalStatusSubmitted),
+			Comment:   params.Comment,
+			CreatedBy: params.CreatedBy,
+		},
+		FromStatuses: []string{
+			"",
+			string(entity.ReconciliationApprovalStatusRejected),
+			string(entity.ReconciliationApprovalStatusUnlocked),
+		},
+	})
+}
+
+// Approve approve a submitted job by a different user than the preparer and
+// lock the period of the job for every bank of the job, a job cannot be
+// approved while a period it covers is still locked by another job, the
+// period is checked again along with the lock so concurrent approvals and
+// job creations of the same bank are serialized
+func (s *ApproverService) Approve(ctx context.Context, job *entity.ReconciliationJob, params *ApprovalParams) (*entity.ReconciliationApproval, error) {
+	if err := s.validateReview(ctx, job, params, "approved"); err != nil {
+		return nil, err
+	}
+	bankNames := jobBankNames(job)
+	if err := checkPeriodUnlocked(ctx, s.repo.ListOverlappingReconciliationPeriodLocks, bankNames, job.StartDate, job.EndDate); err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, job, dbgen.SaveReconciliationApprovalParams{
+		CreateReconciliationApprovalParams: dbgen.CreateReconciliationApprovalParams{
+			JobID:     job.ID,
+			Status:    string(entity.ReconciliationApprovalStatusApproved),
+			Comment:   params.Comment,
+			CreatedBy: params.CreatedBy,
+		},
+		FromStatuses: []string{string(entity.ReconciliationApprovalStatusSubmitted)},
+		Lock: &dbgen.CreateReconciliationPeriodLocksParams{
+			JobID:     job.ID,
+			BankNames: bankNames,
+			StartDate: job.StartDate,
+			EndDate:   job.EndDate,
+			LockedBy:  params.CreatedBy,
+		},
+	})
+}
+
+// Reject reject a submitted job by a different user than the preparer, the
+// comment is required so the preparer knows what to fix
+func (s *ApproverService) Reject(ctx context.Context, job *entity.ReconciliationJob, params *ApprovalParams) (*entity.ReconciliationApproval, error) {
+	if params.Comment == "" {
+		return nil, ErrApprovalCommentRequired
+	}
+	if err := s.validateReview(ctx, job, params, "rejected"); err != nil {
+		return nil, err
+	}
+
+	return s.save(ctx, job, dbgen.SaveReconciliationApprovalParams{
+		CreateReconciliationApprovalParams: dbgen.CreateReconciliationApprovalParams{
+			JobID:     job.ID,
+			Status:    string(entity.ReconciliationApprovalStatusRejected),
+			Comment:   params.Comment,
+			CreatedBy: params.CreatedBy,
+		},
+		FromStatuses: []string{string(entity.ReconciliationApprovalStatusSubmitted)},
+	})
+}
+
+// FindApprovals find the approval history of a job from the oldest
+func (s *ApproverService) FindApprovals(ctx context.Context, jobID int64) ([]*entity.ReconciliationApproval, error) {
+	log := logger.WithMethod(s.log, "FindApprovals")
+	approvals, err := s.repo.ListReconciliationApprovals(ctx, jobID)
+	if err != nil {
+		log.Error("failed to list reconciliation approvals", zap.Error(err), zap.Int64("job_id", jobID))
+		return nil, err
+	}
+
+	res := make([]*entity.ReconciliationApproval, 0, len(approvals))
+	for _, approval := range approvals {
+		res = append(res, convertToEntityReconciliationApproval(approval))
+	}
+
+	return res, nil
+}
+
+// FindPeriodLocks find the period locks from the latest
+func (s *ApproverService) FindPeriodLocks(ctx context.Context, filter PeriodLockFilter) ([]*entity.ReconciliationPeriodLock, error) {
+	log := logger.WithMethod(s.log, "FindPeriodLocks")
+	locks, err := s.repo.ListReconciliationPeriodLocks(ctx, dbgen.ListReconciliationPeriodLocksParams{
+		BankName:   sql.NullString{String: filter.BankName, Valid: filter.BankName != ""},
+		ActiveOnly: filter.ActiveOnly,
+	})
+	if err != nil {
+		log.Error("failed to list reconciliation period locks", zap.Error(err))
+		return nil, err
+	}
+
+	res := make([]*entity.ReconciliationPeriodLock, 0, len(locks))
+	for _, lock := range locks {
+		res = append(res, convertToEntityReconciliationPeriodLock(lock))
+	}
+
+	return res, nil
+}
+
+// Unlock unlock a locked period with a reason, the lock is kept for audit,
+// the approval of the job that locked the period is revoked along with the
+// other locks of the job so the job can be corrected and submitted again
+func (s *ApproverService) Unlock(ctx context.Context, params *UnlockParams) (*entity.ReconciliationPeriodLock, error) {
+	log := logger.WithMethod(s.log, "Unlock")
+	if params.Reason == "" {
+		return nil, ErrUnlockReasonRequired
+	}
+
+	lock, err := s.repo.UnlockReconciliationPeriod(ctx, dbgen.UnlockReconciliationPeriodParams{
+		UnlockReconciliationPeriodLockParams: dbgen.UnlockReconciliationPeriodLockParams{
+			ID:           params.LockID,
+			UnlockedBy:   sql.NullString{String: params.UnlockedBy, Valid: true},
+			UnlockReason: sql.NullString{String: params.Reason, Valid: true},
+		},
+		Status:       string(entity.ReconciliationApprovalStatusUnlocked),
+		FromStatuses: []string{string(entity.ReconciliationApprovalStatusApproved)},
+	})
+	if err == nil {
+		return convertToEntityReconciliationPeriodLock(lock), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		log.Error("failed to unlock reconciliation period lock", zap.Error(err), zap.Int64("id", params.LockID))
+		return nil, err
+	}
+
+	// the lock is either not found or already unlocked
+	if _, err = s.repo.GetReconciliationPeriodLockById(ctx, params.LockID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPeriodLockNotFound
+		}
+		log.Error("failed to get reconciliation period lock by id", zap.Error(err), zap.Int64("id", params.LockID))
+		return nil, err
+	}
+
+	return nil, ErrPeriodLockNotActive
+}
+
+// validateReview check the job is submitted and reviewed by a different user
+// than the one who submitted it the last time
+func (s *ApproverService) validateReview(ctx context.Context, job *entity.ReconciliationJob, params *ApprovalParams, action string) error {
+	log := logger.WithMethod(s.log, "validateReview")
+	if job.ApprovalStatus != entity.ReconciliationApprovalStatusSubmitted {
+		return errApprovalTransition(job.ApprovalStatus, action)
+	}
+
+	approvals, err := s.repo.ListReconciliationApprovals(ctx, job.ID)
+	if err != nil {
+		log.Error("failed to list reconciliation approvals", zap.Error(err), zap.Int64("job_id", job.ID))
+		return err
+	}
+	for i := len(approvals) - 1; i >= 0; i-- {
+		if approvals[i].Status != string(entity.ReconciliationApprovalStatusSubmitted) {
+			continue
+		}
+		if approvals[i].CreatedBy == params.CreatedBy {
+			return ErrSelfApproval
+		}
+		break
+	}
+
+	return nil
+}
+
+func (s *ApproverService) save(ctx context.Context, job *entity.ReconciliationJob, params dbgen.SaveReconciliationApprovalParams) (*entity.ReconciliationApproval, error) {
+	log := logger.WithMethod(s.log, "save")
+	approval, err := s.repo.SaveReconciliationApproval(ctx, params)
+	if err != nil {
+		// the job is reviewed by someone else since it is found
+		if errors.Is(err, dbgen.ErrApprovalStatusChanged) {
+			return nil, errApprovalTransition(job.ApprovalStatus, "changed to "+params.Status)
+		}
+		// the period is locked by another job since it is checked
+		if err = convertPeriodLockedError(err); errors.Is(err, ErrPeriodLocked) {
+			return nil, err
+		}
+		log.Error("failed to save reconciliation approval", zap.Error(err), zap.Int64("job_id", job.ID))
+		return nil, err
+	}
+
+	return convertToEntityReconciliationApproval(approval), nil
+}
+
+// checkPeriodUnlocked check none of the banks is locked in any day of the
+// period, list is the query of the repository finding the overlapping locks
+func checkPeriodUnlocked(ctx context.Context,
+	list func(context.Context, dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error),
+	bankNames []string, startDate, endDate time.Time) error {
+	locks, err := list(ctx, dbgen.ListOverlappingReconciliationPeriodLocksParams{
+		BankNames: bankNames,
+		StartDate: startDate,
+		EndDate:   endDate,
+	})
+	if err != nil {
+		return err
+	}
+	if len(locks) > 0 {
+		return errPeriodLocked(locks[0])
+	}
+
+	return nil
+}
+
+// convertPeriodLockedError convert the period locked error of the repository
+// into ErrPeriodLocked, any other error is returned as is
+func convertPeriodLockedError(err error) error {
+	var locked *dbgen.PeriodLockedError
+	if !errors.As(err, &locked) {
+		return err
+	}
+	if locked.Lock.ID == 0 {
+		return ErrPeriodLocked
+	}
+
+	return errPeriodLocked(locked.Lock)
+}
+
+func jobBankNames(job *entity.ReconciliationJob) []string {
+	res := make([]string, 0, len(job.BankTransactionCsvPaths))
+	for _, bankFile := range job.BankTransactionCsvPaths {
+		res = append(res, bankFile.BankName)
+	}
+
+	return res
+}
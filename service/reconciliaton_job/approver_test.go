@@ -0,0 +1,413 @@
+package reconciliatonjob_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ApproverTestSuite struct {
+	suite.Suite
+	repo *mock_reconciliatonjob.MockApproverRepository
+	svc  *reconciliatonjob.ApproverService
+}
+
+func (s *ApproverTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_reconciliatonjob.NewMockApproverRepository(ctrl)
+	s.svc = reconciliatonjob.NewApproverService(s.repo)
+}
+
+func TestApproverTestSuite(t *testing.T) {
+	suite.Run(t, new(ApproverTestSuite))
+}
+
+var (
+	approvalCreatedAt = time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	lockParams        = dbgen.ListOverlappingReconciliationPeriodLocksParams{
+		BankNames: []string{"BCA"},
+		StartDate: lastMonth,
+		EndDate:   yesterday,
+	}
+	dbPeriodLock = dbgen.ReconciliationPeriodLock{
+		ID:        7,
+		JobID:     2,
+		BankName:  "BCA",
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		LockedBy:  "carol",
+		LockedAt:  approvalCreatedAt,
+	}
+)
+
+func submittedJob() *entity.ReconciliationJob {
+	rj := *entityReconJob
+	rj.ApprovalStatus = entity.ReconciliationApprovalStatusSubmitted
+
+	return &rj
+}
+
+func (s *ApproverTestSuite) TestSubmit() {
+	ctx := context.Background()
+	params := &reconciliatonjob.ApprovalParams{Comment: "ready", CreatedBy: "alice"}
+	saveParams := dbgen.SaveReconciliationApprovalParams{
+		CreateReconciliationApprovalParams: dbgen.CreateReconciliationApprovalParams{
+			JobID:     id,
+			Status:    "SUBMITTED",
+			Comment:   "ready",
+			CreatedBy: "alice",
+		},
+		FromStatuses: []string{"", "REJECTED", "UNLOCKED"},
+	}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, saveParams).Return(dbgen.ReconciliationApproval{
+			ID:        1,
+			JobID:     id,
+			Status:    "SUBMITTED",
+			Comment:   "ready",
+			CreatedBy: "alice",
+			CreatedAt: approvalCreatedAt,
+		}, nil)
+
+		res, err := s.svc.Submit(ctx, entityReconJob, params)
+
+		s.NoError(err)
+		s.Equal(&entity.ReconciliationApproval{
+			ID:        1,
+			JobID:     id,
+			Status:    entity.ReconciliationApprovalStatusSubmitted,
+			Comment:   "ready",
+			CreatedBy: "alice",
+			CreatedAt: approvalCreatedAt,
+		}, res)
+	})
+
+	s.Run("resubmit rejected job", func() {
+		rj := *entityReconJob
+		rj.ApprovalStatus = entity.ReconciliationApprovalStatusRejected
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, saveParams).Return(dbgen.ReconciliationApproval{ID: 3, Status: "SUBMITTED"}, nil)
+
+		res, err := s.svc.Submit(ctx, &rj, params)
+
+		s.NoError(err)
+		s.Equal(int64(3), res.ID)
+	})
+
+	s.Run("resubmit unlocked job", func() {
+		rj := *entityReconJob
+		rj.ApprovalStatus = entity.ReconciliationApprovalStatusUnlocked
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, saveParams).Return(dbgen.ReconciliationApproval{ID: 5, Status: "SUBMITTED"}, nil)
+
+		res, err := s.svc.Submit(ctx, &rj, params)
+
+		s.NoError(err)
+		s.Equal(int64(5), res.ID)
+	})
+
+	s.Run("job already approved", func() {
+		rj := *entityReconJob
+		rj.ApprovalStatus = entity.ReconciliationApprovalStatusApproved
+
+		res, err := s.svc.Submit(ctx, &rj, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidApprovalTransition)
+	})
+
+	s.Run("job not success", func() {
+		rj := *entityReconJob
+		rj.Status = entity.ReconciliationJobStatusProcessing
+
+		res, err := s.svc.Submit(ctx, &rj, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotSuccess)
+	})
+
+	s.Run("job already submitted", func() {
+		res, err := s.svc.Submit(ctx, submittedJob(), params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidApprovalTransition)
+	})
+
+	s.Run("job submitted concurrently", func() {
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, saveParams).Return(dbgen.ReconciliationApproval{}, dbgen.ErrApprovalStatusChanged)
+
+		res, err := s.svc.Submit(ctx, entityReconJob, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidApprovalTransition)
+	})
+
+	s.Run("error save approval", func() {
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, saveParams).Return(dbgen.ReconciliationApproval{}, assert.AnError)
+
+		res, err := s.svc.Submit(ctx, entityReconJob, params)
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+}
+
+func (s *ApproverTestSuite) TestApprove() {
+	ctx := context.Background()
+	params := &reconciliatonjob.ApprovalParams{Comment: "looks good", CreatedBy: "bob"}
+	history := []dbgen.ReconciliationApproval{
+		{ID: 1, JobID: id, Status: "SUBMITTED", CreatedBy: "bob"},
+		{ID: 2, JobID: id, Status: "REJECTED", Comment: "wrong period", CreatedBy: "alice"},
+		{ID: 3, JobID: id, Status: "SUBMITTED", CreatedBy: "alice"},
+	}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return(history, nil)
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, dbgen.SaveReconciliationApprovalParams{
+			CreateReconciliationApprovalParams: dbgen.CreateReconciliationApprovalParams{
+				JobID:     id,
+				Status:    "APPROVED",
+				Comment:   "looks good",
+				CreatedBy: "bob",
+			},
+			FromStatuses: []string{"SUBMITTED"},
+			Lock: &dbgen.CreateReconciliationPeriodLocksParams{
+				JobID:     id,
+				BankNames: []string{"BCA"},
+				StartDate: lastMonth,
+				EndDate:   yesterday,
+				LockedBy:  "bob",
+			},
+		}).Return(dbgen.ReconciliationApproval{ID: 4, JobID: id, Status: "APPROVED", Comment: "looks good", CreatedBy: "bob"}, nil)
+
+		res, err := s.svc.Approve(ctx, submittedJob(), params)
+
+		s.NoError(err)
+		s.Equal(entity.ReconciliationApprovalStatusApproved, res.Status)
+	})
+
+	s.Run("job not submitted", func() {
+		res, err := s.svc.Approve(ctx, entityReconJob, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidApprovalTransition)
+	})
+
+	s.Run("approved by preparer", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return(history, nil)
+
+		res, err := s.svc.Approve(ctx, submittedJob(), &reconciliatonjob.ApprovalParams{CreatedBy: "alice"})
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrSelfApproval)
+	})
+
+	s.Run("period locked by another job", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return(history, nil)
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return([]dbgen.ReconciliationPeriodLock{dbPeriodLock}, nil)
+
+		res, err := s.svc.Approve(ctx, submittedJob(), params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+	})
+
+	s.Run("period locked by another job on save", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return(history, nil)
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, gomock.Any()).
+			Return(dbgen.ReconciliationApproval{}, &dbgen.PeriodLockedError{Lock: dbPeriodLock})
+
+		res, err := s.svc.Approve(ctx, submittedJob(), params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+	})
+
+	s.Run("error list approvals", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return(nil, assert.AnError)
+
+		res, err := s.svc.Approve(ctx, submittedJob(), params)
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+}
+
+func (s *ApproverTestSuite) TestReject() {
+	ctx := context.Background()
+	params := &reconciliatonjob.ApprovalParams{Comment: "wrong period", CreatedBy: "bob"}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return([]dbgen.ReconciliationApproval{
+			{ID: 1, JobID: id, Status: "SUBMITTED", CreatedBy: "alice"},
+		}, nil)
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, dbgen.SaveReconciliationApprovalParams{
+			CreateReconciliationApprovalParams: dbgen.CreateReconciliationApprovalParams{
+				JobID:     id,
+				Status:    "REJECTED",
+				Comment:   "wrong period",
+				CreatedBy: "bob",
+			},
+			FromStatuses: []string{"SUBMITTED"},
+		}).Return(dbgen.ReconciliationApproval{ID: 2, JobID: id, Status: "REJECTED", Comment: "wrong period", CreatedBy: "bob"}, nil)
+
+		res, err := s.svc.Reject(ctx, submittedJob(), params)
+
+		s.NoError(err)
+		s.Equal(entity.ReconciliationApprovalStatusRejected, res.Status)
+	})
+
+	s.Run("comment required", func() {
+		res, err := s.svc.Reject(ctx, submittedJob(), &reconciliatonjob.ApprovalParams{CreatedBy: "bob"})
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrApprovalCommentRequired)
+	})
+
+	s.Run("job reviewed concurrently", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return(nil, nil)
+		s.repo.EXPECT().SaveReconciliationApproval(ctx, gomock.Any()).Return(dbgen.ReconciliationApproval{}, dbgen.ErrApprovalStatusChanged)
+
+		res, err := s.svc.Reject(ctx, submittedJob(), params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidApprovalTransition)
+	})
+}
+
+func (s *ApproverTestSuite) TestFindApprovals() {
+	ctx := context.Background()
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return([]dbgen.ReconciliationApproval{
+			{ID: 1, JobID: id, Status: "SUBMITTED", CreatedBy: "alice", CreatedAt: approvalCreatedAt},
+		}, nil)
+
+		res, err := s.svc.FindApprovals(ctx, id)
+
+		s.NoError(err)
+		s.Equal([]*entity.ReconciliationApproval{
+			{ID: 1, JobID: id, Status: entity.ReconciliationApprovalStatusSubmitted, CreatedBy: "alice", CreatedAt: approvalCreatedAt},
+		}, res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().ListReconciliationApprovals(ctx, id).Return(nil, assert.AnError)
+
+		res, err := s.svc.FindApprovals(ctx, id)
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+}
+
+func (s *ApproverTestSuite) TestFindPeriodLocks() {
+	ctx := context.Background()
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListReconciliationPeriodLocks(ctx, dbgen.ListReconciliationPeriodLocksParams{
+			BankName:   sql.NullString{String: "BCA", Valid: true},
+			ActiveOnly: true,
+		}).Return([]dbgen.ReconciliationPeriodLock{dbPeriodLock}, nil)
+
+		res, err := s.svc.FindPeriodLocks(ctx, reconciliatonjob.PeriodLockFilter{BankName: "BCA", ActiveOnly: true})
+
+		s.NoError(err)
+		s.Equal([]*entity.ReconciliationPeriodLock{
+			{
+				ID:        7,
+				JobID:     2,
+				BankName:  "BCA",
+				StartDate: dbPeriodLock.StartDate,
+				EndDate:   dbPeriodLock.EndDate,
+				LockedBy:  "carol",
+				LockedAt:  approvalCreatedAt,
+			},
+		}, res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().ListReconciliationPeriodLocks(ctx, dbgen.ListReconciliationPeriodLocksParams{}).Return(nil, assert.AnError)
+
+		res, err := s.svc.FindPeriodLocks(ctx, reconciliatonjob.PeriodLockFilter{})
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+}
+
+func (s *ApproverTestSuite) TestUnlock() {
+	ctx := context.Background()
+	params := &reconciliatonjob.UnlockParams{LockID: 7, Reason: "late bank statement", UnlockedBy: "dave"}
+	unlockParams := dbgen.UnlockReconciliationPeriodParams{
+		UnlockReconciliationPeriodLockParams: dbgen.UnlockReconciliationPeriodLockParams{
+			ID:           7,
+			UnlockedBy:   sql.NullString{String: "dave", Valid: true},
+			UnlockReason: sql.NullString{String: "late bank statement", Valid: true},
+		},
+		Status:       "UNLOCKED",
+		FromStatuses: []string{"APPROVED"},
+	}
+
+	s.Run("success", func() {
+		unlocked := dbPeriodLock
+		unlocked.UnlockedBy = unlockParams.UnlockedBy
+		unlocked.UnlockReason = unlockParams.UnlockReason
+		unlocked.UnlockedAt = sql.NullTime{Time: approvalCreatedAt, Valid: true}
+		s.repo.EXPECT().UnlockReconciliationPeriod(ctx, unlockParams).Return(unlocked, nil)
+
+		res, err := s.svc.Unlock(ctx, params)
+
+		s.NoError(err)
+		s.Equal("dave", res.UnlockedBy)
+		s.Equal("late bank statement", res.UnlockReason)
+		s.Equal(&approvalCreatedAt, res.UnlockedAt)
+	})
+
+	s.Run("reason required", func() {
+		res, err := s.svc.Unlock(ctx, &reconciliatonjob.UnlockParams{LockID: 7, UnlockedBy: "dave"})
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrUnlockReasonRequired)
+	})
+
+	s.Run("lock not found", func() {
+		s.repo.EXPECT().UnlockReconciliationPeriod(ctx, unlockParams).Return(dbgen.ReconciliationPeriodLock{}, pgx.ErrNoRows)
+		s.repo.EXPECT().GetReconciliationPeriodLockById(ctx, int64(7)).Return(dbgen.ReconciliationPeriodLock{}, pgx.ErrNoRows)
+
+		res, err := s.svc.Unlock(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLockNotFound)
+	})
+
+	s.Run("lock already unlocked", func() {
+		s.repo.EXPECT().UnlockReconciliationPeriod(ctx, unlockParams).Return(dbgen.ReconciliationPeriodLock{}, pgx.ErrNoRows)
+		s.repo.EXPECT().GetReconciliationPeriodLockById(ctx, int64(7)).Return(dbPeriodLock, nil)
+
+		res, err := s.svc.Unlock(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLockNotActive)
+	})
+
+	s.Run("error unlock", func() {
+		s.repo.EXPECT().UnlockReconciliationPeriod(ctx, unlockParams).Return(dbgen.ReconciliationPeriodLock{}, assert.AnError)
+
+		res, err := s.svc.Unlock(ctx, params)
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+}
@@ -0,0 +1,63 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"errors"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	"github.com/jackc/pgx/v4"
+	"go.uber.org/zap"
+)
+
+// Canceler is a contract to cancel reconciliation job
+type Canceler interface {
+	Cancel(ctx context.Context, id int64) (*entity.ReconciliationJob, error)
+}
+
+// CancelerRepository is a contract to cancel reconciliation job
+type CancelerRepository interface {
+	CancelReconciliationJob(ctx context.Context, id int64) (dbgen.ReconciliationJob, error)
+	GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error)
+}
+
+// CancelerService is a service to cancel reconciliation job
+type CancelerService struct {
+	repo CancelerRepository
+	log  *zap.Logger
+}
+
+var _ = Canceler(&CancelerService{})
+
+// NewCancelerService create new canceler service
+func NewCancelerService(repo CancelerRepository) *CancelerService {
+	return &CancelerService{
+		repo: repo,
+		log:  zap.L().With(zap.String("service", "reconciliation_job.canceler")),
+	}
+}
+
+// Cancel cancel pending or processing reconciliation job, a processing job
+// would be aborted by the processer at the next checkpoint
+func (s *CancelerService) Cancel(ctx context.Context, id int64) (*entity.ReconciliationJob, error) {
+	log := logger.WithMethod(s.log, "Cancel")
+	rj, err := s.repo.CancelReconciliationJob(ctx, id)
+	if err == nil {
+		return convertToEntityReconciliationJob(rj), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		log.Error("failed to cancel reconciliation job", zap.Error(err), zap.Int64("id", id))
+		return nil, err
+	}
+
+	if _, err = s.repo.GetReconciliationJobById(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReconciliationJobNotFound
+		}
+		log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("id", id))
+		return nil, err
+	}
+
+	return nil, ErrReconciliationJobNotCancellable
+}
@@ -0,0 +1,88 @@
+package reconciliatonjob_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CancelerTestSuite struct {
+	suite.Suite
+	repo *mock_reconciliatonjob.MockCancelerRepository
+	svc  *reconciliatonjob.CancelerService
+}
+
+func (s *CancelerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_reconciliatonjob.NewMockCancelerRepository(ctrl)
+	s.svc = reconciliatonjob.NewCancelerService(s.repo)
+}
+
+func TestCancelerTestSuite(t *testing.T) {
+	suite.Run(t, new(CancelerTestSuite))
+}
+
+func (s *CancelerTestSuite) TestCancel() {
+	ctx := context.Background()
+
+	s.Run("success", func() {
+		rj := dbReconJob
+		rj.Status = string(entity.ReconciliationJobStatusCancelled)
+		expectedRJ := *entityReconJob
+		expectedRJ.Status = entity.ReconciliationJobStatusCancelled
+
+		s.repo.EXPECT().CancelReconciliationJob(ctx, id).Return(rj, nil)
+
+		res, err := s.svc.Cancel(ctx, id)
+
+		s.NoError(err)
+		s.Equal(&expectedRJ, res)
+	})
+
+	s.Run("not found", func() {
+		s.repo.EXPECT().CancelReconciliationJob(ctx, id).Return(dbgen.ReconciliationJob{}, pgx.ErrNoRows)
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbgen.ReconciliationJob{}, pgx.ErrNoRows)
+
+		res, err := s.svc.Cancel(ctx, id)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotFound)
+		s.Nil(res)
+	})
+
+	s.Run("not cancellable", func() {
+		s.repo.EXPECT().CancelReconciliationJob(ctx, id).Return(dbgen.ReconciliationJob{}, pgx.ErrNoRows)
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbReconJob, nil)
+
+		res, err := s.svc.Cancel(ctx, id)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotCancellable)
+		s.Nil(res)
+	})
+
+	s.Run("error cancel", func() {
+		s.repo.EXPECT().CancelReconciliationJob(ctx, id).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		res, err := s.svc.Cancel(ctx, id)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+
+	s.Run("error get reconciliation job", func() {
+		s.repo.EXPECT().CancelReconciliationJob(ctx, id).Return(dbgen.ReconciliationJob{}, pgx.ErrNoRows)
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		res, err := s.svc.Cancel(ctx, id)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
@@ -0,0 +1,241 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"time"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	"go.uber.org/zap"
+)
+
+// carriedKey identify a transaction carried from the previous job by its
+// source and its row in the job, source is systemSource or the bank file index
+type carriedKey struct {
+	source int
+	row    int
+}
+
+// carryForwardWindowDays is the days across the boundary of the periods a
+// carried transaction is matched within, a transaction booked on the last day
+// of the previous period is matched with a transaction of the first day of the
+// job
+const carryForwardWindowDays = 1
+
+// carryForward hold the open items of the previous job carried into the job,
+// the carried transactions are kept in a single partition, only the carried
+// transactions dated from windowStart are matched and only with the missing
+// transactions of the job dated before windowEnd, fromJobID is nil when there
+// is no previous job
+type carryForward struct {
+	fromJobID   *int64
+	itemIDs     map[carriedKey]int64
+	trxs        *partition
+	windowStart string
+	windowEnd   string
+}
+
+// loadCarryForward load the still open unmatched items of the latest
+// successful job of the same banks whose period ends the day before the job
+// starts, the carried transactions are numbered after the last row of their
+// file in the job, lastRows is keyed by source, so they never collide with
+// the rows of the file
+func (s *ProcesserService) loadCarryForward(ctx context.Context, job *entity.ReconciliationJob, lastRows map[int]int) (*carryForward, error) {
+	log := logger.WithMethod(s.log, "loadCarryForward")
+	carried := &carryForward{
+		itemIDs:     map[carriedKey]int64{},
+		trxs:        newPartition("", len(job.BankTransactionCsvPaths)),
+		windowStart: job.StartDate.AddDate(0, 0, -carryForwardWindowDays).Format(time.DateOnly),
+		windowEnd:   job.StartDate.AddDate(0, 0, carryForwardWindowDays).Format(time.DateOnly),
+	}
+	previousJobs, err := s.repo.ListSuccessReconciliationJobsByEndDate(ctx, job.StartDate.AddDate(0, 0, -1))
+	if err != nil {
+		return nil, err
+	}
+	var previous *entity.ReconciliationJob
+	for _, dbJob := range previousJobs {
+		candidate := convertToEntityReconciliationJob(dbJob)
+		if sameBanks(candidate.BankTransactionCsvPaths, job.BankTransactionCsvPaths) {
+			previous = candidate
+			break
+		}
+	}
+	if previous == nil {
+		return carried, nil
+	}
+	carried.fromJobID = &previous.ID
+
+	items, err := s.repo.ListOpenReconciliationItems(ctx, previous.ID)
+	if err != nil {
+		return nil, err
+	}
+	bankIdxs := map[string]int{}
+	for bankIdx, bank := range job.BankTransactionCsvPaths {
+		bankIdxs[entity.BankNameKey(bank.BankName)] = bankIdx
+	}
+	for _, item := range items {
+		source := systemSource
+		if item.Source == string(entity.ReconciliationItemSourceBank) {
+			bankIdx, ok := bankIdxs[entity.BankNameKey(item.BankName.String)]
+			if !ok {
+				// the banks of both jobs are the same so it is never expected,
+				// the item is left open rather than carried into another bank
+				log.Warn("carried reconciliation item of unknown bank skipped", zap.Int64("item_id", item.ID), zap.String("bank_name", item.BankName.String))
+				continue
+			}
+			source = bankIdx
+		}
+		lastRows[source]++
+		row := lastRows[source]
+		carried.itemIDs[carriedKey{source: source, row: row}] = item.ID
+		trx := convertToEntityReconciliationItem(item).Transaction
+		carried.trxs.add(source, rowTransaction{row: row, trx: &trx})
+	}
+
+	return carried, nil
+}
+
+// sameBanks check whether both jobs reconcile the same banks regardless of
+// their order, bank names are compared by their key
+func sameBanks(a, b []entity.BankTransactionCsv) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	names := map[string]bool{}
+	for _, bank := range a {
+		names[entity.BankNameKey(bank.BankName)] = true
+	}
+	for _, bank := range b {
+		if !names[entity.BankNameKey(bank.BankName)] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// setCarryForward set the transactions carried from the previous job before
+// the partitions are collected, so the missing transactions of the job within
+// the window of the carry forward are kept apart to be matched with them
+func (c *resultCollector) setCarryForward(carried *carryForward) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.carried = carried
+	c.window = newPartition("", len(c.bankFiles))
+}
+
+// matchCarried match the transactions carried from the previous job with the
+// missing transactions of the job within the window of the carry forward, the
+// carried system transactions are matched against the missing bank
+// transactions and then the missing system transactions against the carried
+// bank transactions, the carried transactions that are still unmatched stay
+// missing in the job, the carried system transactions are counted apart from
+// the totals of the job, it returns the number of transactions matched
+func (c *resultCollector) matchCarried(reconcile func(*partition) (*partitionResult, error)) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	carried, window := c.carried, c.window
+	c.window = nil
+	fromWindowStart := func(date string) bool { return date >= carried.windowStart }
+
+	// the window is collected in the order the partitions are, it is sorted
+	// so the matches do not depend on that order
+	sortRowTransactions(window.systemTrxs)
+	for _, trxs := range window.bankTrxs {
+		sortRowTransactions(trxs)
+	}
+	carriedSystemTrxs, outsideSystemTrxs := splitByDate(carried.trxs.systemTrxs, fromWindowStart)
+	carriedRes, err := reconcile(&partition{systemTrxs: carriedSystemTrxs, bankTrxs: window.bankTrxs})
+	if err != nil {
+		return 0, err
+	}
+
+	carriedBankTrxs := make([][]rowTransaction, len(carried.trxs.bankTrxs))
+	outsideBankTrxs := make([][]rowTransaction, len(carried.trxs.bankTrxs))
+	for bankIdx, trxs := range carried.trxs.bankTrxs {
+		carriedBankTrxs[bankIdx], outsideBankTrxs[bankIdx] = splitByDate(trxs, fromWindowStart)
+	}
+	missingRes, err := reconcile(&partition{systemTrxs: window.systemTrxs, bankTrxs: carriedBankTrxs})
+	if err != nil {
+		return 0, err
+	}
+
+	// the missing system transactions are already counted as processed
+	c.totalMatched += missingRes.totalMatched
+	c.totalReviews += len(missingRes.reviews)
+	c.carriedMatched = carriedRes.totalMatched
+	c.carriedReviews = len(carriedRes.reviews)
+	for _, res := range []*partitionResult{carriedRes, missingRes} {
+		if err := c.addPartitionResult(res); err != nil {
+			return 0, err
+		}
+	}
+	if err := c.addPartitionResult(&partitionResult{missingTrxs: outsideSystemTrxs, missingBankTrxs: outsideBankTrxs}); err != nil {
+		return 0, err
+	}
+
+	return carriedRes.totalMatched + missingRes.totalMatched, nil
+}
+
+// splitByDate split the transactions into the transactions whose date is in
+// and the rest, both keep the order of the transactions
+func splitByDate(trxs []rowTransaction, in func(date string) bool) (inside, outside []rowTransaction) {
+	for _, trx := range trxs {
+		if in(trx.trx.Time.Format(time.DateOnly)) {
+			inside = append(inside, trx)
+			continue
+		}
+		outside = append(outside, trx)
+	}
+
+	return inside, outside
+}
+
+// isCarried check whether the transaction of a file is carried from the
+// previous job
+func (c *resultCollector) isCarried(source, row int) bool {
+	if c.carried == nil {
+		return false
+	}
+	_, ok := c.carried.itemIDs[carriedKey{source: source, row: row}]
+
+	return ok
+}
+
+// carryForwardSummary count the carried items and the carried items that are
+// no longer missing in the job, the resolved carried items are kept in the
+// order of the items of the job to be closed once the job is saved
+func (c *resultCollector) carryForwardSummary(ctx context.Context) (*entity.CarryForwardSummary, error) {
+	summary := &entity.CarryForwardSummary{}
+	if c.carried == nil {
+		return summary, nil
+	}
+	summary.FromJobID = c.carried.fromJobID
+	summary.TotalCarried = len(c.carried.itemIDs)
+	summary.TotalSystemCarried = len(c.carried.trxs.systemTrxs)
+	summary.TotalSystemMatched = c.carriedMatched
+	summary.TotalSystemNeedsReview = c.carriedReviews
+	summary.TotalDiscrepancyAmount = c.carriedDiscrepancy
+	c.resolvedItemIDs = nil
+	err := c.forEachItem(ctx, func(item entity.ReconciliationItem) error {
+		if item.CarriedFromID != nil && item.Status != entity.ReconciliationItemStatusMissing {
+			c.resolvedItemIDs = append(c.resolvedItemIDs, *item.CarriedFromID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	summary.TotalResolved = len(c.resolvedItemIDs)
+
+	return summary, nil
+}
+
+// resolvedIDs return the carried items of the previous jobs matched by the
+// job, it is only valid once the result is built
+func (c *resultCollector) resolvedIDs() []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.resolvedItemIDs
+}
@@ -0,0 +1,364 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/config"
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	"github.com/jackc/pgx/v4"
+	"go.uber.org/zap"
+)
+
+// CaseManager is a contract to follow up the unmatched items of reconciliation job as exception cases
+type CaseManager interface {
+	Open(ctx context.Context, params *OpenCaseParams) (*entity.ReconciliationCase, error)
+	FindByID(ctx context.Context, id int64) (*entity.ReconciliationCase, error)
+	CountByAssignee(ctx context.Context, filter CaseFilter) (int64, error)
+	FindByAssignee(ctx context.Context, filter CaseFilter, limit, offset int32) ([]*entity.ReconciliationCase, error)
+	ChangeStatus(ctx context.Context, params *ChangeCaseStatusParams) (*entity.ReconciliationCase, error)
+	AddComment(ctx context.Context, params *AddCaseCommentParams) (*entity.ReconciliationCaseComment, error)
+}
+
+// CaseManagerRepository is a contract to follow up the unmatched items of reconciliation job as exception cases
+type CaseManagerRepository interface {
+	ListReconciliationItemsByIds(ctx context.Context, arg dbgen.ListReconciliationItemsByIdsParams) ([]dbgen.ReconciliationItem, error)
+	OpenReconciliationCase(ctx context.Context, arg dbgen.OpenReconciliationCaseParams) (dbgen.ReconciliationCase, error)
+	GetReconciliationCaseById(ctx context.Context, id int64) (dbgen.GetReconciliationCaseByIdRow, error)
+	ListReconciliationCaseComments(ctx context.Context, caseID int64) ([]dbgen.ReconciliationCaseComment, error)
+	CountReconciliationCasesByAssignee(ctx context.Context, arg dbgen.CountReconciliationCasesByAssigneeParams) (int64, error)
+	ListReconciliationCasesByAssignee(ctx context.Context, arg dbgen.ListReconciliationCasesByAssigneeParams) ([]dbgen.ListReconciliationCasesByAssigneeRow, error)
+	ChangeReconciliationCaseStatus(ctx context.Context, arg dbgen.ChangeReconciliationCaseStatusParams) (dbgen.ReconciliationCase, error)
+	CreateReconciliationCaseComment(ctx context.Context, arg dbgen.CreateReconciliationCaseCommentParams) (dbgen.ReconciliationCaseComment, error)
+	GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error)
+	ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error)
+}
+
+// OpenCaseParams is a parameter to open a case on an unmatched item
+type OpenCaseParams struct {
+	JobID     int64
+	ItemID    int64
+	Assignee  string
+	DueDate   *time.Time
+	Comment   string
+	CreatedBy string
+}
+
+// ChangeCaseStatusParams is a parameter to change the status of a case,
+// reason code is the reason the item is resolved when the case is resolved
+type ChangeCaseStatusParams struct {
+	CaseID     int64
+	Status     entity.ReconciliationCaseStatus
+	ReasonCode entity.ResolutionReasonCode
+	Comment    string
+	ChangedBy  string
+}
+
+// AddCaseCommentParams is a parameter to comment on a case
+type AddCaseCommentParams struct {
+	CaseID    int64
+	Comment   string
+	CreatedBy string
+}
+
+// CaseFilter is a filter to find the cases assigned to a user, every status is
+// found when status is empty
+type CaseFilter struct {
+	Assignee string
+	Status   entity.ReconciliationCaseStatus
+}
+
+// caseStatusTransitions is the statuses a case can be changed to from its
+// status, a resolved or written off case can only be reopened
+var caseStatusTransitions = map[entity.ReconciliationCaseStatus][]entity.ReconciliationCaseStatus{
+	entity.ReconciliationCaseStatusOpen: {
+		entity.ReconciliationCaseStatusInvestigating,
+		entity.ReconciliationCaseStatusResolved,
+		entity.ReconciliationCaseStatusWrittenOff,
+	},
+	entity.ReconciliationCaseStatusInvestigating: {
+		entity.ReconciliationCaseStatusOpen,
+		entity.ReconciliationCaseStatusResolved,
+		entity.ReconciliationCaseStatusWrittenOff,
+	},
+	entity.ReconciliationCaseStatusResolved:   {entity.ReconciliationCaseStatusOpen},
+	entity.ReconciliationCaseStatusWrittenOff: {entity.ReconciliationCaseStatusOpen},
+}
+
+// CaseManagerService is a service to follow up the unmatched items of reconciliation job as exception cases
+type CaseManagerService struct {
+	repo CaseManagerRepository
+	cfg  config.CaseManagerConfig
+	log  *zap.Logger
+}
+
+var _ = CaseManager(&CaseManagerService{})
+
+// NewCaseManagerService create new case manager service
+func NewCaseManagerService(repo CaseManagerRepository, cfg config.CaseManagerConfig) *CaseManagerService {
+	return &CaseManagerService{
+		repo: repo,
+		cfg:  cfg,
+		log:  zap.L().With(zap.String("service", "reconciliation_job.case_manager")),
+	}
+}
+
+// Open open a case on a missing item of a job that is not adjusted or
+// resolved yet, an item has at most a case
+func (s *CaseManagerService) Open(ctx context.Context, params *OpenCaseParams) (*entity.ReconciliationCase, error) {
+	log := logger.WithMethod(s.log, "Open")
+	items, err := s.repo.ListReconciliationItemsByIds(ctx, dbgen.ListReconciliationItemsByIdsParams{JobID: params.JobID, Ids: []int64{params.ItemID}})
+	if err != nil {
+		log.Error("failed to list reconciliation items by ids", zap.Error(err), zap.Int64("job_id", params.JobID), zap.Int64("item_id", params.ItemID))
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, ErrReconciliationItemNotFound
+	}
+	item := items[0]
+	if item.Status != string(entity.ReconciliationItemStatusMissing) || item.AdjustmentID.Valid || item.ResolvedJobID.Valid {
+		return nil, ErrReconciliationItemNotOpen
+	}
+
+	arg := dbgen.OpenReconciliationCaseParams{
+		CreateReconciliationCaseParams: dbgen.CreateReconciliationCaseParams{
+			ItemID:    params.ItemID,
+			Assignee:  params.Assignee,
+			CreatedBy: params.CreatedBy,
+		},
+		Status:  string(entity.ReconciliationCaseStatusOpen),
+		Comment: params.Comment,
+	}
+	if params.DueDate != nil {
+		arg.DueDate = sql.NullTime{Time: *params.DueDate, Valid: true}
+	}
+	rc, err := s.repo.OpenReconciliationCase(ctx, arg)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReconciliationCaseExists
+		}
+		log.Error("failed to open reconciliation case", zap.Error(err), zap.Int64("item_id", params.ItemID))
+		return nil, err
+	}
+
+	return convertToEntityReconciliationCase(dbgen.GetReconciliationCaseByIdRow{
+		ID:              rc.ID,
+		ItemID:          rc.ItemID,
+		Assignee:        rc.Assignee,
+		Status:          rc.Status,
+		DueDate:         rc.DueDate,
+		CreatedBy:       rc.CreatedBy,
+		CreatedAt:       rc.CreatedAt,
+		UpdatedAt:       rc.UpdatedAt,
+		JobID:           item.JobID,
+		Source:          item.Source,
+		BankName:        item.BankName,
+		TransactionID:   item.TransactionID,
+		Amount:          item.Amount,
+		Type:            item.Type,
+		TransactionTime: item.TransactionTime,
+	}), nil
+}
+
+// FindByID find a case along with its comments
+func (s *CaseManagerService) FindByID(ctx context.Context, id int64) (*entity.ReconciliationCase, error) {
+	log := logger.WithMethod(s.log, "FindByID")
+	rc, err := s.findByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.ListReconciliationCaseComments(ctx, id)
+	if err != nil {
+		log.Error("failed to list reconciliation case comments", zap.Error(err), zap.Int64("id", id))
+		return nil, err
+	}
+	rc.Comments = make([]entity.ReconciliationCaseComment, 0, len(comments))
+	for _, comment := range comments {
+		rc.Comments = append(rc.Comments, *convertToEntityReconciliationCaseComment(comment))
+	}
+
+	return rc, nil
+}
+
+// CountByAssignee count the cases assigned to a user
+func (s *CaseManagerService) CountByAssignee(ctx context.Context, filter CaseFilter) (int64, error) {
+	log := logger.WithMethod(s.log, "CountByAssignee")
+	total, err := s.repo.CountReconciliationCasesByAssignee(ctx, dbgen.CountReconciliationCasesByAssigneeParams{
+		Assignee: filter.Assignee,
+		Status:   sql.NullString{String: string(filter.Status), Valid: filter.Status != ""},
+	})
+	if err != nil {
+		log.Error("failed to count reconciliation cases", zap.Error(err), zap.String("assignee", filter.Assignee))
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// FindByAssignee find the cases assigned to a user, the cases due the
+// earliest come first and the cases without due date come last
+func (s *CaseManagerService) FindByAssignee(ctx context.Context, filter CaseFilter, limit, offset int32) ([]*entity.ReconciliationCase, error) {
+	log := logger.WithMethod(s.log, "FindByAssignee")
+	rows, err := s.repo.ListReconciliationCasesByAssignee(ctx, dbgen.ListReconciliationCasesByAssigneeParams{
+		Assignee: filter.Assignee,
+		Status:   sql.NullString{String: string(filter.Status), Valid: filter.Status != ""},
+		Limit:    limit,
+		Offset:   offset,
+	})
+	if err != nil {
+		log.Error("failed to list reconciliation cases", zap.Error(err), zap.String("assignee", filter.Assignee))
+		return nil, err
+	}
+
+	res := make([]*entity.ReconciliationCase, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, convertToEntityReconciliationCase(dbgen.GetReconciliationCaseByIdRow(row)))
+	}
+
+	return res, nil
+}
+
+// ChangeStatus change the status of a case by its assignee or a reviewer and
+// record it along with the comment, resolving or writing off a case requires a
+// comment and resolves the item of the case by an adjustment in the same
+// transaction, written off with the write off reason, the adjustment is
+// removed when the case is reopened
+func (s *CaseManagerService) ChangeStatus(ctx context.Context, params *ChangeCaseStatusParams) (*entity.ReconciliationCase, error) {
+	log := logger.WithMethod(s.log, "ChangeStatus")
+	closing := params.Status == entity.ReconciliationCaseStatusResolved || params.Status == entity.ReconciliationCaseStatusWrittenOff
+	if params.Comment == "" && closing {
+		return nil, ErrCaseCommentRequired
+	}
+	rc, err := s.findByID(ctx, params.CaseID)
+	if err != nil {
+		return nil, err
+	}
+	if rc.Assignee != params.ChangedBy && !slices.Contains(s.cfg.Reviewers, params.ChangedBy) {
+		return nil, ErrCaseStatusForbidden
+	}
+	if !slices.Contains(caseStatusTransitions[rc.Status], params.Status) {
+		return nil, errCaseStatusTransition(rc.Status, params.Status)
+	}
+
+	arg := dbgen.ChangeReconciliationCaseStatusParams{
+		UpdateReconciliationCaseStatusParams: dbgen.UpdateReconciliationCaseStatusParams{
+			Status:     string(params.Status),
+			ID:         params.CaseID,
+			FromStatus: string(rc.Status),
+		},
+		Comment:   params.Comment,
+		CreatedBy: params.ChangedBy,
+	}
+	if closing || rc.AdjustmentID != nil {
+		if err := s.checkJobAdjustable(ctx, rc.JobID); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case closing:
+		arg.Resolution = &dbgen.SaveReconciliationAdjustmentParams{
+			CreateReconciliationAdjustmentParams: dbgen.CreateReconciliationAdjustmentParams{
+				JobID:      rc.JobID,
+				Type:       string(entity.ReconciliationAdjustmentTypeResolved),
+				ReasonCode: sql.NullString{String: string(caseResolutionReason(params)), Valid: true},
+				Note:       params.Comment,
+				CreatedBy:  params.ChangedBy,
+			},
+			ItemIDs: []int64{rc.ItemID},
+		}
+	case rc.AdjustmentID != nil:
+		arg.Reverted = &dbgen.RevertReconciliationAdjustmentParams{JobID: rc.JobID, ID: *rc.AdjustmentID}
+	}
+
+	updated, err := s.repo.ChangeReconciliationCaseStatus(ctx, arg)
+	if err != nil {
+		// the status is changed by someone else since the case is found
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errCaseStatusTransition(rc.Status, params.Status)
+		}
+		if errors.Is(err, dbgen.ErrNotAdjustable) {
+			return nil, ErrReconciliationItemNotAdjustable
+		}
+		log.Error("failed to change reconciliation case status", zap.Error(err), zap.Int64("id", params.CaseID))
+		return nil, err
+	}
+	rc.Status = entity.ReconciliationCaseStatus(updated.Status)
+	rc.AdjustmentID = nil
+	if updated.AdjustmentID.Valid {
+		rc.AdjustmentID = &updated.AdjustmentID.Int64
+	}
+	rc.UpdatedAt = updated.UpdatedAt
+
+	return rc, nil
+}
+
+// AddComment add a comment to a case without changing its status
+func (s *CaseManagerService) AddComment(ctx context.Context, params *AddCaseCommentParams) (*entity.ReconciliationCaseComment, error) {
+	log := logger.WithMethod(s.log, "AddComment")
+	if _, err := s.findByID(ctx, params.CaseID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.repo.CreateReconciliationCaseComment(ctx, dbgen.CreateReconciliationCaseCommentParams{
+		CaseID:    params.CaseID,
+		Comment:   params.Comment,
+		CreatedBy: params.CreatedBy,
+	})
+	if err != nil {
+		log.Error("failed to create reconciliation case comment", zap.Error(err), zap.Int64("id", params.CaseID))
+		return nil, err
+	}
+
+	return convertToEntityReconciliationCaseComment(comment), nil
+}
+
+// checkJobAdjustable check the item of a case can be resolved or its
+// resolution removed, the same way the items of the job are adjusted
+func (s *CaseManagerService) checkJobAdjustable(ctx context.Context, jobID int64) error {
+	log := logger.WithMethod(s.log, "checkJobAdjustable")
+	rj, err := s.repo.GetReconciliationJobById(ctx, jobID)
+	if err != nil {
+		log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("job_id", jobID))
+		return err
+	}
+	err = checkJobAdjustable(ctx, s.repo.ListOverlappingReconciliationPeriodLocks, convertToEntityReconciliationJob(rj))
+	if err != nil && isUnexpectedAdjustableError(err) {
+		log.Error("failed to list overlapping reconciliation period locks", zap.Error(err), zap.Int64("job_id", jobID))
+	}
+
+	return err
+}
+
+// caseResolutionReason return the reason the item of a closed case is
+// resolved, a written off case is always written off and a resolved case
+// without reason is resolved for other reason explained in the comment
+func caseResolutionReason(params *ChangeCaseStatusParams) entity.ResolutionReasonCode {
+	if params.Status == entity.ReconciliationCaseStatusWrittenOff {
+		return entity.ResolutionReasonWriteOff
+	}
+	if params.ReasonCode == "" {
+		return entity.ResolutionReasonOther
+	}
+
+	return params.ReasonCode
+}
+
+func (s *CaseManagerService) findByID(ctx context.Context, id int64) (*entity.ReconciliationCase, error) {
+	log := logger.WithMethod(s.log, "findByID")
+	row, err := s.repo.GetReconciliationCaseById(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReconciliationCaseNotFound
+		}
+		log.Error("failed to get reconciliation case by id", zap.Error(err), zap.Int64("id", id))
+		return nil, err
+	}
+
+	return convertToEntityReconciliationCase(row), nil
+}
@@ -0,0 +1,478 @@
+package reconciliatonjob_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/delly/amartha/config"
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type CaseManagerTestSuite struct {
+	suite.Suite
+	repo *mock_reconciliatonjob.MockCaseManagerRepository
+	svc  *reconciliatonjob.CaseManagerService
+}
+
+func (s *CaseManagerTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_reconciliatonjob.NewMockCaseManagerRepository(ctrl)
+	s.svc = reconciliatonjob.NewCaseManagerService(s.repo, config.CaseManagerConfig{Reviewers: []string{"carol"}})
+}
+
+func TestCaseManagerTestSuite(t *testing.T) {
+	suite.Run(t, new(CaseManagerTestSuite))
+}
+
+var (
+	caseCreatedAt = time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	caseDueDate   = time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	dbReconCase   = dbgen.GetReconciliationCaseByIdRow{
+		ID:              1,
+		ItemID:          10,
+		Assignee:        "alice",
+		Status:          "OPEN",
+		DueDate:         sql.NullTime{Time: caseDueDate, Valid: true},
+		CreatedBy:       "bob",
+		CreatedAt:       caseCreatedAt,
+		UpdatedAt:       caseCreatedAt,
+		JobID:           id,
+		Source:          "BANK",
+		BankName:        sql.NullString{String: "BCA", Valid: true},
+		TransactionID:   "trx",
+		Amount:          1000,
+		Type:            "DEBIT",
+		TransactionTime: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	entityReconCase = &entity.ReconciliationCase{
+		ID:       1,
+		ItemID:   10,
+		JobID:    id,
+		Source:   entity.ReconciliationItemSourceBank,
+		BankName: "BCA",
+		Transaction: entity.Transaction{
+			ID:     "trx",
+			Amount: 1000,
+			Type:   entity.TxTypeDebit,
+			Time:   time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Assignee:  "alice",
+		Status:    entity.ReconciliationCaseStatusOpen,
+		DueDate:   &caseDueDate,
+		CreatedBy: "bob",
+		CreatedAt: caseCreatedAt,
+		UpdatedAt: caseCreatedAt,
+	}
+)
+
+func (s *CaseManagerTestSuite) TestOpen() {
+	ctx := context.Background()
+	params := &reconciliatonjob.OpenCaseParams{JobID: id, ItemID: 10, Assignee: "alice", DueDate: &caseDueDate, Comment: "please check", CreatedBy: "bob"}
+	listParams := dbgen.ListReconciliationItemsByIdsParams{JobID: id, Ids: []int64{10}}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceBank),
+		}, nil)
+		s.repo.EXPECT().OpenReconciliationCase(ctx, dbgen.OpenReconciliationCaseParams{
+			CreateReconciliationCaseParams: dbgen.CreateReconciliationCaseParams{
+				ItemID:    10,
+				Assignee:  "alice",
+				DueDate:   sql.NullTime{Time: caseDueDate, Valid: true},
+				CreatedBy: "bob",
+			},
+			Status:  "OPEN",
+			Comment: "please check",
+		}).Return(dbgen.ReconciliationCase{
+			ID:        1,
+			ItemID:    10,
+			Assignee:  "alice",
+			Status:    "OPEN",
+			DueDate:   sql.NullTime{Time: caseDueDate, Valid: true},
+			CreatedBy: "bob",
+			CreatedAt: caseCreatedAt,
+			UpdatedAt: caseCreatedAt,
+		}, nil)
+
+		res, err := s.svc.Open(ctx, params)
+
+		s.NoError(err)
+		s.Equal(entityReconCase, res)
+	})
+
+	s.Run("item not found", func() {
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return(nil, nil)
+
+		res, err := s.svc.Open(ctx, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotFound)
+		s.Nil(res)
+	})
+
+	s.Run("item adjusted", func() {
+		adjusted := missingItem(10, entity.ReconciliationItemSourceBank)
+		adjusted.AdjustmentID = sql.NullInt64{Int64: 1, Valid: true}
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{adjusted}, nil)
+
+		res, err := s.svc.Open(ctx, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotOpen)
+		s.Nil(res)
+	})
+
+	s.Run("case exists", func() {
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceBank),
+		}, nil)
+		s.repo.EXPECT().OpenReconciliationCase(ctx, gomock.Any()).Return(dbgen.ReconciliationCase{}, pgx.ErrNoRows)
+
+		res, err := s.svc.Open(ctx, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationCaseExists)
+		s.Nil(res)
+	})
+
+	s.Run("error open case", func() {
+		s.repo.EXPECT().ListReconciliationItemsByIds(ctx, listParams).Return([]dbgen.ReconciliationItem{
+			missingItem(10, entity.ReconciliationItemSourceBank),
+		}, nil)
+		s.repo.EXPECT().OpenReconciliationCase(ctx, gomock.Any()).Return(dbgen.ReconciliationCase{}, assert.AnError)
+
+		res, err := s.svc.Open(ctx, params)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
+
+func (s *CaseManagerTestSuite) TestFindByID() {
+	ctx := context.Background()
+
+	s.Run("success", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		s.repo.EXPECT().ListReconciliationCaseComments(ctx, int64(1)).Return([]dbgen.ReconciliationCaseComment{
+			{ID: 1, CaseID: 1, Status: sql.NullString{String: "OPEN", Valid: true}, Comment: "please check", CreatedBy: "bob", CreatedAt: caseCreatedAt},
+			{ID: 2, CaseID: 1, Comment: "asked the bank", CreatedBy: "alice", CreatedAt: caseCreatedAt},
+		}, nil)
+
+		res, err := s.svc.FindByID(ctx, 1)
+
+		expected := *entityReconCase
+		expected.Comments = []entity.ReconciliationCaseComment{
+			{ID: 1, CaseID: 1, Status: entity.ReconciliationCaseStatusOpen, Comment: "please check", CreatedBy: "bob", CreatedAt: caseCreatedAt},
+			{ID: 2, CaseID: 1, Comment: "asked the bank", CreatedBy: "alice", CreatedAt: caseCreatedAt},
+		}
+		s.NoError(err)
+		s.Equal(&expected, res)
+	})
+
+	s.Run("not found", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbgen.GetReconciliationCaseByIdRow{}, pgx.ErrNoRows)
+
+		res, err := s.svc.FindByID(ctx, 1)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationCaseNotFound)
+		s.Nil(res)
+	})
+
+	s.Run("error list comments", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		s.repo.EXPECT().ListReconciliationCaseComments(ctx, int64(1)).Return(nil, assert.AnError)
+
+		res, err := s.svc.FindByID(ctx, 1)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
+
+func (s *CaseManagerTestSuite) TestFindByAssignee() {
+	ctx := context.Background()
+	filter := reconciliatonjob.CaseFilter{Assignee: "alice", Status: entity.ReconciliationCaseStatusOpen}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().ListReconciliationCasesByAssignee(ctx, dbgen.ListReconciliationCasesByAssigneeParams{
+			Assignee: "alice",
+			Status:   sql.NullString{String: "OPEN", Valid: true},
+			Limit:    10,
+			Offset:   0,
+		}).Return([]dbgen.ListReconciliationCasesByAssigneeRow{dbgen.ListReconciliationCasesByAssigneeRow(dbReconCase)}, nil)
+
+		res, err := s.svc.FindByAssignee(ctx, filter, 10, 0)
+
+		s.NoError(err)
+		s.Equal([]*entity.ReconciliationCase{entityReconCase}, res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().ListReconciliationCasesByAssignee(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+		res, err := s.svc.FindByAssignee(ctx, filter, 10, 0)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
+
+func (s *CaseManagerTestSuite) TestCountByAssignee() {
+	ctx := context.Background()
+
+	s.Run("every status", func() {
+		s.repo.EXPECT().CountReconciliationCasesByAssignee(ctx, dbgen.CountReconciliationCasesByAssigneeParams{Assignee: "alice"}).Return(int64(3), nil)
+
+		total, err := s.svc.CountByAssignee(ctx, reconciliatonjob.CaseFilter{Assignee: "alice"})
+
+		s.NoError(err)
+		s.Equal(int64(3), total)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().CountReconciliationCasesByAssignee(ctx, gomock.Any()).Return(int64(0), assert.AnError)
+
+		total, err := s.svc.CountByAssignee(ctx, reconciliatonjob.CaseFilter{Assignee: "alice"})
+
+		s.ErrorIs(err, assert.AnError)
+		s.Zero(total)
+	})
+}
+
+func (s *CaseManagerTestSuite) TestChangeStatus() {
+	ctx := context.Background()
+	updatedAt := caseCreatedAt.Add(time.Hour)
+
+	adjustmentID := int64(5)
+	expectJobAdjustable := func() {
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbReconJob, nil)
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return(nil, nil)
+	}
+
+	s.Run("success write off resolve the item", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		expectJobAdjustable()
+		s.repo.EXPECT().ChangeReconciliationCaseStatus(ctx, dbgen.ChangeReconciliationCaseStatusParams{
+			UpdateReconciliationCaseStatusParams: dbgen.UpdateReconciliationCaseStatusParams{
+				Status:     "WRITTEN_OFF",
+				ID:         1,
+				FromStatus: "OPEN",
+			},
+			Comment:   "immaterial",
+			CreatedBy: "alice",
+			Resolution: &dbgen.SaveReconciliationAdjustmentParams{
+				CreateReconciliationAdjustmentParams: dbgen.CreateReconciliationAdjustmentParams{
+					JobID:      id,
+					Type:       "RESOLVED",
+					ReasonCode: sql.NullString{String: "WRITE_OFF", Valid: true},
+					Note:       "immaterial",
+					CreatedBy:  "alice",
+				},
+				ItemIDs: []int64{10},
+			},
+		}).Return(dbgen.ReconciliationCase{ID: 1, Status: "WRITTEN_OFF", UpdatedAt: updatedAt, AdjustmentID: sql.NullInt64{Int64: adjustmentID, Valid: true}}, nil)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{
+			CaseID:     1,
+			Status:     entity.ReconciliationCaseStatusWrittenOff,
+			ReasonCode: entity.ResolutionReasonBankError,
+			Comment:    "immaterial",
+			ChangedBy:  "alice",
+		})
+
+		expected := *entityReconCase
+		expected.Status = entity.ReconciliationCaseStatusWrittenOff
+		expected.AdjustmentID = &adjustmentID
+		expected.UpdatedAt = updatedAt
+		s.NoError(err)
+		s.Equal(&expected, res)
+	})
+
+	s.Run("success resolve with reason by a reviewer", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		expectJobAdjustable()
+		s.repo.EXPECT().ChangeReconciliationCaseStatus(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, arg dbgen.ChangeReconciliationCaseStatusParams) (dbgen.ReconciliationCase, error) {
+				s.Equal(sql.NullString{String: "BANK_ERROR", Valid: true}, arg.Resolution.ReasonCode)
+				s.Equal("carol", arg.Resolution.CreatedBy)
+				return dbgen.ReconciliationCase{ID: 1, Status: "RESOLVED", AdjustmentID: sql.NullInt64{Int64: adjustmentID, Valid: true}}, nil
+			})
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{
+			CaseID:     1,
+			Status:     entity.ReconciliationCaseStatusResolved,
+			ReasonCode: entity.ResolutionReasonBankError,
+			Comment:    "bank corrected it",
+			ChangedBy:  "carol",
+		})
+
+		s.NoError(err)
+		s.Equal(entity.ReconciliationCaseStatusResolved, res.Status)
+		s.Equal(&adjustmentID, res.AdjustmentID)
+	})
+
+	s.Run("success investigate without resolving the item", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		s.repo.EXPECT().ChangeReconciliationCaseStatus(ctx, dbgen.ChangeReconciliationCaseStatusParams{
+			UpdateReconciliationCaseStatusParams: dbgen.UpdateReconciliationCaseStatusParams{
+				Status:     "INVESTIGATING",
+				ID:         1,
+				FromStatus: "OPEN",
+			},
+			CreatedBy: "alice",
+		}).Return(dbgen.ReconciliationCase{ID: 1, Status: "INVESTIGATING", UpdatedAt: updatedAt}, nil)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusInvestigating, ChangedBy: "alice"})
+
+		s.NoError(err)
+		s.Equal(entity.ReconciliationCaseStatusInvestigating, res.Status)
+		s.Nil(res.AdjustmentID)
+	})
+
+	s.Run("reopen without comment revert the resolution", func() {
+		resolved := dbReconCase
+		resolved.Status = "RESOLVED"
+		resolved.AdjustmentID = sql.NullInt64{Int64: adjustmentID, Valid: true}
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(resolved, nil)
+		expectJobAdjustable()
+		s.repo.EXPECT().ChangeReconciliationCaseStatus(ctx, dbgen.ChangeReconciliationCaseStatusParams{
+			UpdateReconciliationCaseStatusParams: dbgen.UpdateReconciliationCaseStatusParams{
+				Status:     "OPEN",
+				ID:         1,
+				FromStatus: "RESOLVED",
+			},
+			CreatedBy: "alice",
+			Reverted:  &dbgen.RevertReconciliationAdjustmentParams{JobID: id, ID: adjustmentID},
+		}).Return(dbgen.ReconciliationCase{ID: 1, Status: "OPEN", UpdatedAt: updatedAt}, nil)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusOpen, ChangedBy: "alice"})
+
+		s.NoError(err)
+		s.Equal(entity.ReconciliationCaseStatusOpen, res.Status)
+		s.Nil(res.AdjustmentID)
+	})
+
+	s.Run("neither assignee nor reviewer", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusInvestigating, ChangedBy: "dave"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrCaseStatusForbidden)
+		s.Nil(res)
+	})
+
+	s.Run("period of the job locked", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbReconJob, nil)
+		s.repo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, lockParams).Return([]dbgen.ReconciliationPeriodLock{
+			{ID: 3, JobID: 2, BankName: "BCA", StartDate: lastMonth, EndDate: yesterday},
+		}, nil)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusResolved, Comment: "found", ChangedBy: "alice"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+		s.Nil(res)
+	})
+
+	s.Run("item closed concurrently", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		expectJobAdjustable()
+		s.repo.EXPECT().ChangeReconciliationCaseStatus(ctx, gomock.Any()).Return(dbgen.ReconciliationCase{}, dbgen.ErrNotAdjustable)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusResolved, Comment: "found", ChangedBy: "alice"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationItemNotAdjustable)
+		s.Nil(res)
+	})
+
+	s.Run("error get job", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusResolved, Comment: "found", ChangedBy: "alice"})
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+
+	s.Run("comment required to close", func() {
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusResolved, ChangedBy: "alice"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrCaseCommentRequired)
+		s.Nil(res)
+	})
+
+	s.Run("invalid transition", func() {
+		written := dbReconCase
+		written.Status = "WRITTEN_OFF"
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(written, nil)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusInvestigating, ChangedBy: "alice"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidCaseStatusTransition)
+		s.EqualError(err, "invalid reconciliation case status transition: case cannot be changed from WRITTEN_OFF to INVESTIGATING")
+		s.Nil(res)
+	})
+
+	s.Run("same status", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusOpen, ChangedBy: "alice"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidCaseStatusTransition)
+		s.Nil(res)
+	})
+
+	s.Run("status changed concurrently", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		s.repo.EXPECT().ChangeReconciliationCaseStatus(ctx, gomock.Any()).Return(dbgen.ReconciliationCase{}, pgx.ErrNoRows)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusInvestigating, ChangedBy: "alice"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidCaseStatusTransition)
+		s.Nil(res)
+	})
+
+	s.Run("not found", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbgen.GetReconciliationCaseByIdRow{}, pgx.ErrNoRows)
+
+		res, err := s.svc.ChangeStatus(ctx, &reconciliatonjob.ChangeCaseStatusParams{CaseID: 1, Status: entity.ReconciliationCaseStatusInvestigating, ChangedBy: "alice"})
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationCaseNotFound)
+		s.Nil(res)
+	})
+}
+
+func (s *CaseManagerTestSuite) TestAddComment() {
+	ctx := context.Background()
+	params := &reconciliatonjob.AddCaseCommentParams{CaseID: 1, Comment: "asked the bank", CreatedBy: "alice"}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbReconCase, nil)
+		s.repo.EXPECT().CreateReconciliationCaseComment(ctx, dbgen.CreateReconciliationCaseCommentParams{
+			CaseID:    1,
+			Comment:   "asked the bank",
+			CreatedBy: "alice",
+		}).Return(dbgen.ReconciliationCaseComment{ID: 2, CaseID: 1, Comment: "asked the bank", CreatedBy: "alice", CreatedAt: caseCreatedAt}, nil)
+
+		res, err := s.svc.AddComment(ctx, params)
+
+		s.NoError(err)
+		s.Equal(&entity.ReconciliationCaseComment{ID: 2, CaseID: 1, Comment: "asked the bank", CreatedBy: "alice", CreatedAt: caseCreatedAt}, res)
+	})
+
+	s.Run("not found", func() {
+		s.repo.EXPECT().GetReconciliationCaseById(ctx, int64(1)).Return(dbgen.GetReconciliationCaseByIdRow{}, pgx.ErrNoRows)
+
+		res, err := s.svc.AddComment(ctx, params)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationCaseNotFound)
+		s.Nil(res)
+	})
+}
@@ -1,6 +1,8 @@
 package reconciliatonjob
 
 import (
+	"database/sql"
+
 	"github.com/delly/amartha/entity"
 	dbgen "github.com/delly/amartha/repository/postgresql"
 )
@@ -12,13 +14,21 @@ func convertToEntityReconciliationJob(rj dbgen.ReconciliationJob) *entity.Reconc
 		SystemTransactionCsvPath: rj.SystemTransactionCsvPath,
 		DiscrepancyThreshold:     float32(rj.DiscrepancyThreshold),
 		ErrorInformation:         rj.ErrorInformation.String,
+		ErrorCode:                entity.ReconciliationJobErrorCode(rj.ErrorCode.String),
+		ApprovalStatus:           entity.ReconciliationApprovalStatus(rj.ApprovalStatus.String),
 		StartDate:                rj.StartDate,
 		EndDate:                  rj.EndDate,
 		CreatedAt:                rj.CreatedAt,
 		UpdatedAt:                rj.UpdatedAt,
 	}
+	if rj.ParentJobID.Valid {
+		res.ParentJobID = &rj.ParentJobID.Int64
+	}
 	rj.BankTransactionCsvPaths.AssignTo(&res.BankTransactionCsvPaths)
+	rj.MatchingOptions.AssignTo(&res.MatchingOptions)
 	rj.Result.AssignTo(&res.Result)
+	rj.AdjustedTotals.AssignTo(&res.AdjustedTotals)
+	rj.Progress.AssignTo(&res.Progress)
 
 	return res
 }
@@ -29,6 +39,7 @@ func convertRowListDbToEntitySimpleReconciliationJob(r dbgen.ListReconciliationJ
 		DiscrepancyThreshold:     float32(r.DiscrepancyThreshold),
 		SystemTransactionCsvPath: r.SystemTransactionCsvPath,
 		Status:                   entity.ReconciliationJobStatus(r.Status),
+		ApprovalStatus:           entity.ReconciliationApprovalStatus(r.ApprovalStatus.String),
 		StartDate:                r.StartDate,
 		EndDate:                  r.EndDate,
 	}
@@ -36,3 +47,205 @@ func convertRowListDbToEntitySimpleReconciliationJob(r dbgen.ListReconciliationJ
 
 	return res
 }
+
+func convertToEntityReconciliationItem(item dbgen.ReconciliationItem) *entity.ReconciliationItem {
+	res := &entity.ReconciliationItem{
+		ID:    item.ID,
+		JobID: item.JobID,
+		Transaction: entity.Transaction{
+			ID:     item.TransactionID,
+			Amount: item.Amount,
+			Type:   entity.TransactionType(item.Type),
+			Time:   item.TransactionTime,
+		},
+		Source:   entity.ReconciliationItemSource(item.Source),
+		BankName: item.BankName.String,
+		Row:      int(item.RowNumber),
+		Status:   entity.ReconciliationItemStatus(item.Status),
+	}
+	if item.CarriedFromID.Valid {
+		res.CarriedFromID = &item.CarriedFromID.Int64
+	}
+	if item.ResolvedJobID.Valid {
+		res.ResolvedJobID = &item.ResolvedJobID.Int64
+	}
+	if item.AdjustmentID.Valid {
+		res.AdjustmentID = &item.AdjustmentID.Int64
+	}
+	res.DuplicateOfRow = nullInt32ToIntPtr(item.DuplicateOfRow)
+	if item.OverlapBankName.Valid {
+		res.OverlapBankName = &item.OverlapBankName.String
+	}
+	res.OverlapRow = nullInt32ToIntPtr(item.OverlapRow)
+	if item.PairedBankName.Valid {
+		res.PairedBankName = &item.PairedBankName.String
+	}
+	res.PairedRow = nullInt32ToIntPtr(item.PairedRow)
+
+	return res
+}
+
+func convertToEntityReconciliationDiffItem(row dbgen.DiffReconciliationItemsRow) entity.ReconciliationDiffItem {
+	item := entity.ReconciliationDiffItem{
+		Source:        entity.ReconciliationItemSource(row.Source),
+		BankName:      row.BankName,
+		TransactionID: row.TransactionID,
+	}
+	item.Row = nullInt32ToIntPtr(row.BaseRow)
+	if row.BaseStatus.Valid {
+		status := entity.ReconciliationItemStatus(row.BaseStatus.String)
+		item.Status = &status
+	}
+	if row.BaseAmount.Valid {
+		item.Amount = &row.BaseAmount.Float64
+	}
+	item.OtherRow = nullInt32ToIntPtr(row.OtherRow)
+	if row.OtherStatus.Valid {
+		status := entity.ReconciliationItemStatus(row.OtherStatus.String)
+		item.OtherStatus = &status
+	}
+	if row.OtherAmount.Valid {
+		item.OtherAmount = &row.OtherAmount.Float64
+	}
+
+	return item
+}
+
+func convertToDbReconciliationItem(jobID int64, item entity.ReconciliationItem) dbgen.CopyReconciliationItemsParams {
+	res := dbgen.CopyReconciliationItemsParams{
+		JobID:           jobID,
+		Source:          string(item.Source),
+		BankName:        sql.NullString{String: item.BankName, Valid: item.Source == entity.ReconciliationItemSourceBank},
+		RowNumber:       int32(item.Row),
+		TransactionID:   item.Transaction.ID,
+		Amount:          item.Transaction.Amount,
+		Type:            string(item.Transaction.Type),
+		TransactionTime: item.Transaction.Time,
+		Status:          string(item.Status),
+		DuplicateOfRow:  intPtrToNullInt32(item.DuplicateOfRow),
+		OverlapRow:      intPtrToNullInt32(item.OverlapRow),
+		PairedRow:       intPtrToNullInt32(item.PairedRow),
+	}
+	if item.CarriedFromID != nil {
+		res.CarriedFromID = sql.NullInt64{Int64: *item.CarriedFromID, Valid: true}
+	}
+	if item.OverlapBankName != nil {
+		res.OverlapBankName = sql.NullString{String: *item.OverlapBankName, Valid: true}
+	}
+	if item.PairedBankName != nil {
+		res.PairedBankName = sql.NullString{String: *item.PairedBankName, Valid: true}
+	}
+
+	return res
+}
+
+func convertToDbReconciliationMatch(jobID int64, match entity.ReconciliationMatch) dbgen.CopyReconciliationMatchesParams {
+	return dbgen.CopyReconciliationMatchesParams{
+		JobID:       jobID,
+		SystemRow:   int32(match.SystemRow),
+		BankName:    match.BankName,
+		BankRow:     int32(match.BankRow),
+		Status:      string(match.Status),
+		Confidence:  match.Confidence,
+		ExpectedFee: match.ExpectedFee,
+		ImpliedFee:  match.ImpliedFee,
+	}
+}
+
+func nullInt32ToIntPtr(v sql.NullInt32) *int {
+	if !v.Valid {
+		return nil
+	}
+	res := int(v.Int32)
+	return &res
+}
+
+func intPtrToNullInt32(v *int) sql.NullInt32 {
+	if v == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: int32(*v), Valid: true}
+}
+
+func convertRowListDbToEntityReconciliationAdjustment(r dbgen.ListReconciliationAdjustmentsRow) *entity.ReconciliationAdjustment {
+	return &entity.ReconciliationAdjustment{
+		ID:         r.ID,
+		JobID:      r.JobID,
+		Type:       entity.ReconciliationAdjustmentType(r.Type),
+		ReasonCode: entity.ResolutionReasonCode(r.ReasonCode.String),
+		Note:       r.Note,
+		ItemIDs:    r.ItemIds,
+		CreatedBy:  r.CreatedBy,
+		CreatedAt:  r.CreatedAt,
+	}
+}
+
+func convertToEntityReconciliationCase(r dbgen.GetReconciliationCaseByIdRow) *entity.ReconciliationCase {
+	res := &entity.ReconciliationCase{
+		ID:       r.ID,
+		ItemID:   r.ItemID,
+		JobID:    r.JobID,
+		Source:   entity.ReconciliationItemSource(r.Source),
+		BankName: r.BankName.String,
+		Transaction: entity.Transaction{
+			ID:     r.TransactionID,
+			Amount: r.Amount,
+			Type:   entity.TransactionType(r.Type),
+			Time:   r.TransactionTime,
+		},
+		Assignee:  r.Assignee,
+		Status:    entity.ReconciliationCaseStatus(r.Status),
+		CreatedBy: r.CreatedBy,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+	if r.DueDate.Valid {
+		res.DueDate = &r.DueDate.Time
+	}
+	if r.AdjustmentID.Valid {
+		res.AdjustmentID = &r.AdjustmentID.Int64
+	}
+
+	return res
+}
+
+func convertToEntityReconciliationCaseComment(c dbgen.ReconciliationCaseComment) *entity.ReconciliationCaseComment {
+	return &entity.ReconciliationCaseComment{
+		ID:        c.ID,
+		CaseID:    c.CaseID,
+		Status:    entity.ReconciliationCaseStatus(c.Status.String),
+		Comment:   c.Comment,
+		CreatedBy: c.CreatedBy,
+		CreatedAt: c.CreatedAt,
+	}
+}
+
+func convertToEntityReconciliationApproval(a dbgen.ReconciliationApproval) *entity.ReconciliationApproval {
+	return &entity.ReconciliationApproval{
+		ID:        a.ID,
+		JobID:     a.JobID,
+		Status:    entity.ReconciliationApprovalStatus(a.Status),
+		Comment:   a.Comment,
+		CreatedBy: a.CreatedBy,
+		CreatedAt: a.CreatedAt,
+	}
+}
+
+func convertToEntityReconciliationPeriodLock(l dbgen.ReconciliationPeriodLock) *entity.ReconciliationPeriodLock {
+	res := &entity.ReconciliationPeriodLock{
+		ID:           l.ID,
+		JobID:        l.JobID,
+		BankName:     l.BankName,
+		StartDate:    l.StartDate,
+		EndDate:      l.EndDate,
+		LockedBy:     l.LockedBy,
+		LockedAt:     l.LockedAt,
+		UnlockedBy:   l.UnlockedBy.String,
+		UnlockReason: l.UnlockReason.String,
+	}
+	if l.UnlockedAt.Valid {
+		res.UnlockedAt = &l.UnlockedAt.Time
+	}
+
+	return res
+}
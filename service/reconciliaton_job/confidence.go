@@ -0,0 +1,71 @@
+package reconciliatonjob
+
+import (
+	"math"
+	"time"
+
+	"github.com/delly/amartha/entity"
+)
+
+// weights of the confidence score components, they sum up to 1
+const (
+	amountConfidenceWeight     = 0.5
+	dateConfidenceWeight       = 0.25
+	uniquenessConfidenceWeight = 0.25
+)
+
+// scoreMatch compute the confidence of matching the system transaction with
+// the bank transaction, tolerance is the discrepancy allowed from the expected
+// bank amount and totalCandidates is the number of bank transactions the
+// system transaction could be matched with, the transaction IDs are not
+// compared since the system and the bank number their transactions apart
+func scoreMatch(system, bank *entity.Transaction, expectedAmount, tolerance float64, totalCandidates int) entity.MatchConfidence {
+	confidence := entity.MatchConfidence{
+		Amount:     amountSimilarity(expectedAmount, bank.Amount, tolerance),
+		Date:       timeSimilarity(system.Time, bank.Time),
+		Uniqueness: 1 / float64(max(totalCandidates, 1)),
+	}
+	confidence.Score = roundScore(confidence.Amount*amountConfidenceWeight +
+		confidence.Date*dateConfidenceWeight +
+		confidence.Uniqueness*uniquenessConfidenceWeight)
+	confidence.Amount = roundScore(confidence.Amount)
+	confidence.Date = roundScore(confidence.Date)
+	confidence.Uniqueness = roundScore(confidence.Uniqueness)
+
+	return confidence
+}
+
+// amountSimilarity is 1 for the exact amount and decreases linearly to 0 at
+// the edge of the tolerance
+func amountSimilarity(expected, actual, tolerance float64) float64 {
+	diff := math.Abs(expected - actual)
+	if diff == 0 {
+		return 1
+	}
+	if tolerance <= 0 {
+		return 0
+	}
+
+	return math.Max(0, 1-diff/tolerance)
+}
+
+// timeSimilarity is 1 when the system transaction time is within the day the
+// bank posts the transaction, the bank file only has the date, and decreases
+// linearly to 0 a day away from it, so a carried transaction booked just
+// before midnight scores higher than one booked early in the previous day
+func timeSimilarity(systemTime, bankDate time.Time) float64 {
+	var diff time.Duration
+	switch {
+	case systemTime.Before(bankDate):
+		diff = bankDate.Sub(systemTime)
+	case !systemTime.Before(bankDate.AddDate(0, 0, 1)):
+		diff = systemTime.Sub(bankDate.AddDate(0, 0, 1))
+	}
+
+	return math.Max(0, 1-diff.Hours()/24)
+}
+
+// roundScore round the score to 4 decimal places so it is stable in the result
+func roundScore(score float64) float64 {
+	return math.Round(score*10000) / 10000
+}
@@ -1,31 +1,40 @@
 package reconciliatonjob
 
 import (
-	"bytes"
 	"context"
 	"crypto/rand"
+	"database/sql"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/delly/amartha/common/logger"
 	"github.com/delly/amartha/entity"
 	filestorage "github.com/delly/amartha/repository/file_storage"
 	dbgen "github.com/delly/amartha/repository/postgresql"
+	"github.com/jackc/pgx/v4"
 	"go.uber.org/zap"
 )
 
 // Creator is a contract to create reconciliation job
 type Creator interface {
 	Create(ctx context.Context, params *CreateParams) (*entity.ReconciliationJob, error)
+	Rerun(ctx context.Context, params *RerunParams) (*entity.ReconciliationJob, error)
+	StoreFile(ctx context.Context, params *CreateParams, file *File) error
+	DiscardFiles(ctx context.Context, params *CreateParams)
 }
 
 // CreatorRepository is a contract to create reconciliation job
 type CreatorRepository interface {
-	CreateReconciliationJob(ctx context.Context, job dbgen.CreateReconciliationJobParams) (dbgen.ReconciliationJob, error)
+	CreateReconciliationJobInUnlockedPeriod(ctx context.Context, arg dbgen.CreateReconciliationJobInUnlockedPeriodParams) (dbgen.ReconciliationJob, error)
+	GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error)
+	ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error)
 }
 
-// FileStorer is a contract to store file
+// FileStorer is a contract to store and delete file
 type FileStorer interface {
+	Delete(ctx context.Context, filePath string) error
 	Store(ctx context.Context, file *filestorage.File) (string, error)
 }
 
@@ -36,11 +45,11 @@ type CreatorService struct {
 	log      *zap.Logger
 }
 
-// File is a struct to hold metadata of csv file
+// File is a struct to hold metadata and content stream of csv file
 type File struct {
-	Name string
-	Buf  *bytes.Buffer
-	Path string
+	Name   string
+	Reader io.Reader
+	Path   string
 }
 
 // BankTransactionFile is a struct to hold metadata of bank transaction csv files
@@ -49,13 +58,30 @@ type BankTransactionFile struct {
 	File     *File
 }
 
-// CreateParams is a parameter to create reconciliation job
+// CreateParams is a parameter to create reconciliation job, the files that are
+// already stored by StoreFile are not stored again
 type CreateParams struct {
 	SystemTransactionCsv *File
 	BankTransactionCsvs  []*BankTransactionFile
 	StartDate            time.Time
 	EndDate              time.Time
 	DiscrepancyThreshold float32
+	MatchingOptions      entity.MatchingOptions
+
+	// dir is the directory of the stored files of the job
+	dir string
+	// storedPaths is the paths of the files stored by StoreFile
+	storedPaths []string
+}
+
+// RerunParams is a parameter to re-run reconciliation job against the uploaded
+// files of the original job, nil value means the original job value is used
+type RerunParams struct {
+	JobID                int64
+	StartDate            *time.Time
+	EndDate              *time.Time
+	DiscrepancyThreshold *float32
+	MatchingOptions      *entity.MatchingOptions
 }
 
 var _ = Creator(&CreatorService{})
@@ -73,33 +99,36 @@ func NewCreatorService(repo CreatorRepository,
 // Create create reconciliation job
 func (s *CreatorService) Create(ctx context.Context, params *CreateParams) (*entity.ReconciliationJob, error) {
 	log := logger.WithMethod(s.log, "Create")
-	dir := s.generateUniqueFileDirectory()
-	path, err := s.fileRepo.Store(ctx, &filestorage.File{
-		Name: params.SystemTransactionCsv.Name,
-		Dir:  dir,
-		Buf:  params.SystemTransactionCsv.Buf,
-	})
-	if err != nil {
-		log.Error("failed to store system transaction csv", zap.Error(err))
+	if err := normalizeMatchingOptions(&params.MatchingOptions); err != nil {
+		return nil, err
+	}
+	if err := validateFeeRuleBanks(&params.MatchingOptions, params.bankNames()); err != nil {
+		return nil, err
+	}
+	if err := s.checkPeriodUnlocked(ctx, params.bankNames(), params.StartDate, params.EndDate); err != nil {
 		return nil, err
 	}
-	params.SystemTransactionCsv.Path = path
 
+	if err := s.StoreFile(ctx, params, params.SystemTransactionCsv); err != nil {
+		log.Error("failed to store system transaction csv", zap.Error(err))
+		return nil, err
+	}
 	for _, v := range params.BankTransactionCsvs {
-		path, err := s.fileRepo.Store(ctx, &filestorage.File{
-			Name: v.File.Name,
-			Dir:  dir,
-			Buf:  v.File.Buf,
-		})
-		if err != nil {
+		if err := s.StoreFile(ctx, params, v.File); err != nil {
 			log.Error("failed to store bank transaction csv", zap.Error(err))
 			return nil, err
 		}
-		v.File.Path = path
 	}
 
-	rj, err := s.repo.CreateReconciliationJob(ctx, params.convertParamsToDB())
+	rj, err := s.repo.CreateReconciliationJobInUnlockedPeriod(ctx, dbgen.CreateReconciliationJobInUnlockedPeriodParams{
+		CreateReconciliationJobParams: params.convertParamsToDB(),
+		BankNames:                     params.bankNames(),
+	})
 	if err != nil {
+		// the period is locked by an approval since it is checked
+		if err = convertPeriodLockedError(err); errors.Is(err, ErrPeriodLocked) {
+			return nil, err
+		}
 		log.Error("failed to create reconciliation job", zap.Error(err))
 		return nil, err
 	}
@@ -107,6 +136,138 @@ func (s *CreatorService) Create(ctx context.Context, params *CreateParams) (*ent
 	return convertToEntityReconciliationJob(rj), nil
 }
 
+// Rerun create new reconciliation job referencing the stored files of the original job
+func (s *CreatorService) Rerun(ctx context.Context, params *RerunParams) (*entity.ReconciliationJob, error) {
+	log := logger.WithMethod(s.log, "Rerun")
+	if params.MatchingOptions != nil {
+		if err := normalizeMatchingOptions(params.MatchingOptions); err != nil {
+			return nil, err
+		}
+	}
+
+	parent, err := s.repo.GetReconciliationJobById(ctx, params.JobID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReconciliationJobNotFound
+		}
+		log.Error("failed to get reconciliation job by id", zap.Error(err), zap.Int64("id", params.JobID))
+		return nil, err
+	}
+	bankNames := jobBankNames(convertToEntityReconciliationJob(parent))
+	if params.MatchingOptions != nil {
+		if err := validateFeeRuleBanks(params.MatchingOptions, bankNames); err != nil {
+			return nil, err
+		}
+	}
+
+	dbParams := params.convertParamsToDB(parent)
+	if dbParams.StartDate.After(dbParams.EndDate) {
+		return nil, ErrInvalidDateRange
+	}
+	if err := s.checkPeriodUnlocked(ctx, bankNames, dbParams.StartDate, dbParams.EndDate); err != nil {
+		return nil, err
+	}
+
+	rj, err := s.repo.CreateReconciliationJobInUnlockedPeriod(ctx, dbgen.CreateReconciliationJobInUnlockedPeriodParams{
+		CreateReconciliationJobParams: dbParams,
+		BankNames:                     bankNames,
+	})
+	if err != nil {
+		if err = convertPeriodLockedError(err); errors.Is(err, ErrPeriodLocked) {
+			return nil, err
+		}
+		log.Error("failed to create reconciliation job", zap.Error(err), zap.Int64("parent_job_id", params.JobID))
+		return nil, err
+	}
+
+	return convertToEntityReconciliationJob(rj), nil
+}
+
+// StoreFile stream the file to the storage under the directory of the job to
+// be created and set its stored path, so an uploaded file can be stored as soon
+// as it is received, a file that is already stored is skipped, the stored name
+// is prefixed by the order of the file so uploads of the same name are kept
+func (s *CreatorService) StoreFile(ctx context.Context, params *CreateParams, file *File) error {
+	if file.Path != "" {
+		return nil
+	}
+	if params.dir == "" {
+		params.dir = s.generateUniqueFileDirectory()
+	}
+
+	path, err := s.fileRepo.Store(ctx, &filestorage.File{
+		Name:   fmt.Sprintf("%d_%s", len(params.storedPaths), file.Name),
+		Dir:    params.dir,
+		Reader: file.Reader,
+	})
+	if err != nil {
+		return err
+	}
+	file.Path = path
+	params.storedPaths = append(params.storedPaths, path)
+
+	return nil
+}
+
+// DiscardFiles delete the files stored by StoreFile when the job is not
+// created, a file failed to be deleted is only logged
+func (s *CreatorService) DiscardFiles(ctx context.Context, params *CreateParams) {
+	log := logger.WithMethod(s.log, "DiscardFiles")
+	for _, path := range params.storedPaths {
+		if err := s.fileRepo.Delete(ctx, path); err != nil {
+			log.Error("failed to delete stored file", zap.Error(err), zap.String("file", path))
+		}
+	}
+	params.storedPaths = nil
+}
+
+// checkPeriodUnlocked check the period of the new job is not locked by an
+// approved job of the same bank
+func (s *CreatorService) checkPeriodUnlocked(ctx context.Context, bankNames []string, startDate, endDate time.Time) error {
+	log := logger.WithMethod(s.log, "checkPeriodUnlocked")
+	err := checkPeriodUnlocked(ctx, s.repo.ListOverlappingReconciliationPeriodLocks, bankNames, startDate, endDate)
+	if err != nil && !errors.Is(err, ErrPeriodLocked) {
+		log.Error("failed to list overlapping reconciliation period locks", zap.Error(err))
+	}
+
+	return err
+}
+
+func (p *RerunParams) convertParamsToDB(parent dbgen.ReconciliationJob) dbgen.CreateReconciliationJobParams {
+	res := dbgen.CreateReconciliationJobParams{
+		SystemTransactionCsvPath: parent.SystemTransactionCsvPath,
+		BankTransactionCsvPaths:  parent.BankTransactionCsvPaths,
+		DiscrepancyThreshold:     parent.DiscrepancyThreshold,
+		StartDate:                parent.StartDate,
+		EndDate:                  parent.EndDate,
+		ParentJobID:              sql.NullInt64{Int64: parent.ID, Valid: true},
+		MatchingOptions:          parent.MatchingOptions,
+	}
+	if p.StartDate != nil {
+		res.StartDate = *p.StartDate
+	}
+	if p.EndDate != nil {
+		res.EndDate = *p.EndDate
+	}
+	if p.DiscrepancyThreshold != nil {
+		res.DiscrepancyThreshold = float64(*p.DiscrepancyThreshold)
+	}
+	if p.MatchingOptions != nil {
+		res.MatchingOptions.Set(p.MatchingOptions)
+	}
+
+	return res
+}
+
+func (p *CreateParams) bankNames() []string {
+	res := []string{}
+	for _, bankFile := range p.BankTransactionCsvs {
+		res = append(res, bankFile.BankName)
+	}
+
+	return res
+}
+
 func (p *CreateParams) convertParamsToDB() dbgen.CreateReconciliationJobParams {
 	res := dbgen.CreateReconciliationJobParams{
 		SystemTransactionCsvPath: p.SystemTransactionCsv.Path,
@@ -115,6 +276,7 @@ func (p *CreateParams) convertParamsToDB() dbgen.CreateReconciliationJobParams {
 		EndDate:                  p.EndDate,
 	}
 	res.BankTransactionCsvPaths.Set(p.convertBankTransactionFilesToEntity())
+	res.MatchingOptions.Set(p.MatchingOptions)
 
 	return res
 }
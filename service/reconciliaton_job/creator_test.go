@@ -2,13 +2,16 @@ package reconciliatonjob_test
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/delly/amartha/entity"
+	filestorage "github.com/delly/amartha/repository/file_storage"
 	dbgen "github.com/delly/amartha/repository/postgresql"
 	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
 	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -32,27 +35,41 @@ func TestReconciliationJobCreatorTestSuite(t *testing.T) {
 	suite.Run(t, new(ReconciliationJobCreatorTestSuite))
 }
 
+// inUnlockedPeriod wrap the params of a job created for BCA only
+func inUnlockedPeriod(params dbgen.CreateReconciliationJobParams) dbgen.CreateReconciliationJobInUnlockedPeriodParams {
+	return dbgen.CreateReconciliationJobInUnlockedPeriodParams{
+		CreateReconciliationJobParams: params,
+		BankNames:                     []string{"BCA"},
+	}
+}
+
 func (s *ReconciliationJobCreatorTestSuite) TestCreate() {
 	ctx := context.Background()
 
 	systemTrxPath := "/path/to/system/transaction.csv"
 	bcaTrxPath := "/path/to/bca/transaction.csv"
-	params := &reconciliatonjob.CreateParams{
-		SystemTransactionCsv: &reconciliatonjob.File{
-			Name: "system_transaction.csv",
-		},
-		DiscrepancyThreshold: 0.1,
-		StartDate:            time.Now(),
-		EndDate:              time.Now(),
-		BankTransactionCsvs: []*reconciliatonjob.BankTransactionFile{
-			{
-				BankName: "BCA",
-				File: &reconciliatonjob.File{
-					Name: "bca_transaction.csv",
+	today := time.Now()
+	// newParams create new params on every call since the stored files are
+	// not stored again
+	newParams := func() *reconciliatonjob.CreateParams {
+		return &reconciliatonjob.CreateParams{
+			SystemTransactionCsv: &reconciliatonjob.File{
+				Name: "system_transaction.csv",
+			},
+			DiscrepancyThreshold: 0.1,
+			StartDate:            today,
+			EndDate:              today,
+			BankTransactionCsvs: []*reconciliatonjob.BankTransactionFile{
+				{
+					BankName: "BCA",
+					File: &reconciliatonjob.File{
+						Name: "bca_transaction.csv",
+					},
 				},
 			},
-		},
+		}
 	}
+	params := newParams()
 	bankTrxCsvPaths := []entity.BankTransactionCsv{
 		{
 			BankName: params.BankTransactionCsvs[0].BankName,
@@ -66,6 +83,9 @@ func (s *ReconciliationJobCreatorTestSuite) TestCreate() {
 		EndDate:                  params.EndDate,
 	}
 	dbParams.BankTransactionCsvPaths.Set(bankTrxCsvPaths)
+	dbParams.MatchingOptions.Set(entity.MatchingOptions{
+		DuplicateRule: entity.DuplicateRuleID,
+	})
 	dbResult := dbgen.ReconciliationJob{
 		ID:                       1,
 		Status:                   "PENDING",
@@ -74,6 +94,7 @@ func (s *ReconciliationJobCreatorTestSuite) TestCreate() {
 		DiscrepancyThreshold:     dbParams.DiscrepancyThreshold,
 		StartDate:                dbParams.StartDate,
 		EndDate:                  dbParams.EndDate,
+		MatchingOptions:          dbParams.MatchingOptions,
 		CreatedAt:                now,
 		UpdatedAt:                now,
 	}
@@ -83,50 +104,474 @@ func (s *ReconciliationJobCreatorTestSuite) TestCreate() {
 		SystemTransactionCsvPath: systemTrxPath,
 		BankTransactionCsvPaths:  bankTrxCsvPaths,
 		DiscrepancyThreshold:     float32(dbResult.DiscrepancyThreshold),
-		StartDate:                dbResult.StartDate,
-		EndDate:                  dbResult.EndDate,
-		CreatedAt:                dbResult.CreatedAt,
-		UpdatedAt:                dbResult.UpdatedAt,
+		MatchingOptions: entity.MatchingOptions{
+			DuplicateRule: entity.DuplicateRuleID,
+		},
+		StartDate: dbResult.StartDate,
+		EndDate:   dbResult.EndDate,
+		CreatedAt: dbResult.CreatedAt,
+		UpdatedAt: dbResult.UpdatedAt,
 	}
 
 	s.Run("success", func() {
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
 		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(systemTrxPath, nil)
 		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(bcaTrxPath, nil)
-		s.mockRepo.EXPECT().CreateReconciliationJob(ctx, dbParams).Return(dbResult, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).Return(dbResult, nil)
 
-		res, err := s.svc.Create(ctx, params)
+		res, err := s.svc.Create(ctx, newParams())
 
 		s.Nil(err)
 		s.Equal(jrResult, res)
 	})
 
+	s.Run("error invalid matching options", func() {
+		params := *newParams()
+		params.MatchingOptions = entity.MatchingOptions{
+			DuplicateRule: "UNKNOWN",
+		}
+
+		res, err := s.svc.Create(ctx, &params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidMatchingOptions)
+	})
+
+	s.Run("error negative reversal window", func() {
+		params := *newParams()
+		params.MatchingOptions = entity.MatchingOptions{
+			PairReversals:      true,
+			ReversalWindowDays: -1,
+		}
+
+		res, err := s.svc.Create(ctx, &params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidMatchingOptions)
+	})
+
+	s.Run("error fee rule of unknown bank", func() {
+		params := *newParams()
+		params.MatchingOptions = entity.MatchingOptions{
+			FeeRules: map[string]entity.FeeRule{
+				"BRI": {Type: entity.FeeTypeFixed, Fixed: 2500},
+			},
+		}
+
+		res, err := s.svc.Create(ctx, &params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidMatchingOptions)
+	})
+
+	s.Run("success fee rule of bank name in another case", func() {
+		params := *newParams()
+		params.MatchingOptions = entity.MatchingOptions{
+			FeeRules: map[string]entity.FeeRule{
+				" bca": {Type: entity.FeeTypeFixed, Fixed: 2500},
+			},
+		}
+		dbParams := dbParams
+		dbParams.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule: entity.DuplicateRuleID,
+			FeeRules: map[string]entity.FeeRule{
+				"BCA": {Type: entity.FeeTypeFixed, Fixed: 2500},
+			},
+		})
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(systemTrxPath, nil)
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(bcaTrxPath, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).Return(dbResult, nil)
+
+		_, err := s.svc.Create(ctx, &params)
+
+		s.Nil(err)
+	})
+
+	s.Run("error fee rule of the same bank in another case", func() {
+		params := *newParams()
+		params.MatchingOptions = entity.MatchingOptions{
+			FeeRules: map[string]entity.FeeRule{
+				"BCA": {Type: entity.FeeTypeFixed, Fixed: 2500},
+				"bca": {Type: entity.FeeTypeFixed, Fixed: 1000},
+			},
+		}
+
+		res, err := s.svc.Create(ctx, &params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidMatchingOptions)
+	})
+
+	s.Run("error invalid fee rule", func() {
+		params := *newParams()
+		params.MatchingOptions = entity.MatchingOptions{
+			FeeRules: map[string]entity.FeeRule{
+				"BCA": {Type: entity.FeeTypeTiered},
+			},
+		}
+
+		res, err := s.svc.Create(ctx, &params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidMatchingOptions)
+	})
+
+	s.Run("error period locked", func() {
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, dbgen.ListOverlappingReconciliationPeriodLocksParams{
+			BankNames: []string{"BCA"},
+			StartDate: params.StartDate,
+			EndDate:   params.EndDate,
+		}).Return([]dbgen.ReconciliationPeriodLock{dbPeriodLock}, nil)
+
+		res, err := s.svc.Create(ctx, newParams())
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+	})
+
+	s.Run("error list period locks", func() {
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+		res, err := s.svc.Create(ctx, newParams())
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+
 	s.Run("error store system transaction csv", func() {
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
 		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return("", assert.AnError)
 
-		res, err := s.svc.Create(ctx, params)
+		res, err := s.svc.Create(ctx, newParams())
 
 		s.Nil(res)
 		s.Equal(assert.AnError, err)
 	})
 
 	s.Run("error store bank transaction csv", func() {
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
 		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(systemTrxPath, nil)
 		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return("", assert.AnError)
 
-		res, err := s.svc.Create(ctx, params)
+		res, err := s.svc.Create(ctx, newParams())
 
 		s.Nil(res)
 		s.Equal(assert.AnError, err)
 	})
 
 	s.Run("error create recon", func() {
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(systemTrxPath, nil)
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(bcaTrxPath, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		res, err := s.svc.Create(ctx, newParams())
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+
+	s.Run("error period locked on create", func() {
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
 		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(systemTrxPath, nil)
 		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(bcaTrxPath, nil)
-		s.mockRepo.EXPECT().CreateReconciliationJob(ctx, dbParams).Return(dbgen.ReconciliationJob{}, assert.AnError)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).
+			Return(dbgen.ReconciliationJob{}, &dbgen.PeriodLockedError{Lock: dbPeriodLock})
+
+		res, err := s.svc.Create(ctx, newParams())
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+	})
+
+	s.Run("success skip files already stored", func() {
+		params := newParams()
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(systemTrxPath, nil)
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return(bcaTrxPath, nil)
+		s.Require().NoError(s.svc.StoreFile(ctx, params, params.SystemTransactionCsv))
+		s.Require().NoError(s.svc.StoreFile(ctx, params, params.BankTransactionCsvs[0].File))
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).Return(dbResult, nil)
 
 		res, err := s.svc.Create(ctx, params)
 
+		s.Nil(err)
+		s.Equal(jrResult, res)
+	})
+}
+
+func (s *ReconciliationJobCreatorTestSuite) TestRerun() {
+	ctx := context.Background()
+
+	parent := dbReconJob
+	startDate := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	threshold := float32(0.5)
+	dbParams := dbgen.CreateReconciliationJobParams{
+		SystemTransactionCsvPath: parent.SystemTransactionCsvPath,
+		BankTransactionCsvPaths:  parent.BankTransactionCsvPaths,
+		DiscrepancyThreshold:     float64(threshold),
+		StartDate:                startDate,
+		EndDate:                  endDate,
+		ParentJobID:              sql.NullInt64{Int64: parent.ID, Valid: true},
+	}
+	dbResult := dbgen.ReconciliationJob{
+		ID:                       2,
+		Status:                   "PENDING",
+		SystemTransactionCsvPath: dbParams.SystemTransactionCsvPath,
+		BankTransactionCsvPaths:  dbParams.BankTransactionCsvPaths,
+		DiscrepancyThreshold:     dbParams.DiscrepancyThreshold,
+		StartDate:                dbParams.StartDate,
+		EndDate:                  dbParams.EndDate,
+		ParentJobID:              dbParams.ParentJobID,
+		CreatedAt:                now,
+		UpdatedAt:                now,
+	}
+
+	s.Run("success with overridden params", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID:                parent.ID,
+			StartDate:            &startDate,
+			EndDate:              &endDate,
+			DiscrepancyThreshold: &threshold,
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).Return(dbResult, nil)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.NoError(err)
+		s.Equal(dbResult.ID, res.ID)
+		s.Equal(&parent.ID, res.ParentJobID)
+		s.Equal(entityReconJob.BankTransactionCsvPaths, res.BankTransactionCsvPaths)
+	})
+
+	s.Run("success with original params", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID: parent.ID,
+		}
+		originalParams := dbgen.CreateReconciliationJobParams{
+			SystemTransactionCsvPath: parent.SystemTransactionCsvPath,
+			BankTransactionCsvPaths:  parent.BankTransactionCsvPaths,
+			DiscrepancyThreshold:     parent.DiscrepancyThreshold,
+			StartDate:                parent.StartDate,
+			EndDate:                  parent.EndDate,
+			ParentJobID:              sql.NullInt64{Int64: parent.ID, Valid: true},
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(originalParams)).Return(dbResult, nil)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.NoError(err)
+		s.NotNil(res)
+	})
+
+	s.Run("success with overridden matching options", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID: parent.ID,
+			MatchingOptions: &entity.MatchingOptions{
+				DuplicateRule: entity.DuplicateRuleFingerprint,
+				Deduplicate:   true,
+			},
+		}
+		overriddenParams := dbgen.CreateReconciliationJobParams{
+			SystemTransactionCsvPath: parent.SystemTransactionCsvPath,
+			BankTransactionCsvPaths:  parent.BankTransactionCsvPaths,
+			DiscrepancyThreshold:     parent.DiscrepancyThreshold,
+			StartDate:                parent.StartDate,
+			EndDate:                  parent.EndDate,
+			ParentJobID:              sql.NullInt64{Int64: parent.ID, Valid: true},
+		}
+		overriddenParams.MatchingOptions.Set(params.MatchingOptions)
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(overriddenParams)).Return(dbResult, nil)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.NoError(err)
+		s.NotNil(res)
+	})
+
+	s.Run("error invalid matching options", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID: parent.ID,
+			MatchingOptions: &entity.MatchingOptions{
+				DuplicateRule: "UNKNOWN",
+			},
+		}
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidMatchingOptions)
+	})
+
+	s.Run("error fee rule of unknown bank", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID: parent.ID,
+			MatchingOptions: &entity.MatchingOptions{
+				FeeRules: map[string]entity.FeeRule{
+					"BRI": {Type: entity.FeeTypePercentage, Rate: 0.007},
+				},
+			},
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidMatchingOptions)
+	})
+
+	s.Run("error original job not found", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID: parent.ID,
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(dbgen.ReconciliationJob{}, pgx.ErrNoRows)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotFound)
+	})
+
+	s.Run("error get original job", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID: parent.ID,
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		res, err := s.svc.Rerun(ctx, params)
+
 		s.Nil(res)
 		s.Equal(assert.AnError, err)
 	})
+
+	s.Run("error invalid date range", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID:     parent.ID,
+			StartDate: &endDate,
+			EndDate:   &startDate,
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrInvalidDateRange)
+	})
+
+	s.Run("error period locked", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID:     parent.ID,
+			StartDate: &startDate,
+			EndDate:   &endDate,
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, dbgen.ListOverlappingReconciliationPeriodLocksParams{
+			BankNames: []string{"BCA"},
+			StartDate: startDate,
+			EndDate:   endDate,
+		}).Return([]dbgen.ReconciliationPeriodLock{dbPeriodLock}, nil)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+	})
+
+	s.Run("error period locked on create", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID:                parent.ID,
+			StartDate:            &startDate,
+			EndDate:              &endDate,
+			DiscrepancyThreshold: &threshold,
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).
+			Return(dbgen.ReconciliationJob{}, &dbgen.PeriodLockedError{})
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.Nil(res)
+		s.ErrorIs(err, reconciliatonjob.ErrPeriodLocked)
+	})
+
+	s.Run("error create recon", func() {
+		params := &reconciliatonjob.RerunParams{
+			JobID:                parent.ID,
+			StartDate:            &startDate,
+			EndDate:              &endDate,
+			DiscrepancyThreshold: &threshold,
+		}
+		s.mockRepo.EXPECT().GetReconciliationJobById(ctx, parent.ID).Return(parent, nil)
+		s.mockRepo.EXPECT().ListOverlappingReconciliationPeriodLocks(ctx, gomock.Any()).Return(nil, nil)
+		s.mockRepo.EXPECT().CreateReconciliationJobInUnlockedPeriod(ctx, inUnlockedPeriod(dbParams)).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		res, err := s.svc.Rerun(ctx, params)
+
+		s.Nil(res)
+		s.Equal(assert.AnError, err)
+	})
+}
+
+func (s *ReconciliationJobCreatorTestSuite) TestStoreFile() {
+	ctx := context.Background()
+
+	s.Run("success store files of the same name under unique names", func() {
+		params := &reconciliatonjob.CreateParams{}
+		first := &reconciliatonjob.File{Name: "trx.csv"}
+		second := &reconciliatonjob.File{Name: "trx.csv"}
+		var names []string
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).DoAndReturn(
+			func(_ context.Context, file *filestorage.File) (string, error) {
+				names = append(names, file.Name)
+				return file.Dir + "/" + file.Name, nil
+			}).Times(2)
+
+		s.Require().NoError(s.svc.StoreFile(ctx, params, first))
+		s.Require().NoError(s.svc.StoreFile(ctx, params, second))
+
+		s.Equal([]string{"0_trx.csv", "1_trx.csv"}, names)
+		s.NotEqual(first.Path, second.Path)
+	})
+
+	s.Run("error store file", func() {
+		params := &reconciliatonjob.CreateParams{}
+		file := &reconciliatonjob.File{Name: "trx.csv"}
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return("", assert.AnError)
+
+		err := s.svc.StoreFile(ctx, params, file)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Empty(file.Path)
+	})
+}
+
+func (s *ReconciliationJobCreatorTestSuite) TestDiscardFiles() {
+	ctx := context.Background()
+
+	s.Run("success delete stored files", func() {
+		params := &reconciliatonjob.CreateParams{}
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return("dir/0_system.csv", nil)
+		s.mockFileStorer.EXPECT().Store(ctx, gomock.Any()).Return("dir/1_bca.csv", nil)
+		s.Require().NoError(s.svc.StoreFile(ctx, params, &reconciliatonjob.File{Name: "system.csv"}))
+		s.Require().NoError(s.svc.StoreFile(ctx, params, &reconciliatonjob.File{Name: "bca.csv"}))
+		s.mockFileStorer.EXPECT().Delete(ctx, "dir/0_system.csv").Return(assert.AnError)
+		s.mockFileStorer.EXPECT().Delete(ctx, "dir/1_bca.csv").Return(nil)
+
+		s.svc.DiscardFiles(ctx, params)
+		// the files are deleted once
+		s.svc.DiscardFiles(ctx, params)
+	})
+
+	s.Run("success nothing stored", func() {
+		s.svc.DiscardFiles(ctx, &reconciliatonjob.CreateParams{})
+	})
 }
@@ -0,0 +1,205 @@
+package reconciliatonjob
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delly/amartha/entity"
+)
+
+// fileRow is the position of a transaction in the files of a job, source is
+// systemSource for the system file and the bank file index for a bank file
+type fileRow struct {
+	source int
+	row    int
+}
+
+// keyEntry is a transaction of a file along with its duplicate key
+type keyEntry struct {
+	fileRow
+	trx *entity.Transaction
+}
+
+// keyIndex collect the duplicate key of every transaction while the files are
+// read, the transactions sharing the same key are only grouped once every
+// file is read, so the duplicates and the overlaps are found in a single pass
+type keyIndex interface {
+	add(source, row int, trx *entity.Transaction) error
+	// forEachGroup call fn for every group of transactions sharing the same key
+	forEachGroup(ctx context.Context, fn func([]keyEntry) error) error
+	close() error
+}
+
+// memoryKeyIndex keep the key of every transaction in memory
+type memoryKeyIndex struct {
+	rule   entity.DuplicateRule
+	groups map[string][]keyEntry
+}
+
+func newMemoryKeyIndex(rule entity.DuplicateRule) *memoryKeyIndex {
+	return &memoryKeyIndex{
+		rule:   rule,
+		groups: map[string][]keyEntry{},
+	}
+}
+
+func (m *memoryKeyIndex) add(source, row int, trx *entity.Transaction) error {
+	key := transactionKey(m.rule, trx)
+	if key == "" {
+		return nil
+	}
+	m.groups[key] = append(m.groups[key], keyEntry{fileRow: fileRow{source: source, row: row}, trx: trx})
+
+	return nil
+}
+
+func (m *memoryKeyIndex) forEachGroup(ctx context.Context, fn func([]keyEntry) error) error {
+	for _, entries := range m.groups {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(entries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *memoryKeyIndex) close() error {
+	m.groups = nil
+	return nil
+}
+
+// diskKeyIndex spill the key of every transaction into temporary bucket files
+// keyed by the hash of the key, so only the keys of a single bucket are loaded
+// into memory while grouping
+type diskKeyIndex struct {
+	rule    entity.DuplicateRule
+	buckets *spillBuckets
+}
+
+func newDiskKeyIndex(rule entity.DuplicateRule, tempDir, pattern string, totalBuckets int) (*diskKeyIndex, error) {
+	buckets, err := newSpillBuckets(tempDir, pattern, totalBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &diskKeyIndex{
+		rule:    rule,
+		buckets: buckets,
+	}, nil
+}
+
+func (d *diskKeyIndex) add(source, row int, trx *entity.Transaction) error {
+	key := transactionKey(d.rule, trx)
+	if key == "" {
+		return nil
+	}
+
+	return d.buckets.write(key, append([]string{key}, encodeRowTransaction(source, row, trx)...))
+}
+
+func (d *diskKeyIndex) forEachGroup(ctx context.Context, fn func([]keyEntry) error) error {
+	return d.buckets.forEachBucket(ctx, func(r *csv.Reader) error {
+		groups := map[string][]keyEntry{}
+		for {
+			record, err := r.Read()
+			if err != nil {
+				if err == io.EOF {
+					break
+				}
+				return err
+			}
+			source, row, trx, err := decodeRowTransaction(record[1:])
+			if err != nil {
+				return err
+			}
+			groups[record[0]] = append(groups[record[0]], keyEntry{fileRow: fileRow{source: source, row: row}, trx: trx})
+		}
+
+		for _, entries := range groups {
+			if err := fn(entries); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// close remove the temporary bucket files
+func (d *diskKeyIndex) close() error {
+	return d.buckets.close()
+}
+
+// detectDuplicates report every transaction with the same key as an earlier
+// transaction of the same file as duplicate, and every bank transaction with
+// the same key as a transaction of an earlier bank file as overlap, it
+// returns the duplicate rows to be excluded from matching when deduplicate
+// is enabled
+func detectDuplicates(ctx context.Context, job *entity.ReconciliationJob, index keyIndex, collector *resultCollector) (map[fileRow]bool, error) {
+	excluded := map[fileRow]bool{}
+	err := index.forEachGroup(ctx, func(entries []keyEntry) error {
+		if len(entries) < 2 {
+			return nil
+		}
+		slices.SortFunc(entries, func(a, b keyEntry) int {
+			return cmp.Or(cmp.Compare(a.source, b.source), cmp.Compare(a.row, b.row))
+		})
+
+		firstRows := map[int]int{}
+		var firstBank *keyEntry
+		for i, entry := range entries {
+			trx := rowTransaction{row: entry.row, trx: entry.trx}
+			if entry.source != systemSource {
+				if firstBank == nil {
+					firstBank = &entries[i]
+				} else if firstBank.source != entry.source {
+					if err := collector.addOverlap(entry.source, trx, firstBank.fileRow); err != nil {
+						return err
+					}
+				}
+			}
+
+			firstRow, ok := firstRows[entry.source]
+			if !ok {
+				firstRows[entry.source] = entry.row
+				continue
+			}
+			if err := collector.addDuplicate(entry.source, trx, firstRow, job.MatchingOptions.Deduplicate); err != nil {
+				return err
+			}
+			if job.MatchingOptions.Deduplicate {
+				excluded[entry.fileRow] = true
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return excluded, nil
+}
+
+// transactionKey return the key to identify the same transaction based on the rule
+func transactionKey(rule entity.DuplicateRule, trx *entity.Transaction) string {
+	if rule == entity.DuplicateRuleFingerprint {
+		return strings.Join([]string{
+			strconv.FormatFloat(trx.Amount, 'f', -1, 64),
+			trx.Time.Format(time.DateOnly),
+			string(trx.Type),
+		}, "|")
+	}
+
+	// transaction without ID can not be identified by ID rule
+	return trx.ID
+}
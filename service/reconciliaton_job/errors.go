@@ -1,16 +1,116 @@
 package reconciliatonjob
 
 import (
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
 )
 
 var (
-	errEmptyBuffer = func(filename string) error {
-		return fmt.Errorf("file buffer of file %s is empty", filename)
+	// ErrReconciliationJobNotFound is an error when reconciliation job is not found
+	ErrReconciliationJobNotFound = errors.New("reconciliation job not found")
+	// ErrReconciliationJobNotCancellable is an error when reconciliation job is already finished
+	ErrReconciliationJobNotCancellable = errors.New("only pending or processing reconciliation job can be cancelled")
+	// ErrReconciliationJobNotSuccess is an error when reconciliation job has no result to be read
+	ErrReconciliationJobNotSuccess = errors.New("reconciliation job is not success")
+	// ErrInvalidDateRange is an error when start date is after end date
+	ErrInvalidDateRange = errors.New("start date must be before end date")
+	// ErrInvalidMatchingOptions is an error when matching options is not valid
+	ErrInvalidMatchingOptions = errors.New("invalid matching options")
+	// ErrReconciliationItemNotFound is an error when reconciliation item is not found in the job
+	ErrReconciliationItemNotFound = errors.New("reconciliation item not found")
+	// ErrReconciliationItemNotAdjustable is an error when reconciliation item is no longer open
+	ErrReconciliationItemNotAdjustable = errors.New("only missing items that are not adjusted, resolved or carried forward can be adjusted")
+	// ErrInvalidManualMatch is an error when manual match does not pair a system item with a bank item
+	ErrInvalidManualMatch = errors.New("manual match must pair a system item with a bank item")
+	// ErrManualMatchTypeMismatch is an error when manual match pairs items of different transaction type
+	ErrManualMatchTypeMismatch = errors.New("manual match must pair items of the same type")
+	// ErrManualMatchReasonRequired is an error when manual match pairs items whose amounts differ beyond the discrepancy threshold without a reason
+	ErrManualMatchReasonRequired = errors.New("reason_code is required to match items whose amounts differ beyond the discrepancy threshold")
+
+	// ErrReconciliationItemNotOpen is an error when a case is opened on an item that is no longer open
+	ErrReconciliationItemNotOpen = errors.New("only missing items that are not adjusted or resolved can have a case")
+	// ErrReconciliationCaseExists is an error when the item already has a case
+	ErrReconciliationCaseExists = errors.New("reconciliation item already has a case")
+	// ErrReconciliationCaseNotFound is an error when reconciliation case is not found
+	ErrReconciliationCaseNotFound = errors.New("reconciliation case not found")
+	// ErrInvalidCaseStatusTransition is an error when a case cannot be changed to the status
+	ErrInvalidCaseStatusTransition = errors.New("invalid reconciliation case status transition")
+	// ErrCaseCommentRequired is an error when a case is closed without a comment
+	ErrCaseCommentRequired = errors.New("comment is required to resolve or write off a case")
+	// ErrCaseStatusForbidden is an error when the status of a case is changed by neither its assignee nor a reviewer
+	ErrCaseStatusForbidden = errors.New("only the assignee or a reviewer can change the status of a case")
+	// ErrReconciliationJobAwaitingApproval is an error when a job submitted for approval is adjusted
+	ErrReconciliationJobAwaitingApproval = errors.New("reconciliation job is awaiting approval")
+	// ErrInvalidApprovalTransition is an error when a job is not in the approval status the action requires
+	ErrInvalidApprovalTransition = errors.New("invalid reconciliation job approval transition")
+	// ErrSelfApproval is an error when a job is approved or rejected by the user who submitted it
+	ErrSelfApproval = errors.New("reconciliation job must be approved or rejected by a different user than the preparer")
+	// ErrApprovalCommentRequired is an error when a job is rejected without a comment
+	ErrApprovalCommentRequired = errors.New("comment is required to reject a reconciliation job")
+	// ErrPeriodLocked is an error when a period of a bank is locked by an approved job
+	ErrPeriodLocked = errors.New("reconciliation period is locked")
+	// ErrPeriodLockNotFound is an error when period lock is not found
+	ErrPeriodLockNotFound = errors.New("reconciliation period lock not found")
+	// ErrPeriodLockNotActive is an error when period lock is already unlocked
+	ErrPeriodLockNotActive = errors.New("reconciliation period lock is already unlocked")
+	// ErrUnlockReasonRequired is an error when a period is unlocked without a reason
+	ErrUnlockReasonRequired = errors.New("reason is required to unlock a reconciliation period")
+
+	errJobCancelled         = errors.New("reconciliation job cancelled")
+	errJobLeaseLost         = errors.New("reconciliation job is no longer processing by this worker")
+	errJobTimedOut          = errors.New("reconciliation job exceeded processing timeout")
+	errIterationStopped     = errors.New("iteration stopped")
+	errInvalidDuplicateRule = func(rule entity.DuplicateRule) error {
+		return fmt.Errorf("%w: duplicate rule %s is not supported", ErrInvalidMatchingOptions, rule)
+	}
+	errInvalidReversalWindow = func(days int) error {
+		return fmt.Errorf("%w: reversal window days %d must not be negative", ErrInvalidMatchingOptions, days)
+	}
+	errInvalidFeeType = func(bankName string, feeType entity.FeeType) error {
+		return fmt.Errorf("%w: fee type %s of bank %s is not supported", ErrInvalidMatchingOptions, feeType, bankName)
+	}
+	errInvalidFeeRule = func(bankName string) error {
+		return fmt.Errorf("%w: fee rule of bank %s must have non negative fees, rates less than 1 and at least a tier for tiered fee", ErrInvalidMatchingOptions, bankName)
+	}
+	errDuplicateFeeRuleBank = func(bankName string) error {
+		return fmt.Errorf("%w: fee rule of bank %s is specified more than once", ErrInvalidMatchingOptions, bankName)
+	}
+	errUnknownFeeRuleBank = func(bankName string) error {
+		return fmt.Errorf("%w: fee rule bank %s is not found in the bank transaction files", ErrInvalidMatchingOptions, bankName)
+	}
+	errInvalidMinConfidence = func(score float64) error {
+		return fmt.Errorf("%w: min confidence %g must be between 0 and 1", ErrInvalidMatchingOptions, score)
+	}
+	errUnsupportedExportFormat = func(format ExportFormat) error {
+		return fmt.Errorf("export format %s is not supported", format)
 	}
 	errInvalidTrxType = func(trxType entity.TransactionType, trxID string) error {
 		return fmt.Errorf("invalid transaction type: %s, trx id: %s", trxType, trxID)
 	}
+	errClaimJob = func(jobID int64, err error) error {
+		return fmt.Errorf("failed to claim reconciliation job %d: %w", jobID, err)
+	}
+	errJobTimeout = func(timeout time.Duration) error {
+		return fmt.Errorf("%w of %s", errJobTimedOut, timeout)
+	}
+	errCaseStatusTransition = func(from, to entity.ReconciliationCaseStatus) error {
+		return fmt.Errorf("%w: case cannot be changed from %s to %s", ErrInvalidCaseStatusTransition, from, to)
+	}
+	errApprovalTransition = func(status entity.ReconciliationApprovalStatus, action string) error {
+		if status == "" {
+			status = "NOT_SUBMITTED"
+		}
+		return fmt.Errorf("%w: job with approval status %s cannot be %s", ErrInvalidApprovalTransition, status, action)
+	}
+	errPeriodLocked = func(lock dbgen.ReconciliationPeriodLock) error {
+		return fmt.Errorf("%w: bank %s is locked from %s to %s by job %d, unlock period lock %d with a reason first",
+			ErrPeriodLocked, lock.BankName, lock.StartDate.Format(time.DateOnly), lock.EndDate.Format(time.DateOnly), lock.JobID, lock.ID)
+	}
+	errSaveJob = func(jobID int64, err error) error {
+		return fmt.Errorf("failed to save reconciliation job %d: %w", jobID, err)
+	}
 )
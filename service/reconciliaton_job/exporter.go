@@ -0,0 +1,240 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"strings"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+const (
+	// exportBatchSize is the number of rows fetched at a time while streaming the export
+	exportBatchSize  = 1000
+	exportDateFormat = "2006-01-02"
+	// exportLegacyNotice is written in the summary of a job saved before its
+	// items were stored, its transactions are only listed in the job result
+	exportLegacyNotice = "The job was saved before its transactions were stored, the sections below are empty, " +
+		"the transactions are listed in the result of Get Reconciliation Job Request by ID"
+)
+
+// ExportFormat is a custom type for the file format of the exported result
+type ExportFormat string
+
+const (
+	// ExportFormatCSV export the result as a single CSV with a section per table
+	ExportFormatCSV ExportFormat = "csv"
+	// ExportFormatXLSX export the result as a spreadsheet with a sheet per table
+	ExportFormatXLSX ExportFormat = "xlsx"
+)
+
+// Exporter is a contract to export the result of reconciliation job
+type Exporter interface {
+	Export(ctx context.Context, job *entity.ReconciliationJob, format ExportFormat, w io.Writer) error
+}
+
+// ExporterRepository is a contract to read the result of reconciliation job to be exported
+type ExporterRepository interface {
+	ExistsReconciliationItems(ctx context.Context, jobID int64) (bool, error)
+	ListMissingReconciliationItems(ctx context.Context, arg dbgen.ListMissingReconciliationItemsParams) ([]dbgen.ReconciliationItem, error)
+	ListReconciliationMatchedPairs(ctx context.Context, arg dbgen.ListReconciliationMatchedPairsParams) ([]dbgen.ListReconciliationMatchedPairsRow, error)
+}
+
+// ExporterService is a service to export the result of reconciliation job
+type ExporterService struct {
+	repo ExporterRepository
+	log  *zap.Logger
+}
+
+var _ = Exporter(&ExporterService{})
+
+// NewExporterService create new exporter service
+func NewExporterService(repo ExporterRepository) *ExporterService {
+	return &ExporterService{
+		repo: repo,
+		log:  zap.L().With(zap.String("service", "reconciliation_job.exporter")),
+	}
+}
+
+// Export write the summary, the matched pairs, the missing system transactions
+// and the missing bank transactions of a successful job, the rows are fetched
+// in batches and streamed to the writer, nothing is written when the job is
+// not successful or the format is not supported
+func (s *ExporterService) Export(ctx context.Context, job *entity.ReconciliationJob, format ExportFormat, w io.Writer) error {
+	log := logger.WithMethod(s.log, "Export")
+	if job.Status != entity.ReconciliationJobStatusSuccess || job.Result == nil {
+		return ErrReconciliationJobNotSuccess
+	}
+
+	var table tableWriter
+	switch format {
+	case ExportFormatCSV:
+		table = newCSVTableWriter(w)
+	case ExportFormatXLSX:
+		table = newXLSXTableWriter(w)
+	default:
+		return errUnsupportedExportFormat(format)
+	}
+
+	for _, write := range []func(context.Context, *entity.ReconciliationJob, tableWriter) error{
+		s.writeSummary,
+		s.writeMatchedPairs,
+		s.writeMissingSystemTransactions,
+		s.writeMissingBankTransactions,
+	} {
+		if err := write(ctx, job, table); err != nil {
+			log.Error("failed to export reconciliation job", zap.Error(err), zap.Int64("id", job.ID))
+			return err
+		}
+	}
+	if err := table.close(); err != nil {
+		log.Error("failed to close export", zap.Error(err), zap.Int64("id", job.ID))
+		return err
+	}
+
+	return nil
+}
+
+// writeSummary write the totals after the manual adjustments once the job is
+// adjusted, the content hash is still the hash of the result given by the job
+func (s *ExporterService) writeSummary(ctx context.Context, job *entity.ReconciliationJob, table tableWriter) error {
+	bankNames := make([]string, 0, len(job.BankTransactionCsvPaths))
+	for _, bank := range job.BankTransactionCsvPaths {
+		bankNames = append(bankNames, bank.BankName)
+	}
+	result := job.Result
+	matched, unmatched, discrepancy := result.TotalTransactionMatched, result.TotalTransactionUnmatched, result.TotalDiscrepancyAmount
+	if adjusted := job.AdjustedTotals; adjusted != nil {
+		matched, unmatched, discrepancy = adjusted.TotalTransactionMatched, adjusted.TotalTransactionUnmatched, adjusted.TotalDiscrepancyAmount
+	}
+	rows := [][]any{
+		{"Job ID", job.ID},
+		{"Start Date", job.StartDate.Format(exportDateFormat)},
+		{"End Date", job.EndDate.Format(exportDateFormat)},
+		{"Banks", strings.Join(bankNames, ", ")},
+		{"Total Transaction Processed", result.TotalTransactionProcessed},
+		{"Total Transaction Matched", matched},
+		{"Total Transaction Unmatched", unmatched},
+		{"Total Transaction Needs Review", result.TotalTransactionNeedsReview},
+		{"Total Transaction Reversed", result.TotalTransactionReversed},
+		{"Total Discrepancy Amount", discrepancy},
+	}
+	if adjusted := job.AdjustedTotals; adjusted != nil {
+		rows = append(rows,
+			[]any{"Total Transaction Manually Matched", adjusted.TotalTransactionManuallyMatched},
+			[]any{"Total Transaction Resolved", adjusted.TotalTransactionResolved},
+		)
+	}
+	rows = append(rows, []any{"Content Hash", result.ContentHash})
+	hasItems, err := s.repo.ExistsReconciliationItems(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	if !hasItems {
+		rows = append(rows, []any{"Notice", exportLegacyNotice})
+	}
+	if err := table.section("Summary", "Field", "Value"); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := table.row(row...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *ExporterService) writeMatchedPairs(ctx context.Context, job *entity.ReconciliationJob, table tableWriter) error {
+	if err := table.section("Matched Transactions",
+		"Status", "Confidence",
+		"System Row", "System Transaction ID", "System Amount", "System Type", "System Time",
+		"Bank Name", "Bank Row", "Bank Transaction ID", "Bank Amount", "Bank Type", "Bank Time",
+		"Expected Fee", "Implied Fee"); err != nil {
+		return err
+	}
+
+	params := dbgen.ListReconciliationMatchedPairsParams{JobID: job.ID, Limit: exportBatchSize}
+	for {
+		pairs, err := s.repo.ListReconciliationMatchedPairs(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, pair := range pairs {
+			if err := table.row(pair.Status, pair.Confidence,
+				pair.SystemRow, pair.SystemTransactionID, pair.SystemAmount, pair.SystemType, pair.SystemTransactionTime,
+				pair.BankName, pair.BankRow, pair.BankTransactionID, pair.BankAmount, pair.BankType, pair.BankTransactionTime,
+				pair.ExpectedFee, pair.ImpliedFee); err != nil {
+				return err
+			}
+		}
+		if len(pairs) < exportBatchSize {
+			return nil
+		}
+		params.ID = pairs[len(pairs)-1].ID
+	}
+}
+
+func (s *ExporterService) writeMissingSystemTransactions(ctx context.Context, job *entity.ReconciliationJob, table tableWriter) error {
+	if err := table.section("Missing System Transactions",
+		"Row", "Transaction ID", "Amount", "Type", "Time", "Adjustment ID", "Resolved Job ID"); err != nil {
+		return err
+	}
+
+	return eachMissingItem(ctx, s.repo.ListMissingReconciliationItems, job.ID, entity.ReconciliationItemSourceSystem, func(item dbgen.ReconciliationItem) error {
+		return table.row(item.RowNumber, item.TransactionID, item.Amount, item.Type, item.TransactionTime,
+			nullInt64Value(item.AdjustmentID), nullInt64Value(item.ResolvedJobID))
+	})
+}
+
+func (s *ExporterService) writeMissingBankTransactions(ctx context.Context, job *entity.ReconciliationJob, table tableWriter) error {
+	if err := table.section("Missing Bank Transactions",
+		"Bank Name", "Row", "Transaction ID", "Amount", "Type", "Time", "Adjustment ID", "Resolved Job ID"); err != nil {
+		return err
+	}
+
+	return eachMissingItem(ctx, s.repo.ListMissingReconciliationItems, job.ID, entity.ReconciliationItemSourceBank, func(item dbgen.ReconciliationItem) error {
+		return table.row(item.BankName.String, item.RowNumber, item.TransactionID, item.Amount, item.Type, item.TransactionTime,
+			nullInt64Value(item.AdjustmentID), nullInt64Value(item.ResolvedJobID))
+	})
+}
+
+// nullInt64Value return the value to be written for a nullable column, a
+// missing item is adjusted or resolved by a later job when the column is set
+func nullInt64Value(v sql.NullInt64) any {
+	if !v.Valid {
+		return nil
+	}
+
+	return v.Int64
+}
+
+// eachMissingItem call fn for every missing item of the source listed in batches
+func eachMissingItem(ctx context.Context,
+	list func(context.Context, dbgen.ListMissingReconciliationItemsParams) ([]dbgen.ReconciliationItem, error),
+	jobID int64, source entity.ReconciliationItemSource, fn func(item dbgen.ReconciliationItem) error) error {
+	params := dbgen.ListMissingReconciliationItemsParams{
+		JobID:  jobID,
+		Source: string(source),
+		Limit:  exportBatchSize,
+	}
+	for {
+		items, err := list(ctx, params)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+		if len(items) < exportBatchSize {
+			return nil
+		}
+		params.ID = items[len(items)-1].ID
+	}
+}
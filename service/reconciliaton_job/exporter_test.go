@@ -0,0 +1,301 @@
+package reconciliatonjob_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ExporterTestSuite struct {
+	suite.Suite
+	repo *mock_reconciliatonjob.MockExporterRepository
+	svc  *reconciliatonjob.ExporterService
+}
+
+func (s *ExporterTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_reconciliatonjob.NewMockExporterRepository(ctrl)
+	s.svc = reconciliatonjob.NewExporterService(s.repo)
+}
+
+func TestExporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ExporterTestSuite))
+}
+
+func (s *ExporterTestSuite) TestExport() {
+	ctx := context.Background()
+	trxTime := time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC)
+	job := &entity.ReconciliationJob{
+		ID:        id,
+		Status:    entity.ReconciliationJobStatusSuccess,
+		StartDate: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC),
+		BankTransactionCsvPaths: []entity.BankTransactionCsv{
+			{BankName: "BCA", FilePath: "path_to_file_bca"},
+			{BankName: "BRI", FilePath: "path_to_file_bri"},
+		},
+		Result: &entity.ReconciliationResult{
+			TotalTransactionProcessed: 3,
+			TotalTransactionMatched:   2,
+			TotalTransactionUnmatched: 1,
+			TotalDiscrepancyAmount:    1500.5,
+			ContentHash:               "hash",
+		},
+	}
+	pair := dbgen.ListReconciliationMatchedPairsRow{
+		ID:                    1,
+		BankName:              "BCA",
+		Status:                "MATCHED",
+		Confidence:            1,
+		SystemRow:             1,
+		SystemTransactionID:   "TRX-1",
+		SystemAmount:          1000,
+		SystemType:            "CREDIT",
+		SystemTransactionTime: trxTime,
+		BankRow:               2,
+		BankTransactionID:     "BCA-1",
+		BankAmount:            1000,
+		BankType:              "CREDIT",
+		BankTransactionTime:   trxTime,
+	}
+	systemItem := dbgen.ReconciliationItem{
+		ID:              3,
+		JobID:           id,
+		Source:          "SYSTEM",
+		RowNumber:       2,
+		TransactionID:   "TRX-2",
+		Amount:          500.5,
+		Type:            "DEBIT",
+		TransactionTime: trxTime,
+		Status:          "MISSING",
+	}
+	bankItem := dbgen.ReconciliationItem{
+		ID:              4,
+		JobID:           id,
+		Source:          "BANK",
+		BankName:        sql.NullString{String: "BRI", Valid: true},
+		RowNumber:       1,
+		TransactionID:   "BRI-1",
+		Amount:          1000,
+		Type:            "CREDIT",
+		TransactionTime: trxTime,
+		Status:          "MISSING",
+	}
+	expectMissingItems := func(systemItems, bankItems []dbgen.ReconciliationItem) {
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "SYSTEM", Limit: 1000}).Return(systemItems, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "BANK", Limit: 1000}).Return(bankItems, nil)
+	}
+
+	s.Run("success export csv with a section per table", func() {
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(true, nil)
+		var buf bytes.Buffer
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, dbgen.ListReconciliationMatchedPairsParams{JobID: id, Limit: 1000}).Return([]dbgen.ListReconciliationMatchedPairsRow{pair}, nil)
+		expectMissingItems([]dbgen.ReconciliationItem{systemItem}, []dbgen.ReconciliationItem{bankItem})
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormatCSV, &buf)
+
+		s.NoError(err)
+		s.Equal(strings.Join([]string{
+			"Summary",
+			"Field,Value",
+			"Job ID,1",
+			"Start Date,2024-11-01",
+			"End Date,2024-11-30",
+			`Banks,"BCA, BRI"`,
+			"Total Transaction Processed,3",
+			"Total Transaction Matched,2",
+			"Total Transaction Unmatched,1",
+			"Total Transaction Needs Review,0",
+			"Total Transaction Reversed,0",
+			"Total Discrepancy Amount,1500.5",
+			"Content Hash,hash",
+			"",
+			"Matched Transactions",
+			"Status,Confidence,System Row,System Transaction ID,System Amount,System Type,System Time,Bank Name,Bank Row,Bank Transaction ID,Bank Amount,Bank Type,Bank Time,Expected Fee,Implied Fee",
+			"MATCHED,1,1,TRX-1,1000,CREDIT,2024-11-01T10:00:00Z,BCA,2,BCA-1,1000,CREDIT,2024-11-01T10:00:00Z,0,0",
+			"",
+			"Missing System Transactions",
+			"Row,Transaction ID,Amount,Type,Time,Adjustment ID,Resolved Job ID",
+			"2,TRX-2,500.5,DEBIT,2024-11-01T10:00:00Z,,",
+			"",
+			"Missing Bank Transactions",
+			"Bank Name,Row,Transaction ID,Amount,Type,Time,Adjustment ID,Resolved Job ID",
+			"BRI,1,BRI-1,1000,CREDIT,2024-11-01T10:00:00Z,,",
+			"",
+		}, "\n"), buf.String())
+	})
+
+	s.Run("success export the adjusted totals and mark the adjusted and resolved items", func() {
+		var buf bytes.Buffer
+		job := *job
+		job.AdjustedTotals = &entity.ReconciliationAdjustedTotals{
+			TotalTransactionMatched:         3,
+			TotalTransactionManuallyMatched: 1,
+			TotalTransactionResolved:        1,
+			TotalTransactionUnmatched:       0,
+			TotalDiscrepancyAmount:          1000,
+		}
+		systemItem := systemItem
+		systemItem.AdjustmentID = sql.NullInt64{Int64: 7, Valid: true}
+		bankItem := bankItem
+		bankItem.ResolvedJobID = sql.NullInt64{Int64: 2, Valid: true}
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(true, nil)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return(nil, nil)
+		expectMissingItems([]dbgen.ReconciliationItem{systemItem}, []dbgen.ReconciliationItem{bankItem})
+
+		err := s.svc.Export(ctx, &job, reconciliatonjob.ExportFormatCSV, &buf)
+
+		s.NoError(err)
+		s.Contains(buf.String(), strings.Join([]string{
+			"Total Transaction Processed,3",
+			"Total Transaction Matched,3",
+			"Total Transaction Unmatched,0",
+			"Total Transaction Needs Review,0",
+			"Total Transaction Reversed,0",
+			"Total Discrepancy Amount,1000",
+			"Total Transaction Manually Matched,1",
+			"Total Transaction Resolved,1",
+			"Content Hash,hash",
+		}, "\n"))
+		s.Contains(buf.String(), "2,TRX-2,500.5,DEBIT,2024-11-01T10:00:00Z,7,\n")
+		s.Contains(buf.String(), "BRI,1,BRI-1,1000,CREDIT,2024-11-01T10:00:00Z,,2\n")
+	})
+
+	s.Run("success export xlsx with a sheet per table", func() {
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(true, nil)
+		var buf bytes.Buffer
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return([]dbgen.ListReconciliationMatchedPairsRow{pair}, nil)
+		expectMissingItems([]dbgen.ReconciliationItem{systemItem}, []dbgen.ReconciliationItem{bankItem})
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormatXLSX, &buf)
+
+		s.Require().NoError(err)
+		r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		s.Require().NoError(err)
+		workbook := readZipFile(s.T(), r, "xl/workbook.xml")
+		for _, sheet := range []string{"Summary", "Matched Transactions", "Missing System Transactions", "Missing Bank Transactions"} {
+			s.Contains(workbook, fmt.Sprintf(`name="%s"`, sheet))
+		}
+		s.Contains(readZipFile(s.T(), r, "xl/worksheets/sheet4.xml"), "BRI-1")
+	})
+
+	s.Run("success fetch the rows in batches", func() {
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(true, nil)
+		var buf bytes.Buffer
+		firstBatch := make([]dbgen.ReconciliationItem, 1000)
+		for i := range firstBatch {
+			firstBatch[i] = systemItem
+			firstBatch[i].ID = int64(i + 1)
+		}
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return(nil, nil)
+		gomock.InOrder(
+			s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "SYSTEM", Limit: 1000}).Return(firstBatch, nil),
+			s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "SYSTEM", ID: 1000, Limit: 1000}).Return([]dbgen.ReconciliationItem{systemItem}, nil),
+		)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "BANK", Limit: 1000}).Return(nil, nil)
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormatCSV, &buf)
+
+		s.NoError(err)
+		s.Equal(1001, strings.Count(buf.String(), "TRX-2"))
+	})
+
+	s.Run("success export a notice for a job saved without items", func() {
+		var buf bytes.Buffer
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(false, nil)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return(nil, nil)
+		expectMissingItems(nil, nil)
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormatCSV, &buf)
+
+		s.NoError(err)
+		s.Contains(buf.String(), "Content Hash,hash\nNotice,\"The job was saved before its transactions were stored, the sections below are empty")
+	})
+
+	s.Run("success escape values read as formula", func() {
+		var buf bytes.Buffer
+		pair := pair
+		pair.SystemTransactionID = "=HYPERLINK(\"http://evil\")"
+		pair.BankTransactionID = "@SUM(A1)"
+		systemItem := systemItem
+		systemItem.TransactionID = "-2+3"
+		systemItem.Amount = -500.5
+		bankItem := bankItem
+		bankItem.TransactionID = "\tBRI-1"
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(true, nil)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return([]dbgen.ListReconciliationMatchedPairsRow{pair}, nil)
+		expectMissingItems([]dbgen.ReconciliationItem{systemItem}, []dbgen.ReconciliationItem{bankItem})
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormatCSV, &buf)
+
+		s.NoError(err)
+		s.Contains(buf.String(), `MATCHED,1,1,"'=HYPERLINK(""http://evil"")",1000,CREDIT,2024-11-01T10:00:00Z,BCA,2,'@SUM(A1),`)
+		s.Contains(buf.String(), "2,'-2+3,-500.5,DEBIT,")
+		s.Contains(buf.String(), "BRI,1,'\tBRI-1,1000,CREDIT,")
+	})
+
+	s.Run("error check items", func() {
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(false, assert.AnError)
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormatCSV, io.Discard)
+
+		s.ErrorIs(err, assert.AnError)
+	})
+
+	s.Run("job is not success", func() {
+		var buf bytes.Buffer
+		job := *job
+		job.Status = entity.ReconciliationJobStatusProcessing
+
+		err := s.svc.Export(ctx, &job, reconciliatonjob.ExportFormatCSV, &buf)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotSuccess)
+		s.Zero(buf.Len())
+	})
+
+	s.Run("unsupported format", func() {
+		var buf bytes.Buffer
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormat("pdf"), &buf)
+
+		s.Error(err)
+		s.Zero(buf.Len())
+	})
+
+	s.Run("error list matched pairs", func() {
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(true, nil)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+		err := s.svc.Export(ctx, job, reconciliatonjob.ExportFormatCSV, io.Discard)
+
+		s.ErrorIs(err, assert.AnError)
+	})
+}
+
+func readZipFile(t *testing.T, r *zip.Reader, name string) string {
+	f, err := r.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	b, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(b)
+}
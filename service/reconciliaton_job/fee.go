@@ -0,0 +1,88 @@
+package reconciliatonjob
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/delly/amartha/entity"
+)
+
+// bankFee compute the fee deducted by the bank from the system amount, tiers
+// must be sorted by their min amount
+func bankFee(rule entity.FeeRule, amount float64) float64 {
+	switch rule.Type {
+	case entity.FeeTypeFixed:
+		return rule.Fixed
+	case entity.FeeTypePercentage:
+		return amount * rule.Rate
+	case entity.FeeTypeTiered:
+		fee := 0.0
+		for _, tier := range rule.Tiers {
+			if amount < tier.MinAmount {
+				break
+			}
+			fee = tier.Fixed + amount*tier.Rate
+		}
+		return fee
+	}
+
+	return 0
+}
+
+// normalizeFeeRule validate the fee rule of a bank and sort its tiers by
+// their min amount
+func normalizeFeeRule(bankName string, rule *entity.FeeRule) error {
+	switch rule.Type {
+	case entity.FeeTypeFixed, entity.FeeTypePercentage:
+		if !isValidFee(rule.Fixed, rule.Rate) {
+			return errInvalidFeeRule(bankName)
+		}
+	case entity.FeeTypeTiered:
+		if len(rule.Tiers) == 0 {
+			return errInvalidFeeRule(bankName)
+		}
+		for _, tier := range rule.Tiers {
+			if tier.MinAmount < 0 || !isValidFee(tier.Fixed, tier.Rate) {
+				return errInvalidFeeRule(bankName)
+			}
+		}
+		slices.SortFunc(rule.Tiers, func(a, b entity.FeeTier) int {
+			return cmp.Compare(a.MinAmount, b.MinAmount)
+		})
+	default:
+		return errInvalidFeeType(bankName, rule.Type)
+	}
+
+	return nil
+}
+
+func isValidFee(fixed, rate float64) bool {
+	return fixed >= 0 && rate >= 0 && rate < 1
+}
+
+// validateFeeRuleBanks check every fee rule belongs to a bank of the job,
+// bank names are compared by their key
+func validateFeeRuleBanks(opts *entity.MatchingOptions, bankNames []string) error {
+	keys := make([]string, len(bankNames))
+	for i, bankName := range bankNames {
+		keys[i] = entity.BankNameKey(bankName)
+	}
+	for bankName := range opts.FeeRules {
+		if !slices.Contains(keys, entity.BankNameKey(bankName)) {
+			return errUnknownFeeRuleBank(bankName)
+		}
+	}
+
+	return nil
+}
+
+// findFeeRule return the fee rule of the bank, fee rules of the jobs created
+// before they are keyed by the bank name key are keyed by the bank name as is
+func findFeeRule(opts *entity.MatchingOptions, bankName string) (entity.FeeRule, bool) {
+	if rule, ok := opts.FeeRules[entity.BankNameKey(bankName)]; ok {
+		return rule, true
+	}
+	rule, ok := opts.FeeRules[bankName]
+
+	return rule, ok
+}
@@ -2,8 +2,11 @@ package reconciliatonjob
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"time"
 
+	"github.com/delly/amartha/common"
 	"github.com/delly/amartha/common/logger"
 	"github.com/delly/amartha/entity"
 	dbgen "github.com/delly/amartha/repository/postgresql"
@@ -16,6 +19,11 @@ type Finder interface {
 	Count(ctx context.Context) (int64, error)
 	FindAll(ctx context.Context, limit, offset int32) ([]*entity.SimpleReconciliationJob, error)
 	FindByID(ctx context.Context, id int64) (*entity.ReconciliationJob, error)
+	CountItems(ctx context.Context, filter ItemFilter) (int64, error)
+	FindItems(ctx context.Context, filter ItemFilter, limit, offset int32) ([]*entity.ReconciliationItem, error)
+	CountDiff(ctx context.Context, job, other *entity.ReconciliationJob) (int64, error)
+	Diff(ctx context.Context, job, other *entity.ReconciliationJob, limit, offset int32) (*entity.ReconciliationDiff, error)
+	Ageing(ctx context.Context, asOf time.Time) (*entity.UnmatchedAgeing, error)
 }
 
 // FinderRepository is a contract to find reconciliation job
@@ -23,6 +31,41 @@ type FinderRepository interface {
 	CountReconciliationJobs(ctx context.Context) (int64, error)
 	GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error)
 	ListReconciliationJobs(ctx context.Context, arg dbgen.ListReconciliationJobsParams) ([]dbgen.ListReconciliationJobsRow, error)
+	CountReconciliationItems(ctx context.Context, arg dbgen.CountReconciliationItemsParams) (int64, error)
+	ExistsReconciliationItems(ctx context.Context, jobID int64) (bool, error)
+	ListReconciliationItems(ctx context.Context, arg dbgen.ListReconciliationItemsParams) ([]dbgen.ReconciliationItem, error)
+	CountReconciliationDiffItems(ctx context.Context, arg dbgen.CountReconciliationDiffItemsParams) (int64, error)
+	DiffReconciliationItems(ctx context.Context, arg dbgen.DiffReconciliationItemsParams) ([]dbgen.DiffReconciliationItemsRow, error)
+	SummarizeUnresolvedReconciliationItems(ctx context.Context, asOf time.Time) ([]dbgen.SummarizeUnresolvedReconciliationItemsRow, error)
+}
+
+const (
+	// ItemSortByAmount sort the items by their amount
+	ItemSortByAmount = "amount"
+	// ItemSortByTransactionTime sort the items by their transaction time
+	ItemSortByTransactionTime = "transaction_time"
+	// ItemFindingDuplicate filter the items duplicating an earlier row of the same file
+	ItemFindingDuplicate = "duplicate"
+	// ItemFindingOverlap filter the bank items found in an earlier bank file
+	ItemFindingOverlap = "overlap"
+)
+
+// ItemFilter is a filter to find the items of a reconciliation job, the zero
+// value of a field is not filtered, the items are ordered by their position
+// in the job result when sort by is empty
+type ItemFilter struct {
+	JobID     int64
+	Source    entity.ReconciliationItemSource
+	BankName  string
+	Type      entity.TransactionType
+	Status    entity.ReconciliationItemStatus
+	MinAmount *float64
+	MaxAmount *float64
+	StartDate *time.Time
+	EndDate   *time.Time
+	Finding   string
+	SortBy    string
+	SortDesc  bool
 }
 
 // FinderService is a service to find reconciliation job
@@ -53,7 +96,23 @@ func (s *FinderService) FindByID(ctx context.Context, id int64) (*entity.Reconci
 		return nil, err
 	}
 
-	return convertToEntityReconciliationJob(rj), nil
+	res := convertToEntityReconciliationJob(rj)
+	if res.Result == nil {
+		return res, nil
+	}
+	// jobs saved before the items were stored have no items, so their result
+	// is the only place of their lists and it is returned as saved
+	exists, err := s.repo.ExistsReconciliationItems(ctx, id)
+	if err != nil {
+		log.Error("failed to check reconciliation items", zap.Error(err), zap.Int64("id", id))
+		return nil, err
+	}
+	if exists {
+		summary := summaryResult(res.Result)
+		res.Result = &summary
+	}
+
+	return res, nil
 }
 
 // FindAll find all reconciliation job
@@ -88,3 +147,230 @@ func (s *FinderService) Count(ctx context.Context) (int64, error) {
 
 	return res, nil
 }
+
+// CountItems count the items of a reconciliation job matching the filter
+func (s *FinderService) CountItems(ctx context.Context, filter ItemFilter) (int64, error) {
+	log := logger.WithMethod(s.log, "CountItems")
+	res, err := s.repo.CountReconciliationItems(ctx, filter.countParams())
+	if err != nil {
+		log.Error("failed to count reconciliation items", zap.Error(err), zap.Int64("job_id", filter.JobID))
+		return 0, err
+	}
+
+	return res, nil
+}
+
+// FindItems find the items of a reconciliation job matching the filter
+func (s *FinderService) FindItems(ctx context.Context, filter ItemFilter, limit, offset int32) ([]*entity.ReconciliationItem, error) {
+	log := logger.WithMethod(s.log, "FindItems")
+	p := filter.countParams()
+	params := dbgen.ListReconciliationItemsParams{
+		JobID:     p.JobID,
+		Source:    p.Source,
+		BankName:  p.BankName,
+		Type:      p.Type,
+		Status:    p.Status,
+		MinAmount: p.MinAmount,
+		MaxAmount: p.MaxAmount,
+		StartTime: p.StartTime,
+		EndTime:   p.EndTime,
+		Finding:   p.Finding,
+		SortBy:    filter.SortBy,
+		SortDesc:  filter.SortDesc,
+		Limit:     limit,
+		Offset:    offset,
+	}
+	items, err := s.repo.ListReconciliationItems(ctx, params)
+	if err != nil {
+		log.Error("failed to list reconciliation items", zap.Error(err), zap.Int64("job_id", filter.JobID))
+		return nil, err
+	}
+
+	res := []*entity.ReconciliationItem{}
+	for _, item := range items {
+		res = append(res, convertToEntityReconciliationItem(item))
+	}
+
+	return res, nil
+}
+
+// CountDiff count the items of two successful jobs that changed from the job
+// to the other
+func (s *FinderService) CountDiff(ctx context.Context, job, other *entity.ReconciliationJob) (int64, error) {
+	log := logger.WithMethod(s.log, "CountDiff")
+	if err := checkDiffJobs(job, other); err != nil {
+		return 0, err
+	}
+
+	res, err := s.repo.CountReconciliationDiffItems(ctx, dbgen.CountReconciliationDiffItemsParams{JobID: job.ID, OtherJobID: other.ID})
+	if err != nil {
+		log.Error("failed to count reconciliation diff items", zap.Error(err), zap.Int64("id", job.ID), zap.Int64("other_id", other.ID))
+		return 0, err
+	}
+
+	return res, nil
+}
+
+// Diff compare the items of two successful jobs, usually a job and its re-run
+// after the data is fixed, and report what changed from the job to the other,
+// the changed items are paginated in the order of their source, bank name and
+// transaction ID
+func (s *FinderService) Diff(ctx context.Context, job, other *entity.ReconciliationJob, limit, offset int32) (*entity.ReconciliationDiff, error) {
+	log := logger.WithMethod(s.log, "Diff")
+	if err := checkDiffJobs(job, other); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.DiffReconciliationItems(ctx, dbgen.DiffReconciliationItemsParams{
+		JobID:      job.ID,
+		OtherJobID: other.ID,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		log.Error("failed to diff reconciliation items", zap.Error(err), zap.Int64("id", job.ID), zap.Int64("other_id", other.ID))
+		return nil, err
+	}
+
+	res := &entity.ReconciliationDiff{
+		JobID:          job.ID,
+		OtherJobID:     other.ID,
+		NewlyMatched:   []entity.ReconciliationDiffItem{},
+		NewlyUnmatched: []entity.ReconciliationDiffItem{},
+		StatusChanges:  []entity.ReconciliationDiffItem{},
+		Added:          []entity.ReconciliationDiffItem{},
+		Removed:        []entity.ReconciliationDiffItem{},
+		AmountChanges:  []entity.ReconciliationDiffItem{},
+	}
+	for _, row := range rows {
+		item := convertToEntityReconciliationDiffItem(row)
+		if row.OtherStatus.String == string(entity.ReconciliationItemStatusMatched) && row.BaseStatus.String != row.OtherStatus.String {
+			res.NewlyMatched = append(res.NewlyMatched, item)
+		}
+		if row.OtherStatus.String == string(entity.ReconciliationItemStatusMissing) && row.BaseStatus.String != row.OtherStatus.String {
+			res.NewlyUnmatched = append(res.NewlyUnmatched, item)
+		}
+		switch {
+		case !row.BaseStatus.Valid:
+			res.Added = append(res.Added, item)
+		case !row.OtherStatus.Valid:
+			res.Removed = append(res.Removed, item)
+		case row.BaseStatus.String != row.OtherStatus.String:
+			res.StatusChanges = append(res.StatusChanges, item)
+		}
+		if row.BaseAmount.Valid && row.OtherAmount.Valid && row.BaseAmount.Float64 != row.OtherAmount.Float64 {
+			res.AmountChanges = append(res.AmountChanges, item)
+		}
+	}
+
+	return res, nil
+}
+
+func checkDiffJobs(job, other *entity.ReconciliationJob) error {
+	for _, rj := range []*entity.ReconciliationJob{job, other} {
+		if rj.Status != entity.ReconciliationJobStatusSuccess {
+			return ErrReconciliationJobNotSuccess
+		}
+	}
+
+	return nil
+}
+
+// Ageing group the unmatched transactions of every successful job that are not
+// resolved by a later job into ageing buckets by the days from their UTC
+// transaction date to the as of date, per source, bank and transaction type,
+// only the jobs created by the end of the as of date are counted, a day of a
+// bank is only counted from the latest job covering it, so a successful re-run
+// only supersedes the days it covers, and the transactions carried forward are
+// only counted in the latest job they are carried to
+func (s *FinderService) Ageing(ctx context.Context, asOf time.Time) (*entity.UnmatchedAgeing, error) {
+	log := logger.WithMethod(s.log, "Ageing")
+	rows, err := s.repo.SummarizeUnresolvedReconciliationItems(ctx, asOf)
+	if err != nil {
+		log.Error("failed to summarize unresolved reconciliation items", zap.Error(err), zap.Time("as_of", asOf))
+		return nil, err
+	}
+
+	res := &entity.UnmatchedAgeing{
+		AsOf:    asOf,
+		Groups:  []entity.UnmatchedAgeingGroup{},
+		Buckets: newAgeingBucketTotals(),
+	}
+	for _, row := range rows {
+		last := len(res.Groups) - 1
+		if last < 0 || string(res.Groups[last].Source) != row.Source ||
+			res.Groups[last].BankName != row.BankName.String || string(res.Groups[last].Type) != row.Type {
+			res.Groups = append(res.Groups, entity.UnmatchedAgeingGroup{
+				Source:   entity.ReconciliationItemSource(row.Source),
+				BankName: row.BankName.String,
+				Type:     entity.TransactionType(row.Type),
+				Buckets:  newAgeingBucketTotals(),
+			})
+			last++
+		}
+		group := &res.Groups[last]
+		idx := ageingBucketIndex(row.AgeDays)
+		for _, bucket := range []*entity.AgeingBucketTotal{&group.Buckets[idx], &res.Buckets[idx]} {
+			bucket.TotalTransaction += row.TotalTransaction
+			bucket.TotalAmount += row.TotalAmount
+		}
+		group.TotalTransaction += row.TotalTransaction
+		group.TotalAmount += row.TotalAmount
+		res.TotalTransaction += row.TotalTransaction
+		res.TotalAmount += row.TotalAmount
+	}
+
+	return res, nil
+}
+
+func newAgeingBucketTotals() []entity.AgeingBucketTotal {
+	totals := make([]entity.AgeingBucketTotal, 0, len(entity.AgeingBuckets))
+	for _, bucket := range entity.AgeingBuckets {
+		totals = append(totals, entity.AgeingBucketTotal{Bucket: bucket})
+	}
+
+	return totals
+}
+
+// ageingBucketIndex return the index in entity.AgeingBuckets of the days a
+// transaction has been unmatched, transactions dated after the as of date are
+// counted in the youngest bucket
+func ageingBucketIndex(ageDays int32) int {
+	switch {
+	case ageDays <= 7:
+		return 0
+	case ageDays <= 30:
+		return 1
+	case ageDays <= 90:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// countParams convert the filter to the nullable query params, the date range
+// covers the whole start and end date
+func (f ItemFilter) countParams() dbgen.CountReconciliationItemsParams {
+	params := dbgen.CountReconciliationItemsParams{
+		JobID:    f.JobID,
+		Source:   sql.NullString{String: string(f.Source), Valid: f.Source != ""},
+		BankName: sql.NullString{String: f.BankName, Valid: f.BankName != ""},
+		Type:     sql.NullString{String: string(f.Type), Valid: f.Type != ""},
+		Status:   sql.NullString{String: string(f.Status), Valid: f.Status != ""},
+		Finding:  sql.NullString{String: f.Finding, Valid: f.Finding != ""},
+	}
+	if f.MinAmount != nil {
+		params.MinAmount = sql.NullFloat64{Float64: *f.MinAmount, Valid: true}
+	}
+	if f.MaxAmount != nil {
+		params.MaxAmount = sql.NullFloat64{Float64: *f.MaxAmount, Valid: true}
+	}
+	if f.StartDate != nil {
+		params.StartTime = sql.NullTime{Time: common.StartOfDay(*f.StartDate), Valid: true}
+	}
+	if f.EndDate != nil {
+		params.EndTime = sql.NullTime{Time: common.EndOfDay(*f.EndDate), Valid: true}
+	}
+
+	return params
+}
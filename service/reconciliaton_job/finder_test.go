@@ -156,15 +156,38 @@ func TestFinderTestSuite(t *testing.T) {
 func (s *FinderTestSuite) TestFindByID() {
 	ctx := context.Background()
 
-	s.Run("success", func() {
+	s.Run("success return the summary of a job with items", func() {
 		rj := dbReconJob
-		expectedRJ := entityReconJob
+		expectedRJ := *entityReconJob
+		expectedResult := *entityReconJob.Result
+		expectedResult.MissingTransactions = nil
+		expectedResult.MissingBankTransactions = nil
+		expectedRJ.Result = &expectedResult
 
 		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(rj, nil)
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(true, nil)
 
 		res, err := s.svc.FindByID(ctx, id)
 		s.NoError(err)
-		s.Equal(expectedRJ, res)
+		s.Equal(&expectedRJ, res)
+	})
+
+	s.Run("success keep the lists of a job saved without items", func() {
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbReconJob, nil)
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(false, nil)
+
+		res, err := s.svc.FindByID(ctx, id)
+		s.NoError(err)
+		s.Equal(entityReconJob, res)
+	})
+
+	s.Run("error check items", func() {
+		s.repo.EXPECT().GetReconciliationJobById(ctx, id).Return(dbReconJob, nil)
+		s.repo.EXPECT().ExistsReconciliationItems(ctx, id).Return(false, assert.AnError)
+
+		res, err := s.svc.FindByID(ctx, id)
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
 	})
 
 	s.Run("not found", func() {
@@ -239,3 +262,340 @@ func (s *FinderTestSuite) TestCount() {
 		s.Zero(res)
 	})
 }
+
+func (s *FinderTestSuite) TestFindItems() {
+	ctx := context.Background()
+	limit := int32(10)
+	offset := int32(20)
+	minAmount := 100.0
+	startDate := time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	filter := reconciliatonjob.ItemFilter{
+		JobID:     id,
+		BankName:  "BCA",
+		Status:    entity.ReconciliationItemStatusMissing,
+		MinAmount: &minAmount,
+		StartDate: &startDate,
+		EndDate:   &endDate,
+		SortBy:    reconciliatonjob.ItemSortByAmount,
+		SortDesc:  true,
+	}
+	params := dbgen.ListReconciliationItemsParams{
+		JobID:     id,
+		BankName:  sql.NullString{String: "BCA", Valid: true},
+		Status:    sql.NullString{String: "MISSING", Valid: true},
+		MinAmount: sql.NullFloat64{Float64: 100, Valid: true},
+		StartTime: sql.NullTime{Time: startDate, Valid: true},
+		EndTime:   sql.NullTime{Time: time.Date(2024, 11, 30, 23, 59, 59, 0, time.UTC), Valid: true},
+		SortBy:    "amount",
+		SortDesc:  true,
+		Limit:     limit,
+		Offset:    offset,
+	}
+
+	s.Run("success", func() {
+		items := []dbgen.ReconciliationItem{
+			{
+				ID:              7,
+				JobID:           id,
+				Source:          "BANK",
+				BankName:        sql.NullString{String: "BCA", Valid: true},
+				RowNumber:       3,
+				TransactionID:   "BCA-3",
+				Amount:          500,
+				Type:            "CREDIT",
+				TransactionTime: startDate,
+				Status:          "MISSING",
+			},
+		}
+		expectedItems := []*entity.ReconciliationItem{
+			{
+				ID:          7,
+				JobID:       id,
+				Transaction: entity.Transaction{ID: "BCA-3", Amount: 500, Type: entity.TxTypeCredit, Time: startDate},
+				Source:      entity.ReconciliationItemSourceBank,
+				BankName:    "BCA",
+				Row:         3,
+				Status:      entity.ReconciliationItemStatusMissing,
+			},
+		}
+		s.repo.EXPECT().ListReconciliationItems(ctx, params).Return(items, nil)
+
+		res, err := s.svc.FindItems(ctx, filter, limit, offset)
+		s.NoError(err)
+		s.Equal(expectedItems, res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().ListReconciliationItems(ctx, params).Return(nil, assert.AnError)
+
+		res, err := s.svc.FindItems(ctx, filter, limit, offset)
+		s.Error(err)
+		s.Nil(res)
+	})
+}
+
+func (s *FinderTestSuite) TestCountItems() {
+	ctx := context.Background()
+	filter := reconciliatonjob.ItemFilter{
+		JobID:  id,
+		Source: entity.ReconciliationItemSourceSystem,
+		Type:   entity.TxTypeDebit,
+	}
+	params := dbgen.CountReconciliationItemsParams{
+		JobID:  id,
+		Source: sql.NullString{String: "SYSTEM", Valid: true},
+		Type:   sql.NullString{String: "DEBIT", Valid: true},
+	}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().CountReconciliationItems(ctx, params).Return(int64(3), nil)
+
+		res, err := s.svc.CountItems(ctx, filter)
+		s.NoError(err)
+		s.Equal(int64(3), res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().CountReconciliationItems(ctx, params).Return(int64(0), assert.AnError)
+
+		res, err := s.svc.CountItems(ctx, filter)
+		s.Error(err)
+		s.Zero(res)
+	})
+}
+
+func (s *FinderTestSuite) TestDiff() {
+	ctx := context.Background()
+	job := &entity.ReconciliationJob{ID: id, Status: entity.ReconciliationJobStatusSuccess}
+	other := &entity.ReconciliationJob{ID: 2, Status: entity.ReconciliationJobStatusSuccess}
+	params := dbgen.DiffReconciliationItemsParams{JobID: id, OtherJobID: 2, Limit: 10, Offset: 20}
+	matched := entity.ReconciliationItemStatusMatched
+	missing := entity.ReconciliationItemStatusMissing
+	review := entity.ReconciliationItemStatusNeedsReview
+	reversed := entity.ReconciliationItemStatusReversed
+	duplicate := entity.ReconciliationItemStatusDuplicate
+	amount := func(v float64) *float64 { return &v }
+	row := func(v int) *int { return &v }
+
+	s.Run("success classify the changed items", func() {
+		s.repo.EXPECT().DiffReconciliationItems(ctx, params).Return([]dbgen.DiffReconciliationItemsRow{
+			{
+				Source: "SYSTEM", TransactionID: "TRX-1",
+				BaseRow: sql.NullInt32{Int32: 1, Valid: true}, BaseAmount: sql.NullFloat64{Float64: 1000, Valid: true}, BaseStatus: sql.NullString{String: "MISSING", Valid: true},
+				OtherRow: sql.NullInt32{Int32: 2, Valid: true}, OtherAmount: sql.NullFloat64{Float64: 1000, Valid: true}, OtherStatus: sql.NullString{String: "MATCHED", Valid: true},
+			},
+			{
+				Source: "SYSTEM", TransactionID: "TRX-2",
+				BaseAmount: sql.NullFloat64{Float64: 500, Valid: true}, BaseStatus: sql.NullString{String: "MATCHED", Valid: true},
+				OtherAmount: sql.NullFloat64{Float64: 550, Valid: true}, OtherStatus: sql.NullString{String: "MISSING", Valid: true},
+			},
+			{
+				Source: "BANK", BankName: "BCA", TransactionID: "BCA-3",
+				OtherAmount: sql.NullFloat64{Float64: 200, Valid: true}, OtherStatus: sql.NullString{String: "MISSING", Valid: true},
+			},
+			{
+				Source: "SYSTEM", TransactionID: "TRX-4",
+				BaseAmount: sql.NullFloat64{Float64: 300, Valid: true}, BaseStatus: sql.NullString{String: "NEEDS_REVIEW", Valid: true},
+				OtherAmount: sql.NullFloat64{Float64: 300, Valid: true}, OtherStatus: sql.NullString{String: "REVERSED", Valid: true},
+			},
+			{
+				Source: "BANK", BankName: "BCA", TransactionID: "BCA-5",
+				BaseAmount: sql.NullFloat64{Float64: 100, Valid: true}, BaseStatus: sql.NullString{String: "DUPLICATE", Valid: true},
+			},
+		}, nil)
+
+		res, err := s.svc.Diff(ctx, job, other, 10, 20)
+
+		s.NoError(err)
+		s.Equal(&entity.ReconciliationDiff{
+			JobID:      id,
+			OtherJobID: 2,
+			NewlyMatched: []entity.ReconciliationDiffItem{
+				{Source: "SYSTEM", TransactionID: "TRX-1", Row: row(1), Status: &missing, Amount: amount(1000), OtherRow: row(2), OtherStatus: &matched, OtherAmount: amount(1000)},
+			},
+			NewlyUnmatched: []entity.ReconciliationDiffItem{
+				{Source: "SYSTEM", TransactionID: "TRX-2", Status: &matched, Amount: amount(500), OtherStatus: &missing, OtherAmount: amount(550)},
+				{Source: "BANK", BankName: "BCA", TransactionID: "BCA-3", OtherStatus: &missing, OtherAmount: amount(200)},
+			},
+			StatusChanges: []entity.ReconciliationDiffItem{
+				{Source: "SYSTEM", TransactionID: "TRX-1", Row: row(1), Status: &missing, Amount: amount(1000), OtherRow: row(2), OtherStatus: &matched, OtherAmount: amount(1000)},
+				{Source: "SYSTEM", TransactionID: "TRX-2", Status: &matched, Amount: amount(500), OtherStatus: &missing, OtherAmount: amount(550)},
+				{Source: "SYSTEM", TransactionID: "TRX-4", Status: &review, Amount: amount(300), OtherStatus: &reversed, OtherAmount: amount(300)},
+			},
+			Added: []entity.ReconciliationDiffItem{
+				{Source: "BANK", BankName: "BCA", TransactionID: "BCA-3", OtherStatus: &missing, OtherAmount: amount(200)},
+			},
+			Removed: []entity.ReconciliationDiffItem{
+				{Source: "BANK", BankName: "BCA", TransactionID: "BCA-5", Status: &duplicate, Amount: amount(100)},
+			},
+			AmountChanges: []entity.ReconciliationDiffItem{
+				{Source: "SYSTEM", TransactionID: "TRX-2", Status: &matched, Amount: amount(500), OtherStatus: &missing, OtherAmount: amount(550)},
+			},
+		}, res)
+	})
+
+	s.Run("success no change", func() {
+		s.repo.EXPECT().DiffReconciliationItems(ctx, params).Return(nil, nil)
+
+		res, err := s.svc.Diff(ctx, job, other, 10, 20)
+
+		s.NoError(err)
+		s.Empty(res.NewlyMatched)
+		s.NotNil(res.NewlyMatched)
+		s.Empty(res.NewlyUnmatched)
+		s.Empty(res.StatusChanges)
+		s.Empty(res.Added)
+		s.Empty(res.Removed)
+		s.Empty(res.AmountChanges)
+	})
+
+	s.Run("other job is not success", func() {
+		other := &entity.ReconciliationJob{ID: 2, Status: entity.ReconciliationJobStatusProcessing}
+
+		res, err := s.svc.Diff(ctx, job, other, 10, 20)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotSuccess)
+		s.Nil(res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().DiffReconciliationItems(ctx, params).Return(nil, assert.AnError)
+
+		res, err := s.svc.Diff(ctx, job, other, 10, 20)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
+
+func (s *FinderTestSuite) TestCountDiff() {
+	ctx := context.Background()
+	job := &entity.ReconciliationJob{ID: id, Status: entity.ReconciliationJobStatusSuccess}
+	other := &entity.ReconciliationJob{ID: 2, Status: entity.ReconciliationJobStatusSuccess}
+	params := dbgen.CountReconciliationDiffItemsParams{JobID: id, OtherJobID: 2}
+
+	s.Run("success", func() {
+		s.repo.EXPECT().CountReconciliationDiffItems(ctx, params).Return(int64(3), nil)
+
+		res, err := s.svc.CountDiff(ctx, job, other)
+
+		s.NoError(err)
+		s.Equal(int64(3), res)
+	})
+
+	s.Run("job is not success", func() {
+		job := &entity.ReconciliationJob{ID: id, Status: entity.ReconciliationJobStatusFailed}
+
+		res, err := s.svc.CountDiff(ctx, job, other)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotSuccess)
+		s.Zero(res)
+	})
+
+	s.Run("error", func() {
+		s.repo.EXPECT().CountReconciliationDiffItems(ctx, params).Return(int64(0), assert.AnError)
+
+		res, err := s.svc.CountDiff(ctx, job, other)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Zero(res)
+	})
+}
+
+func (s *FinderTestSuite) TestAgeing() {
+	ctx := context.Background()
+	asOf := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	bca := sql.NullString{String: "BCA", Valid: true}
+	buckets := func(totals ...entity.AgeingBucketTotal) []entity.AgeingBucketTotal {
+		res := []entity.AgeingBucketTotal{
+			{Bucket: entity.AgeingBucket0To7},
+			{Bucket: entity.AgeingBucket8To30},
+			{Bucket: entity.AgeingBucket31To90},
+			{Bucket: entity.AgeingBucketOver90},
+		}
+		for _, total := range totals {
+			for i := range res {
+				if res[i].Bucket == total.Bucket {
+					res[i] = total
+				}
+			}
+		}
+		return res
+	}
+
+	s.Run("success group into buckets per source, bank and type", func() {
+		s.repo.EXPECT().SummarizeUnresolvedReconciliationItems(ctx, asOf).Return([]dbgen.SummarizeUnresolvedReconciliationItemsRow{
+			{Source: "SYSTEM", Type: "DEBIT", AgeDays: -1, TotalTransaction: 1, TotalAmount: 100},
+			{Source: "SYSTEM", Type: "DEBIT", AgeDays: 7, TotalTransaction: 2, TotalAmount: 200},
+			{Source: "SYSTEM", Type: "DEBIT", AgeDays: 91, TotalTransaction: 1, TotalAmount: 50},
+			{Source: "BANK", BankName: bca, Type: "CREDIT", AgeDays: 8, TotalTransaction: 1, TotalAmount: 1000},
+			{Source: "BANK", BankName: bca, Type: "CREDIT", AgeDays: 90, TotalTransaction: 3, TotalAmount: 3000},
+			{Source: "BANK", BankName: bca, Type: "DEBIT", AgeDays: 30, TotalTransaction: 1, TotalAmount: 400},
+		}, nil)
+
+		res, err := s.svc.Ageing(ctx, asOf)
+
+		s.NoError(err)
+		s.Equal(&entity.UnmatchedAgeing{
+			AsOf: asOf,
+			Groups: []entity.UnmatchedAgeingGroup{
+				{
+					Source: entity.ReconciliationItemSourceSystem,
+					Type:   entity.TxTypeDebit,
+					Buckets: buckets(
+						entity.AgeingBucketTotal{Bucket: entity.AgeingBucket0To7, TotalTransaction: 3, TotalAmount: 300},
+						entity.AgeingBucketTotal{Bucket: entity.AgeingBucketOver90, TotalTransaction: 1, TotalAmount: 50},
+					),
+					TotalTransaction: 4,
+					TotalAmount:      350,
+				},
+				{
+					Source:   entity.ReconciliationItemSourceBank,
+					BankName: "BCA",
+					Type:     entity.TxTypeCredit,
+					Buckets: buckets(
+						entity.AgeingBucketTotal{Bucket: entity.AgeingBucket8To30, TotalTransaction: 1, TotalAmount: 1000},
+						entity.AgeingBucketTotal{Bucket: entity.AgeingBucket31To90, TotalTransaction: 3, TotalAmount: 3000},
+					),
+					TotalTransaction: 4,
+					TotalAmount:      4000,
+				},
+				{
+					Source:           entity.ReconciliationItemSourceBank,
+					BankName:         "BCA",
+					Type:             entity.TxTypeDebit,
+					Buckets:          buckets(entity.AgeingBucketTotal{Bucket: entity.AgeingBucket8To30, TotalTransaction: 1, TotalAmount: 400}),
+					TotalTransaction: 1,
+					TotalAmount:      400,
+				},
+			},
+			Buckets: buckets(
+				entity.AgeingBucketTotal{Bucket: entity.AgeingBucket0To7, TotalTransaction: 3, TotalAmount: 300},
+				entity.AgeingBucketTotal{Bucket: entity.AgeingBucket8To30, TotalTransaction: 2, TotalAmount: 1400},
+				entity.AgeingBucketTotal{Bucket: entity.AgeingBucket31To90, TotalTransaction: 3, TotalAmount: 3000},
+				entity.AgeingBucketTotal{Bucket: entity.AgeingBucketOver90, TotalTransaction: 1, TotalAmount: 50},
+			),
+			TotalTransaction: 9,
+			TotalAmount:      4750,
+		}, res)
+	})
+
+	s.Run("success without unresolved items", func() {
+		s.repo.EXPECT().SummarizeUnresolvedReconciliationItems(ctx, asOf).Return(nil, nil)
+
+		res, err := s.svc.Ageing(ctx, asOf)
+
+		s.NoError(err)
+		s.Equal(&entity.UnmatchedAgeing{AsOf: asOf, Groups: []entity.UnmatchedAgeingGroup{}, Buckets: buckets()}, res)
+	})
+
+	s.Run("error summarize unresolved items", func() {
+		s.repo.EXPECT().SummarizeUnresolvedReconciliationItems(ctx, asOf).Return(nil, assert.AnError)
+
+		res, err := s.svc.Ageing(ctx, asOf)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Nil(res)
+	})
+}
@@ -0,0 +1,46 @@
+package reconciliatonjob
+
+import "github.com/delly/amartha/entity"
+
+// defaultReversalWindowDays is the reversal window used when reversal pairing
+// is enabled without a window
+const defaultReversalWindowDays = 7
+
+// normalizeMatchingOptions validate the matching options and fill the
+// unspecified options with their default value
+func normalizeMatchingOptions(opts *entity.MatchingOptions) error {
+	switch opts.DuplicateRule {
+	case "":
+		opts.DuplicateRule = entity.DuplicateRuleID
+	case entity.DuplicateRuleID, entity.DuplicateRuleFingerprint:
+	default:
+		return errInvalidDuplicateRule(opts.DuplicateRule)
+	}
+	if opts.ReversalWindowDays < 0 {
+		return errInvalidReversalWindow(opts.ReversalWindowDays)
+	}
+	if opts.PairReversals && opts.ReversalWindowDays == 0 {
+		opts.ReversalWindowDays = defaultReversalWindowDays
+	}
+	if opts.MinConfidence < 0 || opts.MinConfidence > 1 {
+		return errInvalidMinConfidence(opts.MinConfidence)
+	}
+	// fee rules are keyed by the bank name key so they are found regardless
+	// of the case the bank name is uploaded with
+	feeRules := make(map[string]entity.FeeRule, len(opts.FeeRules))
+	for bankName, rule := range opts.FeeRules {
+		if err := normalizeFeeRule(bankName, &rule); err != nil {
+			return err
+		}
+		key := entity.BankNameKey(bankName)
+		if _, ok := feeRules[key]; ok {
+			return errDuplicateFeeRuleBank(bankName)
+		}
+		feeRules[key] = rule
+	}
+	if opts.FeeRules != nil {
+		opts.FeeRules = feeRules
+	}
+
+	return nil
+}
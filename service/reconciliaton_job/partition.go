@@ -0,0 +1,396 @@
+package reconciliatonjob
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/delly/amartha/entity"
+)
+
+// systemSource is the source index of system transaction in a partition file,
+// bank transactions use the index of the bank file in the job
+const systemSource = -1
+
+// rowTransaction is a parsed transaction along with its row position in the
+// file, the row is used to keep the result in file order
+type rowTransaction struct {
+	row int
+	trx *entity.Transaction
+}
+
+// partition hold transactions of both sides in a single date, transactions of
+// different dates never match each other so each partition is reconciled
+// independently, bankTrxs is indexed by the bank file index in the job
+type partition struct {
+	date       string
+	systemTrxs []rowTransaction
+	bankTrxs   [][]rowTransaction
+}
+
+func newPartition(date string, totalBanks int) *partition {
+	return &partition{
+		date:     date,
+		bankTrxs: make([][]rowTransaction, totalBanks),
+	}
+}
+
+// exclude remove the rows excluded from matching from the partition
+func (p *partition) exclude(rows map[fileRow]bool) {
+	if len(rows) == 0 {
+		return
+	}
+	excluded := func(source int) func(rowTransaction) bool {
+		return func(trx rowTransaction) bool {
+			return rows[fileRow{source: source, row: trx.row}]
+		}
+	}
+	p.systemTrxs = slices.DeleteFunc(p.systemTrxs, excluded(systemSource))
+	for bankIdx := range p.bankTrxs {
+		p.bankTrxs[bankIdx] = slices.DeleteFunc(p.bankTrxs[bankIdx], excluded(bankIdx))
+	}
+}
+
+// amountIndex is the position of the bank transactions of a partition sorted
+// by amount, so the candidates of a system transaction are found by a binary
+// search of its amount range instead of a scan of every bank transaction
+type amountIndex struct {
+	positions [][]int
+	amounts   [][]float64
+	matched   [][]bool
+}
+
+func newAmountIndex(bankTrxs [][]rowTransaction) *amountIndex {
+	index := &amountIndex{
+		positions: make([][]int, len(bankTrxs)),
+		amounts:   make([][]float64, len(bankTrxs)),
+		matched:   make([][]bool, len(bankTrxs)),
+	}
+	for bankIdx, trxs := range bankTrxs {
+		positions := make([]int, len(trxs))
+		for idx := range trxs {
+			positions[idx] = idx
+		}
+		slices.SortStableFunc(positions, func(a, b int) int {
+			return cmp.Compare(trxs[a].trx.Amount, trxs[b].trx.Amount)
+		})
+		amounts := make([]float64, len(trxs))
+		for i, idx := range positions {
+			amounts[i] = trxs[idx].trx.Amount
+		}
+		index.positions[bankIdx] = positions
+		index.amounts[bankIdx] = amounts
+		index.matched[bankIdx] = make([]bool, len(trxs))
+	}
+
+	return index
+}
+
+// forEachInRange call fn with the position of every unmatched bank
+// transaction of the bank file with an amount within minAmount and maxAmount
+func (a *amountIndex) forEachInRange(bankIdx int, minAmount, maxAmount float64, fn func(idx int)) {
+	amounts := a.amounts[bankIdx]
+	i, _ := slices.BinarySearch(amounts, minAmount)
+	for ; i < len(amounts) && amounts[i] <= maxAmount; i++ {
+		idx := a.positions[bankIdx][i]
+		if !a.matched[bankIdx][idx] {
+			fn(idx)
+		}
+	}
+}
+
+func (a *amountIndex) markMatched(bankIdx, idx int) {
+	a.matched[bankIdx][idx] = true
+}
+
+// unmatched return the unmatched bank transactions in their original order
+func (a *amountIndex) unmatched(bankTrxs [][]rowTransaction) [][]rowTransaction {
+	res := make([][]rowTransaction, len(bankTrxs))
+	for bankIdx, trxs := range bankTrxs {
+		res[bankIdx] = make([]rowTransaction, 0, len(trxs))
+		for idx, trx := range trxs {
+			if !a.matched[bankIdx][idx] {
+				res[bankIdx] = append(res[bankIdx], trx)
+			}
+		}
+	}
+
+	return res
+}
+
+func (p *partition) add(source int, trx rowTransaction) {
+	if source == systemSource {
+		p.systemTrxs = append(p.systemTrxs, trx)
+		return
+	}
+	p.bankTrxs[source] = append(p.bankTrxs[source], trx)
+}
+
+// partitioner collect parsed transactions of a job grouped into date partitions
+type partitioner interface {
+	add(source, row int, trx *entity.Transaction) error
+	// forEachPartition call fn for every partition ordered by date
+	forEachPartition(ctx context.Context, fn func(*partition) error) error
+	close() error
+}
+
+// memoryPartitioner keep all partitions in memory
+type memoryPartitioner struct {
+	totalBanks int
+	partitions map[string]*partition
+}
+
+func newMemoryPartitioner(totalBanks int) *memoryPartitioner {
+	return &memoryPartitioner{
+		totalBanks: totalBanks,
+		partitions: map[string]*partition{},
+	}
+}
+
+func (m *memoryPartitioner) add(source, row int, trx *entity.Transaction) error {
+	date := trx.Time.Format(time.DateOnly)
+	p, ok := m.partitions[date]
+	if !ok {
+		p = newPartition(date, m.totalBanks)
+		m.partitions[date] = p
+	}
+	p.add(source, rowTransaction{row: row, trx: trx})
+
+	return nil
+}
+
+func (m *memoryPartitioner) forEachPartition(ctx context.Context, fn func(*partition) error) error {
+	return forEachSortedPartition(ctx, m.partitions, fn)
+}
+
+func (m *memoryPartitioner) close() error {
+	m.partitions = nil
+	return nil
+}
+
+// diskPartitioner spill transactions into a fixed number of temporary bucket
+// files keyed by the hash of the transaction date, so only a single bucket
+// is loaded into memory while reconciling
+type diskPartitioner struct {
+	totalBanks int
+	buckets    *spillBuckets
+}
+
+func newDiskPartitioner(tempDir, pattern string, totalBuckets, totalBanks int) (*diskPartitioner, error) {
+	buckets, err := newSpillBuckets(tempDir, pattern, totalBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &diskPartitioner{
+		totalBanks: totalBanks,
+		buckets:    buckets,
+	}, nil
+}
+
+func (d *diskPartitioner) add(source, row int, trx *entity.Transaction) error {
+	return d.buckets.write(trx.Time.Format(time.DateOnly), encodeRowTransaction(source, row, trx))
+}
+
+func (d *diskPartitioner) forEachPartition(ctx context.Context, fn func(*partition) error) error {
+	return d.buckets.forEachBucket(ctx, func(r *csv.Reader) error {
+		partitions, err := d.readBucket(r)
+		if err != nil {
+			return err
+		}
+
+		return forEachSortedPartition(ctx, partitions, fn)
+	})
+}
+
+func (d *diskPartitioner) readBucket(r *csv.Reader) (map[string]*partition, error) {
+	partitions := map[string]*partition{}
+	for {
+		record, err := r.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		source, row, trx, err := decodeRowTransaction(record)
+		if err != nil {
+			return nil, err
+		}
+		date := trx.Time.Format(time.DateOnly)
+		p, ok := partitions[date]
+		if !ok {
+			p = newPartition(date, d.totalBanks)
+			partitions[date] = p
+		}
+		p.add(source, rowTransaction{row: row, trx: trx})
+	}
+
+	return partitions, nil
+}
+
+// close remove the temporary bucket files
+func (d *diskPartitioner) close() error {
+	return d.buckets.close()
+}
+
+// spillBuckets is a fixed number of temporary csv files, a record is written
+// into the bucket of the hash of its key so the records of the same key are
+// always read back together from the same bucket
+type spillBuckets struct {
+	dir        string
+	files      []*os.File
+	writers    []*bufio.Writer
+	csvWriters []*csv.Writer
+}
+
+func newSpillBuckets(tempDir, pattern string, totalBuckets int) (*spillBuckets, error) {
+	if totalBuckets <= 0 {
+		totalBuckets = 1
+	}
+	dir, err := os.MkdirTemp(tempDir, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &spillBuckets{
+		dir:        dir,
+		files:      make([]*os.File, totalBuckets),
+		writers:    make([]*bufio.Writer, totalBuckets),
+		csvWriters: make([]*csv.Writer, totalBuckets),
+	}
+	for i := range totalBuckets {
+		f, err := os.Create(filepath.Join(dir, fmt.Sprintf("bucket_%d.csv", i)))
+		if err != nil {
+			b.close()
+			return nil, err
+		}
+		b.files[i] = f
+		b.writers[i] = bufio.NewWriter(f)
+		b.csvWriters[i] = csv.NewWriter(b.writers[i])
+	}
+
+	return b, nil
+}
+
+func (b *spillBuckets) write(key string, record []string) error {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+
+	return b.writeBucket(int(h.Sum32()%uint32(len(b.csvWriters))), record)
+}
+
+// writeBucket write the record into the given bucket
+func (b *spillBuckets) writeBucket(bucket int, record []string) error {
+	return b.csvWriters[bucket].Write(record)
+}
+
+// readBucket flush the records written into the bucket and return the reader
+// of the bucket from its first record, the bucket must not be written again
+// once it is read
+func (b *spillBuckets) readBucket(bucket int) (*csv.Reader, error) {
+	b.csvWriters[bucket].Flush()
+	if err := b.csvWriters[bucket].Error(); err != nil {
+		return nil, err
+	}
+	if err := b.writers[bucket].Flush(); err != nil {
+		return nil, err
+	}
+	if _, err := b.files[bucket].Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	return csv.NewReader(bufio.NewReader(b.files[bucket])), nil
+}
+
+// forEachBucket call fn with the reader of every bucket one bucket at a time
+func (b *spillBuckets) forEachBucket(ctx context.Context, fn func(*csv.Reader) error) error {
+	for i := range b.files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r, err := b.readBucket(i)
+		if err != nil {
+			return err
+		}
+		if err := fn(r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// close remove the temporary bucket files
+func (b *spillBuckets) close() error {
+	for _, f := range b.files {
+		if f != nil {
+			f.Close()
+		}
+	}
+
+	return os.RemoveAll(b.dir)
+}
+
+func encodeRowTransaction(source, row int, trx *entity.Transaction) []string {
+	return []string{
+		strconv.Itoa(source),
+		strconv.Itoa(row),
+		trx.ID,
+		strconv.FormatFloat(trx.Amount, 'g', -1, 64),
+		string(trx.Type),
+		trx.Time.Format(time.RFC3339Nano),
+	}
+}
+
+func decodeRowTransaction(record []string) (source, row int, trx *entity.Transaction, err error) {
+	if source, err = strconv.Atoi(record[0]); err != nil {
+		return 0, 0, nil, err
+	}
+	if row, err = strconv.Atoi(record[1]); err != nil {
+		return 0, 0, nil, err
+	}
+	amount, err := strconv.ParseFloat(record[3], 64)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	trxTime, err := time.Parse(time.RFC3339Nano, record[5])
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	return source, row, &entity.Transaction{
+		ID:     record[2],
+		Amount: amount,
+		Type:   entity.TransactionType(record[4]),
+		Time:   trxTime,
+	}, nil
+}
+
+func forEachSortedPartition(ctx context.Context, partitions map[string]*partition, fn func(*partition) error) error {
+	dates := make([]string, 0, len(partitions))
+	for date := range partitions {
+		dates = append(dates, date)
+	}
+	slices.Sort(dates)
+
+	for _, date := range dates {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(partitions[date]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -4,23 +4,30 @@ import (
 	"context"
 	"database/sql"
 	"encoding/csv"
+	"errors"
+	"fmt"
 	"io"
+	"runtime"
 	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/delly/amartha/common"
 	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/config"
 	"github.com/delly/amartha/entity"
-	filestorage "github.com/delly/amartha/repository/file_storage"
 	dbgen "github.com/delly/amartha/repository/postgresql"
+	"github.com/jackc/pgx/v4"
 	"go.uber.org/zap"
 )
 
-type bankMappedTransactions struct {
-	bankName     string
-	transactions map[string][]*entity.Transaction
-}
+const (
+	// defaultLeaseDuration is the lease of a claimed job when it is not configured
+	defaultLeaseDuration = 2 * time.Minute
+	// defaultSaveTimeout is the timeout to save the outcome of a job when it is not configured
+	defaultSaveTimeout = time.Minute
+)
 
 // Processer is a contract to process pending reconciliation job
 type Processer interface {
@@ -31,12 +38,18 @@ type Processer interface {
 type ProcesserRepository interface {
 	ListPendingReconciliationJobs(ctx context.Context) ([]dbgen.ReconciliationJob, error)
 	SaveFailedReconciliationJob(ctx context.Context, arg dbgen.SaveFailedReconciliationJobParams) (dbgen.ReconciliationJob, error)
-	SaveSuccessReconciliationJob(ctx context.Context, arg dbgen.SaveSuccessReconciliationJobParams) (dbgen.ReconciliationJob, error)
+	SaveSuccessReconciliationJobWithItems(ctx context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error)
+	GetReconciliationJobStatus(ctx context.Context, id int64) (string, error)
+	StartReconciliationJob(ctx context.Context, arg dbgen.StartReconciliationJobParams) (dbgen.ReconciliationJob, error)
+	RenewReconciliationJobLease(ctx context.Context, arg dbgen.RenewReconciliationJobLeaseParams) (int64, error)
+	UpdateReconciliationJobProgress(ctx context.Context, arg dbgen.UpdateReconciliationJobProgressParams) (int64, error)
+	ListSuccessReconciliationJobsByEndDate(ctx context.Context, endDate time.Time) ([]dbgen.ReconciliationJob, error)
+	ListOpenReconciliationItems(ctx context.Context, jobID int64) ([]dbgen.ReconciliationItem, error)
 }
 
-// FileGetter is a dependency of repository that needed to get file from storage
+// FileGetter is a dependency of repository that needed to get file stream from storage
 type FileGetter interface {
-	Get(ctx context.Context, filePath string) (*filestorage.File, error)
+	Get(ctx context.Context, filePath string) (io.ReadCloser, error)
 }
 
 // ProcesserService is an implementation of Processer to process
@@ -44,21 +57,25 @@ type FileGetter interface {
 type ProcesserService struct {
 	repo    ProcesserRepository
 	storage FileGetter
+	cfg     config.ProcesserConfig
 	log     *zap.Logger
 }
 
 var _ = Processer(&ProcesserService{})
 
 // NewProcesserService create new processer service
-func NewProcesserService(repo ProcesserRepository, storage FileGetter) *ProcesserService {
+func NewProcesserService(repo ProcesserRepository, storage FileGetter, cfg config.ProcesserConfig) *ProcesserService {
 	return &ProcesserService{
 		repo:    repo,
 		storage: storage,
+		cfg:     cfg,
 		log:     zap.L().With(zap.String("service", "reconciliation_job.processer")),
 	}
 }
 
-// Process process pending reconciliation job
+// Process process pending reconciliation job, jobs are processed concurrently
+// by a bounded pool of workers and every error that prevents a job outcome
+// from being persisted is aggregated and returned to the caller
 func (s *ProcesserService) Process(ctx context.Context) error {
 	log := logger.WithMethod(s.log, "Process")
 	jobs, err := s.getPendingReconciliationJobs(ctx)
@@ -72,43 +89,340 @@ func (s *ProcesserService) Process(ctx context.Context) error {
 		return nil
 	}
 
+	jobCh := make(chan *entity.ReconciliationJob)
+	errCh := make(chan error, len(jobs)+1)
+	wg := sync.WaitGroup{}
+	for i := 0; i < s.workerSize(len(jobs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobCh {
+				if err := s.runReconciliationJob(ctx, job); err != nil {
+					errCh <- err
+				}
+			}
+		}()
+	}
+
+dispatch:
 	for _, job := range jobs {
-		log.Info("processing reconciliation job", zap.Int64("job_id", job.ID))
-		if err = s.processReconciliationJob(ctx, job); err != nil {
+		select {
+		case <-ctx.Done():
+			log.Warn("stop dispatching reconciliation jobs", zap.Error(ctx.Err()))
+			errCh <- ctx.Err()
+			break dispatch
+		case jobCh <- job:
+		}
+	}
+	close(jobCh)
+	wg.Wait()
+	close(errCh)
+
+	errs := []error{}
+	for err := range errCh {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ProcesserService) runReconciliationJob(ctx context.Context, job *entity.ReconciliationJob) error {
+	log := logger.WithMethod(s.log, "runReconciliationJob")
+	claimToken, claimed, err := s.claimReconciliationJob(ctx, job)
+	if err != nil {
+		log.Error("failed to claim reconciliation job", zap.Error(err), zap.Int64("job_id", job.ID))
+		return errClaimJob(job.ID, err)
+	}
+	if !claimed {
+		log.Info("reconciliation job already claimed", zap.Int64("job_id", job.ID))
+		return nil
+	}
+
+	timeoutCtx, cancelTimeout := s.newJobContext(ctx)
+	defer cancelTimeout()
+	jobCtx, cancelJob := context.WithCancelCause(timeoutCtx)
+	defer cancelJob(nil)
+	go s.watchCancellation(jobCtx, job, cancelJob)
+	go s.renewLease(jobCtx, job, claimToken, cancelJob)
+	progress := newProgressTracker()
+	go s.reportProgress(jobCtx, job, claimToken, progress, cancelJob)
+
+	log.Info("processing reconciliation job", zap.Int64("job_id", job.ID))
+	collector, err := s.processReconciliationJob(jobCtx, job, progress)
+	if collector != nil {
+		defer collector.close()
+	}
+	if err != nil {
+		cause := context.Cause(jobCtx)
+		switch {
+		case errors.Is(cause, errJobCancelled):
+			log.Info("reconciliation job cancelled", zap.Int64("job_id", job.ID))
+			return nil
+		case errors.Is(cause, errJobLeaseLost):
+			log.Warn("reconciliation job lease lost, result discarded", zap.Int64("job_id", job.ID))
+			return nil
+		case errors.Is(cause, errJobTimedOut):
+			job.ErrorCode = entity.ReconciliationJobErrorCodeTimeout
+			err = cause
+		case ctx.Err() != nil:
+			// the runner is shutting down, the job is left processing so it is
+			// claimed again by the next run once its lease expires
+			log.Warn("reconciliation job interrupted by shutdown, left unsaved", zap.Error(ctx.Err()), zap.Int64("job_id", job.ID))
+			return nil
+		default:
+			job.ErrorCode = entity.ReconciliationJobErrorCodeProcessFailed
+		}
+		job.Status = entity.ReconciliationJobStatusFailed
+		job.ErrorInformation = err.Error()
+	}
+	progress.setPhase(entity.ReconciliationJobPhaseSaving)
+	// the outcome is saved even when the runner is shutting down, otherwise
+	// the job would be processed again from scratch once its lease expires
+	saveCtx, cancelSave := context.WithTimeout(context.WithoutCancel(ctx), s.saveTimeout())
+	defer cancelSave()
+	if job.Status == entity.ReconciliationJobStatusSuccess {
+		err = s.saveSuccessJob(saveCtx, job, claimToken, collector)
+		// the period is locked by an approved job while the job is processed,
+		// so the result would change an approved period and is not kept
+		if err = convertPeriodLockedError(err); errors.Is(err, ErrPeriodLocked) {
+			log.Warn("reconciliation job period locked, result discarded", zap.Error(err), zap.Int64("job_id", job.ID))
 			job.Status = entity.ReconciliationJobStatusFailed
+			job.ErrorCode = entity.ReconciliationJobErrorCodePeriodLocked
 			job.ErrorInformation = err.Error()
 		}
-		if job.Status == entity.ReconciliationJobStatusFailed {
-			if s.saveFailedJob(ctx, job); err != nil {
-				log.Error("failed to update job status to failed", zap.Error(err), zap.Int64("job_id", job.ID))
+	}
+	if job.Status == entity.ReconciliationJobStatusFailed {
+		err = s.saveFailedJob(saveCtx, job, claimToken)
+	}
+	if err != nil {
+		// the job is no longer processing when it is cancelled in the middle of
+		// the process, or no longer under the claim when its lease expired and
+		// it is claimed again by another runner, so the result is discarded to
+		// keep the outcome of the other runner
+		if errors.Is(err, pgx.ErrNoRows) {
+			log.Info("reconciliation job is no longer processing under the claim, result discarded", zap.Int64("job_id", job.ID))
+			return nil
+		}
+		log.Error("failed to update job status", zap.Error(err), zap.Int64("job_id", job.ID), zap.String("status", string(job.Status)))
+		return errSaveJob(job.ID, err)
+	}
+	log.Info("reconciliation job processed", zap.Int64("job_id", job.ID))
+
+	return nil
+}
+
+// watchCancellation periodically check the job status and cancel the job
+// context with errJobCancelled once the job is cancelled
+func (s *ProcesserService) watchCancellation(ctx context.Context, job *entity.ReconciliationJob, cancel context.CancelCauseFunc) {
+	if s.cfg.CancelCheckInterval <= 0 {
+		return
+	}
+	log := logger.WithMethod(s.log, "watchCancellation")
+	ticker := time.NewTicker(s.cfg.CancelCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			status, err := s.repo.GetReconciliationJobStatus(ctx, job.ID)
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Warn("failed to get reconciliation job status", zap.Error(err), zap.Int64("job_id", job.ID))
+				}
+				continue
 			}
-		} else if job.Status == entity.ReconciliationJobStatusSuccess {
-			if err = s.saveSuccessJob(ctx, job); err != nil {
-				log.Error("failed to update job status to success", zap.Error(err), zap.Int64("job_id", job.ID))
+			if entity.ReconciliationJobStatus(status) == entity.ReconciliationJobStatusCancelled {
+				cancel(errJobCancelled)
+				return
 			}
 		}
-		log.Info("reconciliation job processed", zap.Int64("job_id", job.ID))
 	}
+}
 
-	return nil
+// renewLease periodically extend the lease of the claimed job so it is not
+// reclaimed by another runner, the job context is cancelled with
+// errJobLeaseLost once the job is no longer processing under the claim
+func (s *ProcesserService) renewLease(ctx context.Context, job *entity.ReconciliationJob, claimToken int64, cancel context.CancelCauseFunc) {
+	log := logger.WithMethod(s.log, "renewLease")
+	lease := s.leaseDuration()
+	ticker := time.NewTicker(lease / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewed, err := s.repo.RenewReconciliationJobLease(ctx, dbgen.RenewReconciliationJobLeaseParams{
+				ID:           job.ID,
+				LeaseSeconds: lease.Seconds(),
+				ClaimToken:   claimToken,
+			})
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Warn("failed to renew reconciliation job lease", zap.Error(err), zap.Int64("job_id", job.ID))
+				}
+				continue
+			}
+			if renewed == 0 {
+				cancel(errJobLeaseLost)
+				return
+			}
+		}
+	}
 }
 
-func (s *ProcesserService) saveSuccessJob(ctx context.Context, job *entity.ReconciliationJob) error {
-	params := dbgen.SaveSuccessReconciliationJobParams{
-		ID: job.ID,
+// reportProgress periodically persist the progress of the job, and right away
+// when the job moves to another phase, until the job context is done, the job
+// context is cancelled with errJobLeaseLost once the job is no longer
+// processing under the claim
+func (s *ProcesserService) reportProgress(ctx context.Context, job *entity.ReconciliationJob, claimToken int64, progress *progressTracker, cancel context.CancelCauseFunc) {
+	if s.cfg.ProgressInterval <= 0 {
+		return
 	}
-	params.Result.Set(job.Result)
-	if _, err := s.repo.SaveSuccessReconciliationJob(ctx, params); err != nil {
+	log := logger.WithMethod(s.log, "reportProgress")
+	ticker := time.NewTicker(s.cfg.ProgressInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-progress.phaseChanged:
+		case <-ticker.C:
+		}
+
+		snapshot, changed := progress.snapshot()
+		if !changed {
+			continue
+		}
+		params := dbgen.UpdateReconciliationJobProgressParams{
+			ID:         job.ID,
+			ClaimToken: claimToken,
+		}
+		params.Progress.Set(snapshot)
+		updated, err := s.repo.UpdateReconciliationJobProgress(ctx, params)
+		if err != nil {
+			if ctx.Err() == nil {
+				log.Warn("failed to update reconciliation job progress", zap.Error(err), zap.Int64("job_id", job.ID))
+			}
+			continue
+		}
+		if updated == 0 {
+			cancel(errJobLeaseLost)
+			return
+		}
+	}
+}
+
+// claimReconciliationJob mark the job as processing with a lease, so it would
+// not be picked up by another worker or runner until the lease expires, a
+// processing job whose lease has expired is claimed again since its runner is
+// gone, it returns false when the job is no longer claimable, the returned
+// claim token is required to renew, report and save the job so a runner
+// whose job is claimed again can no longer change it
+func (s *ProcesserService) claimReconciliationJob(ctx context.Context, job *entity.ReconciliationJob) (int64, bool, error) {
+	claimed, err := s.repo.StartReconciliationJob(ctx, dbgen.StartReconciliationJobParams{
+		ID:           job.ID,
+		LeaseSeconds: s.leaseDuration().Seconds(),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, nil
+		}
+		return 0, false, err
+	}
+	job.Status = entity.ReconciliationJobStatusProcessing
+
+	return claimed.ClaimToken, true, nil
+}
+
+// newJobContext create context for a single job, the context is cancelled
+// once the job is done or when it exceeds the configured job timeout, in which
+// case its cause is errJobTimedOut
+func (s *ProcesserService) newJobContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.cfg.JobTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeoutCause(ctx, s.cfg.JobTimeout, errJobTimeout(s.cfg.JobTimeout))
+}
+
+func (s *ProcesserService) leaseDuration() time.Duration {
+	if s.cfg.LeaseDuration <= 0 {
+		return defaultLeaseDuration
+	}
+
+	return s.cfg.LeaseDuration
+}
+
+func (s *ProcesserService) saveTimeout() time.Duration {
+	if s.cfg.SaveTimeout <= 0 {
+		return defaultSaveTimeout
+	}
+
+	return s.cfg.SaveTimeout
+}
+
+func (s *ProcesserService) workerSize(totalJobs int) int {
+	size := s.cfg.WorkerSize
+	if size <= 0 {
+		size = 1
+	}
+	if size > totalJobs {
+		size = totalJobs
+	}
+
+	return size
+}
+
+// saveSuccessJob save the result summary of the job along with its items and
+// matches, the items and the matches are generated from the collector while
+// they are copied, the stored summary only holds the totals of the job and
+// the carried items matched by the job are marked as resolved
+func (s *ProcesserService) saveSuccessJob(ctx context.Context, job *entity.ReconciliationJob, claimToken int64, collector *resultCollector) error {
+	params := dbgen.SaveSuccessReconciliationJobWithItemsParams{
+		SaveSuccessReconciliationJobParams: dbgen.SaveSuccessReconciliationJobParams{
+			ID:         job.ID,
+			ClaimToken: claimToken,
+		},
+		Items: func(yield func(dbgen.CopyReconciliationItemsParams, error) bool) {
+			for item, err := range collector.items(ctx) {
+				if !yield(convertToDbReconciliationItem(job.ID, item), err) || err != nil {
+					return
+				}
+			}
+		},
+		Matches: func(yield func(dbgen.CopyReconciliationMatchesParams, error) bool) {
+			for match, err := range collector.matchedPairs(ctx) {
+				if !yield(convertToDbReconciliationMatch(job.ID, match), err) || err != nil {
+					return
+				}
+			}
+		},
+		ResolvedItemIDs: collector.resolvedIDs(),
+		Period: &dbgen.ListOverlappingReconciliationPeriodLocksParams{
+			BankNames: jobBankNames(job),
+			StartDate: job.StartDate,
+			EndDate:   job.EndDate,
+		},
+	}
+	params.Result.Set(summaryResult(job.Result))
+	if _, err := s.repo.SaveSuccessReconciliationJobWithItems(ctx, params); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (s *ProcesserService) saveFailedJob(ctx context.Context, job *entity.ReconciliationJob) error {
+func (s *ProcesserService) saveFailedJob(ctx context.Context, job *entity.ReconciliationJob, claimToken int64) error {
 	if _, err := s.repo.SaveFailedReconciliationJob(ctx, dbgen.SaveFailedReconciliationJobParams{
 		ID:               job.ID,
 		ErrorInformation: sql.NullString{String: job.ErrorInformation, Valid: true},
+		ErrorCode:        sql.NullString{String: string(job.ErrorCode), Valid: true},
+		ClaimToken:       claimToken,
 	}); err != nil {
 		return err
 	}
@@ -116,18 +430,34 @@ func (s *ProcesserService) saveFailedJob(ctx context.Context, job *entity.Reconc
 	return nil
 }
 
-func (s *ProcesserService) processReconciliationJob(ctx context.Context, job *entity.ReconciliationJob) error {
+// processReconciliationJob reconcile the job and return the collector of its
+// result, the items and the matches of the job are generated from it, the
+// collector must be closed once the job is saved
+func (s *ProcesserService) processReconciliationJob(ctx context.Context, job *entity.ReconciliationJob, progress *progressTracker) (_ *resultCollector, err error) {
 	log := logger.WithMethod(s.log, "processReconciliationJob")
-	systemTrxFile, bankFiles, err := s.getCSVFiles(ctx, job)
+	partitioner, err := s.newPartitioner(job)
 	if err != nil {
-		log.Error("failed to get csv files", zap.Error(err), zap.Int64("job_id", job.ID))
-		return err
+		log.Error("failed to create partitioner", zap.Error(err), zap.Int64("job_id", job.ID))
+		return nil, err
 	}
+	defer partitioner.close()
+	keys, err := s.newKeyIndex(job)
+	if err != nil {
+		log.Error("failed to create duplicate key index", zap.Error(err), zap.Int64("job_id", job.ID))
+		return nil, err
+	}
+	defer keys.close()
 
+	progress.setPhase(entity.ReconciliationJobPhaseDownloading)
 	startDateTime := common.StartOfDay(job.StartDate)
 	endDateTime := common.EndOfDay(job.EndDate)
-	systemTrxs := []*entity.Transaction{}
-	if err = s.readCSVFile(systemTrxFile, func(record []string) error {
+	// lastRows is the last row of every file keyed by source, the carried
+	// transactions are numbered after it
+	lastRows := map[int]int{}
+	totalSystemTrxs, row := 0, 0
+	if err := s.readStoredCSVFile(ctx, job.SystemTransactionCsvPath, progress, func(record []string) error {
+		row++
+		progress.addSystemRowParsed()
 		trx, err := s.convertSystemTransactionRecordToTransaction(record)
 		if err != nil {
 			log.Error("failed to convert system transaction record to transaction", zap.Error(err), zap.Strings("record", record))
@@ -137,16 +467,22 @@ func (s *ProcesserService) processReconciliationJob(ctx context.Context, job *en
 		if notInRange {
 			return nil
 		}
-		systemTrxs = append(systemTrxs, trx)
-		return nil
+		totalSystemTrxs++
+		if err := keys.add(systemSource, row, trx); err != nil {
+			return err
+		}
+		return partitioner.add(systemSource, row, trx)
 	}); err != nil {
-		return err
+		log.Error("failed to read system transaction csv", zap.Error(err), zap.Int64("job_id", job.ID))
+		return nil, err
 	}
+	lastRows[systemSource] = row
 
-	bankTrxs := []*bankMappedTransactions{}
-	for bankName, file := range bankFiles {
-		mapTrxs := map[string][]*entity.Transaction{}
-		if err = s.readCSVFile(file, func(record []string) error {
+	for bankIdx, bankFile := range job.BankTransactionCsvPaths {
+		row := 0
+		if err := s.readStoredCSVFile(ctx, bankFile.FilePath, progress, func(record []string) error {
+			row++
+			progress.addBankRowParsed(bankFile.BankName)
 			trx, err := s.convertBankTransactionRecordToTransaction(record)
 			if err != nil {
 				log.Error("failed to convert bank transaction record to transaction", zap.Error(err), zap.Strings("record", record))
@@ -156,100 +492,355 @@ func (s *ProcesserService) processReconciliationJob(ctx context.Context, job *en
 			if notInRange {
 				return nil
 			}
-			date := trx.Time.Format(time.DateOnly)
-			if _, ok := mapTrxs[date]; !ok {
-				mapTrxs[date] = []*entity.Transaction{}
+			if err := keys.add(bankIdx, row, trx); err != nil {
+				return err
 			}
-			mapTrxs[date] = append(mapTrxs[date], trx)
-			return nil
+			return partitioner.add(bankIdx, row, trx)
 		}); err != nil {
-			return err
+			log.Error("failed to read bank transaction csv", zap.Error(err), zap.Int64("job_id", job.ID), zap.String("bank_name", bankFile.BankName))
+			return nil, err
+		}
+		lastRows[bankIdx] = row
+	}
+
+	collector, err := s.newResultCollector(job, lastRows)
+	if err != nil {
+		log.Error("failed to create result collector", zap.Error(err), zap.Int64("job_id", job.ID))
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			collector.close()
+		}
+	}()
+
+	excluded, err := detectDuplicates(ctx, job, keys, collector)
+	if err != nil {
+		log.Error("failed to detect duplicate transactions", zap.Error(err), zap.Int64("job_id", job.ID))
+		return nil, err
+	}
+	for r := range excluded {
+		if r.source == systemSource {
+			totalSystemTrxs--
 		}
-		bankTrxs = append(bankTrxs, &bankMappedTransactions{
-			bankName:     bankName,
-			transactions: mapTrxs,
-		})
 	}
+	// the keys are no longer needed while matching
+	keys.close()
 
-	result := s.processReconciliation(job, systemTrxs, bankTrxs)
+	var carried *carryForward
+	if job.MatchingOptions.CarryForward {
+		carried, err = s.loadCarryForward(ctx, job, lastRows)
+		if err != nil {
+			log.Error("failed to load carried reconciliation items", zap.Error(err), zap.Int64("job_id", job.ID))
+			return nil, err
+		}
+		totalSystemTrxs += len(carried.trxs.systemTrxs)
+		collector.setCarryForward(carried)
+	}
+
+	progress.setPhase(entity.ReconciliationJobPhaseMatching)
+	progress.setTotalTransactions(totalSystemTrxs)
+	result, err := s.processReconciliation(ctx, job, partitioner, collector, excluded, carried, progress)
+	if err != nil {
+		return nil, err
+	}
 	job.Result = result
 	job.Status = entity.ReconciliationJobStatusSuccess
 
-	return nil
+	return collector, nil
+}
+
+// newKeyIndex create index of the duplicate key of the job transactions, on
+// out of core mode the keys are spilled into temporary files on disk like the
+// partitions, so the duplicate and overlap detection is not bounded by the
+// available memory either
+func (s *ProcesserService) newKeyIndex(job *entity.ReconciliationJob) (keyIndex, error) {
+	if !s.cfg.OutOfCore {
+		return newMemoryKeyIndex(job.MatchingOptions.DuplicateRule), nil
+	}
+
+	return newDiskKeyIndex(job.MatchingOptions.DuplicateRule,
+		s.cfg.TempDir,
+		fmt.Sprintf("reconciliation_job_%d_keys_*", job.ID),
+		s.cfg.PartitionCount)
 }
 
-func (s *ProcesserService) processReconciliation(job *entity.ReconciliationJob,
-	systemTrxs []*entity.Transaction,
-	bankTrxs []*bankMappedTransactions,
-) *entity.ReconciliationResult {
-	result := &entity.ReconciliationResult{
-		TotalTransactionProcessed: 0,
-		TotalTransactionMatched:   0,
-		TotalTransactionUnmatched: 0,
-		TotalDiscrepancyAmount:    0,
-		MissingTransactions:       []entity.Transaction{},
-		MissingBankTransactions:   map[string][]entity.Transaction{},
-	}
-
-	for _, trx := range systemTrxs {
-		var found bool
-		var bankTrxIdx int
-		date := trx.Time.Format(time.DateOnly)
-		result.TotalTransactionProcessed++
-		for _, bankTrx := range bankTrxs {
-			if bankTrxTrxs, ok := bankTrx.transactions[date]; ok {
-				for idx, bankTrx := range bankTrxTrxs {
-					discrepancyThreshold := float64(job.DiscrepancyThreshold) * trx.Amount
-					minDiscrepancy := trx.Amount - discrepancyThreshold
-					maxDiscrepancy := trx.Amount + discrepancyThreshold
-					bankAmountInThreshold := bankTrx.Amount >= minDiscrepancy && bankTrx.Amount <= maxDiscrepancy
-					sameTrxType := trx.Type == bankTrx.Type
-					if bankAmountInThreshold && sameTrxType {
-						bankTrxIdx = idx
-						found = true
-						break
-					}
+// newResultCollector create the collector of the job result, on out of core
+// mode the lists of the result are spilled into temporary files on disk like
+// the partitions and only their totals are kept in memory, so the result is
+// not bounded by the available memory either
+func (s *ProcesserService) newResultCollector(job *entity.ReconciliationJob, lastRows map[int]int) (*resultCollector, error) {
+	totalBuckets := 1
+	if s.cfg.OutOfCore {
+		totalBuckets = max(s.cfg.PartitionCount, 1)
+	}
+	collector := newResultCollector(job, lastRows, totalBuckets, resultSpills{})
+	var err error
+	if collector.pairs, err = newResultSpill(s.cfg, job.ID, "pairs", totalBuckets, pairRecordCodec); err != nil {
+		return nil, errors.Join(err, collector.close())
+	}
+	if collector.missing, err = newResultSpill(s.cfg, job.ID, "missing", totalBuckets, sourceTransactionCodec); err != nil {
+		return nil, errors.Join(err, collector.close())
+	}
+	if collector.records, err = newResultSpill(s.cfg, job.ID, "records", totalBuckets, itemRecordCodec); err != nil {
+		return nil, errors.Join(err, collector.close())
+	}
+	if collector.duplicates, err = newResultSpill(s.cfg, job.ID, "duplicates", totalBuckets, duplicateRecordCodec); err != nil {
+		return nil, errors.Join(err, collector.close())
+	}
+	if collector.overlaps, err = newResultSpill(s.cfg, job.ID, "overlaps", totalBuckets, overlapRecordCodec); err != nil {
+		return nil, errors.Join(err, collector.close())
+	}
+	if collector.findings, err = newResultSpill(s.cfg, job.ID, "findings", totalBuckets, findingRecordCodec); err != nil {
+		return nil, errors.Join(err, collector.close())
+	}
+
+	return collector, nil
+}
+
+// newResultSpill create the spill of a list of the job result, name is the
+// name of the list in the pattern of its temporary directory
+func newResultSpill[T any](cfg config.ProcesserConfig, jobID int64, name string, totalBuckets int, codec spillCodec[T]) (resultSpill[T], error) {
+	if !cfg.OutOfCore {
+		return newMemorySpill[T](totalBuckets), nil
+	}
+	spill, err := newDiskSpill(cfg.TempDir, fmt.Sprintf("reconciliation_job_%d_%s_*", jobID, name), totalBuckets, codec)
+	if err != nil {
+		return nil, err
+	}
+
+	return spill, nil
+}
+
+// newPartitioner create partitioner of the job transactions, on out of core
+// mode the transactions are partitioned into temporary files on disk instead
+// of memory, so the job size is not bounded by the available memory
+func (s *ProcesserService) newPartitioner(job *entity.ReconciliationJob) (partitioner, error) {
+	if !s.cfg.OutOfCore {
+		return newMemoryPartitioner(len(job.BankTransactionCsvPaths)), nil
+	}
+
+	return newDiskPartitioner(s.cfg.TempDir,
+		fmt.Sprintf("reconciliation_job_%d_*", job.ID),
+		s.cfg.PartitionCount,
+		len(job.BankTransactionCsvPaths))
+}
+
+// processReconciliation reconcile the partitions concurrently using a pool of
+// match workers, the result is merged deterministically regardless of the
+// order the partitions are reconciled, the carried transactions are matched
+// once every partition is reconciled
+func (s *ProcesserService) processReconciliation(ctx context.Context,
+	job *entity.ReconciliationJob,
+	partitioner partitioner,
+	collector *resultCollector,
+	excluded map[fileRow]bool,
+	carried *carryForward,
+	progress *progressTracker,
+) (*entity.ReconciliationResult, error) {
+	matchCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	partitionCh := make(chan *partition)
+	wg := sync.WaitGroup{}
+	for i := 0; i < s.matchWorkerSize(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range partitionCh {
+				res, err := s.reconcilePartition(matchCtx, job, p)
+				if err != nil {
+					cancel(err)
+					continue
 				}
-				if found {
-					result.TotalTransactionMatched++
-					// remove matched bank transaction, so it won't be processed again
-					// and we can track missing bank transactions
-					bankTrx.transactions[date] = slices.Delete(bankTrx.transactions[date], bankTrxIdx, bankTrxIdx+1)
-					break
+				progress.addTransactionsProcessed(res.totalProcessed, res.totalMatched)
+				if err := collector.add(res); err != nil {
+					cancel(err)
 				}
 			}
+		}()
+	}
+
+	err := partitioner.forEachPartition(matchCtx, func(p *partition) error {
+		p.exclude(excluded)
+		select {
+		case <-matchCtx.Done():
+			return matchCtx.Err()
+		case partitionCh <- p:
+			return nil
 		}
-		if !found {
-			// add missing system transaction to result
-			result.MissingTransactions = append(result.MissingTransactions, *trx)
-			result.TotalDiscrepancyAmount += trx.Amount
-			result.TotalTransactionUnmatched++
-			continue
+	})
+	close(partitionCh)
+	wg.Wait()
+	if cause := context.Cause(matchCtx); cause != nil {
+		return nil, cause
+	}
+	if err != nil {
+		return nil, err
+	}
+	if carried != nil {
+		matched, err := collector.matchCarried(func(p *partition) (*partitionResult, error) {
+			return s.reconcilePartition(ctx, job, p)
+		})
+		if err != nil {
+			return nil, err
 		}
+		progress.addTransactionsProcessed(len(carried.trxs.systemTrxs), matched)
+	}
+
+	return collector.result(ctx)
+}
+
+func (s *ProcesserService) matchWorkerSize() int {
+	if s.cfg.MatchWorkerSize <= 0 {
+		return runtime.NumCPU()
+	}
+
+	return s.cfg.MatchWorkerSize
+}
+
+// matchCandidate is a bank transaction a system transaction could be matched with
+type matchCandidate struct {
+	bankIdx        int
+	idx            int
+	expectedAmount float64
+	tolerance      float64
+	fee            float64
+	hasFeeRule     bool
+}
+
+// reconcilePartition match system transactions of a partition against its
+// bank transactions, a bank transaction can only be matched once, the system
+// transaction is matched with the candidate of the highest confidence and the
+// match below the minimum confidence needs review instead of being matched
+func (s *ProcesserService) reconcilePartition(ctx context.Context,
+	job *entity.ReconciliationJob,
+	p *partition,
+) (*partitionResult, error) {
+	res := &partitionResult{
+		totalProcessed: len(p.systemTrxs),
 	}
 
-	for _, bankTrx := range bankTrxs {
-		for _, trxs := range bankTrx.transactions {
-			for _, trx := range trxs {
-				result.MissingBankTransactions[bankTrx.bankName] = append(result.MissingBankTransactions[bankTrx.bankName], *trx)
-				result.TotalDiscrepancyAmount += trx.Amount
+	index := newAmountIndex(p.bankTrxs)
+	for _, trx := range p.systemTrxs {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		candidates := s.findMatchCandidates(job, p, index, trx.trx)
+		if len(candidates) == 0 {
+			res.missingTrxs = append(res.missingTrxs, trx)
+			continue
+		}
+
+		var (
+			best       matchCandidate
+			confidence entity.MatchConfidence
+		)
+		for i, candidate := range candidates {
+			bankTrx := p.bankTrxs[candidate.bankIdx][candidate.idx]
+			c := scoreMatch(trx.trx, bankTrx.trx, candidate.expectedAmount, candidate.tolerance, len(candidates))
+			if i == 0 || c.Score > confidence.Score {
+				best, confidence = candidate, c
 			}
 		}
+		bankTrx := p.bankTrxs[best.bankIdx][best.idx]
+		// mark matched bank transaction, so it won't be processed again
+		// and we can track missing bank transactions
+		index.markMatched(best.bankIdx, best.idx)
+		match := matchedPair{
+			system:      trx,
+			bankIdx:     best.bankIdx,
+			bank:        bankTrx,
+			expectedFee: best.fee,
+			hasFeeRule:  best.hasFeeRule,
+			confidence:  confidence,
+		}
+		if confidence.Score < job.MatchingOptions.MinConfidence {
+			res.reviews = append(res.reviews, match)
+			continue
+		}
+		res.matches = append(res.matches, match)
+		res.totalMatched++
 	}
+	p.bankTrxs = index.unmatched(p.bankTrxs)
+	res.missingBankTrxs = p.bankTrxs
 
-	return result
+	return res, nil
 }
 
-func (s *ProcesserService) readCSVFile(
-	file *filestorage.File,
+// findMatchCandidates find the unmatched bank transactions of the partition
+// with the same type and an amount within the discrepancy threshold of the
+// expected bank amount, ordered by bank file and then by position in the file,
+// only the bank transactions within the amount range are visited
+func (s *ProcesserService) findMatchCandidates(job *entity.ReconciliationJob, p *partition, index *amountIndex, trx *entity.Transaction) []matchCandidate {
+	candidates := []matchCandidate{}
+	for bankIdx, bankTrxs := range p.bankTrxs {
+		expectedAmount, fee, hasFeeRule := s.expectedBankAmount(job, bankIdx, trx)
+		discrepancyThreshold := float64(job.DiscrepancyThreshold) * expectedAmount
+		minDiscrepancy := expectedAmount - discrepancyThreshold
+		maxDiscrepancy := expectedAmount + discrepancyThreshold
+		start := len(candidates)
+		index.forEachInRange(bankIdx, minDiscrepancy, maxDiscrepancy, func(idx int) {
+			if trx.Type != bankTrxs[idx].trx.Type {
+				return
+			}
+			candidates = append(candidates, matchCandidate{
+				bankIdx:        bankIdx,
+				idx:            idx,
+				expectedAmount: expectedAmount,
+				tolerance:      discrepancyThreshold,
+				fee:            fee,
+				hasFeeRule:     hasFeeRule,
+			})
+		})
+		slices.SortFunc(candidates[start:], func(a, b matchCandidate) int {
+			return a.idx - b.idx
+		})
+	}
+
+	return candidates
+}
+
+// expectedBankAmount compute the amount expected in the bank file for the
+// system transaction, bank credits are net of the fee of the bank fee rule
+func (s *ProcesserService) expectedBankAmount(job *entity.ReconciliationJob, bankIdx int, trx *entity.Transaction) (amount, fee float64, hasFeeRule bool) {
+	rule, ok := findFeeRule(&job.MatchingOptions, job.BankTransactionCsvPaths[bankIdx].BankName)
+	if !ok || trx.Type != entity.TxTypeCredit {
+		return trx.Amount, 0, false
+	}
+	fee = bankFee(rule, trx.Amount)
+
+	return trx.Amount - fee, fee, true
+}
+
+// readStoredCSVFile open the file stream from storage and parse it record by
+// record, so only a single record of the file is held in memory at a time
+func (s *ProcesserService) readStoredCSVFile(
+	ctx context.Context,
+	filePath string,
+	progress *progressTracker,
 	callback func([]string) error,
 ) error {
-	if file.Buf == nil {
-		return errEmptyBuffer(file.Name)
+	file, err := s.storage.Get(ctx, filePath)
+	if err != nil {
+		return err
 	}
+	defer file.Close()
+	progress.setPhase(entity.ReconciliationJobPhaseParsing)
 
-	csvReader := csv.NewReader(file.Buf)
+	return s.readCSVFile(ctx, file, callback)
+}
+
+func (s *ProcesserService) readCSVFile(
+	ctx context.Context,
+	file io.Reader,
+	callback func([]string) error,
+) error {
+	csvReader := csv.NewReader(file)
+	csvReader.ReuseRecord = true
 	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
 		record, err := csvReader.Read()
 		if err != nil {
 			if err == io.EOF {
@@ -315,24 +906,6 @@ func (s *ProcesserService) convertBankTransactionRecordToTransaction(record []st
 	}, nil
 }
 
-func (s *ProcesserService) getCSVFiles(ctx context.Context, job *entity.ReconciliationJob) (systemTrxFile *filestorage.File, bankFiles map[string]*filestorage.File, err error) {
-	systemTrxFile, err = s.storage.Get(ctx, job.SystemTransactionCsvPath)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	bankFiles = map[string]*filestorage.File{}
-	for _, bankFile := range job.BankTransactionCsvPaths {
-		file, err := s.storage.Get(ctx, bankFile.FilePath)
-		if err != nil {
-			return nil, nil, err
-		}
-		bankFiles[bankFile.BankName] = file
-	}
-
-	return systemTrxFile, bankFiles, nil
-}
-
 func (s *ProcesserService) getPendingReconciliationJobs(ctx context.Context) ([]*entity.ReconciliationJob, error) {
 	jobs, err := s.repo.ListPendingReconciliationJobs(ctx)
 	if err != nil {
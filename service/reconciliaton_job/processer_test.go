@@ -3,15 +3,27 @@ package reconciliatonjob_test
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
 	"os"
+	"runtime"
+	"slices"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
+	"github.com/delly/amartha/config"
 	"github.com/delly/amartha/entity"
-	filestorage "github.com/delly/amartha/repository/file_storage"
 	dbgen "github.com/delly/amartha/repository/postgresql"
 	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
 	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 	"go.uber.org/mock/gomock"
@@ -29,7 +41,9 @@ func (s *ReconciliationJobProcessorTestSuite) SetupTest() {
 	ctrl := gomock.NewController(s.T())
 	s.mockRepo = mock_reconciliatonjob.NewMockProcesserRepository(ctrl)
 	s.mockFileGetter = mock_reconciliatonjob.NewMockFileGetter(ctrl)
-	s.svc = reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter)
+	s.svc = reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+		WorkerSize: 2,
+	})
 }
 
 func TestReconciliationJobProcessorTestSuite(t *testing.T) {
@@ -47,11 +61,60 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 		s.Error(err)
 	})
 
+	s.Run("error claim reconciliation job", func() {
+		rj := dbReconJob
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		err := s.svc.Process(ctx)
+
+		s.ErrorIs(err, assert.AnError)
+	})
+
+	s.Run("error save failed reconciliation job", func() {
+		rj := dbReconJob
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(nil, assert.AnError)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		err := s.svc.Process(ctx)
+
+		s.ErrorIs(err, assert.AnError)
+	})
+
+	s.Run("error aggregated from multiple jobs", func() {
+		rj1 := dbReconJob
+		rj2 := dbReconJob
+		rj2.ID = 2
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj1, rj2}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj1.ID)).Return(dbgen.ReconciliationJob{}, assert.AnError)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj2.ID)).Return(dbgen.ReconciliationJob{}, assert.AnError)
+
+		err := s.svc.Process(ctx)
+
+		s.ErrorContains(err, "failed to claim reconciliation job 1")
+		s.ErrorContains(err, "failed to claim reconciliation job 2")
+	})
+
+	s.Run("error context cancelled before dispatching jobs", func() {
+		cancelledCtx, cancel := context.WithCancel(ctx)
+		cancel()
+		rj := dbReconJob
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(cancelledCtx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(cancelledCtx, claimParams(rj.ID)).Return(dbgen.ReconciliationJob{}, context.Canceled).MaxTimes(1)
+
+		err := s.svc.Process(cancelledCtx)
+
+		s.ErrorIs(err, context.Canceled)
+	})
+
 	s.Run("error get file system trx", func() {
 		rj := dbReconJob
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(nil, assert.AnError)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(nil, assert.AnError)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -60,13 +123,12 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 
 	s.Run("error get file bank trx", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-		}
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer(nil))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(nil, assert.AnError)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(nil, assert.AnError)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -75,14 +137,11 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 
 	s.Run("error read file system trx", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  nil,
-		}
+		fsSystemTrx := io.NopCloser(iotest.ErrReader(assert.AnError))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(&filestorage.File{}, nil)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -91,14 +150,11 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 
 	s.Run("error convert amount file system trx to entity", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"abc\",\n")),
-		}
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"abc\",\n")))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(&filestorage.File{}, nil)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -107,14 +163,11 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 
 	s.Run("error convert transaction type file system trx to entity", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"10000\",\"abc\",\n")),
-		}
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"10000\",\"abc\",\n")))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(&filestorage.File{}, nil)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -123,34 +176,47 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 
 	s.Run("error convert time file system trx to entity", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"abc\"\n")),
-		}
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"abc\"\n")))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(&filestorage.File{}, nil)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
 		s.Nil(err)
 	})
 
+	s.Run("error reconciliation job exceeded timeout", func() {
+		svc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize: 1,
+			JobTimeout: time.Nanosecond,
+		})
+		rj := dbReconJob
+		fsSystemTrx := fetchSystemFile("system_trx.csv")
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), dbgen.SaveFailedReconciliationJobParams{
+			ID:               rj.ID,
+			ErrorInformation: sql.NullString{String: "reconciliation job exceeded processing timeout of 1ns", Valid: true},
+			ErrorCode:        sql.NullString{String: string(entity.ReconciliationJobErrorCodeTimeout), Valid: true},
+		}).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := svc.Process(ctx)
+
+		s.Nil(err)
+	})
+
 	s.Run("error read file bank trx", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"" + strLastWeek + "\"\n")),
-		}
-		fsBankTrx := &filestorage.File{
-			Name: "bank_transaction.csv",
-			Buf:  nil,
-		}
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"" + strLastWeek + "\"\n")))
+		fsBankTrx := io.NopCloser(iotest.ErrReader(assert.AnError))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -159,18 +225,13 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 
 	s.Run("error convert amount file bank trx to entity", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"" + strLastWeek + "\"\n")),
-		}
-		fsBankTrx := &filestorage.File{
-			Name: "bank_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"abc\",\n")),
-		}
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"" + strLastWeek + "\"\n")))
+		fsBankTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"abc\",\n")))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -179,18 +240,13 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Failed() {
 
 	s.Run("error convert time file bank trx to entity", func() {
 		rj := dbReconJob
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"2021-01-01T01:01:01+07:00\"\n")),
-		}
-		fsBankTrx := &filestorage.File{
-			Name: "bank_transaction.csv",
-			Buf:  bytes.NewBuffer([]byte("\"\",\"10000\",\"abc\"\n")),
-		}
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"2021-01-01T01:01:01+07:00\"\n")))
+		fsBankTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"10000\",\"abc\"\n")))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
-		s.mockRepo.EXPECT().SaveFailedReconciliationJob(ctx, gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -209,19 +265,261 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Success() {
 		s.NoError(err)
 	})
 
-	s.Run("success process reconciliation job with system trx matched and unmatched", func() {
+	s.Run("success abort reconciliation job cancelled while processing", func() {
+		svc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:          1,
+			CancelCheckInterval: time.Millisecond,
+		})
+		rj := dbReconJob
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockRepo.EXPECT().GetReconciliationJobStatus(gomock.Any(), rj.ID).Return(string(entity.ReconciliationJobStatusCancelled), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).DoAndReturn(func(ctx context.Context, _ string) (io.ReadCloser, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		err := svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success discard result of reconciliation job cancelled before saved", func() {
 		rj := dbReconJob
 		rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
 		rj.EndDate = time.Date(2024, 11, 23, 0, 0, 0, 0, time.UTC)
-		rj.DiscrepancyThreshold = 0
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  fetchSystemFile("system_trx.csv"),
+		fsSystemTrx := fetchSystemFile("system_trx.csv")
+		fsBankTrx := fetchSystemFile("bca_trx.csv")
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, pgx.ErrNoRows)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success fail reconciliation job whose period is locked before saved", func() {
+		rj := dbReconJob
+		rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+		rj.EndDate = time.Date(2024, 11, 23, 0, 0, 0, 0, time.UTC)
+		fsSystemTrx := fetchSystemFile("system_trx.csv")
+		fsBankTrx := fetchSystemFile("bca_trx.csv")
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
+				s.Equal(&dbgen.ListOverlappingReconciliationPeriodLocksParams{
+					BankNames: []string{"BCA"},
+					StartDate: rj.StartDate,
+					EndDate:   rj.EndDate,
+				}, arg.Period)
+				return dbgen.ReconciliationJob{}, &dbgen.PeriodLockedError{Lock: dbPeriodLock}
+			})
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, arg dbgen.SaveFailedReconciliationJobParams) (dbgen.ReconciliationJob, error) {
+				s.Equal(rj.ID, arg.ID)
+				s.Equal("PERIOD_LOCKED", arg.ErrorCode.String)
+				s.Contains(arg.ErrorInformation.String, reconciliatonjob.ErrPeriodLocked.Error())
+				return dbgen.ReconciliationJob{}, nil
+			})
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success report progress while processing reconciliation job", func() {
+		svc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:       1,
+			ProgressInterval: time.Hour,
+		})
+		rj := dbReconJob
+		reported := make(chan struct{})
+		rj.ClaimToken = 1
+		downloadingParams := dbgen.UpdateReconciliationJobProgressParams{
+			ID:         rj.ID,
+			ClaimToken: 1,
 		}
-		fsBankTrx := &filestorage.File{
-			Name: "bank_transaction.csv",
-			Buf:  fetchSystemFile("bca_trx.csv"),
+		downloadingParams.Progress.Set(entity.ReconciliationJobProgress{
+			Phase:      entity.ReconciliationJobPhaseDownloading,
+			RowsParsed: map[string]int{},
+		})
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockRepo.EXPECT().UpdateReconciliationJobProgress(gomock.Any(), downloadingParams).DoAndReturn(func(_ context.Context, _ dbgen.UpdateReconciliationJobProgressParams) (int64, error) {
+			close(reported)
+			return 1, nil
+		})
+		s.mockRepo.EXPECT().UpdateReconciliationJobProgress(gomock.Any(), gomock.Any()).Return(int64(1), nil).AnyTimes()
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).DoAndReturn(func(_ context.Context, _ string) (io.ReadCloser, error) {
+			<-reported
+			return nil, assert.AnError
+		})
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success discard result of reconciliation job whose lease is lost", func() {
+		svc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:    1,
+			LeaseDuration: 3 * time.Millisecond,
+		})
+		rj := dbReconJob
+		claimed := rj
+		claimed.ClaimToken = 1
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, dbgen.StartReconciliationJobParams{
+			ID:           rj.ID,
+			LeaseSeconds: (3 * time.Millisecond).Seconds(),
+		}).Return(claimed, nil)
+		s.mockRepo.EXPECT().RenewReconciliationJobLease(gomock.Any(), dbgen.RenewReconciliationJobLeaseParams{
+			ID:           rj.ID,
+			LeaseSeconds: (3 * time.Millisecond).Seconds(),
+			ClaimToken:   1,
+		}).Return(int64(0), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).DoAndReturn(func(ctx context.Context, _ string) (io.ReadCloser, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		err := svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success discard result of reconciliation job reclaimed by another runner", func() {
+		svc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:    1,
+			LeaseDuration: time.Hour,
+		})
+		rj := dbReconJob
+		first, second := rj, rj
+		first.ClaimToken = 1
+		second.ClaimToken = 2
+		stalled, release := make(chan struct{}), make(chan struct{})
+		// the first runner is stalled past its lease while the second runner
+		// claims the job again and saves it, so the first claim is outdated
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil).Times(2)
+		gomock.InOrder(
+			s.mockRepo.EXPECT().StartReconciliationJob(ctx, dbgen.StartReconciliationJobParams{
+				ID:           rj.ID,
+				LeaseSeconds: time.Hour.Seconds(),
+			}).Return(first, nil),
+			s.mockRepo.EXPECT().StartReconciliationJob(ctx, dbgen.StartReconciliationJobParams{
+				ID:           rj.ID,
+				LeaseSeconds: time.Hour.Seconds(),
+			}).Return(second, nil),
+		)
+		gomock.InOrder(
+			s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).DoAndReturn(func(_ context.Context, _ string) (io.ReadCloser, error) {
+				close(stalled)
+				<-release
+				return nil, assert.AnError
+			}),
+			s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(nil, assert.AnError),
+		)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg dbgen.SaveFailedReconciliationJobParams) (dbgen.ReconciliationJob, error) {
+			if arg.ClaimToken != second.ClaimToken {
+				return dbgen.ReconciliationJob{}, pgx.ErrNoRows
+			}
+			return second, nil
+		}).Times(2)
+
+		firstDone := make(chan error)
+		go func() {
+			firstDone <- svc.Process(ctx)
+		}()
+		<-stalled
+		s.NoError(svc.Process(ctx))
+		close(release)
+
+		s.NoError(<-firstDone)
+	})
+
+	s.Run("success save result of reconciliation job when runner is shutting down while saving", func() {
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		rj := dbReconJob
+		fsSystemTrx := io.NopCloser(bytes.NewBuffer([]byte("\"\",\"10000\",\"DEBIT\",\"" + strLastWeek + "\"\n")))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(runCtx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(runCtx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).DoAndReturn(func(_ context.Context, _ string) (io.ReadCloser, error) {
+			return nil, assert.AnError
+		})
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).DoAndReturn(func(saveCtx context.Context, _ dbgen.SaveFailedReconciliationJobParams) (dbgen.ReconciliationJob, error) {
+			cancel()
+			s.NoError(saveCtx.Err())
+			_, ok := saveCtx.Deadline()
+			s.True(ok)
+			return dbgen.ReconciliationJob{}, nil
+		})
+
+		err := s.svc.Process(runCtx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success leave reconciliation job unsaved when runner is shutting down", func() {
+		runCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		rj := dbReconJob
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(runCtx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(runCtx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).DoAndReturn(func(ctx context.Context, _ string) (io.ReadCloser, error) {
+			cancel()
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+
+		err := s.svc.Process(runCtx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success skip reconciliation job claimed by another worker", func() {
+		rj := dbReconJob
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(dbgen.ReconciliationJob{}, pgx.ErrNoRows)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success process multiple reconciliation jobs concurrently", func() {
+		jobs := []dbgen.ReconciliationJob{}
+		for i := int64(1); i <= 3; i++ {
+			rj := dbReconJob
+			rj.ID = i
+			rj.SystemTransactionCsvPath = fmt.Sprintf("path/to/system_%d.csv", i)
+			jobs = append(jobs, rj)
+			s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+			s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(nil, assert.AnError)
 		}
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return(jobs, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil).Times(3)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success process reconciliation job with system trx matched and unmatched", func() {
+		rj := dbReconJob
+		rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+		rj.EndDate = time.Date(2024, 11, 23, 0, 0, 0, 0, time.UTC)
+		rj.DiscrepancyThreshold = 0
+		fsSystemTrx := fetchSystemFile("system_trx.csv")
+		fsBankTrx := fetchSystemFile("bca_trx.csv")
 		expectedResult := entity.ReconciliationResult{
 			TotalTransactionProcessed: 14,
 			TotalTransactionMatched:   9,
@@ -269,15 +567,22 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Success() {
 					},
 				},
 			},
+			Duplicates:        map[string][]entity.DuplicateTransaction{},
+			Warnings:          noWarnings,
+			InternalTransfers: noTransfers,
+			Reversals:         map[string][]entity.ReversalPair{},
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
 		}
 		saveParams := dbgen.SaveSuccessReconciliationJobParams{
 			ID: rj.ID,
 		}
-		saveParams.Result.Set(expectedResult)
+		saveParams.Result.Set(withContentHash(expectedResult))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
-		s.mockRepo.EXPECT().SaveSuccessReconciliationJob(ctx, saveParams).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -300,18 +605,9 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Success() {
 			},
 		}
 		rj.BankTransactionCsvPaths.Set(bankCsvs)
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  fetchSystemFile("system_trx.csv"),
-		}
-		fsBankBcaTrx := &filestorage.File{
-			Name: "bca_transaction.csv",
-			Buf:  fetchSystemFile("bca_trx.csv"),
-		}
-		fsBankBriTrx := &filestorage.File{
-			Name: "bri_transaction.csv",
-			Buf:  fetchSystemFile("bri_trx.csv"),
-		}
+		fsSystemTrx := fetchSystemFile("system_trx.csv")
+		fsBankBcaTrx := fetchSystemFile("bca_trx.csv")
+		fsBankBriTrx := fetchSystemFile("bri_trx.csv")
 		expectedResult := entity.ReconciliationResult{
 			TotalTransactionProcessed: 12,
 			TotalTransactionMatched:   12,
@@ -319,16 +615,23 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Success() {
 			TotalDiscrepancyAmount:    0,
 			MissingTransactions:       []entity.Transaction{},
 			MissingBankTransactions:   map[string][]entity.Transaction{},
+			Duplicates:                map[string][]entity.DuplicateTransaction{},
+			Warnings:                  noWarnings,
+			InternalTransfers:         noTransfers,
+			Reversals:                 map[string][]entity.ReversalPair{},
+			BankFees:                  map[string]entity.BankFeeSummary{},
+			NeedsReview:               noReviews,
 		}
 		saveParams := dbgen.SaveSuccessReconciliationJobParams{
 			ID: rj.ID,
 		}
-		saveParams.Result.Set(expectedResult)
+		saveParams.Result.Set(withContentHash(expectedResult))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, bankCsvs[0].FilePath).Return(fsBankBcaTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, bankCsvs[1].FilePath).Return(fsBankBriTrx, nil)
-		s.mockRepo.EXPECT().SaveSuccessReconciliationJob(ctx, saveParams).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(fsBankBcaTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[1].FilePath).Return(fsBankBriTrx, nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -340,14 +643,8 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Success() {
 		rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
 		rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
 		rj.DiscrepancyThreshold = 0
-		fsSystemTrx := &filestorage.File{
-			Name: "system_transaction.csv",
-			Buf:  fetchSystemFile("system_trx_1.csv"),
-		}
-		fsBankTrx := &filestorage.File{
-			Name: "bank_transaction.csv",
-			Buf:  fetchSystemFile("bca_trx_1.csv"),
-		}
+		fsSystemTrx := fetchSystemFile("system_trx_1.csv")
+		fsBankTrx := fetchSystemFile("bca_trx_1.csv")
 		expectedResult := entity.ReconciliationResult{
 			TotalTransactionProcessed: 1,
 			TotalTransactionMatched:   0,
@@ -371,15 +668,269 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Success() {
 					},
 				},
 			},
+			Duplicates:        map[string][]entity.DuplicateTransaction{},
+			Warnings:          noWarnings,
+			InternalTransfers: noTransfers,
+			Reversals:         map[string][]entity.ReversalPair{},
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success order missing transactions by date then file order", func() {
+		rj := dbReconJob
+		rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+		rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+		rj.DiscrepancyThreshold = 0
+		fsSystemTrx := io.NopCloser(bytes.NewBufferString("S-1,100,DEBIT,2024-11-03T10:00:00Z\n" +
+			"S-2,200,CREDIT,2024-11-01T10:00:00Z\n" +
+			"S-3,300,CREDIT,2024-11-03T09:00:00Z\n"))
+		fsBankTrx := io.NopCloser(bytes.NewBufferString("B-1,400,2024-11-05\n" +
+			"B-2,-500,2024-11-02\n" +
+			"B-3,600,2024-11-02\n"))
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 3,
+			TotalTransactionMatched:   0,
+			TotalTransactionUnmatched: 3,
+			TotalDiscrepancyAmount:    2100,
+			MissingTransactions: []entity.Transaction{
+				{ID: "S-2", Amount: 200, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T10:00:00Z")},
+				{ID: "S-1", Amount: 100, Type: entity.TxTypeDebit, Time: parseTime("2024-11-03T10:00:00Z")},
+				{ID: "S-3", Amount: 300, Type: entity.TxTypeCredit, Time: parseTime("2024-11-03T09:00:00Z")},
+			},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BCA": {
+					{ID: "B-2", Amount: 500, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T00:00:00Z")},
+					{ID: "B-3", Amount: 600, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T00:00:00Z")},
+					{ID: "B-1", Amount: 400, Type: entity.TxTypeCredit, Time: parseTime("2024-11-05T00:00:00Z")},
+				},
+			},
+			Duplicates:        map[string][]entity.DuplicateTransaction{},
+			Warnings:          noWarnings,
+			InternalTransfers: noTransfers,
+			Reversals:         map[string][]entity.ReversalPair{},
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_Duplicates() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0
+	systemCsv := "S-1,100,CREDIT,2024-11-01T10:00:00Z\n" +
+		"S-2,100,CREDIT,2024-11-01T11:00:00Z\n" +
+		"S-1,300,DEBIT,2024-11-02T10:00:00Z\n"
+	bankCsv := "B-1,100,2024-11-01\n" +
+		"B-1,-300,2024-11-02\n"
+
+	s.Run("success report duplicate transactions by id", func() {
+		rj := rj
+		rj.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule: entity.DuplicateRuleID,
+		})
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 3,
+			TotalTransactionMatched:   2,
+			TotalTransactionUnmatched: 1,
+			TotalDiscrepancyAmount:    100,
+			TotalTransactionDuplicate: 2,
+			MissingTransactions: []entity.Transaction{
+				{ID: "S-2", Amount: 100, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T11:00:00Z")},
+			},
+			MissingBankTransactions: map[string][]entity.Transaction{},
+			Duplicates: map[string][]entity.DuplicateTransaction{
+				"SYSTEM": {
+					{
+						Transaction:    entity.Transaction{ID: "S-1", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T10:00:00Z")},
+						Row:            3,
+						DuplicateOfRow: 1,
+					},
+				},
+				"BCA": {
+					{
+						Transaction:    entity.Transaction{ID: "B-1", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T00:00:00Z")},
+						Row:            2,
+						DuplicateOfRow: 1,
+					},
+				},
+			},
+			Warnings:          noWarnings,
+			InternalTransfers: noTransfers,
+			Reversals:         map[string][]entity.ReversalPair{},
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success deduplicate transactions by fingerprint before matching", func() {
+		rj := rj
+		rj.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule: entity.DuplicateRuleFingerprint,
+			Deduplicate:   true,
+		})
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 2,
+			TotalTransactionMatched:   2,
+			TotalTransactionUnmatched: 0,
+			TotalDiscrepancyAmount:    0,
+			TotalTransactionDuplicate: 1,
+			MissingTransactions:       []entity.Transaction{},
+			MissingBankTransactions:   map[string][]entity.Transaction{},
+			Duplicates: map[string][]entity.DuplicateTransaction{
+				"SYSTEM": {
+					{
+						Transaction:    entity.Transaction{ID: "S-2", Amount: 100, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T11:00:00Z")},
+						Row:            2,
+						DuplicateOfRow: 1,
+					},
+				},
+			},
+			Warnings:          noWarnings,
+			InternalTransfers: noTransfers,
+			Reversals:         map[string][]entity.ReversalPair{},
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success warn transactions overlapping across bank files", func() {
+		rj := rj
+		bankCsvs := []entity.BankTransactionCsv{
+			{
+				BankName: "BCA",
+				FilePath: "path/to/bca_transaction.csv",
+			},
+			{
+				BankName: "BRI",
+				FilePath: "path/to/bri_transaction.csv",
+			},
+		}
+		rj.BankTransactionCsvPaths.Set(bankCsvs)
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 3,
+			TotalTransactionMatched:   3,
+			TotalTransactionUnmatched: 0,
+			TotalDiscrepancyAmount:    300,
+			TotalTransactionDuplicate: 3,
+			MissingTransactions:       []entity.Transaction{},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BRI": {
+					{ID: "B-1", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T00:00:00Z")},
+				},
+			},
+			Duplicates: map[string][]entity.DuplicateTransaction{
+				"SYSTEM": {
+					{
+						Transaction:    entity.Transaction{ID: "S-1", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T10:00:00Z")},
+						Row:            3,
+						DuplicateOfRow: 1,
+					},
+				},
+				"BCA": {
+					{
+						Transaction:    entity.Transaction{ID: "B-1", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T00:00:00Z")},
+						Row:            2,
+						DuplicateOfRow: 1,
+					},
+				},
+				"BRI": {
+					{
+						Transaction:    entity.Transaction{ID: "B-1", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T00:00:00Z")},
+						Row:            2,
+						DuplicateOfRow: 1,
+					},
+				},
+			},
+			Warnings: entity.ReconciliationWarnings{
+				BankFileOverlaps: []entity.OverlapTransaction{
+					{
+						Transaction:     entity.Transaction{ID: "B-1", Amount: 100, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T00:00:00Z")},
+						BankName:        "BRI",
+						Row:             1,
+						OverlapBankName: "BCA",
+						OverlapRow:      1,
+					},
+					{
+						Transaction:     entity.Transaction{ID: "B-1", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T00:00:00Z")},
+						BankName:        "BRI",
+						Row:             2,
+						OverlapBankName: "BCA",
+						OverlapRow:      1,
+					},
+				},
+				TotalBankFileOverlap: 2,
+			},
+			InternalTransfers: noTransfers,
+			Reversals:         map[string][]entity.ReversalPair{},
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
 		}
 		saveParams := dbgen.SaveSuccessReconciliationJobParams{
 			ID: rj.ID,
 		}
-		saveParams.Result.Set(expectedResult)
+		saveParams.Result.Set(withContentHash(expectedResult))
 		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, rj.SystemTransactionCsvPath).Return(fsSystemTrx, nil)
-		s.mockFileGetter.EXPECT().Get(ctx, entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(fsBankTrx, nil)
-		s.mockRepo.EXPECT().SaveSuccessReconciliationJob(ctx, saveParams).Return(dbgen.ReconciliationJob{}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[1].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
 
 		err := s.svc.Process(ctx)
 
@@ -387,12 +938,1104 @@ func (s *ReconciliationJobProcessorTestSuite) TestProcess_Success() {
 	})
 }
 
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_InternalTransfers() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0
+	bankCsvs := []entity.BankTransactionCsv{
+		{
+			BankName: "BCA",
+			FilePath: "path/to/bca_transaction.csv",
+		},
+		{
+			BankName: "BRI",
+			FilePath: "path/to/bri_transaction.csv",
+		},
+	}
+	rj.BankTransactionCsvPaths.Set(bankCsvs)
+	systemCsv := "S-1,100,CREDIT,2024-11-01T10:00:00Z\n"
+	bcaCsv := "BCA-1,100,2024-11-01\n" +
+		"BCA-2,-500,2024-11-01\n"
+	briCsv := "BRI-1,500,2024-11-02\n" +
+		"BRI-2,700,2024-11-05\n" +
+		"BRI-3,500,2024-11-04\n"
+	transferFrom := entity.Transaction{ID: "BCA-2", Amount: 500, Type: entity.TxTypeDebit, Time: parseTime("2024-11-01T00:00:00Z")}
+	transferTo := entity.Transaction{ID: "BRI-1", Amount: 500, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T00:00:00Z")}
+
+	s.Run("success pair transfers between bank files", func() {
+		rj := rj
+		rj.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule: entity.DuplicateRuleID,
+			PairTransfers: true,
+		})
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed:        1,
+			TotalTransactionMatched:          1,
+			TotalTransactionUnmatched:        0,
+			TotalDiscrepancyAmount:           1200,
+			TotalTransactionInternalTransfer: 2,
+			MissingTransactions:              []entity.Transaction{},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BRI": {
+					{ID: "BRI-3", Amount: 500, Type: entity.TxTypeCredit, Time: parseTime("2024-11-04T00:00:00Z")},
+					{ID: "BRI-2", Amount: 700, Type: entity.TxTypeCredit, Time: parseTime("2024-11-05T00:00:00Z")},
+				},
+			},
+			Duplicates: map[string][]entity.DuplicateTransaction{},
+			InternalTransfers: []entity.InternalTransfer{
+				{
+					From: entity.TransferLeg{Transaction: transferFrom, BankName: "BCA", Row: 2},
+					To:   entity.TransferLeg{Transaction: transferTo, BankName: "BRI", Row: 1},
+				},
+			},
+			Warnings:    noWarnings,
+			Reversals:   map[string][]entity.ReversalPair{},
+			BankFees:    map[string]entity.BankFeeSummary{},
+			NeedsReview: noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bcaCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[1].FilePath).Return(io.NopCloser(bytes.NewBufferString(briCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success report transfers as missing when not paired", func() {
+		rj := rj
+		rj.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule: entity.DuplicateRuleID,
+		})
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 1,
+			TotalTransactionMatched:   1,
+			TotalTransactionUnmatched: 0,
+			TotalDiscrepancyAmount:    2200,
+			MissingTransactions:       []entity.Transaction{},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BCA": {transferFrom},
+				"BRI": {
+					transferTo,
+					{ID: "BRI-3", Amount: 500, Type: entity.TxTypeCredit, Time: parseTime("2024-11-04T00:00:00Z")},
+					{ID: "BRI-2", Amount: 700, Type: entity.TxTypeCredit, Time: parseTime("2024-11-05T00:00:00Z")},
+				},
+			},
+			Duplicates:        map[string][]entity.DuplicateTransaction{},
+			InternalTransfers: noTransfers,
+			Warnings:          noWarnings,
+			Reversals:         map[string][]entity.ReversalPair{},
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bcaCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[1].FilePath).Return(io.NopCloser(bytes.NewBufferString(briCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_Reversals() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0
+	systemCsv := "S-1,500,DEBIT,2024-11-01T10:00:00Z\n" +
+		"REV-S-1,500,CREDIT,2024-11-03T10:00:00Z\n" +
+		"S-2,100,CREDIT,2024-11-01T11:00:00Z\n" +
+		"S-3,200,CREDIT,2024-11-20T10:00:00Z\n" +
+		"S-4,400,DEBIT,2024-11-10T10:00:00Z\n" +
+		"S-5,400,CREDIT,2024-11-11T10:00:00Z\n" +
+		"REV-S-4,400,CREDIT,2024-11-12T10:00:00Z\n"
+	bankCsv := "B-1,100,2024-11-01\n" +
+		"B-2,-300,2024-11-02\n" +
+		"B-3,300,2024-11-04\n" +
+		"B-4,-50,2024-11-05\n"
+	s1 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "S-1", Amount: 500, Type: entity.TxTypeDebit, Time: parseTime("2024-11-01T10:00:00Z")}, Row: 1}
+	revS1 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "REV-S-1", Amount: 500, Type: entity.TxTypeCredit, Time: parseTime("2024-11-03T10:00:00Z")}, Row: 2}
+	s4 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "S-4", Amount: 400, Type: entity.TxTypeDebit, Time: parseTime("2024-11-10T10:00:00Z")}, Row: 5}
+	s5 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "S-5", Amount: 400, Type: entity.TxTypeCredit, Time: parseTime("2024-11-11T10:00:00Z")}, Row: 6}
+	revS4 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "REV-S-4", Amount: 400, Type: entity.TxTypeCredit, Time: parseTime("2024-11-12T10:00:00Z")}, Row: 7}
+	s3 := entity.Transaction{ID: "S-3", Amount: 200, Type: entity.TxTypeCredit, Time: parseTime("2024-11-20T10:00:00Z")}
+	b2 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "B-2", Amount: 300, Type: entity.TxTypeDebit, Time: parseTime("2024-11-02T00:00:00Z")}, Row: 2}
+	b3 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "B-3", Amount: 300, Type: entity.TxTypeCredit, Time: parseTime("2024-11-04T00:00:00Z")}, Row: 3}
+	b4 := entity.Transaction{ID: "B-4", Amount: 50, Type: entity.TxTypeDebit, Time: parseTime("2024-11-05T00:00:00Z")}
+
+	s.Run("success pair reversals preferring the referenced transaction", func() {
+		rj := rj
+		rj.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule:      entity.DuplicateRuleID,
+			PairReversals:      true,
+			ReversalWindowDays: 7,
+		})
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 7,
+			TotalTransactionMatched:   1,
+			TotalTransactionUnmatched: 2,
+			TotalTransactionReversed:  4,
+			TotalDiscrepancyAmount:    650,
+			MissingTransactions:       []entity.Transaction{s5.Transaction, s3},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BCA": {b4},
+			},
+			Duplicates: map[string][]entity.DuplicateTransaction{},
+			Reversals: map[string][]entity.ReversalPair{
+				"SYSTEM": {
+					{Original: s1, Reversal: revS1},
+					{Original: s4, Reversal: revS4},
+				},
+				"BCA": {
+					{Original: b2, Reversal: b3},
+				},
+			},
+			InternalTransfers: noTransfers,
+			Warnings:          noWarnings,
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success pair reversals only within the window", func() {
+		rj := rj
+		rj.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule:      entity.DuplicateRuleID,
+			PairReversals:      true,
+			ReversalWindowDays: 1,
+		})
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 7,
+			TotalTransactionMatched:   1,
+			TotalTransactionUnmatched: 4,
+			TotalTransactionReversed:  2,
+			TotalDiscrepancyAmount:    2250,
+			MissingTransactions:       []entity.Transaction{s1.Transaction, revS1.Transaction, revS4.Transaction, s3},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BCA": {b2.Transaction, b3.Transaction, b4},
+			},
+			Duplicates: map[string][]entity.DuplicateTransaction{},
+			Reversals: map[string][]entity.ReversalPair{
+				"SYSTEM": {
+					{Original: s4, Reversal: s5},
+				},
+			},
+			InternalTransfers: noTransfers,
+			Warnings:          noWarnings,
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success pair reversals only referencing the whole original ID", func() {
+		rj := rj
+		rj.MatchingOptions.Set(entity.MatchingOptions{
+			DuplicateRule:      entity.DuplicateRuleID,
+			PairReversals:      true,
+			ReversalWindowDays: 7,
+		})
+		systemCsv := "BCA-1,100,DEBIT,2024-11-01T10:00:00Z\n" +
+			"X-9,100,CREDIT,2024-11-02T10:00:00Z\n" +
+			"REV-BCA-13,100,CREDIT,2024-11-03T10:00:00Z\n"
+		bca1 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "BCA-1", Amount: 100, Type: entity.TxTypeDebit, Time: parseTime("2024-11-01T10:00:00Z")}, Row: 1}
+		x9 := entity.ReversalLeg{Transaction: entity.Transaction{ID: "X-9", Amount: 100, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T10:00:00Z")}, Row: 2}
+		revBCA13 := entity.Transaction{ID: "REV-BCA-13", Amount: 100, Type: entity.TxTypeCredit, Time: parseTime("2024-11-03T10:00:00Z")}
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 3,
+			TotalTransactionMatched:   0,
+			TotalTransactionUnmatched: 1,
+			TotalTransactionReversed:  2,
+			TotalDiscrepancyAmount:    100,
+			MissingTransactions:       []entity.Transaction{revBCA13},
+			MissingBankTransactions:   map[string][]entity.Transaction{},
+			Duplicates:                map[string][]entity.DuplicateTransaction{},
+			Reversals: map[string][]entity.ReversalPair{
+				"SYSTEM": {
+					{Original: bca1, Reversal: x9},
+				},
+			},
+			InternalTransfers: noTransfers,
+			Warnings:          noWarnings,
+			BankFees:          map[string]entity.BankFeeSummary{},
+			NeedsReview:       noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString("")), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_BankFees() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0
+	bankCsvs := []entity.BankTransactionCsv{
+		{
+			BankName: "BCA",
+			FilePath: "path/to/bca_transaction.csv",
+		},
+		{
+			BankName: "BRI",
+			FilePath: "path/to/bri_transaction.csv",
+		},
+	}
+	rj.BankTransactionCsvPaths.Set(bankCsvs)
+	rj.MatchingOptions.Set(entity.MatchingOptions{
+		DuplicateRule: entity.DuplicateRuleID,
+		FeeRules: map[string]entity.FeeRule{
+			"BCA": {Type: entity.FeeTypePercentage, Rate: 0.01},
+			"BRI": {
+				Type: entity.FeeTypeTiered,
+				Tiers: []entity.FeeTier{
+					{MinAmount: 0, Fixed: 2500},
+					{MinAmount: 1000000, Fixed: 5000},
+				},
+			},
+		},
+	})
+	systemCsv := "S-1,100000,CREDIT,2024-11-01T10:00:00Z\n" +
+		"S-2,2000000,CREDIT,2024-11-01T11:00:00Z\n" +
+		"S-3,50000,CREDIT,2024-11-02T10:00:00Z\n" +
+		"S-4,30000,DEBIT,2024-11-02T11:00:00Z\n" +
+		"S-5,10000,CREDIT,2024-11-03T10:00:00Z\n"
+	bcaCsv := "BCA-1,99000,2024-11-01\n" +
+		"BCA-2,-30000,2024-11-02\n" +
+		"BCA-3,10000,2024-11-03\n"
+	briCsv := "BRI-1,1995000,2024-11-01\n" +
+		"BRI-2,47500,2024-11-02\n"
+
+	s.Run("success match bank credits net of fees", func() {
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed: 5,
+			TotalTransactionMatched:   4,
+			TotalTransactionUnmatched: 1,
+			TotalDiscrepancyAmount:    20000,
+			MissingTransactions: []entity.Transaction{
+				{ID: "S-5", Amount: 10000, Type: entity.TxTypeCredit, Time: parseTime("2024-11-03T10:00:00Z")},
+			},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BCA": {
+					{ID: "BCA-3", Amount: 10000, Type: entity.TxTypeCredit, Time: parseTime("2024-11-03T00:00:00Z")},
+				},
+			},
+			Duplicates:        map[string][]entity.DuplicateTransaction{},
+			Reversals:         map[string][]entity.ReversalPair{},
+			InternalTransfers: noTransfers,
+			BankFees: map[string]entity.BankFeeSummary{
+				"BCA": {
+					TotalFee:         1000,
+					TotalExpectedFee: 1000,
+					Matches: []entity.MatchFee{
+						{
+							SystemTransaction: entity.Transaction{ID: "S-1", Amount: 100000, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T10:00:00Z")},
+							BankTransaction:   entity.Transaction{ID: "BCA-1", Amount: 99000, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T00:00:00Z")},
+							ExpectedFee:       1000,
+							ImpliedFee:        1000,
+						},
+					},
+				},
+				"BRI": {
+					TotalFee:         7500,
+					TotalExpectedFee: 7500,
+					Matches: []entity.MatchFee{
+						{
+							SystemTransaction: entity.Transaction{ID: "S-2", Amount: 2000000, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T11:00:00Z")},
+							BankTransaction:   entity.Transaction{ID: "BRI-1", Amount: 1995000, Type: entity.TxTypeCredit, Time: parseTime("2024-11-01T00:00:00Z")},
+							ExpectedFee:       5000,
+							ImpliedFee:        5000,
+						},
+						{
+							SystemTransaction: entity.Transaction{ID: "S-3", Amount: 50000, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T10:00:00Z")},
+							BankTransaction:   entity.Transaction{ID: "BRI-2", Amount: 47500, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T00:00:00Z")},
+							ExpectedFee:       2500,
+							ImpliedFee:        2500,
+						},
+					},
+				},
+			},
+			Warnings:    noWarnings,
+			NeedsReview: noReviews,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bcaCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[1].FilePath).Return(io.NopCloser(bytes.NewBufferString(briCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+
+	s.Run("success find fee rule of bank name in another case", func() {
+		rj := rj
+		rj.BankTransactionCsvPaths.Set([]entity.BankTransactionCsv{
+			{
+				BankName: "bca",
+				FilePath: bankCsvs[0].FilePath,
+			},
+		})
+		var saved dbgen.SaveSuccessReconciliationJobWithItemsParams
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bcaCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
+			saved = copied(arg)
+			return dbgen.ReconciliationJob{}, nil
+		})
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+		var result entity.ReconciliationResult
+		s.Require().NoError(json.Unmarshal(saved.Result.Bytes, &result))
+		s.Equal(float64(1000), result.BankFees["bca"].TotalFee)
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_NeedsReview() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0.1
+	rj.MatchingOptions.Set(entity.MatchingOptions{
+		DuplicateRule: entity.DuplicateRuleID,
+		MinConfidence: 0.7,
+	})
+	systemCsv := "BCA-1,1000,CREDIT,2024-11-01T10:00:00Z\n" +
+		"TRX-9,500,CREDIT,2024-11-02T10:00:00Z\n" +
+		"S-3,200,DEBIT,2024-11-02T11:00:00Z\n"
+	bankCsv := "BCA-1,1000,2024-11-01\n" +
+		"X-1,480,2024-11-02\n" +
+		"X-2,470,2024-11-02\n" +
+		"B-3,-200,2024-11-02\n"
+
+	s.Run("success put low confidence match to review", func() {
+		expectedResult := entity.ReconciliationResult{
+			TotalTransactionProcessed:   3,
+			TotalTransactionMatched:     2,
+			TotalTransactionUnmatched:   0,
+			TotalTransactionNeedsReview: 1,
+			TotalDiscrepancyAmount:      470,
+			MissingTransactions:         []entity.Transaction{},
+			MissingBankTransactions: map[string][]entity.Transaction{
+				"BCA": {
+					{ID: "X-2", Amount: 470, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T00:00:00Z")},
+				},
+			},
+			NeedsReview: []entity.ReviewMatch{
+				{
+					SystemTransaction: entity.Transaction{ID: "TRX-9", Amount: 500, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T10:00:00Z")},
+					BankName:          "BCA",
+					BankTransaction:   entity.Transaction{ID: "X-1", Amount: 480, Type: entity.TxTypeCredit, Time: parseTime("2024-11-02T00:00:00Z")},
+					Confidence: entity.MatchConfidence{
+						Score:      0.675,
+						Amount:     0.6,
+						Date:       1,
+						Uniqueness: 0.5,
+					},
+				},
+			},
+			Duplicates:        map[string][]entity.DuplicateTransaction{},
+			Reversals:         map[string][]entity.ReversalPair{},
+			InternalTransfers: noTransfers,
+			BankFees:          map[string]entity.BankFeeSummary{},
+			Warnings:          noWarnings,
+		}
+		saveParams := dbgen.SaveSuccessReconciliationJobParams{
+			ID: rj.ID,
+		}
+		saveParams.Result.Set(withContentHash(expectedResult))
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), savedJob(saveParams)).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.NoError(err)
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_Items() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0.1
+	rj.MatchingOptions.Set(entity.MatchingOptions{
+		DuplicateRule: entity.DuplicateRuleID,
+		MinConfidence: 0.7,
+	})
+	systemCsv := "BCA-1,1000,CREDIT,2024-11-01T10:00:00Z\n" +
+		"TRX-9,500,CREDIT,2024-11-02T10:00:00Z\n" +
+		"S-3,200,DEBIT,2024-11-02T11:00:00Z\n" +
+		"S-4,300,CREDIT,2024-11-03T10:00:00Z\n"
+	bankCsv := "BCA-1,1000,2024-11-01\n" +
+		"X-1,480,2024-11-02\n" +
+		"X-2,470,2024-11-02\n" +
+		"B-3,-200,2024-11-02\n"
+	bca := sql.NullString{String: "BCA", Valid: true}
+
+	s.Run("success save items and matches of the job", func() {
+		var saved dbgen.SaveSuccessReconciliationJobWithItemsParams
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
+			saved = copied(arg)
+			return dbgen.ReconciliationJob{}, nil
+		})
+
+		err := s.svc.Process(ctx)
+
+		s.Require().NoError(err)
+		s.Equal([]dbgen.CopyReconciliationItemsParams{
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 1, TransactionID: "BCA-1", Amount: 1000, Type: "CREDIT", TransactionTime: parseTime("2024-11-01T10:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 1, TransactionID: "BCA-1", Amount: 1000, Type: "CREDIT", TransactionTime: parseTime("2024-11-01T00:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 3, TransactionID: "S-3", Amount: 200, Type: "DEBIT", TransactionTime: parseTime("2024-11-02T11:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 4, TransactionID: "B-3", Amount: 200, Type: "DEBIT", TransactionTime: parseTime("2024-11-02T00:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 2, TransactionID: "TRX-9", Amount: 500, Type: "CREDIT", TransactionTime: parseTime("2024-11-02T10:00:00Z"), Status: "NEEDS_REVIEW"},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 2, TransactionID: "X-1", Amount: 480, Type: "CREDIT", TransactionTime: parseTime("2024-11-02T00:00:00Z"), Status: "NEEDS_REVIEW"},
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 4, TransactionID: "S-4", Amount: 300, Type: "CREDIT", TransactionTime: parseTime("2024-11-03T10:00:00Z"), Status: "MISSING"},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 3, TransactionID: "X-2", Amount: 470, Type: "CREDIT", TransactionTime: parseTime("2024-11-02T00:00:00Z"), Status: "MISSING"},
+		}, collect(saved.Items))
+		s.Equal([]dbgen.CopyReconciliationMatchesParams{
+			{JobID: rj.ID, SystemRow: 1, BankName: "BCA", BankRow: 1, Status: "MATCHED", Confidence: 1},
+			{JobID: rj.ID, SystemRow: 3, BankName: "BCA", BankRow: 4, Status: "MATCHED", Confidence: 1},
+			{JobID: rj.ID, SystemRow: 2, BankName: "BCA", BankRow: 2, Status: "NEEDS_REVIEW", Confidence: 0.675, ImpliedFee: 20},
+		}, collect(saved.Matches))
+
+		var summary map[string]json.RawMessage
+		s.Require().NoError(json.Unmarshal(saved.Result.Bytes, &summary))
+		s.NotContains(summary, "missing_transactions")
+		s.NotContains(summary, "missing_bank_transactions")
+		s.Contains(summary, "content_hash")
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_CarryForward() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0
+	rj.MatchingOptions.Set(entity.MatchingOptions{
+		DuplicateRule: entity.DuplicateRuleID,
+		CarryForward:  true,
+	})
+	previousEndDate := time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	previous := dbReconJob
+	previous.ID = 10
+	previous.Status = string(entity.ReconciliationJobStatusSuccess)
+	otherBanks := dbReconJob
+	otherBanks.ID = 11
+	otherBanks.BankTransactionCsvPaths.Set([]entity.BankTransactionCsv{{BankName: "BRI", FilePath: "path_to_file_bri"}})
+	systemCsv := "S-1,1000,CREDIT,2024-12-01T09:00:00Z\n" +
+		"S-2,300,CREDIT,2024-12-02T10:00:00Z\n" +
+		"S-3,450,CREDIT,2024-12-10T10:00:00Z\n"
+	bankCsv := "B-2,300,2024-12-02\n" +
+		"B-3,-700,2024-12-01\n" +
+		"B-4,-250,2024-12-01\n"
+	bca := sql.NullString{String: "BCA", Valid: true}
+	// N-2 and S-OLD are outside of the window of the carry forward so they
+	// are not matched with S-3 and B-3 of the same amount
+	openItems := []dbgen.ReconciliationItem{
+		{ID: 101, JobID: previous.ID, Source: "BANK", BankName: bca, RowNumber: 7, TransactionID: "N-1", Amount: 1000, Type: "CREDIT", TransactionTime: parseTime("2024-11-30T00:00:00Z"), Status: "MISSING"},
+		{ID: 102, JobID: previous.ID, Source: "SYSTEM", RowNumber: 4, TransactionID: "S-OLD", Amount: 700, Type: "DEBIT", TransactionTime: parseTime("2024-11-15T10:00:00Z"), Status: "MISSING"},
+		{ID: 103, JobID: previous.ID, Source: "BANK", BankName: bca, RowNumber: 8, TransactionID: "N-2", Amount: 450, Type: "CREDIT", TransactionTime: parseTime("2024-11-20T00:00:00Z"), Status: "MISSING"},
+		{ID: 104, JobID: previous.ID, Source: "SYSTEM", RowNumber: 5, TransactionID: "S-LAST", Amount: 250, Type: "DEBIT", TransactionTime: parseTime("2024-11-30T18:00:00Z"), Status: "MISSING"},
+		{ID: 105, JobID: previous.ID, Source: "BANK", BankName: bca, RowNumber: 9, TransactionID: "N-3", Amount: 90, Type: "DEBIT", TransactionTime: parseTime("2024-11-30T00:00:00Z"), Status: "MISSING"},
+	}
+	expectProcess := func() *dbgen.SaveSuccessReconciliationJobWithItemsParams {
+		saved := &dbgen.SaveSuccessReconciliationJobWithItemsParams{}
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
+			*saved = copied(arg)
+			return dbgen.ReconciliationJob{}, nil
+		}).MaxTimes(1)
+		return saved
+	}
+
+	s.Run("success match open items of the previous job of the same banks", func() {
+		saved := expectProcess()
+		s.mockRepo.EXPECT().ListSuccessReconciliationJobsByEndDate(gomock.Any(), previousEndDate).Return([]dbgen.ReconciliationJob{otherBanks, previous}, nil)
+		s.mockRepo.EXPECT().ListOpenReconciliationItems(gomock.Any(), previous.ID).Return(openItems, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.Require().NoError(err)
+		carried := func(id int64) sql.NullInt64 { return sql.NullInt64{Int64: id, Valid: true} }
+		s.Equal([]dbgen.CopyReconciliationItemsParams{
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 5, TransactionID: "S-LAST", Amount: 250, Type: "DEBIT", TransactionTime: parseTime("2024-11-30T18:00:00Z"), Status: "MATCHED", CarriedFromID: carried(104)},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 3, TransactionID: "B-4", Amount: 250, Type: "DEBIT", TransactionTime: parseTime("2024-12-01T00:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 1, TransactionID: "S-1", Amount: 1000, Type: "CREDIT", TransactionTime: parseTime("2024-12-01T09:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 4, TransactionID: "N-1", Amount: 1000, Type: "CREDIT", TransactionTime: parseTime("2024-11-30T00:00:00Z"), Status: "MATCHED", CarriedFromID: carried(101)},
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 2, TransactionID: "S-2", Amount: 300, Type: "CREDIT", TransactionTime: parseTime("2024-12-02T10:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 1, TransactionID: "B-2", Amount: 300, Type: "CREDIT", TransactionTime: parseTime("2024-12-02T00:00:00Z"), Status: "MATCHED"},
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 4, TransactionID: "S-OLD", Amount: 700, Type: "DEBIT", TransactionTime: parseTime("2024-11-15T10:00:00Z"), Status: "MISSING", CarriedFromID: carried(102)},
+			{JobID: rj.ID, Source: "SYSTEM", RowNumber: 3, TransactionID: "S-3", Amount: 450, Type: "CREDIT", TransactionTime: parseTime("2024-12-10T10:00:00Z"), Status: "MISSING"},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 5, TransactionID: "N-2", Amount: 450, Type: "CREDIT", TransactionTime: parseTime("2024-11-20T00:00:00Z"), Status: "MISSING", CarriedFromID: carried(103)},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 6, TransactionID: "N-3", Amount: 90, Type: "DEBIT", TransactionTime: parseTime("2024-11-30T00:00:00Z"), Status: "MISSING", CarriedFromID: carried(105)},
+			{JobID: rj.ID, Source: "BANK", BankName: bca, RowNumber: 2, TransactionID: "B-3", Amount: 700, Type: "DEBIT", TransactionTime: parseTime("2024-12-01T00:00:00Z"), Status: "MISSING"},
+		}, collect(saved.Items))
+		s.Equal([]int64{104, 101}, saved.ResolvedItemIDs)
+
+		// the carried system transactions and the amount of the carried
+		// transactions still missing are only counted in the carry forward
+		var result entity.ReconciliationResult
+		s.Require().NoError(json.Unmarshal(saved.Result.Bytes, &result))
+		s.Equal(3, result.TotalTransactionProcessed)
+		s.Equal(2, result.TotalTransactionMatched)
+		s.Equal(1, result.TotalTransactionUnmatched)
+		s.Equal(1150.0, result.TotalDiscrepancyAmount)
+		s.Equal(&entity.CarryForwardSummary{
+			FromJobID:              &previous.ID,
+			TotalCarried:           5,
+			TotalResolved:          2,
+			TotalSystemCarried:     2,
+			TotalSystemMatched:     1,
+			TotalDiscrepancyAmount: 1240,
+		}, result.CarryForward)
+	})
+
+	s.Run("success match open items of the previous job of the same banks named differently", func() {
+		saved := expectProcess()
+		renamed := previous
+		renamed.BankTransactionCsvPaths.Set([]entity.BankTransactionCsv{{BankName: " bca ", FilePath: "path_to_file_bca"}})
+		renamedItems := slices.Clone(openItems)
+		for i, item := range renamedItems {
+			if item.BankName.Valid {
+				renamedItems[i].BankName.String = "bca "
+			}
+		}
+		// the item of a bank the job does not reconcile is not carried
+		renamedItems = append(renamedItems, dbgen.ReconciliationItem{ID: 106, JobID: previous.ID, Source: "BANK", BankName: sql.NullString{String: "BRI", Valid: true},
+			RowNumber: 10, TransactionID: "N-4", Amount: 300, Type: "CREDIT", TransactionTime: parseTime("2024-11-30T00:00:00Z"), Status: "MISSING"})
+		s.mockRepo.EXPECT().ListSuccessReconciliationJobsByEndDate(gomock.Any(), previousEndDate).Return([]dbgen.ReconciliationJob{otherBanks, renamed}, nil)
+		s.mockRepo.EXPECT().ListOpenReconciliationItems(gomock.Any(), previous.ID).Return(renamedItems, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.Require().NoError(err)
+		s.Equal([]int64{104, 101}, saved.ResolvedItemIDs)
+		var result entity.ReconciliationResult
+		s.Require().NoError(json.Unmarshal(saved.Result.Bytes, &result))
+		s.Equal(5, result.CarryForward.TotalCarried)
+		s.Equal(2, result.CarryForward.TotalResolved)
+	})
+
+	s.Run("success without previous job of the same banks", func() {
+		saved := expectProcess()
+		s.mockRepo.EXPECT().ListSuccessReconciliationJobsByEndDate(gomock.Any(), previousEndDate).Return([]dbgen.ReconciliationJob{otherBanks}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.Require().NoError(err)
+		s.Empty(saved.ResolvedItemIDs)
+		var result entity.ReconciliationResult
+		s.Require().NoError(json.Unmarshal(saved.Result.Bytes, &result))
+		s.Equal(2, result.TotalTransactionUnmatched)
+		s.Equal(&entity.CarryForwardSummary{}, result.CarryForward)
+	})
+
+	s.Run("error list open items of the previous job", func() {
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bankCsv)), nil)
+		s.mockRepo.EXPECT().ListSuccessReconciliationJobsByEndDate(gomock.Any(), previousEndDate).Return([]dbgen.ReconciliationJob{previous}, nil)
+		s.mockRepo.EXPECT().ListOpenReconciliationItems(gomock.Any(), previous.ID).Return(nil, assert.AnError)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := s.svc.Process(ctx)
+
+		s.Nil(err)
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_MatchingModes() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0
+	bankCsvs := []entity.BankTransactionCsv{
+		{
+			BankName: "BCA",
+			FilePath: "path/to/bca_transaction.csv",
+		},
+		{
+			BankName: "BRI",
+			FilePath: "path/to/bri_transaction.csv",
+		},
+	}
+	rj.BankTransactionCsvPaths.Set(bankCsvs)
+	process := func(svc *reconciliatonjob.ProcesserService, rj dbgen.ReconciliationJob) dbgen.SaveSuccessReconciliationJobWithItemsParams {
+		var saved dbgen.SaveSuccessReconciliationJobWithItemsParams
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(fetchSystemFile("system_trx.csv"), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(fetchSystemFile("bca_trx.csv"), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[1].FilePath).Return(fetchSystemFile("bri_trx.csv"), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
+			saved = copied(arg)
+			return dbgen.ReconciliationJob{}, nil
+		})
+
+		s.Require().NoError(svc.Process(ctx))
+
+		return saved
+	}
+
+	s.Run("success parallel matching produce the same result as sequential matching", func() {
+		sequentialSvc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:      1,
+			MatchWorkerSize: 1,
+		})
+		parallelSvc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:      1,
+			MatchWorkerSize: 8,
+		})
+
+		sequential := process(sequentialSvc, rj)
+		parallel := process(parallelSvc, rj)
+
+		s.Equal(string(sequential.Result.Bytes), string(parallel.Result.Bytes))
+	})
+
+	s.Run("success out of core produce the same result as in memory", func() {
+		tempDir := s.T().TempDir()
+		outOfCoreSvc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:     1,
+			OutOfCore:      true,
+			TempDir:        tempDir,
+			PartitionCount: 3,
+		})
+
+		inMemory := process(s.svc, rj)
+		outOfCore := process(outOfCoreSvc, rj)
+
+		s.Equal(string(inMemory.Result.Bytes), string(outOfCore.Result.Bytes))
+		entries, err := os.ReadDir(tempDir)
+		s.NoError(err)
+		s.Empty(entries)
+	})
+
+	s.Run("success out of core detect the same duplicates and overlaps as in memory", func() {
+		systemCsv := "ABC-1,1000,CREDIT,2024-11-01T02:00:00Z\n" +
+			"ABC-2,2000,CREDIT,2024-11-02T02:00:00Z\n" +
+			"ABC-1,1000,CREDIT,2024-11-01T02:00:00Z\n"
+		bcaCsv := "BCA-1,1000,2024-11-01\n" +
+			"BCA-2,2000,2024-11-02\n" +
+			"BCA-2,2000,2024-11-02\n"
+		briCsv := "BCA-2,2000,2024-11-02\n" +
+			"BRI-3,3000,2024-11-03\n"
+		tempDir := s.T().TempDir()
+		outOfCoreSvc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:     1,
+			OutOfCore:      true,
+			TempDir:        tempDir,
+			PartitionCount: 3,
+		})
+		processDuplicates := func(svc *reconciliatonjob.ProcesserService, rj dbgen.ReconciliationJob) dbgen.SaveSuccessReconciliationJobWithItemsParams {
+			var saved dbgen.SaveSuccessReconciliationJobWithItemsParams
+			s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+			s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+			s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(systemCsv)), nil)
+			s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(bcaCsv)), nil)
+			s.mockFileGetter.EXPECT().Get(gomock.Any(), bankCsvs[1].FilePath).Return(io.NopCloser(bytes.NewBufferString(briCsv)), nil)
+			s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
+				saved = copied(arg)
+				return dbgen.ReconciliationJob{}, nil
+			})
+
+			s.Require().NoError(svc.Process(ctx))
+
+			return saved
+		}
+
+		for _, rule := range []entity.DuplicateRule{entity.DuplicateRuleID, entity.DuplicateRuleFingerprint} {
+			rj := rj
+			rj.MatchingOptions.Set(entity.MatchingOptions{
+				DuplicateRule: rule,
+				Deduplicate:   true,
+			})
+
+			inMemory := processDuplicates(s.svc, rj)
+			outOfCore := processDuplicates(outOfCoreSvc, rj)
+
+			s.Equal(string(inMemory.Result.Bytes), string(outOfCore.Result.Bytes), rule)
+			items := collect(inMemory.Items)
+			s.Equal(items, collect(outOfCore.Items), rule)
+			var result entity.ReconciliationResult
+			s.Require().NoError(json.Unmarshal(outOfCore.Result.Bytes, &result))
+			s.Empty(result.Duplicates, rule)
+			s.Empty(result.Warnings.BankFileOverlaps, rule)
+			s.Equal(2, result.TotalTransactionDuplicate, rule)
+			s.Equal(1, result.Warnings.TotalBankFileOverlap, rule)
+			s.Contains(items, dbgen.CopyReconciliationItemsParams{
+				JobID:           rj.ID,
+				Source:          "SYSTEM",
+				RowNumber:       3,
+				TransactionID:   "ABC-1",
+				Amount:          1000,
+				Type:            "CREDIT",
+				TransactionTime: time.Date(2024, 11, 1, 2, 0, 0, 0, time.UTC),
+				Status:          "DUPLICATE",
+				DuplicateOfRow:  sql.NullInt32{Int32: 1, Valid: true},
+			}, rule)
+			overlaps := 0
+			for _, item := range items {
+				if item.OverlapBankName.Valid {
+					overlaps++
+					s.Equal(sql.NullString{String: "BRI", Valid: true}, item.BankName, rule)
+					s.Equal(int32(1), item.RowNumber, rule)
+					s.Equal(sql.NullString{String: "BCA", Valid: true}, item.OverlapBankName, rule)
+					s.Equal(sql.NullInt32{Int32: 2, Valid: true}, item.OverlapRow, rule)
+				}
+			}
+			s.Equal(1, overlaps, rule)
+			s.Equal(2, result.TotalTransactionProcessed, rule)
+		}
+		entries, err := os.ReadDir(tempDir)
+		s.NoError(err)
+		s.Empty(entries)
+	})
+
+	s.Run("error create temporary partition files", func() {
+		svc := reconciliatonjob.NewProcesserService(s.mockRepo, s.mockFileGetter, config.ProcesserConfig{
+			WorkerSize:     1,
+			OutOfCore:      true,
+			TempDir:        "/path/not/exist",
+			PartitionCount: 3,
+		})
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockRepo.EXPECT().SaveFailedReconciliationJob(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil)
+
+		err := svc.Process(ctx)
+
+		s.NoError(err)
+	})
+}
+
+func (s *ReconciliationJobProcessorTestSuite) TestProcess_ContentHash() {
+	ctx := context.Background()
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC)
+	rj.DiscrepancyThreshold = 0
+	systemCsv := []string{
+		"ABC-1,1000,CREDIT,2024-11-01T02:00:00Z",
+		"ABC-2,2000,CREDIT,2024-11-02T02:00:00Z",
+		"ABC-3,3000,DEBIT,2024-11-03T02:00:00Z",
+	}
+	bankCsv := []string{
+		"BCA-1,1000,2024-11-01",
+		"BCA-2,2000,2024-11-02",
+		"BCA-4,4000,2024-11-04",
+	}
+	contentHash := func(systemCsv, bankCsv []string) string {
+		var saved dbgen.SaveSuccessReconciliationJobWithItemsParams
+		s.mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil)
+		s.mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).Return(io.NopCloser(bytes.NewBufferString(strings.Join(systemCsv, "\n"))), nil)
+		s.mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).Return(io.NopCloser(bytes.NewBufferString(strings.Join(bankCsv, "\n"))), nil)
+		s.mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
+			saved = copied(arg)
+			return dbgen.ReconciliationJob{}, nil
+		})
+
+		s.Require().NoError(s.svc.Process(ctx))
+
+		var result entity.ReconciliationResult
+		s.Require().NoError(json.Unmarshal(saved.Result.Bytes, &result))
+		return result.ContentHash
+	}
+
+	s.Run("success hash of the result content", func() {
+		s.Equal("10c2563f4cd4fc75509bc948f28c0e7e4ffef6e518482299211961e8eb38cbaf", contentHash(systemCsv, bankCsv))
+	})
+
+	s.Run("success reordered input produce the same hash", func() {
+		reversedSystemCsv := slices.Clone(systemCsv)
+		slices.Reverse(reversedSystemCsv)
+		reversedBankCsv := slices.Clone(bankCsv)
+		slices.Reverse(reversedBankCsv)
+
+		s.Equal(contentHash(systemCsv, bankCsv), contentHash(reversedSystemCsv, reversedBankCsv))
+	})
+
+	s.Run("success changed content produce a different hash", func() {
+		changedBankCsv := slices.Clone(bankCsv)
+		changedBankCsv[2] = "BCA-4,4001,2024-11-04"
+
+		s.NotEqual(contentHash(systemCsv, bankCsv), contentHash(systemCsv, changedBankCsv))
+	})
+}
+
+func BenchmarkProcess_Matching(b *testing.B) {
+	const (
+		totalDays      = 16
+		totalTrxPerDay = 2000
+	)
+	systemCsv, bankCsv := generateSyntheticCsv(totalDays, totalTrxPerDay)
+	rj := dbReconJob
+	rj.StartDate = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rj.EndDate = rj.StartDate.AddDate(0, 0, totalDays)
+	rj.DiscrepancyThreshold = 0
+
+	for _, bc := range []struct {
+		name            string
+		matchWorkerSize int
+	}{
+		{name: "sequential", matchWorkerSize: 1},
+		{name: "parallel", matchWorkerSize: runtime.GOMAXPROCS(0)},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(b)
+			mockRepo := mock_reconciliatonjob.NewMockProcesserRepository(ctrl)
+			mockFileGetter := mock_reconciliatonjob.NewMockFileGetter(ctrl)
+			svc := reconciliatonjob.NewProcesserService(mockRepo, mockFileGetter, config.ProcesserConfig{
+				WorkerSize:      1,
+				MatchWorkerSize: bc.matchWorkerSize,
+			})
+			mockRepo.EXPECT().ListPendingReconciliationJobs(ctx).Return([]dbgen.ReconciliationJob{rj}, nil).AnyTimes()
+			mockRepo.EXPECT().StartReconciliationJob(ctx, claimParams(rj.ID)).Return(rj, nil).AnyTimes()
+			mockFileGetter.EXPECT().Get(gomock.Any(), rj.SystemTransactionCsvPath).DoAndReturn(func(_ context.Context, _ string) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(systemCsv)), nil
+			}).AnyTimes()
+			mockFileGetter.EXPECT().Get(gomock.Any(), entityReconJob.BankTransactionCsvPaths[0].FilePath).DoAndReturn(func(_ context.Context, _ string) (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(bankCsv)), nil
+			}).AnyTimes()
+			mockRepo.EXPECT().SaveSuccessReconciliationJobWithItems(gomock.Any(), gomock.Any()).Return(dbgen.ReconciliationJob{}, nil).AnyTimes()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := svc.Process(ctx); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// generateSyntheticCsv generate system and bank transaction csv where every
+// system transaction has a matching bank transaction on the same day, the bank
+// transactions are written in reverse order to make the matching scan longer
+func generateSyntheticCsv(totalDays, totalTrxPerDay int) ([]byte, []byte) {
+	systemBuf, bankBuf := bytes.Buffer{}, bytes.Buffer{}
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for day := 0; day < totalDays; day++ {
+		date := startDate.AddDate(0, 0, day)
+		for i := 0; i < totalTrxPerDay; i++ {
+			amount := (day*totalTrxPerDay + i + 1) * 1000
+			fmt.Fprintf(&systemBuf, "TRX-%d-%d,%d,CREDIT,%s\n", day, i, amount, date.Add(time.Duration(i)*time.Second).Format(time.RFC3339))
+		}
+		for i := totalTrxPerDay - 1; i >= 0; i-- {
+			amount := (day*totalTrxPerDay + i + 1) * 1000
+			fmt.Fprintf(&bankBuf, "BANK-%d-%d,%d,%s\n", day, i, amount, date.Format(time.DateOnly))
+		}
+	}
+
+	return systemBuf.Bytes(), bankBuf.Bytes()
+}
+
+var noWarnings = entity.ReconciliationWarnings{
+	BankFileOverlaps: []entity.OverlapTransaction{},
+}
+
+var noTransfers = []entity.InternalTransfer{}
+
+var noReviews = []entity.ReviewMatch{}
+
+// claimParams is the claim of the job with the default lease
+func claimParams(id int64) dbgen.StartReconciliationJobParams {
+	return dbgen.StartReconciliationJobParams{
+		ID:           id,
+		LeaseSeconds: (2 * time.Minute).Seconds(),
+	}
+}
+
+func withContentHash(result entity.ReconciliationResult) entity.ReconciliationResult {
+	b, _ := json.Marshal(result)
+	sum := sha256.Sum256(b)
+	result.ContentHash = hex.EncodeToString(sum[:])
+	return result
+}
+
+// savedJobMatcher match the saved job with the expected result, the saved
+// summary only holds the totals so the lists of the expected result are
+// dropped, except the missing transactions that are rebuilt from the saved
+// items, the lists are still covered by the content hash
+type savedJobMatcher struct {
+	expected dbgen.SaveSuccessReconciliationJobParams
+}
+
+// collect return every value of the sequence, or nil when the sequence fails
+// so the values never equal the expected values
+func collect[T any](seq iter.Seq2[T, error]) []T {
+	values := []T{}
+	for v, err := range seq {
+		if err != nil {
+			return nil
+		}
+		values = append(values, v)
+	}
+
+	return values
+}
+
+// copied return the saved job with its items and matches copied, the items
+// and the matches are generated from the job while they are copied so they
+// are no longer available once the job is saved
+func copied(arg dbgen.SaveSuccessReconciliationJobWithItemsParams) dbgen.SaveSuccessReconciliationJobWithItemsParams {
+	arg.Items = values(collect(arg.Items))
+	arg.Matches = values(collect(arg.Matches))
+
+	return arg
+}
+
+// values return the sequence of the values without error
+func values[T any](values []T) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, v := range values {
+			if !yield(v, nil) {
+				return
+			}
+		}
+	}
+}
+
+func savedJob(expected dbgen.SaveSuccessReconciliationJobParams) gomock.Matcher {
+	return savedJobMatcher{expected: expected}
+}
+
+func (m savedJobMatcher) Matches(x any) bool {
+	arg, ok := x.(dbgen.SaveSuccessReconciliationJobWithItemsParams)
+	if !ok || arg.ID != m.expected.ID {
+		return false
+	}
+	var expected, actual entity.ReconciliationResult
+	if err := json.Unmarshal(m.expected.Result.Bytes, &expected); err != nil {
+		return false
+	}
+	if err := json.Unmarshal(arg.Result.Bytes, &actual); err != nil {
+		return false
+	}
+	expected.NeedsReview = nil
+	expected.Duplicates = nil
+	expected.Reversals = nil
+	expected.InternalTransfers = nil
+	expected.Warnings.BankFileOverlaps = nil
+	for bankName, fee := range expected.BankFees {
+		fee.Matches = nil
+		expected.BankFees[bankName] = fee
+	}
+	actual.MissingTransactions = []entity.Transaction{}
+	actual.MissingBankTransactions = map[string][]entity.Transaction{}
+	for _, item := range collect(arg.Items) {
+		if item.Status != string(entity.ReconciliationItemStatusMissing) {
+			continue
+		}
+		trx := entity.Transaction{
+			ID:     item.TransactionID,
+			Amount: item.Amount,
+			Type:   entity.TransactionType(item.Type),
+			Time:   item.TransactionTime,
+		}
+		if !item.BankName.Valid {
+			actual.MissingTransactions = append(actual.MissingTransactions, trx)
+			continue
+		}
+		actual.MissingBankTransactions[item.BankName.String] = append(actual.MissingBankTransactions[item.BankName.String], trx)
+	}
+	expectedBytes, _ := json.Marshal(expected)
+	actualBytes, _ := json.Marshal(actual)
+
+	return string(expectedBytes) == string(actualBytes)
+}
+
+func (m savedJobMatcher) String() string {
+	return fmt.Sprintf("saved job %d with result %s", m.expected.ID, m.expected.Result.Bytes)
+}
+
 func parseTime(t string) time.Time {
 	res, _ := time.Parse(time.RFC3339, t)
 	return res
 }
 
-func fetchSystemFile(filename string) *bytes.Buffer {
-	f, _ := os.ReadFile("../../test/data/" + filename)
-	return bytes.NewBuffer(f)
+func fetchSystemFile(filename string) io.ReadCloser {
+	f, _ := os.Open("../../test/data/" + filename)
+	return f
 }
@@ -0,0 +1,82 @@
+package reconciliatonjob
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/delly/amartha/entity"
+)
+
+// progressTracker track progress of processing reconciliation job,
+// it is safe to be used concurrently
+type progressTracker struct {
+	mu           sync.Mutex
+	progress     entity.ReconciliationJobProgress
+	changed      bool
+	phaseChanged chan struct{}
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{
+		progress: entity.ReconciliationJobProgress{
+			RowsParsed: map[string]int{},
+		},
+		phaseChanged: make(chan struct{}, 1),
+	}
+}
+
+func (p *progressTracker) setPhase(phase entity.ReconciliationJobPhase) {
+	p.mu.Lock()
+	p.progress.Phase = phase
+	p.changed = true
+	p.mu.Unlock()
+
+	// notify without blocking, a pending notification is enough to
+	// make the reporter persist the latest progress
+	select {
+	case p.phaseChanged <- struct{}{}:
+	default:
+	}
+}
+
+func (p *progressTracker) addSystemRowParsed() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.SystemRowsParsed++
+	p.changed = true
+}
+
+func (p *progressTracker) addBankRowParsed(bankName string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.RowsParsed[bankName]++
+	p.changed = true
+}
+
+func (p *progressTracker) setTotalTransactions(total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.TotalTransactions = total
+	p.changed = true
+}
+
+func (p *progressTracker) addTransactionsProcessed(processed, matched int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress.TransactionsProcessed += processed
+	p.progress.TransactionsMatched += matched
+	p.changed = true
+}
+
+// snapshot return copy of the current progress and whether it has changed
+// since the previous snapshot
+func (p *progressTracker) snapshot() (entity.ReconciliationJobProgress, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	res := p.progress
+	res.RowsParsed = maps.Clone(p.progress.RowsParsed)
+	changed := p.changed
+	p.changed = false
+
+	return res, changed
+}
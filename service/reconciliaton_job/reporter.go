@@ -0,0 +1,303 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/delly/amartha/common/logger"
+	"github.com/delly/amartha/common/pdf"
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	"go.uber.org/zap"
+)
+
+const (
+	reportTimeFormat = "2006-01-02 15:04:05"
+	// reportMaxItems is the number of transactions listed per exception table,
+	// the rest is only counted so the report of a huge job stays small, the
+	// full list is in the CSV export
+	reportMaxItems = 500
+)
+
+// column widths of the report tables, every table spans the page content
+var (
+	reportSummaryWidths     = []float64{250, pdf.ContentWidth - 250}
+	reportBankWidths        = []float64{165, 165, pdf.ContentWidth - 330}
+	reportItemWidths        = []float64{40, 190, 50, 115, pdf.ContentWidth - 395}
+	reportNeedsReviewWidths = []float64{150, 150, 70, 70, pdf.ContentWidth - 440}
+	reportSignatureWidth    = 200.0
+)
+
+// reportTotalKey is the file and the status the items are counted by, bank
+// name is empty for the system file
+type reportTotalKey struct {
+	source   entity.ReconciliationItemSource
+	bankName string
+	status   entity.ReconciliationItemStatus
+}
+
+// reportTotals hold the number and the amount of items per file and status
+type reportTotals map[reportTotalKey]dbgen.SummarizeReconciliationItemsRow
+
+func (t reportTotals) get(source entity.ReconciliationItemSource, bankName string, status entity.ReconciliationItemStatus) dbgen.SummarizeReconciliationItemsRow {
+	return t[reportTotalKey{source: source, bankName: bankName, status: status}]
+}
+
+// bankTotal return the number of items of a bank file in every status
+func (t reportTotals) bankTotal(bankName string) int64 {
+	var total int64
+	for key, row := range t {
+		if key.source == entity.ReconciliationItemSourceBank && key.bankName == bankName {
+			total += row.TotalTransaction
+		}
+	}
+
+	return total
+}
+
+// Reporter is a contract to write the printable report of reconciliation job
+type Reporter interface {
+	Report(ctx context.Context, job *entity.ReconciliationJob, w io.Writer) error
+}
+
+// ReporterRepository is a contract to read the result of reconciliation job to be reported
+type ReporterRepository interface {
+	ListMissingReconciliationItems(ctx context.Context, arg dbgen.ListMissingReconciliationItemsParams) ([]dbgen.ReconciliationItem, error)
+	ListReconciliationMatchedPairs(ctx context.Context, arg dbgen.ListReconciliationMatchedPairsParams) ([]dbgen.ListReconciliationMatchedPairsRow, error)
+	SummarizeReconciliationItems(ctx context.Context, jobID int64) ([]dbgen.SummarizeReconciliationItemsRow, error)
+}
+
+// ReporterService is a service to write the printable report of reconciliation job
+type ReporterService struct {
+	repo ReporterRepository
+	log  *zap.Logger
+}
+
+var _ = Reporter(&ReporterService{})
+
+// NewReporterService create new reporter service
+func NewReporterService(repo ReporterRepository) *ReporterService {
+	return &ReporterService{
+		repo: repo,
+		log:  zap.L().With(zap.String("service", "reconciliation_job.reporter")),
+	}
+}
+
+// Report write a PDF of a successful job, the first page has its period and
+// banks, the summary, the discrepancy per bank and the missing system
+// transactions, then every bank account has its own statement on a new page
+// with its totals, its itemized exceptions and a section to be signed by the
+// approver, the document is written only once it is complete so nothing is
+// written on error
+func (s *ReporterService) Report(ctx context.Context, job *entity.ReconciliationJob, w io.Writer) error {
+	log := logger.WithMethod(s.log, "Report")
+	if job.Status != entity.ReconciliationJobStatusSuccess || job.Result == nil {
+		return ErrReconciliationJobNotSuccess
+	}
+
+	rows, err := s.repo.SummarizeReconciliationItems(ctx, job.ID)
+	if err != nil {
+		log.Error("failed to summarize reconciliation items", zap.Error(err), zap.Int64("id", job.ID))
+		return err
+	}
+	totals := reportTotals{}
+	for _, row := range rows {
+		key := reportTotalKey{
+			source:   entity.ReconciliationItemSource(row.Source),
+			bankName: row.BankName.String,
+			status:   entity.ReconciliationItemStatus(row.Status),
+		}
+		totals[key] = row
+	}
+
+	doc := pdf.New()
+	s.writeHeader(doc, job)
+	s.writeSummary(doc, job.Result)
+	s.writeBankDiscrepancies(doc, job, totals)
+	if err := s.writeMissingSystemTransactions(ctx, doc, job, totals); err != nil {
+		log.Error("failed to report reconciliation job", zap.Error(err), zap.Int64("id", job.ID))
+		return err
+	}
+	for _, bank := range job.BankTransactionCsvPaths {
+		doc.PageBreak()
+		if err := s.writeBankStatement(ctx, doc, job, bank.BankName, totals); err != nil {
+			log.Error("failed to report reconciliation job", zap.Error(err), zap.Int64("id", job.ID), zap.String("bank_name", bank.BankName))
+			return err
+		}
+	}
+
+	if _, err := doc.WriteTo(w); err != nil {
+		log.Error("failed to write reconciliation job report", zap.Error(err), zap.Int64("id", job.ID))
+		return err
+	}
+
+	return nil
+}
+
+func (s *ReporterService) writeHeader(doc *pdf.Document, job *entity.ReconciliationJob) {
+	bankNames := make([]string, 0, len(job.BankTransactionCsvPaths))
+	for _, bank := range job.BankTransactionCsvPaths {
+		bankNames = append(bankNames, bank.BankName)
+	}
+	doc.Title("Reconciliation Summary")
+	doc.Text(fmt.Sprintf("Job ID: %d", job.ID))
+	doc.Text(fmt.Sprintf("Period: %s to %s", job.StartDate.Format(exportDateFormat), job.EndDate.Format(exportDateFormat)))
+	doc.Text("Banks: " + strings.Join(bankNames, ", "))
+	doc.Text("Completed At: " + job.UpdatedAt.Format(reportTimeFormat))
+}
+
+func (s *ReporterService) writeSummary(doc *pdf.Document, result *entity.ReconciliationResult) {
+	doc.Heading("Summary")
+	for _, row := range [][]string{
+		{"Total Transaction Processed", strconv.Itoa(result.TotalTransactionProcessed)},
+		{"Total Transaction Matched", strconv.Itoa(result.TotalTransactionMatched)},
+		{"Total Transaction Unmatched", strconv.Itoa(result.TotalTransactionUnmatched)},
+		{"Total Transaction Needs Review", strconv.Itoa(result.TotalTransactionNeedsReview)},
+		{"Total Transaction Reversed", strconv.Itoa(result.TotalTransactionReversed)},
+		{"Total Discrepancy Amount", formatReportAmount(result.TotalDiscrepancyAmount)},
+	} {
+		doc.Row(reportSummaryWidths, false, row...)
+	}
+}
+
+// writeBankDiscrepancies write the unmatched transactions of the system file
+// and of every bank file, the files without any unmatched transaction are
+// written as zero
+func (s *ReporterService) writeBankDiscrepancies(doc *pdf.Document, job *entity.ReconciliationJob, totals reportTotals) {
+	doc.Heading("Discrepancy per Bank")
+	doc.Row(reportBankWidths, true, "Source", "Unmatched Transactions", "Discrepancy Amount")
+	system := totals.get(entity.ReconciliationItemSourceSystem, "", entity.ReconciliationItemStatusMissing)
+	doc.Row(reportBankWidths, false, string(entity.ReconciliationItemSourceSystem),
+		strconv.FormatInt(system.TotalTransaction, 10), formatReportAmount(system.TotalAmount))
+	for _, bank := range job.BankTransactionCsvPaths {
+		row := totals.get(entity.ReconciliationItemSourceBank, bank.BankName, entity.ReconciliationItemStatusMissing)
+		doc.Row(reportBankWidths, false, bank.BankName,
+			strconv.FormatInt(row.TotalTransaction, 10), formatReportAmount(row.TotalAmount))
+	}
+}
+
+func (s *ReporterService) writeMissingSystemTransactions(ctx context.Context, doc *pdf.Document, job *entity.ReconciliationJob, totals reportTotals) error {
+	doc.Heading("Exceptions: Missing System Transactions")
+
+	return s.writeMissingItems(ctx, doc, dbgen.ListMissingReconciliationItemsParams{
+		JobID:  job.ID,
+		Source: string(entity.ReconciliationItemSourceSystem),
+	}, totals.get(entity.ReconciliationItemSourceSystem, "", entity.ReconciliationItemStatusMissing).TotalTransaction)
+}
+
+// writeBankStatement write the statement of a bank account with its own
+// header, its totals, its missing transactions, its matches that need review
+// and the approval section
+func (s *ReporterService) writeBankStatement(ctx context.Context, doc *pdf.Document, job *entity.ReconciliationJob, bankName string, totals reportTotals) error {
+	doc.Title("Reconciliation Statement")
+	doc.Text(fmt.Sprintf("Job ID: %d", job.ID))
+	doc.Text("Bank Account: " + bankName)
+	doc.Text(fmt.Sprintf("Period: %s to %s", job.StartDate.Format(exportDateFormat), job.EndDate.Format(exportDateFormat)))
+	doc.Text("Completed At: " + job.UpdatedAt.Format(reportTimeFormat))
+
+	get := func(status entity.ReconciliationItemStatus) dbgen.SummarizeReconciliationItemsRow {
+		return totals.get(entity.ReconciliationItemSourceBank, bankName, status)
+	}
+	missing := get(entity.ReconciliationItemStatusMissing)
+	needsReview := get(entity.ReconciliationItemStatusNeedsReview)
+	doc.Heading("Summary")
+	for _, row := range [][]string{
+		{"Total Bank Transaction", strconv.FormatInt(totals.bankTotal(bankName), 10)},
+		{"Total Transaction Matched", strconv.FormatInt(get(entity.ReconciliationItemStatusMatched).TotalTransaction, 10)},
+		{"Total Transaction Unmatched", strconv.FormatInt(missing.TotalTransaction, 10)},
+		{"Total Transaction Needs Review", strconv.FormatInt(needsReview.TotalTransaction, 10)},
+		{"Total Transaction Reversed", strconv.FormatInt(get(entity.ReconciliationItemStatusReversed).TotalTransaction, 10)},
+		{"Total Transaction Internal Transfer", strconv.FormatInt(get(entity.ReconciliationItemStatusInternalTransfer).TotalTransaction, 10)},
+		{"Total Transaction Duplicate", strconv.FormatInt(get(entity.ReconciliationItemStatusDuplicate).TotalTransaction, 10)},
+		{"Total Discrepancy Amount", formatReportAmount(missing.TotalAmount)},
+	} {
+		doc.Row(reportSummaryWidths, false, row...)
+	}
+
+	bank := sql.NullString{String: bankName, Valid: true}
+	doc.Heading("Exceptions: Missing Bank Transactions")
+	if err := s.writeMissingItems(ctx, doc, dbgen.ListMissingReconciliationItemsParams{
+		JobID:    job.ID,
+		Source:   string(entity.ReconciliationItemSourceBank),
+		BankName: bank,
+	}, missing.TotalTransaction); err != nil {
+		return err
+	}
+	if err := s.writeNeedsReview(ctx, doc, job, bank, needsReview.TotalTransaction); err != nil {
+		return err
+	}
+	s.writeSignature(doc)
+
+	return nil
+}
+
+// writeMissingItems write the first reportMaxItems missing items of a file
+// followed by the number of items that are not listed
+func (s *ReporterService) writeMissingItems(ctx context.Context, doc *pdf.Document, params dbgen.ListMissingReconciliationItemsParams, total int64) error {
+	params.Limit = reportMaxItems
+	items, err := s.repo.ListMissingReconciliationItems(ctx, params)
+	if err != nil {
+		return err
+	}
+
+	doc.Row(reportItemWidths, true, "Row", "Transaction ID", "Type", "Time", "Amount")
+	for _, item := range items {
+		doc.Row(reportItemWidths, false, strconv.Itoa(int(item.RowNumber)), item.TransactionID, item.Type,
+			item.TransactionTime.Format(reportTimeFormat), formatReportAmount(item.Amount))
+	}
+	writeReportRemainder(doc, len(items), total)
+
+	return nil
+}
+
+// writeNeedsReview write the first reportMaxItems matches of a bank below the
+// minimum confidence followed by the number of matches that are not listed
+func (s *ReporterService) writeNeedsReview(ctx context.Context, doc *pdf.Document, job *entity.ReconciliationJob, bank sql.NullString, total int64) error {
+	pairs, err := s.repo.ListReconciliationMatchedPairs(ctx, dbgen.ListReconciliationMatchedPairsParams{
+		JobID:    job.ID,
+		Status:   sql.NullString{String: string(entity.ReconciliationItemStatusNeedsReview), Valid: true},
+		BankName: bank,
+		Limit:    reportMaxItems,
+	})
+	if err != nil {
+		return err
+	}
+
+	doc.Heading("Exceptions: Needs Review")
+	doc.Row(reportNeedsReviewWidths, true, "System Transaction ID", "Bank Transaction ID", "System Amount", "Bank Amount", "Confidence")
+	for _, pair := range pairs {
+		doc.Row(reportNeedsReviewWidths, false, pair.SystemTransactionID, pair.BankTransactionID,
+			formatReportAmount(pair.SystemAmount), formatReportAmount(pair.BankAmount),
+			strconv.FormatFloat(pair.Confidence, 'f', 2, 64))
+	}
+	writeReportRemainder(doc, len(pairs), total)
+
+	return nil
+}
+
+// writeReportRemainder write None for an empty table, or the number of rows
+// left out of a table capped at reportMaxItems
+func writeReportRemainder(doc *pdf.Document, listed int, total int64) {
+	if listed == 0 && total == 0 {
+		doc.Text("None")
+		return
+	}
+	if rest := total - int64(listed); rest > 0 {
+		doc.Text(fmt.Sprintf("%d more transactions are not listed, the full list is in the CSV export", rest))
+	}
+}
+
+func (s *ReporterService) writeSignature(doc *pdf.Document) {
+	doc.Heading("Approval")
+	for _, label := range []string{"Prepared By:", "Approved By:", "Signature:", "Date:"} {
+		doc.BlankField(label, reportSignatureWidth)
+	}
+}
+
+func formatReportAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
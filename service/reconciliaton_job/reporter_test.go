@@ -0,0 +1,228 @@
+package reconciliatonjob_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	mock_reconciliatonjob "github.com/delly/amartha/test/mock/service/reconciliaton_job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type ReporterTestSuite struct {
+	suite.Suite
+	repo *mock_reconciliatonjob.MockReporterRepository
+	svc  *reconciliatonjob.ReporterService
+}
+
+func (s *ReporterTestSuite) SetupTest() {
+	ctrl := gomock.NewController(s.T())
+	s.repo = mock_reconciliatonjob.NewMockReporterRepository(ctrl)
+	s.svc = reconciliatonjob.NewReporterService(s.repo)
+}
+
+func TestReporterTestSuite(t *testing.T) {
+	suite.Run(t, new(ReporterTestSuite))
+}
+
+func (s *ReporterTestSuite) TestReport() {
+	ctx := context.Background()
+	trxTime := time.Date(2024, 11, 1, 10, 0, 0, 0, time.UTC)
+	job := &entity.ReconciliationJob{
+		ID:        id,
+		Status:    entity.ReconciliationJobStatusSuccess,
+		StartDate: time.Date(2024, 11, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 11, 30, 0, 0, 0, 0, time.UTC),
+		UpdatedAt: time.Date(2024, 12, 1, 8, 30, 0, 0, time.UTC),
+		BankTransactionCsvPaths: []entity.BankTransactionCsv{
+			{BankName: "BCA", FilePath: "path_to_file_bca"},
+			{BankName: "BRI", FilePath: "path_to_file_bri"},
+		},
+		Result: &entity.ReconciliationResult{
+			TotalTransactionProcessed:   4,
+			TotalTransactionMatched:     2,
+			TotalTransactionUnmatched:   1,
+			TotalTransactionNeedsReview: 1,
+			TotalDiscrepancyAmount:      1500.5,
+		},
+	}
+	reviewPair := dbgen.ListReconciliationMatchedPairsRow{
+		ID:                    5,
+		BankName:              "BCA",
+		Status:                "NEEDS_REVIEW",
+		Confidence:            0.594,
+		SystemRow:             3,
+		SystemTransactionID:   "TRX-3",
+		SystemAmount:          200,
+		SystemType:            "CREDIT",
+		SystemTransactionTime: trxTime,
+		BankRow:               3,
+		BankTransactionID:     "BCA-3",
+		BankAmount:            199,
+		BankType:              "CREDIT",
+		BankTransactionTime:   trxTime,
+	}
+	systemItem := dbgen.ReconciliationItem{
+		ID:              3,
+		JobID:           id,
+		Source:          "SYSTEM",
+		RowNumber:       2,
+		TransactionID:   "TRX-2",
+		Amount:          500.5,
+		Type:            "DEBIT",
+		TransactionTime: trxTime,
+		Status:          "MISSING",
+	}
+	bankItem := dbgen.ReconciliationItem{
+		ID:              4,
+		JobID:           id,
+		Source:          "BANK",
+		BankName:        sql.NullString{String: "BRI", Valid: true},
+		RowNumber:       1,
+		TransactionID:   "BRI-1",
+		Amount:          1000,
+		Type:            "CREDIT",
+		TransactionTime: trxTime,
+		Status:          "MISSING",
+	}
+
+	bca := sql.NullString{String: "BCA", Valid: true}
+	bri := sql.NullString{String: "BRI", Valid: true}
+	needsReview := sql.NullString{String: "NEEDS_REVIEW", Valid: true}
+
+	s.Run("success report every section", func() {
+		var buf bytes.Buffer
+		s.repo.EXPECT().SummarizeReconciliationItems(ctx, id).Return([]dbgen.SummarizeReconciliationItemsRow{
+			{Source: "SYSTEM", Status: "MATCHED", TotalTransaction: 2, TotalAmount: 3000},
+			{Source: "SYSTEM", Status: "MISSING", TotalTransaction: 1, TotalAmount: 500.5},
+			{Source: "BANK", BankName: bca, Status: "MATCHED", TotalTransaction: 2, TotalAmount: 3000},
+			{Source: "BANK", BankName: bca, Status: "NEEDS_REVIEW", TotalTransaction: 1, TotalAmount: 199},
+			{Source: "BANK", BankName: bri, Status: "MISSING", TotalTransaction: 1, TotalAmount: 1000},
+		}, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "SYSTEM", Limit: 500}).Return([]dbgen.ReconciliationItem{systemItem}, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "BANK", BankName: bca, Limit: 500}).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, dbgen.ListReconciliationMatchedPairsParams{JobID: id, Status: needsReview, BankName: bca, Limit: 500}).
+			Return([]dbgen.ListReconciliationMatchedPairsRow{reviewPair}, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "BANK", BankName: bri, Limit: 500}).Return([]dbgen.ReconciliationItem{bankItem}, nil)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, dbgen.ListReconciliationMatchedPairsParams{JobID: id, Status: needsReview, BankName: bri, Limit: 500}).Return(nil, nil)
+
+		err := s.svc.Report(ctx, job, &buf)
+
+		s.Require().NoError(err)
+		content := buf.String()
+		s.True(strings.HasPrefix(content, "%PDF-1.4\n"))
+		s.True(strings.HasSuffix(content, "%%EOF\n"))
+		s.Contains(content, "/Count 3")
+		for _, text := range []string{
+			"Reconciliation Summary",
+			"Job ID: 1",
+			"Period: 2024-11-01 to 2024-11-30",
+			"Banks: BCA, BRI",
+			"Completed At: 2024-12-01 08:30:00",
+			"Total Transaction Processed", "Total Discrepancy Amount", "1500.50",
+			"Discrepancy per Bank",
+			"Exceptions: Missing System Transactions", "TRX-2", "500.50",
+			"Bank Account: BCA", "Bank Account: BRI", "Total Bank Transaction",
+			"Exceptions: Missing Bank Transactions", "BRI-1", "1000.00",
+			"Exceptions: Needs Review", "TRX-3", "BCA-3", "0.59",
+		} {
+			s.Contains(content, "("+text+") Tj")
+		}
+		// every bank account has its own statement to be signed
+		s.Equal(2, strings.Count(content, "(Reconciliation Statement) Tj"))
+		for _, text := range []string{"Approval", "Prepared By:", "Approved By:", "Signature:", "Date:"} {
+			s.Equal(2, strings.Count(content, "("+text+") Tj"), text)
+		}
+		// BCA has no unmatched transaction but is still reported
+		s.Contains(content, "(BCA) Tj")
+		s.Equal(2, strings.Count(content, "(0.00) Tj"))
+	})
+
+	s.Run("success report no exceptions", func() {
+		var buf bytes.Buffer
+		job := *job
+		job.Result = &entity.ReconciliationResult{TotalTransactionProcessed: 2, TotalTransactionMatched: 2}
+		s.repo.EXPECT().SummarizeReconciliationItems(ctx, id).Return(nil, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, gomock.Any()).Return(nil, nil).Times(3)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return(nil, nil).Times(2)
+
+		err := s.svc.Report(ctx, &job, &buf)
+
+		s.Require().NoError(err)
+		s.Equal(5, strings.Count(buf.String(), "(None) Tj"))
+	})
+
+	s.Run("success list at most 500 transactions per exception", func() {
+		var buf bytes.Buffer
+		job := *job
+		job.BankTransactionCsvPaths = job.BankTransactionCsvPaths[:1]
+		items := make([]dbgen.ReconciliationItem, 500)
+		for i := range items {
+			items[i] = systemItem
+		}
+		s.repo.EXPECT().SummarizeReconciliationItems(ctx, id).Return([]dbgen.SummarizeReconciliationItemsRow{
+			{Source: "SYSTEM", Status: "MISSING", TotalTransaction: 1200, TotalAmount: 600600},
+		}, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, dbgen.ListMissingReconciliationItemsParams{JobID: id, Source: "SYSTEM", Limit: 500}).Return(items, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, gomock.Any()).Return(nil, nil)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return(nil, nil)
+
+		err := s.svc.Report(ctx, &job, &buf)
+
+		s.Require().NoError(err)
+		s.Equal(500, strings.Count(buf.String(), "(TRX-2) Tj"))
+		s.Contains(buf.String(), "(700 more transactions are not listed, the full list is in the CSV export) Tj")
+	})
+
+	s.Run("job is not success", func() {
+		var buf bytes.Buffer
+		job := *job
+		job.Status = entity.ReconciliationJobStatusFailed
+
+		err := s.svc.Report(ctx, &job, &buf)
+
+		s.ErrorIs(err, reconciliatonjob.ErrReconciliationJobNotSuccess)
+		s.Zero(buf.Len())
+	})
+
+	s.Run("error list missing items write nothing", func() {
+		var buf bytes.Buffer
+		s.repo.EXPECT().SummarizeReconciliationItems(ctx, id).Return(nil, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+		err := s.svc.Report(ctx, job, &buf)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Zero(buf.Len())
+	})
+
+	s.Run("error list needs review write nothing", func() {
+		var buf bytes.Buffer
+		s.repo.EXPECT().SummarizeReconciliationItems(ctx, id).Return(nil, nil)
+		s.repo.EXPECT().ListMissingReconciliationItems(ctx, gomock.Any()).Return(nil, nil).Times(2)
+		s.repo.EXPECT().ListReconciliationMatchedPairs(ctx, gomock.Any()).Return(nil, assert.AnError)
+
+		err := s.svc.Report(ctx, job, &buf)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Zero(buf.Len())
+	})
+
+	s.Run("error summarize items", func() {
+		var buf bytes.Buffer
+		s.repo.EXPECT().SummarizeReconciliationItems(ctx, id).Return(nil, assert.AnError)
+
+		err := s.svc.Report(ctx, job, &buf)
+
+		s.ErrorIs(err, assert.AnError)
+		s.Zero(buf.Len())
+	})
+}
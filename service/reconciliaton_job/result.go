@@ -0,0 +1,1094 @@
+package reconciliatonjob
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"hash"
+	"hash/fnv"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/delly/amartha/entity"
+)
+
+// systemFileKey is the key of the system transaction file in the result maps
+// keyed by bank name, so a bank cannot be named after it
+const systemFileKey = "SYSTEM"
+
+// matchedPair is a system transaction and the bank transaction it matches
+type matchedPair struct {
+	system      rowTransaction
+	bankIdx     int
+	bank        rowTransaction
+	expectedFee float64
+	hasFeeRule  bool
+	confidence  entity.MatchConfidence
+}
+
+// partitionResult is the reconciliation result of a single partition
+type partitionResult struct {
+	totalProcessed  int
+	totalMatched    int
+	matches         []matchedPair
+	reviews         []matchedPair
+	missingTrxs     []rowTransaction
+	missingBankTrxs [][]rowTransaction
+}
+
+// itemFinding is what is found on a transaction besides its status, a nil
+// position is not found
+type itemFinding struct {
+	duplicateOfRow *int
+	overlap        *fileRow
+}
+
+// resultSpills is the spills the lists of the job result are collected into,
+// pairs is the matched pairs keyed by the date of their system transaction,
+// missing is the unmatched transactions keyed by their amount until they are
+// paired into records keyed by their date, duplicates and overlaps are keyed
+// by their row and findings by the date of their transaction
+type resultSpills struct {
+	pairs      resultSpill[pairRecord]
+	missing    resultSpill[sourceTransaction]
+	records    resultSpill[itemRecord]
+	duplicates resultSpill[duplicateRecord]
+	overlaps   resultSpill[overlapRecord]
+	findings   resultSpill[findingRecord]
+}
+
+// resultCollector merge partition results into the job result, the merged
+// result does not depend on the order the partitions are collected and it
+// is safe to be used concurrently, the lists of the result are collected into
+// spills and only their totals are kept, so on out of core mode where the
+// spills are temporary files the result is not bounded by the available
+// memory, the lists are read back one bucket at a time to build the result
+// and to generate the items and the matches of the job
+type resultCollector struct {
+	mu           sync.Mutex
+	bankFiles    []entity.BankTransactionCsv
+	opts         entity.MatchingOptions
+	startDate    time.Time
+	totalDays    int
+	maxRow       int
+	totalBuckets int
+	resultSpills
+	totalProcessed int
+	totalMatched   int
+	totalReviews   int
+	totalReversed  int
+	totalOverlaps  int
+	// the counts of the lists keyed by source, a list is omitted from the
+	// result content when it is empty
+	totalDuplicates map[int]int
+	totalMissing    map[int]int
+	totalReversals  map[int]int
+	totalTransfers  int
+	carried         *carryForward
+	// window is the missing transactions of the job within the window of the
+	// carry forward, they are kept apart until they are matched with the
+	// carried transactions
+	window *partition
+	// the carried system transactions matched or needing review and the
+	// amount of the carried transactions still missing, they are reported in
+	// the carry forward summary apart from the totals of the job
+	carriedMatched     int
+	carriedReviews     int
+	carriedDiscrepancy float64
+	resolvedItemIDs    []int64
+}
+
+// newResultCollector create the collector of the job result, lastRows is the
+// last row of every file keyed by source and totalBuckets is the number of
+// buckets of every spill
+func newResultCollector(job *entity.ReconciliationJob, lastRows map[int]int, totalBuckets int, spills resultSpills) *resultCollector {
+	maxRow := 1
+	for _, row := range lastRows {
+		maxRow = max(maxRow, row)
+	}
+
+	return &resultCollector{
+		bankFiles:       job.BankTransactionCsvPaths,
+		opts:            job.MatchingOptions,
+		startDate:       job.StartDate,
+		totalDays:       max(daysBetween(job.StartDate, job.EndDate)+1, 1),
+		maxRow:          maxRow,
+		totalBuckets:    totalBuckets,
+		resultSpills:    spills,
+		totalDuplicates: map[int]int{},
+		totalMissing:    map[int]int{},
+		totalReversals:  map[int]int{},
+	}
+}
+
+// close release the spills of the collector
+func (c *resultCollector) close() error {
+	var errs []error
+	for _, spill := range []interface{ close() error }{c.pairs, c.missing, c.records, c.duplicates, c.overlaps, c.findings} {
+		if spill != nil {
+			errs = append(errs, spill.close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// dateBucket return the bucket of the date, the days of the job are spread
+// evenly over the buckets in order and the carried transactions dated before
+// the job fall into the first bucket
+func (c *resultCollector) dateBucket(t time.Time) int {
+	day := min(max(daysBetween(c.startDate, t), 0), c.totalDays-1)
+
+	return day * c.totalBuckets / c.totalDays
+}
+
+// rowBucket return the bucket of the row, the rows of every file are spread
+// evenly over the buckets in order
+func (c *resultCollector) rowBucket(row int) int {
+	return min(max(row-1, 0)*c.totalBuckets/c.maxRow, c.totalBuckets-1)
+}
+
+// amountBucket return the bucket of the amount, the transactions of the same
+// amount are always in the same bucket
+func (c *resultCollector) amountBucket(amount float64) int {
+	h := fnv.New32a()
+	h.Write([]byte(amountKey(amount)))
+
+	return int(h.Sum32() % uint32(c.totalBuckets))
+}
+
+// sources return the source of every file of the job, the system file first
+func (c *resultCollector) sources() []int {
+	sources := []int{systemSource}
+	for bankIdx := range c.bankFiles {
+		sources = append(sources, bankIdx)
+	}
+
+	return sources
+}
+
+// addDuplicate add transaction of a file with the same key as an earlier row
+// of the same file, an excluded duplicate is stored as duplicate item since
+// it is not matched
+func (c *resultCollector) addDuplicate(source int, trx rowTransaction, duplicateOfRow int, excluded bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.totalDuplicates[source]++
+	record := duplicateRecord{
+		sourceTransaction: sourceTransaction{source: source, rowTransaction: trx},
+		duplicateOfRow:    duplicateOfRow,
+		excluded:          excluded,
+	}
+	if err := c.duplicates.add(c.rowBucket(trx.row), record); err != nil {
+		return err
+	}
+
+	return c.findings.add(c.dateBucket(trx.trx.Time), findingRecord{
+		fileRow:     fileRow{source: source, row: trx.row},
+		itemFinding: itemFinding{duplicateOfRow: &duplicateOfRow},
+	})
+}
+
+// addOverlap add bank transaction that is found in an earlier bank file
+func (c *resultCollector) addOverlap(bankIdx int, trx rowTransaction, overlap fileRow) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.totalOverlaps++
+	record := overlapRecord{
+		sourceTransaction: sourceTransaction{source: bankIdx, rowTransaction: trx},
+		overlap:           overlap,
+	}
+	if err := c.overlaps.add(c.rowBucket(trx.row), record); err != nil {
+		return err
+	}
+
+	return c.findings.add(c.dateBucket(trx.trx.Time), findingRecord{
+		fileRow:     fileRow{source: bankIdx, row: trx.row},
+		itemFinding: itemFinding{overlap: &overlap},
+	})
+}
+
+// fileKey return the key of the file of the source in the result, SYSTEM for
+// system transaction file and bank name for bank transaction file
+func (c *resultCollector) fileKey(source int) string {
+	if source == systemSource {
+		return systemFileKey
+	}
+
+	return c.bankFiles[source].BankName
+}
+
+func (c *resultCollector) add(res *partitionResult) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.totalProcessed += res.totalProcessed
+	c.totalMatched += res.totalMatched
+	c.totalReviews += len(res.reviews)
+
+	return c.addPartitionResult(res)
+}
+
+// addPartitionResult spill the matched pairs and the missing transactions of
+// the partition result
+func (c *resultCollector) addPartitionResult(res *partitionResult) error {
+	for _, group := range []pairGroup{
+		{pairs: res.matches, status: entity.ReconciliationItemStatusMatched},
+		{pairs: res.reviews, status: entity.ReconciliationItemStatusNeedsReview},
+	} {
+		for _, pair := range group.pairs {
+			if err := c.pairs.add(c.dateBucket(pair.system.trx.Time), pairRecord{matchedPair: pair, status: group.status}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, trx := range res.missingTrxs {
+		if err := c.addMissing(systemSource, trx); err != nil {
+			return err
+		}
+	}
+	for bankIdx, trxs := range res.missingBankTrxs {
+		for _, trx := range trxs {
+			if err := c.addMissing(bankIdx, trx); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// addMissing add the unmatched transaction, the transaction within the window
+// of the carry forward is kept apart until it is matched with the carried
+// transactions
+func (c *resultCollector) addMissing(source int, trx rowTransaction) error {
+	if c.window != nil && trx.trx.Time.Format(time.DateOnly) < c.carried.windowEnd {
+		c.window.add(source, trx)
+		return nil
+	}
+
+	return c.missing.add(c.amountBucket(trx.trx.Amount), sourceTransaction{source: source, rowTransaction: trx})
+}
+
+// pairGroup is the matched pairs stored with the same status
+type pairGroup struct {
+	pairs  []matchedPair
+	status entity.ReconciliationItemStatus
+}
+
+// result build the summary of the job result along with the content hash of
+// the full result, missing transactions are ordered by their date and then by
+// their row in the file, duplicates and overlaps by their row in the file, so
+// the same inputs always produce the same result along with its content hash
+func (c *resultCollector) result(ctx context.Context) (*entity.ReconciliationResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.pairMissing(ctx); err != nil {
+		return nil, err
+	}
+
+	result := &entity.ReconciliationResult{
+		TotalTransactionProcessed:        c.totalProcessed,
+		TotalTransactionMatched:          c.totalMatched,
+		TotalTransactionUnmatched:        c.totalProcessed - c.totalMatched - c.totalReviews - c.totalReversed,
+		TotalTransactionReversed:         c.totalReversed,
+		TotalTransactionNeedsReview:      c.totalReviews,
+		TotalTransactionInternalTransfer: c.totalTransfers * 2,
+		BankFees:                         map[string]entity.BankFeeSummary{},
+		Warnings: entity.ReconciliationWarnings{
+			TotalBankFileOverlap: c.totalOverlaps,
+		},
+	}
+	for _, total := range c.totalDuplicates {
+		result.TotalTransactionDuplicate += total
+	}
+	// the discrepancy is summed in the order the missing transactions are
+	// listed so the total does not depend on how they are collected
+	for _, source := range c.sources() {
+		if err := c.forEachRecord(ctx, entity.ReconciliationItemStatusMissing, source, func(record itemRecord) error {
+			c.addDiscrepancy(result, source, record.rowTransaction)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.forEachPair(ctx, entity.ReconciliationItemStatusMatched, func(pair matchedPair) error {
+		c.addBankFee(result, pair)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if c.opts.CarryForward {
+		summary, err := c.carryForwardSummary(ctx)
+		if err != nil {
+			return nil, err
+		}
+		result.CarryForward = summary
+	}
+
+	hash, err := c.hashResult(ctx, result)
+	if err != nil {
+		return nil, err
+	}
+	result.ContentHash = hash
+
+	return result, nil
+}
+
+// pairMissing pair the missing transactions of every file as reversals and
+// the missing bank transactions as internal transfers, and record them along
+// with the transactions left missing, a transaction is only paired with a
+// transaction of the same amount so the missing transactions are paired one
+// amount at a time
+func (c *resultCollector) pairMissing(ctx context.Context) error {
+	return forEachSpillBucket(ctx, c.missing, c.totalBuckets, func(trxs []sourceTransaction) error {
+		groups := map[string]*partition{}
+		for _, trx := range trxs {
+			key := amountKey(trx.trx.Amount)
+			group, ok := groups[key]
+			if !ok {
+				group = newPartition(key, len(c.bankFiles))
+				groups[key] = group
+			}
+			group.add(trx.source, trx.rowTransaction)
+		}
+		for _, group := range groups {
+			if err := c.pairAmountGroup(group); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// pairAmountGroup pair the missing transactions of the same amount, the
+// transactions are sorted so the pairs are deterministic
+func (c *resultCollector) pairAmountGroup(group *partition) error {
+	sortRowTransactions(group.systemTrxs)
+	for _, trxs := range group.bankTrxs {
+		sortRowTransactions(trxs)
+	}
+
+	if c.opts.PairReversals {
+		var pairs []reversalPair
+		pairs, group.systemTrxs = pairReversals(group.systemTrxs, c.opts.ReversalWindowDays)
+		// the carried system transactions are not counted as unmatched
+		for _, pair := range pairs {
+			for _, leg := range []rowTransaction{pair.original, pair.reversal} {
+				if !c.isCarried(systemSource, leg.row) {
+					c.totalReversed++
+				}
+			}
+		}
+		if err := c.addReversals(systemSource, pairs); err != nil {
+			return err
+		}
+		for bankIdx, trxs := range group.bankTrxs {
+			pairs, group.bankTrxs[bankIdx] = pairReversals(trxs, c.opts.ReversalWindowDays)
+			if err := c.addReversals(bankIdx, pairs); err != nil {
+				return err
+			}
+		}
+	}
+
+	if c.opts.PairTransfers {
+		var pairs []transferPair
+		pairs, group.bankTrxs = pairTransfers(group.bankTrxs)
+		c.totalTransfers += len(pairs)
+		for _, pair := range pairs {
+			if err := c.addRecord(pair.debit.bankIdx, pair.debit.rowTransaction, entity.ReconciliationItemStatusInternalTransfer,
+				&sourceTransaction{source: pair.credit.bankIdx, rowTransaction: pair.credit.rowTransaction}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, trx := range group.systemTrxs {
+		if err := c.addRecord(systemSource, trx, entity.ReconciliationItemStatusMissing, nil); err != nil {
+			return err
+		}
+	}
+	for bankIdx, trxs := range group.bankTrxs {
+		for _, trx := range trxs {
+			if err := c.addRecord(bankIdx, trx, entity.ReconciliationItemStatusMissing, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *resultCollector) addReversals(source int, pairs []reversalPair) error {
+	c.totalReversals[source] += len(pairs)
+	for _, pair := range pairs {
+		if err := c.addRecord(source, pair.original, entity.ReconciliationItemStatusReversed,
+			&sourceTransaction{source: source, rowTransaction: pair.reversal}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addRecord record the transaction keyed by its date, the record of a pair is
+// keyed by the date of its first transaction
+func (c *resultCollector) addRecord(source int, trx rowTransaction, status entity.ReconciliationItemStatus, paired *sourceTransaction) error {
+	if status == entity.ReconciliationItemStatusMissing {
+		c.totalMissing[source]++
+	}
+
+	return c.records.add(c.dateBucket(trx.trx.Time), itemRecord{
+		sourceTransaction: sourceTransaction{source: source, rowTransaction: trx},
+		status:            status,
+		paired:            paired,
+	})
+}
+
+// forEachPair call fn with the matched pairs of the status ordered by their
+// system transaction
+func (c *resultCollector) forEachPair(ctx context.Context, status entity.ReconciliationItemStatus, fn func(matchedPair) error) error {
+	return forEachSpillBucket(ctx, c.pairs, c.totalBuckets, func(records []pairRecord) error {
+		pairs := []matchedPair{}
+		for _, record := range records {
+			if record.status == status {
+				pairs = append(pairs, record.matchedPair)
+			}
+		}
+		sortMatchedPairs(pairs)
+		for _, pair := range pairs {
+			if err := fn(pair); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// forEachRecord call fn with the records of the status of the file ordered by
+// their first transaction
+func (c *resultCollector) forEachRecord(ctx context.Context, status entity.ReconciliationItemStatus, source int, fn func(itemRecord) error) error {
+	return forEachSpillBucket(ctx, c.records, c.totalBuckets, func(records []itemRecord) error {
+		matched := []itemRecord{}
+		for _, record := range records {
+			if record.status == status && record.source == source {
+				matched = append(matched, record)
+			}
+		}
+		slices.SortFunc(matched, func(a, b itemRecord) int {
+			return compareRowTransactions(a.rowTransaction, b.rowTransaction)
+		})
+		for _, record := range matched {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// forEachDuplicate call fn with the duplicates of the file ordered by their row
+func (c *resultCollector) forEachDuplicate(ctx context.Context, source int, fn func(duplicateRecord) error) error {
+	return forEachSpillBucket(ctx, c.duplicates, c.totalBuckets, func(records []duplicateRecord) error {
+		duplicates := []duplicateRecord{}
+		for _, record := range records {
+			if record.source == source {
+				duplicates = append(duplicates, record)
+			}
+		}
+		slices.SortFunc(duplicates, func(a, b duplicateRecord) int {
+			return cmp.Compare(a.row, b.row)
+		})
+		for _, record := range duplicates {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// forEachOverlap call fn with the overlaps of the bank file ordered by their row
+func (c *resultCollector) forEachOverlap(ctx context.Context, bankIdx int, fn func(overlapRecord) error) error {
+	return forEachSpillBucket(ctx, c.overlaps, c.totalBuckets, func(records []overlapRecord) error {
+		overlaps := []overlapRecord{}
+		for _, record := range records {
+			if record.source == bankIdx {
+				overlaps = append(overlaps, record)
+			}
+		}
+		slices.SortFunc(overlaps, func(a, b overlapRecord) int {
+			return cmp.Compare(a.row, b.row)
+		})
+		for _, record := range overlaps {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// items return every transaction of the job as item, the transactions of the
+// matched pairs come first in the order of their system transaction, it is
+// only valid once the result is built, the iteration stops at the first error
+func (c *resultCollector) items(ctx context.Context) iter.Seq2[entity.ReconciliationItem, error] {
+	return func(yield func(entity.ReconciliationItem, error) bool) {
+		err := c.forEachItem(ctx, func(item entity.ReconciliationItem) error {
+			if !yield(item, nil) {
+				return errIterationStopped
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errIterationStopped) {
+			yield(entity.ReconciliationItem{}, err)
+		}
+	}
+}
+
+// forEachItem call fn with every item of the job, the matched pairs are
+// followed by the reversals, the missing system transactions, the internal
+// transfers, the missing bank transactions and lastly the excluded duplicates
+func (c *resultCollector) forEachItem(ctx context.Context, fn func(entity.ReconciliationItem) error) error {
+	findings := &findingIndex{spill: c.findings}
+	emit := func(trx sourceTransaction, status entity.ReconciliationItemStatus, paired *sourceTransaction) error {
+		item, err := c.item(findings, trx, status, paired)
+		if err != nil {
+			return err
+		}
+		return fn(item)
+	}
+	emitRecord := func(record itemRecord) error {
+		if err := emit(record.sourceTransaction, record.status, record.paired); err != nil {
+			return err
+		}
+		if record.paired == nil {
+			return nil
+		}
+		return emit(*record.paired, record.status, &record.sourceTransaction)
+	}
+
+	for _, status := range []entity.ReconciliationItemStatus{entity.ReconciliationItemStatusMatched, entity.ReconciliationItemStatusNeedsReview} {
+		if err := c.forEachPair(ctx, status, func(pair matchedPair) error {
+			if err := emit(sourceTransaction{source: systemSource, rowTransaction: pair.system}, status, nil); err != nil {
+				return err
+			}
+			return emit(sourceTransaction{source: pair.bankIdx, rowTransaction: pair.bank}, status, nil)
+		}); err != nil {
+			return err
+		}
+	}
+	for _, source := range c.sources() {
+		if err := c.forEachRecord(ctx, entity.ReconciliationItemStatusReversed, source, emitRecord); err != nil {
+			return err
+		}
+	}
+	if err := c.forEachRecord(ctx, entity.ReconciliationItemStatusMissing, systemSource, emitRecord); err != nil {
+		return err
+	}
+	for bankIdx := range c.bankFiles {
+		if err := c.forEachRecord(ctx, entity.ReconciliationItemStatusInternalTransfer, bankIdx, emitRecord); err != nil {
+			return err
+		}
+	}
+	for bankIdx := range c.bankFiles {
+		if err := c.forEachRecord(ctx, entity.ReconciliationItemStatusMissing, bankIdx, emitRecord); err != nil {
+			return err
+		}
+	}
+	for _, source := range c.sources() {
+		if err := c.forEachDuplicate(ctx, source, func(record duplicateRecord) error {
+			if !record.excluded {
+				return nil
+			}
+			return emit(record.sourceTransaction, entity.ReconciliationItemStatusDuplicate, nil)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matchedPairs return every matched pair of the job as match, it is only
+// valid once the result is built, the iteration stops at the first error
+func (c *resultCollector) matchedPairs(ctx context.Context) iter.Seq2[entity.ReconciliationMatch, error] {
+	return func(yield func(entity.ReconciliationMatch, error) bool) {
+		var err error
+		for _, status := range []entity.ReconciliationItemStatus{entity.ReconciliationItemStatusMatched, entity.ReconciliationItemStatusNeedsReview} {
+			err = c.forEachPair(ctx, status, func(pair matchedPair) error {
+				if !yield(entity.ReconciliationMatch{
+					SystemRow:   pair.system.row,
+					BankName:    c.bankFiles[pair.bankIdx].BankName,
+					BankRow:     pair.bank.row,
+					Status:      status,
+					Confidence:  pair.confidence.Score,
+					ExpectedFee: pair.expectedFee,
+					ImpliedFee:  pair.system.trx.Amount - pair.bank.trx.Amount,
+				}, nil) {
+					return errIterationStopped
+				}
+				return nil
+			})
+			if err != nil {
+				break
+			}
+		}
+		if err != nil && !errors.Is(err, errIterationStopped) {
+			yield(entity.ReconciliationMatch{}, err)
+		}
+	}
+}
+
+// findingIndex look up the findings of a transaction, the findings of the
+// last two date buckets are kept loaded since the items are generated in the
+// order of their date and both transactions of a pair are close in date
+type findingIndex struct {
+	spill   resultSpill[findingRecord]
+	buckets []int
+	loaded  []map[fileRow]*itemFinding
+}
+
+func (f *findingIndex) get(bucket int, key fileRow) (*itemFinding, error) {
+	if idx := slices.Index(f.buckets, bucket); idx != -1 {
+		return f.loaded[idx][key], nil
+	}
+
+	records, err := f.spill.bucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+	findings := map[fileRow]*itemFinding{}
+	for _, record := range records {
+		finding, ok := findings[record.fileRow]
+		if !ok {
+			finding = &itemFinding{}
+			findings[record.fileRow] = finding
+		}
+		if record.duplicateOfRow != nil {
+			finding.duplicateOfRow = record.duplicateOfRow
+		}
+		if record.overlap != nil {
+			finding.overlap = record.overlap
+		}
+	}
+	if len(f.buckets) == 2 {
+		f.buckets, f.loaded = f.buckets[1:], f.loaded[1:]
+	}
+	f.buckets = append(f.buckets, bucket)
+	f.loaded = append(f.loaded, findings)
+
+	return findings[key], nil
+}
+
+// item build the item of a transaction, paired is the other transaction of a
+// reversal or an internal transfer
+func (c *resultCollector) item(findings *findingIndex, trx sourceTransaction, status entity.ReconciliationItemStatus, paired *sourceTransaction) (entity.ReconciliationItem, error) {
+	item := entity.ReconciliationItem{
+		Transaction: *trx.trx,
+		Source:      entity.ReconciliationItemSourceSystem,
+		Row:         trx.row,
+		Status:      status,
+	}
+	if trx.source != systemSource {
+		item.Source = entity.ReconciliationItemSourceBank
+		item.BankName = c.bankFiles[trx.source].BankName
+	}
+	if c.carried != nil {
+		if id, ok := c.carried.itemIDs[carriedKey{source: trx.source, row: trx.row}]; ok {
+			item.CarriedFromID = &id
+		}
+	}
+	finding, err := findings.get(c.dateBucket(trx.trx.Time), fileRow{source: trx.source, row: trx.row})
+	if err != nil {
+		return entity.ReconciliationItem{}, err
+	}
+	if finding != nil {
+		item.DuplicateOfRow = finding.duplicateOfRow
+		if finding.overlap != nil {
+			bankName := c.bankFiles[finding.overlap.source].BankName
+			item.OverlapBankName = &bankName
+			item.OverlapRow = &finding.overlap.row
+		}
+	}
+	if paired != nil {
+		if paired.source != systemSource {
+			bankName := c.bankFiles[paired.source].BankName
+			item.PairedBankName = &bankName
+		}
+		item.PairedRow = &paired.row
+	}
+
+	return item, nil
+}
+
+// addBankFee add the fee of the matched transaction of the bank with fee rule
+func (c *resultCollector) addBankFee(result *entity.ReconciliationResult, match matchedPair) {
+	if !match.hasFeeRule {
+		return
+	}
+	bankName := c.bankFiles[match.bankIdx].BankName
+	summary := result.BankFees[bankName]
+	summary.TotalFee += match.system.trx.Amount - match.bank.trx.Amount
+	summary.TotalExpectedFee += match.expectedFee
+	result.BankFees[bankName] = summary
+}
+
+// addDiscrepancy add the amount of a missing transaction to the discrepancy
+// of the job, or of the carry forward when it is carried from the previous job
+func (c *resultCollector) addDiscrepancy(result *entity.ReconciliationResult, source int, trx rowTransaction) {
+	if c.isCarried(source, trx.row) {
+		c.carriedDiscrepancy += trx.trx.Amount
+		return
+	}
+	result.TotalDiscrepancyAmount += trx.trx.Amount
+}
+
+func (c *resultCollector) transferLeg(trx sourceTransaction) entity.TransferLeg {
+	return entity.TransferLeg{
+		Transaction: *trx.trx,
+		BankName:    c.bankFiles[trx.source].BankName,
+		Row:         trx.row,
+	}
+}
+
+// hashResult compute the SHA-256 of the full result encoded as JSON, the
+// lists of the result are read back from the spills and encoded in the order
+// encoding/json encodes the result along with them, the content hash itself
+// is excluded and map keys are always encoded in sorted order
+func (c *resultCollector) hashResult(ctx context.Context, result *entity.ReconciliationResult) (string, error) {
+	h := sha256.New()
+	w := &jsonHashWriter{h: h}
+	w.raw("{")
+	w.field("total_transaction_processed", result.TotalTransactionProcessed)
+	w.field("total_transaction_matched", result.TotalTransactionMatched)
+	w.field("total_transaction_unmatched", result.TotalTransactionUnmatched)
+	w.field("total_transaction_reversed", result.TotalTransactionReversed)
+	w.field("total_transaction_needs_review", result.TotalTransactionNeedsReview)
+	w.field("total_transaction_duplicate", result.TotalTransactionDuplicate)
+	w.field("total_transaction_internal_transfer", result.TotalTransactionInternalTransfer)
+	w.field("total_discrepancy_amount", result.TotalDiscrepancyAmount)
+
+	missingBanks := c.sortedByFileKey(c.bankSources(), c.totalMissing)
+	fileSources := func(totals map[int]int) []int { return c.sortedByFileKey(c.sources(), totals) }
+	hasFee := func(bankIdx int) bool { _, ok := result.BankFees[c.bankFiles[bankIdx].BankName]; return ok }
+	steps := []func() error{
+		func() error {
+			if c.totalMissing[systemSource] == 0 {
+				return nil
+			}
+			w.key("missing_transactions")
+			return w.array(func(elem func(any)) error {
+				return c.forEachRecord(ctx, entity.ReconciliationItemStatusMissing, systemSource, func(record itemRecord) error {
+					elem(record.trx)
+					return nil
+				})
+			})
+		},
+		func() error {
+			if len(missingBanks) == 0 {
+				return nil
+			}
+			w.key("missing_bank_transactions")
+			return w.object(missingBanks, c.fileKey, func(bankIdx int) error {
+				return w.array(func(elem func(any)) error {
+					return c.forEachRecord(ctx, entity.ReconciliationItemStatusMissing, bankIdx, func(record itemRecord) error {
+						elem(record.trx)
+						return nil
+					})
+				})
+			})
+		},
+		func() error {
+			if c.totalReviews+c.carriedReviews == 0 {
+				return nil
+			}
+			w.key("needs_review")
+			return w.array(func(elem func(any)) error {
+				return c.forEachPair(ctx, entity.ReconciliationItemStatusNeedsReview, func(review matchedPair) error {
+					elem(entity.ReviewMatch{
+						SystemTransaction: *review.system.trx,
+						BankName:          c.bankFiles[review.bankIdx].BankName,
+						BankTransaction:   *review.bank.trx,
+						Confidence:        review.confidence,
+					})
+					return nil
+				})
+			})
+		},
+		func() error {
+			sources := fileSources(c.totalDuplicates)
+			if len(sources) == 0 {
+				return nil
+			}
+			w.key("duplicates")
+			return w.object(sources, c.fileKey, func(source int) error {
+				return w.array(func(elem func(any)) error {
+					return c.forEachDuplicate(ctx, source, func(record duplicateRecord) error {
+						elem(entity.DuplicateTransaction{
+							Transaction:    *record.trx,
+							Row:            record.row,
+							DuplicateOfRow: record.duplicateOfRow,
+						})
+						return nil
+					})
+				})
+			})
+		},
+		func() error {
+			sources := fileSources(c.totalReversals)
+			if len(sources) == 0 {
+				return nil
+			}
+			w.key("reversals")
+			return w.object(sources, c.fileKey, func(source int) error {
+				return w.array(func(elem func(any)) error {
+					return c.forEachRecord(ctx, entity.ReconciliationItemStatusReversed, source, func(record itemRecord) error {
+						elem(toReversalPair(reversalPair{original: record.rowTransaction, reversal: record.paired.rowTransaction}))
+						return nil
+					})
+				})
+			})
+		},
+		func() error {
+			if c.totalTransfers == 0 {
+				return nil
+			}
+			w.key("internal_transfers")
+			return w.array(func(elem func(any)) error {
+				for bankIdx := range c.bankFiles {
+					if err := c.forEachRecord(ctx, entity.ReconciliationItemStatusInternalTransfer, bankIdx, func(record itemRecord) error {
+						elem(entity.InternalTransfer{
+							From: c.transferLeg(record.sourceTransaction),
+							To:   c.transferLeg(*record.paired),
+						})
+						return nil
+					}); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		},
+		func() error {
+			w.key("bank_fees")
+			feeBanks := slices.DeleteFunc(c.bankSources(), func(bankIdx int) bool { return !hasFee(bankIdx) })
+			return w.object(c.sortedByFileKey(feeBanks, nil), c.fileKey, func(bankIdx int) error {
+				fee := result.BankFees[c.bankFiles[bankIdx].BankName]
+				w.raw("{")
+				w.field("total_fee", fee.TotalFee)
+				w.field("total_expected_fee", fee.TotalExpectedFee)
+				w.key("matches")
+				err := w.array(func(elem func(any)) error {
+					return c.forEachPair(ctx, entity.ReconciliationItemStatusMatched, func(match matchedPair) error {
+						if match.bankIdx != bankIdx || !match.hasFeeRule {
+							return nil
+						}
+						elem(entity.MatchFee{
+							SystemTransaction: *match.system.trx,
+							BankTransaction:   *match.bank.trx,
+							ExpectedFee:       match.expectedFee,
+							ImpliedFee:        match.system.trx.Amount - match.bank.trx.Amount,
+						})
+						return nil
+					})
+				})
+				w.raw("}")
+				return err
+			})
+		},
+		func() error {
+			w.key("warnings")
+			w.raw("{")
+			if c.totalOverlaps > 0 {
+				w.key("bank_file_overlaps")
+				if err := w.array(func(elem func(any)) error {
+					for bankIdx := range c.bankFiles {
+						if err := c.forEachOverlap(ctx, bankIdx, func(record overlapRecord) error {
+							elem(entity.OverlapTransaction{
+								Transaction:     *record.trx,
+								BankName:        c.bankFiles[bankIdx].BankName,
+								Row:             record.row,
+								OverlapBankName: c.bankFiles[record.overlap.source].BankName,
+								OverlapRow:      record.overlap.row,
+							})
+							return nil
+						}); err != nil {
+							return err
+						}
+					}
+					return nil
+				}); err != nil {
+					return err
+				}
+			}
+			w.field("total_bank_file_overlap", result.Warnings.TotalBankFileOverlap)
+			w.raw("}")
+			return nil
+		},
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return "", err
+		}
+	}
+	if result.CarryForward != nil {
+		w.field("carry_forward", result.CarryForward)
+	}
+	w.field("content_hash", "")
+	w.raw("}")
+	if w.err != nil {
+		return "", w.err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// bankSources return the source of every bank file of the job
+func (c *resultCollector) bankSources() []int {
+	return c.sources()[1:]
+}
+
+// sortedByFileKey return the sources ordered by their file key the way
+// encoding/json orders map keys, a source whose total is zero is left out
+// unless totals is nil
+func (c *resultCollector) sortedByFileKey(sources []int, totals map[int]int) []int {
+	sorted := []int{}
+	for _, source := range sources {
+		if totals == nil || totals[source] > 0 {
+			sorted = append(sorted, source)
+		}
+	}
+	slices.SortFunc(sorted, func(a, b int) int {
+		return cmp.Compare(c.fileKey(a), c.fileKey(b))
+	})
+
+	return sorted
+}
+
+// jsonHashWriter write JSON into the hash value by value, every value is
+// encoded by encoding/json, the first error is kept and the rest is skipped
+type jsonHashWriter struct {
+	h hash.Hash
+	// first is whether the next member of the current object or array is its first
+	first bool
+	err   error
+}
+
+func (w *jsonHashWriter) raw(s string) {
+	if w.err != nil {
+		return
+	}
+	w.h.Write([]byte(s))
+	w.first = s == "{" || s == "["
+}
+
+func (w *jsonHashWriter) value(v any) {
+	if w.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		w.err = err
+		return
+	}
+	w.h.Write(b)
+	w.first = false
+}
+
+// key write the key of the next member of the current object
+func (w *jsonHashWriter) key(name string) {
+	if !w.first {
+		w.raw(",")
+	}
+	w.value(name)
+	w.raw(":")
+}
+
+func (w *jsonHashWriter) field(name string, v any) {
+	w.key(name)
+	w.value(v)
+}
+
+// array write the elements passed to elem by fn as array
+func (w *jsonHashWriter) array(fn func(elem func(any)) error) error {
+	w.raw("[")
+	err := fn(func(v any) {
+		if !w.first {
+			w.raw(",")
+		}
+		w.value(v)
+	})
+	w.raw("]")
+
+	return err
+}
+
+// object write a member for every source keyed by its file key, the value of
+// the member is written by fn
+func (w *jsonHashWriter) object(sources []int, key func(int) string, fn func(int) error) error {
+	w.raw("{")
+	for _, source := range sources {
+		w.key(key(source))
+		if err := fn(source); err != nil {
+			return err
+		}
+	}
+	w.raw("}")
+
+	return nil
+}
+
+// summaryResult return the totals of the result without the lists that are
+// stored as items and matches
+func summaryResult(result *entity.ReconciliationResult) entity.ReconciliationResult {
+	summary := *result
+	summary.MissingTransactions = nil
+	summary.MissingBankTransactions = nil
+	summary.NeedsReview = nil
+	summary.Duplicates = nil
+	summary.Reversals = nil
+	summary.InternalTransfers = nil
+	summary.Warnings.BankFileOverlaps = nil
+	if result.BankFees != nil {
+		summary.BankFees = make(map[string]entity.BankFeeSummary, len(result.BankFees))
+		for bankName, fee := range result.BankFees {
+			fee.Matches = nil
+			summary.BankFees[bankName] = fee
+		}
+	}
+
+	return summary
+}
+
+func sortRowTransactions(trxs []rowTransaction) {
+	slices.SortFunc(trxs, compareRowTransactions)
+}
+
+func sortMatchedPairs(pairs []matchedPair) {
+	slices.SortFunc(pairs, func(a, b matchedPair) int {
+		return compareRowTransactions(a.system, b.system)
+	})
+}
+
+// compareRowTransactions order transactions by their date and then by their row
+func compareRowTransactions(a, b rowTransaction) int {
+	aYear, aMonth, aDay := a.trx.Time.Date()
+	bYear, bMonth, bDay := b.trx.Time.Date()
+	return cmp.Or(
+		cmp.Compare(aYear, bYear),
+		cmp.Compare(aMonth, bMonth),
+		cmp.Compare(aDay, bDay),
+		cmp.Compare(a.row, b.row),
+	)
+}
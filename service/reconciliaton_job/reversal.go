@@ -0,0 +1,98 @@
+package reconciliatonjob
+
+import (
+	"strings"
+
+	"github.com/delly/amartha/entity"
+)
+
+// reversalPair is a transaction and the later transaction of the same file
+// that reverses it
+type reversalPair struct {
+	original rowTransaction
+	reversal rowTransaction
+}
+
+// pairReversals pair each transaction with a later transaction of the opposite
+// type and the same amount within the window days, a candidate whose ID refers
+// to the original transaction ID is preferred over the earliest candidate,
+// the transactions must be sorted by date and row so the pairs are deterministic
+func pairReversals(trxs []rowTransaction, windowDays int) ([]reversalPair, []rowTransaction) {
+	pairs := []reversalPair{}
+	paired := make([]bool, len(trxs))
+	for i, trx := range trxs {
+		if paired[i] {
+			continue
+		}
+		candidate := -1
+		for j := i + 1; j < len(trxs); j++ {
+			other := trxs[j]
+			if daysBetween(trx.trx.Time, other.trx.Time) > windowDays {
+				break
+			}
+			if paired[j] || other.trx.Type == trx.trx.Type || other.trx.Amount != trx.trx.Amount {
+				continue
+			}
+			if isReversalReference(trx.trx, other.trx) {
+				candidate = j
+				break
+			}
+			if candidate == -1 {
+				candidate = j
+			}
+		}
+		if candidate == -1 {
+			continue
+		}
+		paired[i] = true
+		paired[candidate] = true
+		pairs = append(pairs, reversalPair{original: trx, reversal: trxs[candidate]})
+	}
+
+	remaining := []rowTransaction{}
+	for i, trx := range trxs {
+		if !paired[i] {
+			remaining = append(remaining, trx)
+		}
+	}
+
+	return pairs, remaining
+}
+
+// reversalPrefixes and reversalSuffixes are the markers a reversal ID is
+// built with from the original ID
+var (
+	reversalPrefixes = []string{"REVERSAL-", "REVERSAL_", "REV-", "REV_"}
+	reversalSuffixes = []string{"-REVERSAL", "_REVERSAL", "-REV", "_REV"}
+)
+
+// isReversalReference check whether the reversal ID refers to the original ID,
+// e.g. REV-BCA-1 or BCA-1-REV for BCA-1, the reversal ID must be the original
+// ID once a known prefix or suffix is stripped so REV-BCA-13 does not refer
+// to BCA-1
+func isReversalReference(original, reversal *entity.Transaction) bool {
+	if original.ID == "" {
+		return false
+	}
+	id := strings.ToUpper(reversal.ID)
+	originalID := strings.ToUpper(original.ID)
+	for _, prefix := range reversalPrefixes {
+		if strings.HasPrefix(id, prefix) && id[len(prefix):] == originalID {
+			return true
+		}
+	}
+	for _, suffix := range reversalSuffixes {
+		if strings.HasSuffix(id, suffix) && id[:len(id)-len(suffix)] == originalID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func toReversalPair(pair reversalPair) entity.ReversalPair {
+	return entity.ReversalPair{
+		Original: entity.ReversalLeg{Transaction: *pair.original.trx, Row: pair.original.row},
+		Reversal: entity.ReversalLeg{Transaction: *pair.reversal.trx, Row: pair.reversal.row},
+	}
+}
@@ -0,0 +1,343 @@
+package reconciliatonjob
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/delly/amartha/entity"
+)
+
+// resultSpill hold the records of a list of the job result in a fixed number
+// of buckets, a record is added into the bucket of its position in the list
+// so the list is read back in order one bucket at a time
+type resultSpill[T any] interface {
+	add(bucket int, record T) error
+	// bucket return the records of the bucket in no particular order, a
+	// bucket is only read once every record is added
+	bucket(idx int) ([]T, error)
+	close() error
+}
+
+// memorySpill keep the records in memory
+type memorySpill[T any] struct {
+	buckets [][]T
+}
+
+func newMemorySpill[T any](totalBuckets int) *memorySpill[T] {
+	return &memorySpill[T]{
+		buckets: make([][]T, totalBuckets),
+	}
+}
+
+func (m *memorySpill[T]) add(bucket int, record T) error {
+	m.buckets[bucket] = append(m.buckets[bucket], record)
+	return nil
+}
+
+func (m *memorySpill[T]) bucket(idx int) ([]T, error) {
+	return m.buckets[idx], nil
+}
+
+func (m *memorySpill[T]) close() error {
+	m.buckets = nil
+	return nil
+}
+
+// spillCodec encode a record into a csv record and decode it back
+type spillCodec[T any] struct {
+	encode func(T) []string
+	decode func([]string) (T, error)
+}
+
+// diskSpill spill the records into temporary bucket files, so only the
+// records of a single bucket are loaded into memory
+type diskSpill[T any] struct {
+	buckets *spillBuckets
+	codec   spillCodec[T]
+}
+
+func newDiskSpill[T any](tempDir, pattern string, totalBuckets int, codec spillCodec[T]) (*diskSpill[T], error) {
+	buckets, err := newSpillBuckets(tempDir, pattern, totalBuckets)
+	if err != nil {
+		return nil, err
+	}
+
+	return &diskSpill[T]{
+		buckets: buckets,
+		codec:   codec,
+	}, nil
+}
+
+func (d *diskSpill[T]) add(bucket int, record T) error {
+	return d.buckets.writeBucket(bucket, d.codec.encode(record))
+}
+
+func (d *diskSpill[T]) bucket(idx int) ([]T, error) {
+	r, err := d.buckets.readBucket(idx)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []T{}
+	for {
+		record, err := r.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+		v, err := d.codec.decode(record)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, v)
+	}
+
+	return records, nil
+}
+
+// close remove the temporary bucket files
+func (d *diskSpill[T]) close() error {
+	return d.buckets.close()
+}
+
+// forEachSpillBucket call fn with the records of every bucket of the spill in
+// the order of the buckets
+func forEachSpillBucket[T any](ctx context.Context, spill resultSpill[T], totalBuckets int, fn func([]T) error) error {
+	for idx := range totalBuckets {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		records, err := spill.bucket(idx)
+		if err != nil {
+			return err
+		}
+		if err := fn(records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sourceTransaction is a transaction along with the file it is read from,
+// source is systemSource for system transaction or the bank file index
+type sourceTransaction struct {
+	source int
+	rowTransaction
+}
+
+// pairRecord is a matched pair along with the status of its items
+type pairRecord struct {
+	matchedPair
+	status entity.ReconciliationItemStatus
+}
+
+// itemRecord is a transaction the job does not match, paired is the other
+// transaction of a reversal or an internal transfer and nil otherwise
+type itemRecord struct {
+	sourceTransaction
+	status entity.ReconciliationItemStatus
+	paired *sourceTransaction
+}
+
+// duplicateRecord is a transaction with the same key as an earlier row of the
+// same file, an excluded duplicate is not matched
+type duplicateRecord struct {
+	sourceTransaction
+	duplicateOfRow int
+	excluded       bool
+}
+
+// overlapRecord is a bank transaction found in an earlier bank file
+type overlapRecord struct {
+	sourceTransaction
+	overlap fileRow
+}
+
+// findingRecord is what is found on a transaction of a file besides its status
+type findingRecord struct {
+	fileRow
+	itemFinding
+}
+
+var sourceTransactionCodec = spillCodec[sourceTransaction]{
+	encode: func(trx sourceTransaction) []string {
+		return encodeRowTransaction(trx.source, trx.row, trx.trx)
+	},
+	decode: decodeSourceTransaction,
+}
+
+var pairRecordCodec = spillCodec[pairRecord]{
+	encode: func(pair pairRecord) []string {
+		record := []string{
+			string(pair.status),
+			strconv.Itoa(pair.bankIdx),
+			strconv.FormatBool(pair.hasFeeRule),
+			formatSpillFloat(pair.expectedFee),
+			formatSpillFloat(pair.confidence.Score),
+			formatSpillFloat(pair.confidence.Amount),
+			formatSpillFloat(pair.confidence.Date),
+			formatSpillFloat(pair.confidence.Uniqueness),
+		}
+		record = append(record, encodeRowTransaction(systemSource, pair.system.row, pair.system.trx)...)
+
+		return append(record, encodeRowTransaction(pair.bankIdx, pair.bank.row, pair.bank.trx)...)
+	},
+	decode: func(record []string) (pairRecord, error) {
+		pair := pairRecord{status: entity.ReconciliationItemStatus(record[0])}
+		var err error
+		if pair.bankIdx, err = strconv.Atoi(record[1]); err != nil {
+			return pairRecord{}, err
+		}
+		if pair.hasFeeRule, err = strconv.ParseBool(record[2]); err != nil {
+			return pairRecord{}, err
+		}
+		floats := []*float64{&pair.expectedFee, &pair.confidence.Score, &pair.confidence.Amount,
+			&pair.confidence.Date, &pair.confidence.Uniqueness}
+		for i, f := range floats {
+			if *f, err = strconv.ParseFloat(record[3+i], 64); err != nil {
+				return pairRecord{}, err
+			}
+		}
+		system, err := decodeSourceTransaction(record[8:14])
+		if err != nil {
+			return pairRecord{}, err
+		}
+		bank, err := decodeSourceTransaction(record[14:20])
+		if err != nil {
+			return pairRecord{}, err
+		}
+		pair.system, pair.bank = system.rowTransaction, bank.rowTransaction
+
+		return pair, nil
+	},
+}
+
+var itemRecordCodec = spillCodec[itemRecord]{
+	encode: func(record itemRecord) []string {
+		encoded := append([]string{string(record.status)}, encodeRowTransaction(record.source, record.row, record.trx)...)
+		if record.paired == nil {
+			return append(encoded, make([]string, 6)...)
+		}
+
+		return append(encoded, encodeRowTransaction(record.paired.source, record.paired.row, record.paired.trx)...)
+	},
+	decode: func(encoded []string) (itemRecord, error) {
+		trx, err := decodeSourceTransaction(encoded[1:7])
+		if err != nil {
+			return itemRecord{}, err
+		}
+		record := itemRecord{sourceTransaction: trx, status: entity.ReconciliationItemStatus(encoded[0])}
+		if encoded[7] == "" {
+			return record, nil
+		}
+		paired, err := decodeSourceTransaction(encoded[7:13])
+		if err != nil {
+			return itemRecord{}, err
+		}
+		record.paired = &paired
+
+		return record, nil
+	},
+}
+
+var duplicateRecordCodec = spillCodec[duplicateRecord]{
+	encode: func(record duplicateRecord) []string {
+		return append(encodeRowTransaction(record.source, record.row, record.trx),
+			strconv.Itoa(record.duplicateOfRow),
+			strconv.FormatBool(record.excluded))
+	},
+	decode: func(encoded []string) (duplicateRecord, error) {
+		trx, err := decodeSourceTransaction(encoded[:6])
+		if err != nil {
+			return duplicateRecord{}, err
+		}
+		record := duplicateRecord{sourceTransaction: trx}
+		if record.duplicateOfRow, err = strconv.Atoi(encoded[6]); err != nil {
+			return duplicateRecord{}, err
+		}
+		if record.excluded, err = strconv.ParseBool(encoded[7]); err != nil {
+			return duplicateRecord{}, err
+		}
+
+		return record, nil
+	},
+}
+
+var overlapRecordCodec = spillCodec[overlapRecord]{
+	encode: func(record overlapRecord) []string {
+		return append(encodeRowTransaction(record.source, record.row, record.trx),
+			strconv.Itoa(record.overlap.source),
+			strconv.Itoa(record.overlap.row))
+	},
+	decode: func(encoded []string) (overlapRecord, error) {
+		trx, err := decodeSourceTransaction(encoded[:6])
+		if err != nil {
+			return overlapRecord{}, err
+		}
+		record := overlapRecord{sourceTransaction: trx}
+		if record.overlap.source, err = strconv.Atoi(encoded[6]); err != nil {
+			return overlapRecord{}, err
+		}
+		if record.overlap.row, err = strconv.Atoi(encoded[7]); err != nil {
+			return overlapRecord{}, err
+		}
+
+		return record, nil
+	},
+}
+
+// findingRecordCodec encode a position that is not found as empty fields
+var findingRecordCodec = spillCodec[findingRecord]{
+	encode: func(record findingRecord) []string {
+		encoded := []string{strconv.Itoa(record.source), strconv.Itoa(record.row), "", "", ""}
+		if record.duplicateOfRow != nil {
+			encoded[2] = strconv.Itoa(*record.duplicateOfRow)
+		}
+		if record.overlap != nil {
+			encoded[3] = strconv.Itoa(record.overlap.source)
+			encoded[4] = strconv.Itoa(record.overlap.row)
+		}
+
+		return encoded
+	},
+	decode: func(encoded []string) (findingRecord, error) {
+		ints := make([]int, len(encoded))
+		for i, field := range encoded {
+			if field == "" {
+				continue
+			}
+			n, err := strconv.Atoi(field)
+			if err != nil {
+				return findingRecord{}, err
+			}
+			ints[i] = n
+		}
+		record := findingRecord{fileRow: fileRow{source: ints[0], row: ints[1]}}
+		if encoded[2] != "" {
+			record.duplicateOfRow = &ints[2]
+		}
+		if encoded[3] != "" {
+			record.overlap = &fileRow{source: ints[3], row: ints[4]}
+		}
+
+		return record, nil
+	},
+}
+
+func decodeSourceTransaction(record []string) (sourceTransaction, error) {
+	source, row, trx, err := decodeRowTransaction(record)
+	if err != nil {
+		return sourceTransaction{}, err
+	}
+
+	return sourceTransaction{source: source, rowTransaction: rowTransaction{row: row, trx: trx}}, nil
+}
+
+// formatSpillFloat format the float so it is parsed back to the same value
+func formatSpillFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
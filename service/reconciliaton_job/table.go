@@ -0,0 +1,121 @@
+package reconciliatonjob
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/delly/amartha/common/xlsx"
+)
+
+// tableWriter write tables of rows section by section, a section is a sheet
+// for spreadsheet or a block of rows separated by an empty row for CSV
+type tableWriter interface {
+	section(name string, header ...string) error
+	row(values ...any) error
+	close() error
+}
+
+// csvTableWriter write every section in a single CSV, a section starts with
+// its name followed by its header
+type csvTableWriter struct {
+	w        *csv.Writer
+	sections int
+}
+
+func newCSVTableWriter(w io.Writer) *csvTableWriter {
+	return &csvTableWriter{w: csv.NewWriter(w)}
+}
+
+func (t *csvTableWriter) section(name string, header ...string) error {
+	if t.sections > 0 {
+		if err := t.w.Write([]string{}); err != nil {
+			return err
+		}
+	}
+	t.sections++
+	if err := t.w.Write([]string{name}); err != nil {
+		return err
+	}
+
+	return t.w.Write(header)
+}
+
+func (t *csvTableWriter) row(values ...any) error {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		record = append(record, formatCSVValue(value))
+	}
+
+	return t.w.Write(record)
+}
+
+func (t *csvTableWriter) close() error {
+	t.w.Flush()
+	return t.w.Error()
+}
+
+// formatCSVValue format numbers and time as is and escape every other value
+// so a spreadsheet opening the CSV does not evaluate it as a formula
+func formatCSVValue(value any) string {
+	switch v := value.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case nil:
+		return ""
+	default:
+		return escapeFormula(fmt.Sprint(v))
+	}
+}
+
+// escapeFormula prefix a quote to text starting with a character a
+// spreadsheet reads as the start of a formula
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+
+	return text
+}
+
+// xlsxTableWriter write every section in its own sheet
+type xlsxTableWriter struct {
+	w *xlsx.Writer
+}
+
+func newXLSXTableWriter(w io.Writer) *xlsxTableWriter {
+	return &xlsxTableWriter{w: xlsx.NewWriter(w)}
+}
+
+func (t *xlsxTableWriter) section(name string, header ...string) error {
+	if err := t.w.AddSheet(name); err != nil {
+		return err
+	}
+	values := make([]any, 0, len(header))
+	for _, h := range header {
+		values = append(values, h)
+	}
+
+	return t.w.WriteRow(values...)
+}
+
+func (t *xlsxTableWriter) row(values ...any) error {
+	return t.w.WriteRow(values...)
+}
+
+func (t *xlsxTableWriter) close() error {
+	return t.w.Close()
+}
@@ -0,0 +1,109 @@
+package reconciliatonjob
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/delly/amartha/entity"
+)
+
+// transferDayWindow is the maximum days between the debit and the credit of
+// an internal transfer, the credit may be booked by the receiving bank a day
+// before or after the debit
+const transferDayWindow = 1
+
+// bankRowTransaction is a bank transaction along with its bank file index
+type bankRowTransaction struct {
+	bankIdx int
+	rowTransaction
+}
+
+// transferPair is a debit and a credit bank transaction paired as an internal transfer
+type transferPair struct {
+	debit  bankRowTransaction
+	credit bankRowTransaction
+}
+
+// pairTransfers pair debit bank transactions with credit bank transactions of
+// the same amount in another bank file within the transfer day window, the
+// closest credit by date is chosen and ties are broken by file order, the
+// bank transactions must be sorted so the pairs are deterministic
+func pairTransfers(bankTrxs [][]rowTransaction) ([]transferPair, [][]rowTransaction) {
+	credits := map[string][]bankRowTransaction{}
+	for bankIdx, trxs := range bankTrxs {
+		for _, trx := range trxs {
+			if trx.trx.Type != entity.TxTypeCredit {
+				continue
+			}
+			key := amountKey(trx.trx.Amount)
+			credits[key] = append(credits[key], bankRowTransaction{bankIdx: bankIdx, rowTransaction: trx})
+		}
+	}
+
+	pairs := []transferPair{}
+	paired := map[*entity.Transaction]bool{}
+	for bankIdx, trxs := range bankTrxs {
+		for _, trx := range trxs {
+			if trx.trx.Type != entity.TxTypeDebit {
+				continue
+			}
+			var (
+				found   bool
+				closest bankRowTransaction
+				minDays int
+			)
+			for _, credit := range credits[amountKey(trx.trx.Amount)] {
+				if credit.bankIdx == bankIdx || paired[credit.trx] {
+					continue
+				}
+				days := abs(daysBetween(trx.trx.Time, credit.trx.Time))
+				if days > transferDayWindow || (found && days >= minDays) {
+					continue
+				}
+				found, closest, minDays = true, credit, days
+			}
+			if !found {
+				continue
+			}
+			paired[trx.trx] = true
+			paired[closest.trx] = true
+			pairs = append(pairs, transferPair{
+				debit:  bankRowTransaction{bankIdx: bankIdx, rowTransaction: trx},
+				credit: closest,
+			})
+		}
+	}
+
+	remaining := make([][]rowTransaction, len(bankTrxs))
+	for bankIdx, trxs := range bankTrxs {
+		for _, trx := range trxs {
+			if !paired[trx.trx] {
+				remaining[bankIdx] = append(remaining[bankIdx], trx)
+			}
+		}
+	}
+
+	return pairs, remaining
+}
+
+func amountKey(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// daysBetween return the number of calendar days from a to b
+func daysBetween(a, b time.Time) int {
+	aYear, aMonth, aDay := a.Date()
+	bYear, bMonth, bDay := b.Date()
+	aDate := time.Date(aYear, aMonth, aDay, 0, 0, 0, 0, time.UTC)
+	bDate := time.Date(bYear, bMonth, bDay, 0, 0, 0, 0, time.UTC)
+
+	return int(bDate.Sub(aDate).Hours() / 24)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
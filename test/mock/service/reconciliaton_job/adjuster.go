@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/reconciliaton_job/adjuster.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./service/reconciliaton_job/adjuster.go -destination=test/mock/service/./reconciliaton_job/adjuster.go
+//
+
+// Package mock_reconciliatonjob is a generated GoMock package.
+package mock_reconciliatonjob
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockAdjuster is a mock of Adjuster interface.
+type MockAdjuster struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdjusterMockRecorder
+}
+
+// MockAdjusterMockRecorder is the mock recorder for MockAdjuster.
+type MockAdjusterMockRecorder struct {
+	mock *MockAdjuster
+}
+
+// NewMockAdjuster creates a new mock instance.
+func NewMockAdjuster(ctrl *gomock.Controller) *MockAdjuster {
+	mock := &MockAdjuster{ctrl: ctrl}
+	mock.recorder = &MockAdjusterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdjuster) EXPECT() *MockAdjusterMockRecorder {
+	return m.recorder
+}
+
+// FindAdjustments mocks base method.
+func (m *MockAdjuster) FindAdjustments(ctx context.Context, jobID int64) ([]*entity.ReconciliationAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAdjustments", ctx, jobID)
+	ret0, _ := ret[0].([]*entity.ReconciliationAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAdjustments indicates an expected call of FindAdjustments.
+func (mr *MockAdjusterMockRecorder) FindAdjustments(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAdjustments", reflect.TypeOf((*MockAdjuster)(nil).FindAdjustments), ctx, jobID)
+}
+
+// ManualMatch mocks base method.
+func (m *MockAdjuster) ManualMatch(ctx context.Context, job *entity.ReconciliationJob, params *reconciliatonjob.ManualMatchParams) (*entity.ReconciliationAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ManualMatch", ctx, job, params)
+	ret0, _ := ret[0].(*entity.ReconciliationAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ManualMatch indicates an expected call of ManualMatch.
+func (mr *MockAdjusterMockRecorder) ManualMatch(ctx, job, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ManualMatch", reflect.TypeOf((*MockAdjuster)(nil).ManualMatch), ctx, job, params)
+}
+
+// Resolve mocks base method.
+func (m *MockAdjuster) Resolve(ctx context.Context, job *entity.ReconciliationJob, params *reconciliatonjob.ResolveParams) (*entity.ReconciliationAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Resolve", ctx, job, params)
+	ret0, _ := ret[0].(*entity.ReconciliationAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Resolve indicates an expected call of Resolve.
+func (mr *MockAdjusterMockRecorder) Resolve(ctx, job, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resolve", reflect.TypeOf((*MockAdjuster)(nil).Resolve), ctx, job, params)
+}
+
+// MockAdjusterRepository is a mock of AdjusterRepository interface.
+type MockAdjusterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdjusterRepositoryMockRecorder
+}
+
+// MockAdjusterRepositoryMockRecorder is the mock recorder for MockAdjusterRepository.
+type MockAdjusterRepositoryMockRecorder struct {
+	mock *MockAdjusterRepository
+}
+
+// NewMockAdjusterRepository creates a new mock instance.
+func NewMockAdjusterRepository(ctrl *gomock.Controller) *MockAdjusterRepository {
+	mock := &MockAdjusterRepository{ctrl: ctrl}
+	mock.recorder = &MockAdjusterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdjusterRepository) EXPECT() *MockAdjusterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListOverlappingReconciliationPeriodLocks mocks base method.
+func (m *MockAdjusterRepository) ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverlappingReconciliationPeriodLocks", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverlappingReconciliationPeriodLocks indicates an expected call of ListOverlappingReconciliationPeriodLocks.
+func (mr *MockAdjusterRepositoryMockRecorder) ListOverlappingReconciliationPeriodLocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverlappingReconciliationPeriodLocks", reflect.TypeOf((*MockAdjusterRepository)(nil).ListOverlappingReconciliationPeriodLocks), ctx, arg)
+}
+
+// ListReconciliationAdjustments mocks base method.
+func (m *MockAdjusterRepository) ListReconciliationAdjustments(ctx context.Context, jobID int64) ([]dbgen.ListReconciliationAdjustmentsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationAdjustments", ctx, jobID)
+	ret0, _ := ret[0].([]dbgen.ListReconciliationAdjustmentsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationAdjustments indicates an expected call of ListReconciliationAdjustments.
+func (mr *MockAdjusterRepositoryMockRecorder) ListReconciliationAdjustments(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationAdjustments", reflect.TypeOf((*MockAdjusterRepository)(nil).ListReconciliationAdjustments), ctx, jobID)
+}
+
+// ListReconciliationItemsByIds mocks base method.
+func (m *MockAdjusterRepository) ListReconciliationItemsByIds(ctx context.Context, arg dbgen.ListReconciliationItemsByIdsParams) ([]dbgen.ReconciliationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationItemsByIds", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationItemsByIds indicates an expected call of ListReconciliationItemsByIds.
+func (mr *MockAdjusterRepositoryMockRecorder) ListReconciliationItemsByIds(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationItemsByIds", reflect.TypeOf((*MockAdjusterRepository)(nil).ListReconciliationItemsByIds), ctx, arg)
+}
+
+// SaveReconciliationAdjustment mocks base method.
+func (m *MockAdjusterRepository) SaveReconciliationAdjustment(ctx context.Context, arg dbgen.SaveReconciliationAdjustmentParams) (dbgen.ReconciliationAdjustment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReconciliationAdjustment", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationAdjustment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveReconciliationAdjustment indicates an expected call of SaveReconciliationAdjustment.
+func (mr *MockAdjusterRepositoryMockRecorder) SaveReconciliationAdjustment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReconciliationAdjustment", reflect.TypeOf((*MockAdjusterRepository)(nil).SaveReconciliationAdjustment), ctx, arg)
+}
@@ -0,0 +1,246 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/reconciliaton_job/approver.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./service/reconciliaton_job/approver.go -destination=test/mock/service/./reconciliaton_job/approver.go
+//
+
+// Package mock_reconciliatonjob is a generated GoMock package.
+package mock_reconciliatonjob
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockApprover is a mock of Approver interface.
+type MockApprover struct {
+	ctrl     *gomock.Controller
+	recorder *MockApproverMockRecorder
+}
+
+// MockApproverMockRecorder is the mock recorder for MockApprover.
+type MockApproverMockRecorder struct {
+	mock *MockApprover
+}
+
+// NewMockApprover creates a new mock instance.
+func NewMockApprover(ctrl *gomock.Controller) *MockApprover {
+	mock := &MockApprover{ctrl: ctrl}
+	mock.recorder = &MockApproverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApprover) EXPECT() *MockApproverMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockApprover) Approve(ctx context.Context, job *entity.ReconciliationJob, params *reconciliatonjob.ApprovalParams) (*entity.ReconciliationApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, job, params)
+	ret0, _ := ret[0].(*entity.ReconciliationApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockApproverMockRecorder) Approve(ctx, job, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockApprover)(nil).Approve), ctx, job, params)
+}
+
+// FindApprovals mocks base method.
+func (m *MockApprover) FindApprovals(ctx context.Context, jobID int64) ([]*entity.ReconciliationApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindApprovals", ctx, jobID)
+	ret0, _ := ret[0].([]*entity.ReconciliationApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindApprovals indicates an expected call of FindApprovals.
+func (mr *MockApproverMockRecorder) FindApprovals(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindApprovals", reflect.TypeOf((*MockApprover)(nil).FindApprovals), ctx, jobID)
+}
+
+// FindPeriodLocks mocks base method.
+func (m *MockApprover) FindPeriodLocks(ctx context.Context, filter reconciliatonjob.PeriodLockFilter) ([]*entity.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindPeriodLocks", ctx, filter)
+	ret0, _ := ret[0].([]*entity.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindPeriodLocks indicates an expected call of FindPeriodLocks.
+func (mr *MockApproverMockRecorder) FindPeriodLocks(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindPeriodLocks", reflect.TypeOf((*MockApprover)(nil).FindPeriodLocks), ctx, filter)
+}
+
+// Reject mocks base method.
+func (m *MockApprover) Reject(ctx context.Context, job *entity.ReconciliationJob, params *reconciliatonjob.ApprovalParams) (*entity.ReconciliationApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, job, params)
+	ret0, _ := ret[0].(*entity.ReconciliationApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockApproverMockRecorder) Reject(ctx, job, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockApprover)(nil).Reject), ctx, job, params)
+}
+
+// Submit mocks base method.
+func (m *MockApprover) Submit(ctx context.Context, job *entity.ReconciliationJob, params *reconciliatonjob.ApprovalParams) (*entity.ReconciliationApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, job, params)
+	ret0, _ := ret[0].(*entity.ReconciliationApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockApproverMockRecorder) Submit(ctx, job, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockApprover)(nil).Submit), ctx, job, params)
+}
+
+// Unlock mocks base method.
+func (m *MockApprover) Unlock(ctx context.Context, params *reconciliatonjob.UnlockParams) (*entity.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unlock", ctx, params)
+	ret0, _ := ret[0].(*entity.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Unlock indicates an expected call of Unlock.
+func (mr *MockApproverMockRecorder) Unlock(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unlock", reflect.TypeOf((*MockApprover)(nil).Unlock), ctx, params)
+}
+
+// MockApproverRepository is a mock of ApproverRepository interface.
+type MockApproverRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockApproverRepositoryMockRecorder
+}
+
+// MockApproverRepositoryMockRecorder is the mock recorder for MockApproverRepository.
+type MockApproverRepositoryMockRecorder struct {
+	mock *MockApproverRepository
+}
+
+// NewMockApproverRepository creates a new mock instance.
+func NewMockApproverRepository(ctrl *gomock.Controller) *MockApproverRepository {
+	mock := &MockApproverRepository{ctrl: ctrl}
+	mock.recorder = &MockApproverRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockApproverRepository) EXPECT() *MockApproverRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetReconciliationPeriodLockById mocks base method.
+func (m *MockApproverRepository) GetReconciliationPeriodLockById(ctx context.Context, id int64) (dbgen.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReconciliationPeriodLockById", ctx, id)
+	ret0, _ := ret[0].(dbgen.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReconciliationPeriodLockById indicates an expected call of GetReconciliationPeriodLockById.
+func (mr *MockApproverRepositoryMockRecorder) GetReconciliationPeriodLockById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationPeriodLockById", reflect.TypeOf((*MockApproverRepository)(nil).GetReconciliationPeriodLockById), ctx, id)
+}
+
+// ListOverlappingReconciliationPeriodLocks mocks base method.
+func (m *MockApproverRepository) ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverlappingReconciliationPeriodLocks", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverlappingReconciliationPeriodLocks indicates an expected call of ListOverlappingReconciliationPeriodLocks.
+func (mr *MockApproverRepositoryMockRecorder) ListOverlappingReconciliationPeriodLocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverlappingReconciliationPeriodLocks", reflect.TypeOf((*MockApproverRepository)(nil).ListOverlappingReconciliationPeriodLocks), ctx, arg)
+}
+
+// ListReconciliationApprovals mocks base method.
+func (m *MockApproverRepository) ListReconciliationApprovals(ctx context.Context, jobID int64) ([]dbgen.ReconciliationApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationApprovals", ctx, jobID)
+	ret0, _ := ret[0].([]dbgen.ReconciliationApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationApprovals indicates an expected call of ListReconciliationApprovals.
+func (mr *MockApproverRepositoryMockRecorder) ListReconciliationApprovals(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationApprovals", reflect.TypeOf((*MockApproverRepository)(nil).ListReconciliationApprovals), ctx, jobID)
+}
+
+// ListReconciliationPeriodLocks mocks base method.
+func (m *MockApproverRepository) ListReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationPeriodLocks", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationPeriodLocks indicates an expected call of ListReconciliationPeriodLocks.
+func (mr *MockApproverRepositoryMockRecorder) ListReconciliationPeriodLocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationPeriodLocks", reflect.TypeOf((*MockApproverRepository)(nil).ListReconciliationPeriodLocks), ctx, arg)
+}
+
+// SaveReconciliationApproval mocks base method.
+func (m *MockApproverRepository) SaveReconciliationApproval(ctx context.Context, arg dbgen.SaveReconciliationApprovalParams) (dbgen.ReconciliationApproval, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReconciliationApproval", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationApproval)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveReconciliationApproval indicates an expected call of SaveReconciliationApproval.
+func (mr *MockApproverRepositoryMockRecorder) SaveReconciliationApproval(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReconciliationApproval", reflect.TypeOf((*MockApproverRepository)(nil).SaveReconciliationApproval), ctx, arg)
+}
+
+// UnlockReconciliationPeriod mocks base method.
+func (m *MockApproverRepository) UnlockReconciliationPeriod(ctx context.Context, arg dbgen.UnlockReconciliationPeriodParams) (dbgen.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockReconciliationPeriod", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnlockReconciliationPeriod indicates an expected call of UnlockReconciliationPeriod.
+func (mr *MockApproverRepositoryMockRecorder) UnlockReconciliationPeriod(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockReconciliationPeriod", reflect.TypeOf((*MockApproverRepository)(nil).UnlockReconciliationPeriod), ctx, arg)
+}
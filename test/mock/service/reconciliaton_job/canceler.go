@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/reconciliaton_job/canceler.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./service/reconciliaton_job/canceler.go -destination=test/mock/service/./reconciliaton_job/canceler.go
+//
+
+// Package mock_reconciliatonjob is a generated GoMock package.
+package mock_reconciliatonjob
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCanceler is a mock of Canceler interface.
+type MockCanceler struct {
+	ctrl     *gomock.Controller
+	recorder *MockCancelerMockRecorder
+}
+
+// MockCancelerMockRecorder is the mock recorder for MockCanceler.
+type MockCancelerMockRecorder struct {
+	mock *MockCanceler
+}
+
+// NewMockCanceler creates a new mock instance.
+func NewMockCanceler(ctrl *gomock.Controller) *MockCanceler {
+	mock := &MockCanceler{ctrl: ctrl}
+	mock.recorder = &MockCancelerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCanceler) EXPECT() *MockCancelerMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockCanceler) Cancel(ctx context.Context, id int64) (*entity.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, id)
+	ret0, _ := ret[0].(*entity.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockCancelerMockRecorder) Cancel(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockCanceler)(nil).Cancel), ctx, id)
+}
+
+// MockCancelerRepository is a mock of CancelerRepository interface.
+type MockCancelerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCancelerRepositoryMockRecorder
+}
+
+// MockCancelerRepositoryMockRecorder is the mock recorder for MockCancelerRepository.
+type MockCancelerRepositoryMockRecorder struct {
+	mock *MockCancelerRepository
+}
+
+// NewMockCancelerRepository creates a new mock instance.
+func NewMockCancelerRepository(ctrl *gomock.Controller) *MockCancelerRepository {
+	mock := &MockCancelerRepository{ctrl: ctrl}
+	mock.recorder = &MockCancelerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCancelerRepository) EXPECT() *MockCancelerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelReconciliationJob mocks base method.
+func (m *MockCancelerRepository) CancelReconciliationJob(ctx context.Context, id int64) (dbgen.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelReconciliationJob", ctx, id)
+	ret0, _ := ret[0].(dbgen.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelReconciliationJob indicates an expected call of CancelReconciliationJob.
+func (mr *MockCancelerRepositoryMockRecorder) CancelReconciliationJob(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelReconciliationJob", reflect.TypeOf((*MockCancelerRepository)(nil).CancelReconciliationJob), ctx, id)
+}
+
+// GetReconciliationJobById mocks base method.
+func (m *MockCancelerRepository) GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReconciliationJobById", ctx, id)
+	ret0, _ := ret[0].(dbgen.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReconciliationJobById indicates an expected call of GetReconciliationJobById.
+func (mr *MockCancelerRepositoryMockRecorder) GetReconciliationJobById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationJobById", reflect.TypeOf((*MockCancelerRepository)(nil).GetReconciliationJobById), ctx, id)
+}
@@ -0,0 +1,306 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/reconciliaton_job/case_manager.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./service/reconciliaton_job/case_manager.go -destination=test/mock/service/./reconciliaton_job/case_manager.go
+//
+
+// Package mock_reconciliatonjob is a generated GoMock package.
+package mock_reconciliatonjob
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCaseManager is a mock of CaseManager interface.
+type MockCaseManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockCaseManagerMockRecorder
+}
+
+// MockCaseManagerMockRecorder is the mock recorder for MockCaseManager.
+type MockCaseManagerMockRecorder struct {
+	mock *MockCaseManager
+}
+
+// NewMockCaseManager creates a new mock instance.
+func NewMockCaseManager(ctrl *gomock.Controller) *MockCaseManager {
+	mock := &MockCaseManager{ctrl: ctrl}
+	mock.recorder = &MockCaseManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCaseManager) EXPECT() *MockCaseManagerMockRecorder {
+	return m.recorder
+}
+
+// AddComment mocks base method.
+func (m *MockCaseManager) AddComment(ctx context.Context, params *reconciliatonjob.AddCaseCommentParams) (*entity.ReconciliationCaseComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddComment", ctx, params)
+	ret0, _ := ret[0].(*entity.ReconciliationCaseComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddComment indicates an expected call of AddComment.
+func (mr *MockCaseManagerMockRecorder) AddComment(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockCaseManager)(nil).AddComment), ctx, params)
+}
+
+// ChangeStatus mocks base method.
+func (m *MockCaseManager) ChangeStatus(ctx context.Context, params *reconciliatonjob.ChangeCaseStatusParams) (*entity.ReconciliationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeStatus", ctx, params)
+	ret0, _ := ret[0].(*entity.ReconciliationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeStatus indicates an expected call of ChangeStatus.
+func (mr *MockCaseManagerMockRecorder) ChangeStatus(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeStatus", reflect.TypeOf((*MockCaseManager)(nil).ChangeStatus), ctx, params)
+}
+
+// CountByAssignee mocks base method.
+func (m *MockCaseManager) CountByAssignee(ctx context.Context, filter reconciliatonjob.CaseFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountByAssignee", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountByAssignee indicates an expected call of CountByAssignee.
+func (mr *MockCaseManagerMockRecorder) CountByAssignee(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountByAssignee", reflect.TypeOf((*MockCaseManager)(nil).CountByAssignee), ctx, filter)
+}
+
+// FindByAssignee mocks base method.
+func (m *MockCaseManager) FindByAssignee(ctx context.Context, filter reconciliatonjob.CaseFilter, limit, offset int32) ([]*entity.ReconciliationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByAssignee", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]*entity.ReconciliationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByAssignee indicates an expected call of FindByAssignee.
+func (mr *MockCaseManagerMockRecorder) FindByAssignee(ctx, filter, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByAssignee", reflect.TypeOf((*MockCaseManager)(nil).FindByAssignee), ctx, filter, limit, offset)
+}
+
+// FindByID mocks base method.
+func (m *MockCaseManager) FindByID(ctx context.Context, id int64) (*entity.ReconciliationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByID", ctx, id)
+	ret0, _ := ret[0].(*entity.ReconciliationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByID indicates an expected call of FindByID.
+func (mr *MockCaseManagerMockRecorder) FindByID(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockCaseManager)(nil).FindByID), ctx, id)
+}
+
+// Open mocks base method.
+func (m *MockCaseManager) Open(ctx context.Context, params *reconciliatonjob.OpenCaseParams) (*entity.ReconciliationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, params)
+	ret0, _ := ret[0].(*entity.ReconciliationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockCaseManagerMockRecorder) Open(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockCaseManager)(nil).Open), ctx, params)
+}
+
+// MockCaseManagerRepository is a mock of CaseManagerRepository interface.
+type MockCaseManagerRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockCaseManagerRepositoryMockRecorder
+}
+
+// MockCaseManagerRepositoryMockRecorder is the mock recorder for MockCaseManagerRepository.
+type MockCaseManagerRepositoryMockRecorder struct {
+	mock *MockCaseManagerRepository
+}
+
+// NewMockCaseManagerRepository creates a new mock instance.
+func NewMockCaseManagerRepository(ctrl *gomock.Controller) *MockCaseManagerRepository {
+	mock := &MockCaseManagerRepository{ctrl: ctrl}
+	mock.recorder = &MockCaseManagerRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCaseManagerRepository) EXPECT() *MockCaseManagerRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ChangeReconciliationCaseStatus mocks base method.
+func (m *MockCaseManagerRepository) ChangeReconciliationCaseStatus(ctx context.Context, arg dbgen.ChangeReconciliationCaseStatusParams) (dbgen.ReconciliationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeReconciliationCaseStatus", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeReconciliationCaseStatus indicates an expected call of ChangeReconciliationCaseStatus.
+func (mr *MockCaseManagerRepositoryMockRecorder) ChangeReconciliationCaseStatus(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeReconciliationCaseStatus", reflect.TypeOf((*MockCaseManagerRepository)(nil).ChangeReconciliationCaseStatus), ctx, arg)
+}
+
+// CountReconciliationCasesByAssignee mocks base method.
+func (m *MockCaseManagerRepository) CountReconciliationCasesByAssignee(ctx context.Context, arg dbgen.CountReconciliationCasesByAssigneeParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReconciliationCasesByAssignee", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReconciliationCasesByAssignee indicates an expected call of CountReconciliationCasesByAssignee.
+func (mr *MockCaseManagerRepositoryMockRecorder) CountReconciliationCasesByAssignee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReconciliationCasesByAssignee", reflect.TypeOf((*MockCaseManagerRepository)(nil).CountReconciliationCasesByAssignee), ctx, arg)
+}
+
+// CreateReconciliationCaseComment mocks base method.
+func (m *MockCaseManagerRepository) CreateReconciliationCaseComment(ctx context.Context, arg dbgen.CreateReconciliationCaseCommentParams) (dbgen.ReconciliationCaseComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReconciliationCaseComment", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationCaseComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReconciliationCaseComment indicates an expected call of CreateReconciliationCaseComment.
+func (mr *MockCaseManagerRepositoryMockRecorder) CreateReconciliationCaseComment(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReconciliationCaseComment", reflect.TypeOf((*MockCaseManagerRepository)(nil).CreateReconciliationCaseComment), ctx, arg)
+}
+
+// GetReconciliationCaseById mocks base method.
+func (m *MockCaseManagerRepository) GetReconciliationCaseById(ctx context.Context, id int64) (dbgen.GetReconciliationCaseByIdRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReconciliationCaseById", ctx, id)
+	ret0, _ := ret[0].(dbgen.GetReconciliationCaseByIdRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReconciliationCaseById indicates an expected call of GetReconciliationCaseById.
+func (mr *MockCaseManagerRepositoryMockRecorder) GetReconciliationCaseById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationCaseById", reflect.TypeOf((*MockCaseManagerRepository)(nil).GetReconciliationCaseById), ctx, id)
+}
+
+// GetReconciliationJobById mocks base method.
+func (m *MockCaseManagerRepository) GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReconciliationJobById", ctx, id)
+	ret0, _ := ret[0].(dbgen.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReconciliationJobById indicates an expected call of GetReconciliationJobById.
+func (mr *MockCaseManagerRepositoryMockRecorder) GetReconciliationJobById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationJobById", reflect.TypeOf((*MockCaseManagerRepository)(nil).GetReconciliationJobById), ctx, id)
+}
+
+// ListOverlappingReconciliationPeriodLocks mocks base method.
+func (m *MockCaseManagerRepository) ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverlappingReconciliationPeriodLocks", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverlappingReconciliationPeriodLocks indicates an expected call of ListOverlappingReconciliationPeriodLocks.
+func (mr *MockCaseManagerRepositoryMockRecorder) ListOverlappingReconciliationPeriodLocks(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverlappingReconciliationPeriodLocks", reflect.TypeOf((*MockCaseManagerRepository)(nil).ListOverlappingReconciliationPeriodLocks), ctx, arg)
+}
+
+// ListReconciliationCaseComments mocks base method.
+func (m *MockCaseManagerRepository) ListReconciliationCaseComments(ctx context.Context, caseID int64) ([]dbgen.ReconciliationCaseComment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationCaseComments", ctx, caseID)
+	ret0, _ := ret[0].([]dbgen.ReconciliationCaseComment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationCaseComments indicates an expected call of ListReconciliationCaseComments.
+func (mr *MockCaseManagerRepositoryMockRecorder) ListReconciliationCaseComments(ctx, caseID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationCaseComments", reflect.TypeOf((*MockCaseManagerRepository)(nil).ListReconciliationCaseComments), ctx, caseID)
+}
+
+// ListReconciliationCasesByAssignee mocks base method.
+func (m *MockCaseManagerRepository) ListReconciliationCasesByAssignee(ctx context.Context, arg dbgen.ListReconciliationCasesByAssigneeParams) ([]dbgen.ListReconciliationCasesByAssigneeRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationCasesByAssignee", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ListReconciliationCasesByAssigneeRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationCasesByAssignee indicates an expected call of ListReconciliationCasesByAssignee.
+func (mr *MockCaseManagerRepositoryMockRecorder) ListReconciliationCasesByAssignee(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationCasesByAssignee", reflect.TypeOf((*MockCaseManagerRepository)(nil).ListReconciliationCasesByAssignee), ctx, arg)
+}
+
+// ListReconciliationItemsByIds mocks base method.
+func (m *MockCaseManagerRepository) ListReconciliationItemsByIds(ctx context.Context, arg dbgen.ListReconciliationItemsByIdsParams) ([]dbgen.ReconciliationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationItemsByIds", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationItemsByIds indicates an expected call of ListReconciliationItemsByIds.
+func (mr *MockCaseManagerRepositoryMockRecorder) ListReconciliationItemsByIds(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationItemsByIds", reflect.TypeOf((*MockCaseManagerRepository)(nil).ListReconciliationItemsByIds), ctx, arg)
+}
+
+// OpenReconciliationCase mocks base method.
+func (m *MockCaseManagerRepository) OpenReconciliationCase(ctx context.Context, arg dbgen.OpenReconciliationCaseParams) (dbgen.ReconciliationCase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenReconciliationCase", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationCase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenReconciliationCase indicates an expected call of OpenReconciliationCase.
+func (mr *MockCaseManagerRepositoryMockRecorder) OpenReconciliationCase(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenReconciliationCase", reflect.TypeOf((*MockCaseManagerRepository)(nil).OpenReconciliationCase), ctx, arg)
+}
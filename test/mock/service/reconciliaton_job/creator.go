@@ -58,6 +58,47 @@ func (mr *MockCreatorMockRecorder) Create(ctx, params any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockCreator)(nil).Create), ctx, params)
 }
 
+// DiscardFiles mocks base method.
+func (m *MockCreator) DiscardFiles(ctx context.Context, params *reconciliatonjob.CreateParams) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "DiscardFiles", ctx, params)
+}
+
+// DiscardFiles indicates an expected call of DiscardFiles.
+func (mr *MockCreatorMockRecorder) DiscardFiles(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardFiles", reflect.TypeOf((*MockCreator)(nil).DiscardFiles), ctx, params)
+}
+
+// Rerun mocks base method.
+func (m *MockCreator) Rerun(ctx context.Context, params *reconciliatonjob.RerunParams) (*entity.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rerun", ctx, params)
+	ret0, _ := ret[0].(*entity.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rerun indicates an expected call of Rerun.
+func (mr *MockCreatorMockRecorder) Rerun(ctx, params any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rerun", reflect.TypeOf((*MockCreator)(nil).Rerun), ctx, params)
+}
+
+// StoreFile mocks base method.
+func (m *MockCreator) StoreFile(ctx context.Context, params *reconciliatonjob.CreateParams, file *reconciliatonjob.File) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StoreFile", ctx, params, file)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StoreFile indicates an expected call of StoreFile.
+func (mr *MockCreatorMockRecorder) StoreFile(ctx, params, file any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StoreFile", reflect.TypeOf((*MockCreator)(nil).StoreFile), ctx, params, file)
+}
+
 // MockCreatorRepository is a mock of CreatorRepository interface.
 type MockCreatorRepository struct {
 	ctrl     *gomock.Controller
@@ -81,19 +122,49 @@ func (m *MockCreatorRepository) EXPECT() *MockCreatorRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CreateReconciliationJob mocks base method.
-func (m *MockCreatorRepository) CreateReconciliationJob(ctx context.Context, job dbgen.CreateReconciliationJobParams) (dbgen.ReconciliationJob, error) {
+// CreateReconciliationJobInUnlockedPeriod mocks base method.
+func (m *MockCreatorRepository) CreateReconciliationJobInUnlockedPeriod(ctx context.Context, arg dbgen.CreateReconciliationJobInUnlockedPeriodParams) (dbgen.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReconciliationJobInUnlockedPeriod", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReconciliationJobInUnlockedPeriod indicates an expected call of CreateReconciliationJobInUnlockedPeriod.
+func (mr *MockCreatorRepositoryMockRecorder) CreateReconciliationJobInUnlockedPeriod(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReconciliationJobInUnlockedPeriod", reflect.TypeOf((*MockCreatorRepository)(nil).CreateReconciliationJobInUnlockedPeriod), ctx, arg)
+}
+
+// GetReconciliationJobById mocks base method.
+func (m *MockCreatorRepository) GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateReconciliationJob", ctx, job)
+	ret := m.ctrl.Call(m, "GetReconciliationJobById", ctx, id)
 	ret0, _ := ret[0].(dbgen.ReconciliationJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateReconciliationJob indicates an expected call of CreateReconciliationJob.
-func (mr *MockCreatorRepositoryMockRecorder) CreateReconciliationJob(ctx, job any) *gomock.Call {
+// GetReconciliationJobById indicates an expected call of GetReconciliationJobById.
+func (mr *MockCreatorRepositoryMockRecorder) GetReconciliationJobById(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationJobById", reflect.TypeOf((*MockCreatorRepository)(nil).GetReconciliationJobById), ctx, id)
+}
+
+// ListOverlappingReconciliationPeriodLocks mocks base method.
+func (m *MockCreatorRepository) ListOverlappingReconciliationPeriodLocks(ctx context.Context, arg dbgen.ListOverlappingReconciliationPeriodLocksParams) ([]dbgen.ReconciliationPeriodLock, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOverlappingReconciliationPeriodLocks", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationPeriodLock)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOverlappingReconciliationPeriodLocks indicates an expected call of ListOverlappingReconciliationPeriodLocks.
+func (mr *MockCreatorRepositoryMockRecorder) ListOverlappingReconciliationPeriodLocks(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReconciliationJob", reflect.TypeOf((*MockCreatorRepository)(nil).CreateReconciliationJob), ctx, job)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOverlappingReconciliationPeriodLocks", reflect.TypeOf((*MockCreatorRepository)(nil).ListOverlappingReconciliationPeriodLocks), ctx, arg)
 }
 
 // MockFileStorer is a mock of FileStorer interface.
@@ -119,6 +190,20 @@ func (m *MockFileStorer) EXPECT() *MockFileStorerMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockFileStorer) Delete(ctx context.Context, filePath string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, filePath)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockFileStorerMockRecorder) Delete(ctx, filePath any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockFileStorer)(nil).Delete), ctx, filePath)
+}
+
 // Store mocks base method.
 func (m *MockFileStorer) Store(ctx context.Context, file *filestorage.File) (string, error) {
 	m.ctrl.T.Helper()
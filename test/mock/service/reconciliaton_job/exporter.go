@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/reconciliaton_job/exporter.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./service/reconciliaton_job/exporter.go -destination=test/mock/service/./reconciliaton_job/exporter.go
+//
+
+// Package mock_reconciliatonjob is a generated GoMock package.
+package mock_reconciliatonjob
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	entity "github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExporter is a mock of Exporter interface.
+type MockExporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockExporterMockRecorder
+}
+
+// MockExporterMockRecorder is the mock recorder for MockExporter.
+type MockExporterMockRecorder struct {
+	mock *MockExporter
+}
+
+// NewMockExporter creates a new mock instance.
+func NewMockExporter(ctrl *gomock.Controller) *MockExporter {
+	mock := &MockExporter{ctrl: ctrl}
+	mock.recorder = &MockExporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExporter) EXPECT() *MockExporterMockRecorder {
+	return m.recorder
+}
+
+// Export mocks base method.
+func (m *MockExporter) Export(ctx context.Context, job *entity.ReconciliationJob, format reconciliatonjob.ExportFormat, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, job, format, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockExporterMockRecorder) Export(ctx, job, format, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockExporter)(nil).Export), ctx, job, format, w)
+}
+
+// MockExporterRepository is a mock of ExporterRepository interface.
+type MockExporterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockExporterRepositoryMockRecorder
+}
+
+// MockExporterRepositoryMockRecorder is the mock recorder for MockExporterRepository.
+type MockExporterRepositoryMockRecorder struct {
+	mock *MockExporterRepository
+}
+
+// NewMockExporterRepository creates a new mock instance.
+func NewMockExporterRepository(ctrl *gomock.Controller) *MockExporterRepository {
+	mock := &MockExporterRepository{ctrl: ctrl}
+	mock.recorder = &MockExporterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExporterRepository) EXPECT() *MockExporterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ExistsReconciliationItems mocks base method.
+func (m *MockExporterRepository) ExistsReconciliationItems(ctx context.Context, jobID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsReconciliationItems", ctx, jobID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsReconciliationItems indicates an expected call of ExistsReconciliationItems.
+func (mr *MockExporterRepositoryMockRecorder) ExistsReconciliationItems(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsReconciliationItems", reflect.TypeOf((*MockExporterRepository)(nil).ExistsReconciliationItems), ctx, jobID)
+}
+
+// ListMissingReconciliationItems mocks base method.
+func (m *MockExporterRepository) ListMissingReconciliationItems(ctx context.Context, arg dbgen.ListMissingReconciliationItemsParams) ([]dbgen.ReconciliationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMissingReconciliationItems", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMissingReconciliationItems indicates an expected call of ListMissingReconciliationItems.
+func (mr *MockExporterRepositoryMockRecorder) ListMissingReconciliationItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMissingReconciliationItems", reflect.TypeOf((*MockExporterRepository)(nil).ListMissingReconciliationItems), ctx, arg)
+}
+
+// ListReconciliationMatchedPairs mocks base method.
+func (m *MockExporterRepository) ListReconciliationMatchedPairs(ctx context.Context, arg dbgen.ListReconciliationMatchedPairsParams) ([]dbgen.ListReconciliationMatchedPairsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationMatchedPairs", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ListReconciliationMatchedPairsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationMatchedPairs indicates an expected call of ListReconciliationMatchedPairs.
+func (mr *MockExporterRepositoryMockRecorder) ListReconciliationMatchedPairs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationMatchedPairs", reflect.TypeOf((*MockExporterRepository)(nil).ListReconciliationMatchedPairs), ctx, arg)
+}
@@ -12,9 +12,11 @@ package mock_reconciliatonjob
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/delly/amartha/entity"
 	dbgen "github.com/delly/amartha/repository/postgresql"
+	reconciliatonjob "github.com/delly/amartha/service/reconciliaton_job"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +43,21 @@ func (m *MockFinder) EXPECT() *MockFinderMockRecorder {
 	return m.recorder
 }
 
+// Ageing mocks base method.
+func (m *MockFinder) Ageing(ctx context.Context, asOf time.Time) (*entity.UnmatchedAgeing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Ageing", ctx, asOf)
+	ret0, _ := ret[0].(*entity.UnmatchedAgeing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Ageing indicates an expected call of Ageing.
+func (mr *MockFinderMockRecorder) Ageing(ctx, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Ageing", reflect.TypeOf((*MockFinder)(nil).Ageing), ctx, asOf)
+}
+
 // Count mocks base method.
 func (m *MockFinder) Count(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +73,51 @@ func (mr *MockFinderMockRecorder) Count(ctx any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockFinder)(nil).Count), ctx)
 }
 
+// CountDiff mocks base method.
+func (m *MockFinder) CountDiff(ctx context.Context, job, other *entity.ReconciliationJob) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDiff", ctx, job, other)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDiff indicates an expected call of CountDiff.
+func (mr *MockFinderMockRecorder) CountDiff(ctx, job, other any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDiff", reflect.TypeOf((*MockFinder)(nil).CountDiff), ctx, job, other)
+}
+
+// CountItems mocks base method.
+func (m *MockFinder) CountItems(ctx context.Context, filter reconciliatonjob.ItemFilter) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountItems", ctx, filter)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountItems indicates an expected call of CountItems.
+func (mr *MockFinderMockRecorder) CountItems(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountItems", reflect.TypeOf((*MockFinder)(nil).CountItems), ctx, filter)
+}
+
+// Diff mocks base method.
+func (m *MockFinder) Diff(ctx context.Context, job, other *entity.ReconciliationJob, limit, offset int32) (*entity.ReconciliationDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, job, other, limit, offset)
+	ret0, _ := ret[0].(*entity.ReconciliationDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockFinderMockRecorder) Diff(ctx, job, other, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockFinder)(nil).Diff), ctx, job, other, limit, offset)
+}
+
 // FindAll mocks base method.
 func (m *MockFinder) FindAll(ctx context.Context, limit, offset int32) ([]*entity.SimpleReconciliationJob, error) {
 	m.ctrl.T.Helper()
@@ -86,6 +148,21 @@ func (mr *MockFinderMockRecorder) FindByID(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockFinder)(nil).FindByID), ctx, id)
 }
 
+// FindItems mocks base method.
+func (m *MockFinder) FindItems(ctx context.Context, filter reconciliatonjob.ItemFilter, limit, offset int32) ([]*entity.ReconciliationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindItems", ctx, filter, limit, offset)
+	ret0, _ := ret[0].([]*entity.ReconciliationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindItems indicates an expected call of FindItems.
+func (mr *MockFinderMockRecorder) FindItems(ctx, filter, limit, offset any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindItems", reflect.TypeOf((*MockFinder)(nil).FindItems), ctx, filter, limit, offset)
+}
+
 // MockFinderRepository is a mock of FinderRepository interface.
 type MockFinderRepository struct {
 	ctrl     *gomock.Controller
@@ -109,6 +186,36 @@ func (m *MockFinderRepository) EXPECT() *MockFinderRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CountReconciliationDiffItems mocks base method.
+func (m *MockFinderRepository) CountReconciliationDiffItems(ctx context.Context, arg dbgen.CountReconciliationDiffItemsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReconciliationDiffItems", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReconciliationDiffItems indicates an expected call of CountReconciliationDiffItems.
+func (mr *MockFinderRepositoryMockRecorder) CountReconciliationDiffItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReconciliationDiffItems", reflect.TypeOf((*MockFinderRepository)(nil).CountReconciliationDiffItems), ctx, arg)
+}
+
+// CountReconciliationItems mocks base method.
+func (m *MockFinderRepository) CountReconciliationItems(ctx context.Context, arg dbgen.CountReconciliationItemsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountReconciliationItems", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountReconciliationItems indicates an expected call of CountReconciliationItems.
+func (mr *MockFinderRepositoryMockRecorder) CountReconciliationItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReconciliationItems", reflect.TypeOf((*MockFinderRepository)(nil).CountReconciliationItems), ctx, arg)
+}
+
 // CountReconciliationJobs mocks base method.
 func (m *MockFinderRepository) CountReconciliationJobs(ctx context.Context) (int64, error) {
 	m.ctrl.T.Helper()
@@ -124,6 +231,36 @@ func (mr *MockFinderRepositoryMockRecorder) CountReconciliationJobs(ctx any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountReconciliationJobs", reflect.TypeOf((*MockFinderRepository)(nil).CountReconciliationJobs), ctx)
 }
 
+// DiffReconciliationItems mocks base method.
+func (m *MockFinderRepository) DiffReconciliationItems(ctx context.Context, arg dbgen.DiffReconciliationItemsParams) ([]dbgen.DiffReconciliationItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiffReconciliationItems", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.DiffReconciliationItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DiffReconciliationItems indicates an expected call of DiffReconciliationItems.
+func (mr *MockFinderRepositoryMockRecorder) DiffReconciliationItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiffReconciliationItems", reflect.TypeOf((*MockFinderRepository)(nil).DiffReconciliationItems), ctx, arg)
+}
+
+// ExistsReconciliationItems mocks base method.
+func (m *MockFinderRepository) ExistsReconciliationItems(ctx context.Context, jobID int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistsReconciliationItems", ctx, jobID)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistsReconciliationItems indicates an expected call of ExistsReconciliationItems.
+func (mr *MockFinderRepositoryMockRecorder) ExistsReconciliationItems(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsReconciliationItems", reflect.TypeOf((*MockFinderRepository)(nil).ExistsReconciliationItems), ctx, jobID)
+}
+
 // GetReconciliationJobById mocks base method.
 func (m *MockFinderRepository) GetReconciliationJobById(ctx context.Context, id int64) (dbgen.ReconciliationJob, error) {
 	m.ctrl.T.Helper()
@@ -139,6 +276,21 @@ func (mr *MockFinderRepositoryMockRecorder) GetReconciliationJobById(ctx, id any
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationJobById", reflect.TypeOf((*MockFinderRepository)(nil).GetReconciliationJobById), ctx, id)
 }
 
+// ListReconciliationItems mocks base method.
+func (m *MockFinderRepository) ListReconciliationItems(ctx context.Context, arg dbgen.ListReconciliationItemsParams) ([]dbgen.ReconciliationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationItems", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationItems indicates an expected call of ListReconciliationItems.
+func (mr *MockFinderRepositoryMockRecorder) ListReconciliationItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationItems", reflect.TypeOf((*MockFinderRepository)(nil).ListReconciliationItems), ctx, arg)
+}
+
 // ListReconciliationJobs mocks base method.
 func (m *MockFinderRepository) ListReconciliationJobs(ctx context.Context, arg dbgen.ListReconciliationJobsParams) ([]dbgen.ListReconciliationJobsRow, error) {
 	m.ctrl.T.Helper()
@@ -153,3 +305,18 @@ func (mr *MockFinderRepositoryMockRecorder) ListReconciliationJobs(ctx, arg any)
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationJobs", reflect.TypeOf((*MockFinderRepository)(nil).ListReconciliationJobs), ctx, arg)
 }
+
+// SummarizeUnresolvedReconciliationItems mocks base method.
+func (m *MockFinderRepository) SummarizeUnresolvedReconciliationItems(ctx context.Context, asOf time.Time) ([]dbgen.SummarizeUnresolvedReconciliationItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SummarizeUnresolvedReconciliationItems", ctx, asOf)
+	ret0, _ := ret[0].([]dbgen.SummarizeUnresolvedReconciliationItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SummarizeUnresolvedReconciliationItems indicates an expected call of SummarizeUnresolvedReconciliationItems.
+func (mr *MockFinderRepositoryMockRecorder) SummarizeUnresolvedReconciliationItems(ctx, asOf any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SummarizeUnresolvedReconciliationItems", reflect.TypeOf((*MockFinderRepository)(nil).SummarizeUnresolvedReconciliationItems), ctx, asOf)
+}
@@ -11,9 +11,10 @@ package mock_reconciliatonjob
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
+	time "time"
 
-	filestorage "github.com/delly/amartha/repository/file_storage"
 	dbgen "github.com/delly/amartha/repository/postgresql"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -78,6 +79,36 @@ func (m *MockProcesserRepository) EXPECT() *MockProcesserRepositoryMockRecorder
 	return m.recorder
 }
 
+// GetReconciliationJobStatus mocks base method.
+func (m *MockProcesserRepository) GetReconciliationJobStatus(ctx context.Context, id int64) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReconciliationJobStatus", ctx, id)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReconciliationJobStatus indicates an expected call of GetReconciliationJobStatus.
+func (mr *MockProcesserRepositoryMockRecorder) GetReconciliationJobStatus(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReconciliationJobStatus", reflect.TypeOf((*MockProcesserRepository)(nil).GetReconciliationJobStatus), ctx, id)
+}
+
+// ListOpenReconciliationItems mocks base method.
+func (m *MockProcesserRepository) ListOpenReconciliationItems(ctx context.Context, jobID int64) ([]dbgen.ReconciliationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOpenReconciliationItems", ctx, jobID)
+	ret0, _ := ret[0].([]dbgen.ReconciliationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOpenReconciliationItems indicates an expected call of ListOpenReconciliationItems.
+func (mr *MockProcesserRepositoryMockRecorder) ListOpenReconciliationItems(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOpenReconciliationItems", reflect.TypeOf((*MockProcesserRepository)(nil).ListOpenReconciliationItems), ctx, jobID)
+}
+
 // ListPendingReconciliationJobs mocks base method.
 func (m *MockProcesserRepository) ListPendingReconciliationJobs(ctx context.Context) ([]dbgen.ReconciliationJob, error) {
 	m.ctrl.T.Helper()
@@ -93,6 +124,36 @@ func (mr *MockProcesserRepositoryMockRecorder) ListPendingReconciliationJobs(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingReconciliationJobs", reflect.TypeOf((*MockProcesserRepository)(nil).ListPendingReconciliationJobs), ctx)
 }
 
+// ListSuccessReconciliationJobsByEndDate mocks base method.
+func (m *MockProcesserRepository) ListSuccessReconciliationJobsByEndDate(ctx context.Context, endDate time.Time) ([]dbgen.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSuccessReconciliationJobsByEndDate", ctx, endDate)
+	ret0, _ := ret[0].([]dbgen.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSuccessReconciliationJobsByEndDate indicates an expected call of ListSuccessReconciliationJobsByEndDate.
+func (mr *MockProcesserRepositoryMockRecorder) ListSuccessReconciliationJobsByEndDate(ctx, endDate any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSuccessReconciliationJobsByEndDate", reflect.TypeOf((*MockProcesserRepository)(nil).ListSuccessReconciliationJobsByEndDate), ctx, endDate)
+}
+
+// RenewReconciliationJobLease mocks base method.
+func (m *MockProcesserRepository) RenewReconciliationJobLease(ctx context.Context, arg dbgen.RenewReconciliationJobLeaseParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewReconciliationJobLease", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewReconciliationJobLease indicates an expected call of RenewReconciliationJobLease.
+func (mr *MockProcesserRepositoryMockRecorder) RenewReconciliationJobLease(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewReconciliationJobLease", reflect.TypeOf((*MockProcesserRepository)(nil).RenewReconciliationJobLease), ctx, arg)
+}
+
 // SaveFailedReconciliationJob mocks base method.
 func (m *MockProcesserRepository) SaveFailedReconciliationJob(ctx context.Context, arg dbgen.SaveFailedReconciliationJobParams) (dbgen.ReconciliationJob, error) {
 	m.ctrl.T.Helper()
@@ -108,19 +169,49 @@ func (mr *MockProcesserRepositoryMockRecorder) SaveFailedReconciliationJob(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveFailedReconciliationJob", reflect.TypeOf((*MockProcesserRepository)(nil).SaveFailedReconciliationJob), ctx, arg)
 }
 
-// SaveSuccessReconciliationJob mocks base method.
-func (m *MockProcesserRepository) SaveSuccessReconciliationJob(ctx context.Context, arg dbgen.SaveSuccessReconciliationJobParams) (dbgen.ReconciliationJob, error) {
+// SaveSuccessReconciliationJobWithItems mocks base method.
+func (m *MockProcesserRepository) SaveSuccessReconciliationJobWithItems(ctx context.Context, arg dbgen.SaveSuccessReconciliationJobWithItemsParams) (dbgen.ReconciliationJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveSuccessReconciliationJob", ctx, arg)
+	ret := m.ctrl.Call(m, "SaveSuccessReconciliationJobWithItems", ctx, arg)
 	ret0, _ := ret[0].(dbgen.ReconciliationJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SaveSuccessReconciliationJob indicates an expected call of SaveSuccessReconciliationJob.
-func (mr *MockProcesserRepositoryMockRecorder) SaveSuccessReconciliationJob(ctx, arg any) *gomock.Call {
+// SaveSuccessReconciliationJobWithItems indicates an expected call of SaveSuccessReconciliationJobWithItems.
+func (mr *MockProcesserRepositoryMockRecorder) SaveSuccessReconciliationJobWithItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSuccessReconciliationJobWithItems", reflect.TypeOf((*MockProcesserRepository)(nil).SaveSuccessReconciliationJobWithItems), ctx, arg)
+}
+
+// StartReconciliationJob mocks base method.
+func (m *MockProcesserRepository) StartReconciliationJob(ctx context.Context, arg dbgen.StartReconciliationJobParams) (dbgen.ReconciliationJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartReconciliationJob", ctx, arg)
+	ret0, _ := ret[0].(dbgen.ReconciliationJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartReconciliationJob indicates an expected call of StartReconciliationJob.
+func (mr *MockProcesserRepositoryMockRecorder) StartReconciliationJob(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartReconciliationJob", reflect.TypeOf((*MockProcesserRepository)(nil).StartReconciliationJob), ctx, arg)
+}
+
+// UpdateReconciliationJobProgress mocks base method.
+func (m *MockProcesserRepository) UpdateReconciliationJobProgress(ctx context.Context, arg dbgen.UpdateReconciliationJobProgressParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReconciliationJobProgress", ctx, arg)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateReconciliationJobProgress indicates an expected call of UpdateReconciliationJobProgress.
+func (mr *MockProcesserRepositoryMockRecorder) UpdateReconciliationJobProgress(ctx, arg any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveSuccessReconciliationJob", reflect.TypeOf((*MockProcesserRepository)(nil).SaveSuccessReconciliationJob), ctx, arg)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReconciliationJobProgress", reflect.TypeOf((*MockProcesserRepository)(nil).UpdateReconciliationJobProgress), ctx, arg)
 }
 
 // MockFileGetter is a mock of FileGetter interface.
@@ -147,10 +238,10 @@ func (m *MockFileGetter) EXPECT() *MockFileGetterMockRecorder {
 }
 
 // Get mocks base method.
-func (m *MockFileGetter) Get(ctx context.Context, filePath string) (*filestorage.File, error) {
+func (m *MockFileGetter) Get(ctx context.Context, filePath string) (io.ReadCloser, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", ctx, filePath)
-	ret0, _ := ret[0].(*filestorage.File)
+	ret0, _ := ret[0].(io.ReadCloser)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service/reconciliaton_job/reporter.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./service/reconciliaton_job/reporter.go -destination=test/mock/service/./reconciliaton_job/reporter.go
+//
+
+// Package mock_reconciliatonjob is a generated GoMock package.
+package mock_reconciliatonjob
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	entity "github.com/delly/amartha/entity"
+	dbgen "github.com/delly/amartha/repository/postgresql"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReporter is a mock of Reporter interface.
+type MockReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockReporterMockRecorder
+}
+
+// MockReporterMockRecorder is the mock recorder for MockReporter.
+type MockReporterMockRecorder struct {
+	mock *MockReporter
+}
+
+// NewMockReporter creates a new mock instance.
+func NewMockReporter(ctrl *gomock.Controller) *MockReporter {
+	mock := &MockReporter{ctrl: ctrl}
+	mock.recorder = &MockReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReporter) EXPECT() *MockReporterMockRecorder {
+	return m.recorder
+}
+
+// Report mocks base method.
+func (m *MockReporter) Report(ctx context.Context, job *entity.ReconciliationJob, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, job, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockReporterMockRecorder) Report(ctx, job, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockReporter)(nil).Report), ctx, job, w)
+}
+
+// MockReporterRepository is a mock of ReporterRepository interface.
+type MockReporterRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReporterRepositoryMockRecorder
+}
+
+// MockReporterRepositoryMockRecorder is the mock recorder for MockReporterRepository.
+type MockReporterRepositoryMockRecorder struct {
+	mock *MockReporterRepository
+}
+
+// NewMockReporterRepository creates a new mock instance.
+func NewMockReporterRepository(ctrl *gomock.Controller) *MockReporterRepository {
+	mock := &MockReporterRepository{ctrl: ctrl}
+	mock.recorder = &MockReporterRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReporterRepository) EXPECT() *MockReporterRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListMissingReconciliationItems mocks base method.
+func (m *MockReporterRepository) ListMissingReconciliationItems(ctx context.Context, arg dbgen.ListMissingReconciliationItemsParams) ([]dbgen.ReconciliationItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMissingReconciliationItems", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ReconciliationItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMissingReconciliationItems indicates an expected call of ListMissingReconciliationItems.
+func (mr *MockReporterRepositoryMockRecorder) ListMissingReconciliationItems(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMissingReconciliationItems", reflect.TypeOf((*MockReporterRepository)(nil).ListMissingReconciliationItems), ctx, arg)
+}
+
+// ListReconciliationMatchedPairs mocks base method.
+func (m *MockReporterRepository) ListReconciliationMatchedPairs(ctx context.Context, arg dbgen.ListReconciliationMatchedPairsParams) ([]dbgen.ListReconciliationMatchedPairsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReconciliationMatchedPairs", ctx, arg)
+	ret0, _ := ret[0].([]dbgen.ListReconciliationMatchedPairsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReconciliationMatchedPairs indicates an expected call of ListReconciliationMatchedPairs.
+func (mr *MockReporterRepositoryMockRecorder) ListReconciliationMatchedPairs(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReconciliationMatchedPairs", reflect.TypeOf((*MockReporterRepository)(nil).ListReconciliationMatchedPairs), ctx, arg)
+}
+
+// SummarizeReconciliationItems mocks base method.
+func (m *MockReporterRepository) SummarizeReconciliationItems(ctx context.Context, jobID int64) ([]dbgen.SummarizeReconciliationItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SummarizeReconciliationItems", ctx, jobID)
+	ret0, _ := ret[0].([]dbgen.SummarizeReconciliationItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SummarizeReconciliationItems indicates an expected call of SummarizeReconciliationItems.
+func (mr *MockReporterRepositoryMockRecorder) SummarizeReconciliationItems(ctx, jobID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SummarizeReconciliationItems", reflect.TypeOf((*MockReporterRepository)(nil).SummarizeReconciliationItems), ctx, jobID)
+}